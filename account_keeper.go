@@ -0,0 +1,342 @@
+package banexg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"github.com/sasha-s/go-deadlock"
+	"go.uber.org/zap"
+)
+
+const (
+	DriftPosition = "position"
+	DriftBalance  = "balance"
+)
+
+// DriftEvent the ws maintained state of an account differs from the rest snapshot
+type DriftEvent struct {
+	AccName    string
+	MarketType string
+	Kind       string                // DriftPosition or DriftBalance
+	Key        string                // symbol#side for positions, currency code for balances
+	Diffs      map[string][2]float64 // field: [ws value, rest value]
+	Healed     bool                  // local state was replaced by the rest snapshot
+}
+
+func (d *DriftEvent) String() string {
+	keys := utils.KeysOfMap(d.Diffs)
+	sort.Strings(keys)
+	text := ""
+	for _, k := range keys {
+		v := d.Diffs[k]
+		text += fmt.Sprintf(" %s: %v -> %v", k, v[0], v[1])
+	}
+	return fmt.Sprintf("%s %s %s %s:%s", d.AccName, d.MarketType, d.Kind, d.Key, text)
+}
+
+/*
+AccountKeeper
+snapshot positions and balances by rest every Intv, compare them with Account.MarPositions/MarBalances maintained
+by ws (WatchPositions/WatchBalance), report each difference by OnDrift and replace the local state with the
+snapshot when Heal is true. Items updated by ws after the snapshot started are skipped.
+Also checks soon after the private ws connection of the account is reconnected.
+每隔Intv通过rest获取持仓和余额快照，与ws维护的Account.MarPositions/MarBalances比较，通过OnDrift报告差异，
+Heal为true时用快照替换本地状态。快照开始后ws更新过的项会被跳过。该账户私有ws重连后也会很快检查一次
+*/
+type AccountKeeper struct {
+	Exg        BanExchange
+	AccName    string
+	MarketType string
+	StateKey   string                 // key of Account.MarPositions/MarBalances maintained by ws, by Exchange.AccStateKey
+	Params     map[string]interface{} // extra params for FetchPositions/FetchBalance
+	Intv       time.Duration          // interval of rest snapshots
+	Tolerance  float64                // relative tolerance of values
+	Heal       bool                   // replace local state with rest snapshot on drift
+	OnDrift    func(evt *DriftEvent)
+
+	lock    deadlock.Mutex // one check at a time
+	stop    chan struct{}
+	stopMu  deadlock.Mutex
+	hookKey string
+}
+
+/*
+NewAccountKeeper
+create a keeper for ParamAccount and ParamMarket in params (defaults of exchange if empty), call Start to run it.
+为params中ParamAccount和ParamMarket(为空时使用交易所默认值)创建检查器，调用Start运行
+*/
+func NewAccountKeeper(exg BanExchange, params map[string]interface{}) (*AccountKeeper, *errs.Error) {
+	args := utils.SafeParams(params)
+	acc, err := exg.GetAccount(utils.PopMapVal(args, ParamAccount, ""))
+	if err != nil {
+		return nil, err
+	}
+	marketType, _ := exg.GetExg().GetArgsMarketType(args, "")
+	stateKey := marketType
+	if getKey := exg.GetExg().AccStateKey; getKey != nil {
+		stateKey = getKey(marketType)
+	}
+	return &AccountKeeper{
+		Exg:        exg,
+		AccName:    acc.Name,
+		MarketType: marketType,
+		StateKey:   stateKey,
+		Params:     args,
+		Intv:       time.Minute,
+		Tolerance:  1e-6,
+		Heal:       true,
+	}, nil
+}
+
+// Start check every Intv in background until Stop
+func (k *AccountKeeper) Start() *errs.Error {
+	if k.Intv <= 0 {
+		return errs.NewMsg(errs.CodeParamInvalid, "Intv of AccountKeeper must > 0")
+	}
+	k.stopMu.Lock()
+	if k.stop != nil {
+		k.stopMu.Unlock()
+		return nil
+	}
+	stop := make(chan struct{})
+	k.stop = stop
+	k.stopMu.Unlock()
+	recon := make(chan struct{}, 1)
+	k.hookKey = fmt.Sprintf("accKeeper_%p", k)
+	k.Exg.GetExg().SetWsReConHook(k.hookKey, func(client *WsClient, connID int) *errs.Error {
+		if client != nil && client.AccName == k.AccName {
+			select {
+			case recon <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	})
+	go func() {
+		ticker := time.NewTicker(k.Intv)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			case <-recon:
+				// wait a moment for the subscriptions to be restored
+				select {
+				case <-stop:
+					return
+				case <-time.After(time.Second):
+				}
+			}
+			if _, err := k.Check(); err != nil {
+				log.Warn("check account drift fail", zap.String("acc", k.AccName), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (k *AccountKeeper) Stop() {
+	k.stopMu.Lock()
+	stop := k.stop
+	k.stop = nil
+	k.stopMu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	k.Exg.GetExg().SetWsReConHook(k.hookKey, nil)
+}
+
+/*
+Check
+take a rest snapshot and compare it with the local state, return drift events (already passed to OnDrift).
+positions are only checked for contract markets.
+获取rest快照并与本地状态比较，返回差异事件(已传给OnDrift)。仅合约市场检查持仓
+*/
+func (k *AccountKeeper) Check() ([]*DriftEvent, *errs.Error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	acc, err := k.Exg.GetAccount(k.AccName)
+	if err != nil {
+		return nil, err
+	}
+	var result []*DriftEvent
+	if k.Exg.IsContract(k.MarketType) {
+		start := k.Exg.MilliSeconds()
+		positions, err := k.Exg.FetchPositions(nil, k.args())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, k.checkPositions(acc, positions, start)...)
+	}
+	start := k.Exg.MilliSeconds()
+	balances, err := k.Exg.FetchBalance(k.args())
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, k.checkBalances(acc, balances, start)...)
+	if k.OnDrift != nil {
+		for _, evt := range result {
+			k.OnDrift(evt)
+		}
+	}
+	return result, nil
+}
+
+func (k *AccountKeeper) args() map[string]interface{} {
+	args := utils.SafeParams(k.Params)
+	args[ParamAccount] = k.AccName
+	args[ParamMarket] = k.MarketType
+	return args
+}
+
+func (k *AccountKeeper) checkPositions(acc *Account, remote []*Position, start int64) []*DriftEvent {
+	remoteMap := make(map[string]*Position)
+	for _, p := range remote {
+		if p.Contracts != 0 {
+			remoteMap[p.Symbol+"#"+p.Side] = p
+		}
+	}
+	stateKey := k.stateKey()
+	acc.LockPos.Lock()
+	defer acc.LockPos.Unlock()
+	local, ok := acc.MarPositions[stateKey]
+	if !ok {
+		// not watched yet, nothing to compare
+		return nil
+	}
+	var result []*DriftEvent
+	var fresh []*Position  // updated by ws after the snapshot, keep them
+	var others []*Position // other markets sharing the state key, not in the snapshot
+	localMap := make(map[string]*Position)
+	for _, p := range local {
+		if stateKey != k.MarketType && !k.isMarketOf(p.Symbol) {
+			others = append(others, p)
+			continue
+		}
+		key := p.Symbol + "#" + p.Side
+		if p.TimeStamp >= start {
+			fresh = append(fresh, p)
+			delete(remoteMap, key)
+			continue
+		}
+		if p.Contracts != 0 {
+			localMap[key] = p
+		}
+	}
+	for key, lp := range localMap {
+		rp, ok := remoteMap[key]
+		diffs := make(map[string][2]float64)
+		if !ok {
+			diffs["contracts"] = [2]float64{lp.Contracts, 0}
+		} else {
+			if !k.equal(lp.Contracts, rp.Contracts) {
+				diffs["contracts"] = [2]float64{lp.Contracts, rp.Contracts}
+			}
+			if lp.EntryPrice > 0 && !k.equal(lp.EntryPrice, rp.EntryPrice) {
+				diffs["entryPrice"] = [2]float64{lp.EntryPrice, rp.EntryPrice}
+			}
+		}
+		if len(diffs) > 0 {
+			result = append(result, k.newDrift(DriftPosition, key, diffs))
+		}
+	}
+	for key, rp := range remoteMap {
+		if _, ok := localMap[key]; !ok {
+			result = append(result, k.newDrift(DriftPosition, key, map[string][2]float64{
+				"contracts": {0, rp.Contracts},
+			}))
+		}
+	}
+	if len(result) > 0 && k.Heal {
+		healed := make([]*Position, 0, len(remoteMap)+len(fresh))
+		for _, p := range remoteMap {
+			healed = append(healed, p)
+		}
+		healed = append(healed, fresh...)
+		acc.MarPositions[stateKey] = append(healed, others...)
+	}
+	return result
+}
+
+func (k *AccountKeeper) stateKey() string {
+	if k.StateKey != "" {
+		return k.StateKey
+	}
+	return k.MarketType
+}
+
+// isMarketOf whether symbol belongs to MarketType of the keeper
+func (k *AccountKeeper) isMarketOf(symbol string) bool {
+	market, err := k.Exg.GetMarket(symbol)
+	return err == nil && market.Type == k.MarketType
+}
+
+func (k *AccountKeeper) checkBalances(acc *Account, remote *Balances, start int64) []*DriftEvent {
+	stateKey := k.stateKey()
+	acc.LockBalance.Lock()
+	defer acc.LockBalance.Unlock()
+	local, ok := acc.MarBalances[stateKey]
+	if !ok || local == nil || local.TimeStamp >= start {
+		// not watched yet, or updated by ws after the snapshot
+		return nil
+	}
+	var result []*DriftEvent
+	codes := make(map[string]bool)
+	for code := range local.Assets {
+		codes[code] = true
+	}
+	for code := range remote.Assets {
+		codes[code] = true
+	}
+	for code := range codes {
+		lv, rv := assetWallet(local.Assets[code]), assetWallet(remote.Assets[code])
+		if !k.equal(lv, rv) {
+			result = append(result, k.newDrift(DriftBalance, code, map[string][2]float64{
+				"wallet": {lv, rv},
+			}))
+		}
+	}
+	if len(result) > 0 && k.Heal {
+		acc.MarBalances[stateKey] = remote
+	}
+	return result
+}
+
+func (k *AccountKeeper) newDrift(kind, key string, diffs map[string][2]float64) *DriftEvent {
+	return &DriftEvent{
+		AccName:    k.AccName,
+		MarketType: k.MarketType,
+		Kind:       kind,
+		Key:        key,
+		Diffs:      diffs,
+		Healed:     k.Heal,
+	}
+}
+
+func (k *AccountKeeper) equal(a, b float64) bool {
+	return math.Abs(a-b) <= k.Tolerance*max(math.Abs(a), math.Abs(b))
+}
+
+/*
+assetWallet
+wallet balance of asset without unrealized pnl, Total falls back to Free+Used.
+ws of some exchanges only push the wallet balance, this is the value comparable across ws and rest.
+不含未实现盈亏的钱包余额，Total为0时使用Free+Used。部分交易所ws仅推送钱包余额，这是ws和rest间可比较的值
+*/
+func assetWallet(a *Asset) float64 {
+	if a == nil {
+		return 0
+	}
+	total := a.Total
+	if total == 0 {
+		total = a.Free + a.Used
+	}
+	return total - a.UPol
+}
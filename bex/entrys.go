@@ -7,6 +7,7 @@ import (
 	"github.com/banbox/banexg/china"
 	"github.com/banbox/banexg/errs"
 	"github.com/banbox/banexg/okx"
+	"github.com/banbox/banexg/paper"
 	"github.com/banbox/banexg/utils"
 	"github.com/banbox/banexg/vietnam"
 )
@@ -17,6 +18,7 @@ func init() {
 		"bybit":   WrapNew(bybit.New),
 		"china":   china.NewExchange,
 		"okx":     okx.NewExchange,
+		"paper":   newPaper,
 		"vietnam": vietnam.NewExchange,
 	}
 }
@@ -28,3 +30,23 @@ func New(name string, options map[string]interface{}) (banexg.BanExchange, *errs
 	}
 	return fn(utils.SafeParams(options))
 }
+
+/*
+newPaper
+create the paper exchange, the source exchange is paper.OptSrcExg or created by paper.OptSrcName with the same options
+创建模拟交易所，源交易所为paper.OptSrcExg，或使用相同选项按paper.OptSrcName创建
+*/
+func newPaper(options map[string]interface{}) (banexg.BanExchange, *errs.Error) {
+	if _, ok := options[paper.OptSrcExg]; !ok {
+		srcName := utils.GetMapVal(options, paper.OptSrcName, "")
+		if srcName == "" || srcName == "paper" {
+			return nil, errs.NewMsg(errs.CodeParamRequired, "%s or %s is required for paper", paper.OptSrcExg, paper.OptSrcName)
+		}
+		src, err := New(srcName, options)
+		if err != nil {
+			return nil, err
+		}
+		options[paper.OptSrcExg] = src
+	}
+	return paper.NewExchange(options)
+}
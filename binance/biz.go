@@ -83,6 +83,8 @@ func makeSign(e *Binance) banexg.FuncSign {
 	return func(api *banexg.Entry, args map[string]interface{}) *banexg.HttpReq {
 		// ParamSettleCoins is introduced for bybit; binance APIs do not support it.
 		delete(args, banexg.ParamSettleCoins)
+		// only used by websocket trading api
+		delete(args, banexg.ParamWsTrade)
 		var params = utils.SafeParams(args)
 		accID := e.PopAccName(params)
 		// 检查NoTrade限制
@@ -133,38 +135,31 @@ func makeSign(e *Binance) banexg.FuncSign {
 				extendParams["recvWindow"] = e.RecvWindow
 			}
 			if path == "batchOrders" || strings.Contains(path, "sub-account") || path == "capital/withdraw/apply" || strings.Contains(path, "staking") {
-				query = append(query, utils.UrlEncodeMap(extendParams, true))
+				var idQuery []string
 				if api.Method == "DELETE" && path == "batchOrders" {
 					if orderIds, ok := extendParams[banexg.ParamOrderIds]; ok {
+						delete(extendParams, banexg.ParamOrderIds)
 						if ids, ok := orderIds.([]string); ok {
 							idText := strings.Join(ids, ",")
-							query = append(query, "orderidlist=["+idText+"]")
+							idQuery = append(idQuery, "orderidlist=["+idText+"]")
 						}
 					}
 					if orderIds, ok := extendParams[banexg.ParamOrigClientOrderIDs]; ok {
+						delete(extendParams, banexg.ParamOrigClientOrderIDs)
 						if ids, ok := orderIds.([]string); ok {
 							idText := strings.Join(ids, ",")
-							query = append(query, "origclientorderidlist=["+idText+"]")
+							idQuery = append(idQuery, "origclientorderidlist=["+idText+"]")
 						}
 					}
 				}
+				query = append(query, utils.UrlEncodeMap(extendParams, true))
+				query = append(query, idQuery...)
 			} else {
 				query = append(query, utils.UrlEncodeMap(extendParams, false))
 			}
-			var sign, method, hash string
-			var digest = "hex"
-			var secret = creds.Secret
-			if strings.Contains(secret, "PRIVATE KEY") {
-				if len(secret) > 120 {
-					method, hash = "rsa", "sha256"
-				} else {
-					method, hash = "eddsa", "ed25519"
-				}
-			} else {
-				method, hash = "hmac", "sha256"
-			}
+			var sign string
 			queryText := strings.Join(query, "&")
-			sign, err = utils.Signature(queryText, secret, method, hash, digest)
+			sign, err = signQuery(creds.Secret, queryText)
 			if err != nil {
 				return &banexg.HttpReq{Error: err, Private: true}
 			}
@@ -184,6 +179,21 @@ func makeSign(e *Binance) banexg.FuncSign {
 	}
 }
 
+// signQuery sign with hmac, or rsa/ed25519 when the secret is a private key
+func signQuery(secret, text string) (string, *errs.Error) {
+	var method, hash string
+	if strings.Contains(secret, "PRIVATE KEY") {
+		if len(secret) > 120 {
+			method, hash = "rsa", "sha256"
+		} else {
+			method, hash = "eddsa", "ed25519"
+		}
+	} else {
+		method, hash = "hmac", "sha256"
+	}
+	return utils.Signature(text, secret, method, hash, "hex")
+}
+
 /*
 fetches all available currencies on an exchange
 :see: https://binance-docs.github.io/apidocs/spot/en/#all-coins-39-information-user_data
@@ -295,6 +305,84 @@ func makeGetRetryWait(e *Binance) func(e *errs.Error) int {
 	}
 }
 
+/*
+mapBizErr
+map binance business code to normalized errs code, -2010 shares one code for many reasons so msg is checked
+将币安业务错误码映射为标准错误码，-2010包含多种原因，需检查msg
+https://developers.binance.com/docs/binance-spot-api-docs/errors
+*/
+func mapBizErr(bizCode int, msg string) int {
+	switch bizCode {
+	case -1021:
+		// Timestamp for this request is outside of the recvWindow.
+		return errs.CodeInvalidNonce
+	case -1022:
+		return errs.CodeSignFail
+	case -2014, -2015:
+		return errs.CodeAccKeyError
+	case -2011, -2013:
+		// Unknown order sent. / Order does not exist.
+		return errs.CodeOrderNotFound
+	case -2018, -2019:
+		// Balance is insufficient. / Margin is insufficient.
+		return errs.CodeInsufficientBalance
+	case -2021, -5022:
+		// Order would immediately trigger. / Post Only order will be rejected
+		return errs.CodeOrderWouldMatch
+	case -2022:
+		// ReduceOnly Order is rejected.
+		return errs.CodeReduceOnlyRejected
+	case -4061:
+		// Order's position side does not match user's setting.
+		return errs.CodePositionModeMismatch
+	case -2010:
+		lowMsg := strings.ToLower(msg)
+		if strings.Contains(lowMsg, "insufficient balance") {
+			return errs.CodeInsufficientBalance
+		} else if strings.Contains(lowMsg, "immediately match") {
+			return errs.CodeOrderWouldMatch
+		} else if strings.Contains(lowMsg, "market is closed") {
+			return errs.CodeMarketClosed
+		}
+	}
+	return 0
+}
+
+/*
+FetchTime
+get server time of the market type in params, default MarketType is used if not provided
+获取params中市场类型的服务器时间，未提供时使用默认MarketType
+:see: https://developers.binance.com/docs/binance-spot-api-docs/rest-api/general-endpoints#check-server-time
+*/
+func (e *Binance) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType, _ := e.GetArgsMarketType(args, "")
+	var method string
+	switch marketType {
+	case banexg.MarketOption:
+		method = MethodEapiPublicGetTime
+	case banexg.MarketLinear:
+		method = MethodFapiPublicGetTime
+	case banexg.MarketInverse:
+		method = MethodDapiPublicGetTime
+	default:
+		method = MethodPublicGetTime
+	}
+	tryNum := e.GetRetryNum("FetchTime", 1)
+	rsp := e.RequestApiRetryAdv(context.Background(), method, args, tryNum, false, false)
+	if rsp.Error != nil {
+		return 0, rsp.Error
+	}
+	var res = struct {
+		ServerTime int64 `json:"serverTime"`
+	}{}
+	err := utils.UnmarshalString(rsp.Content, &res, utils.JsonNumDefault)
+	if err != nil {
+		return 0, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	return res.ServerTime, nil
+}
+
 var marketApiMap = map[string]string{
 	banexg.MarketSpot:    MethodPublicGetExchangeInfo,
 	banexg.MarketLinear:  MethodFapiPublicGetExchangeInfo,
@@ -573,7 +661,7 @@ fetches historical candlestick data containing the open, high, low, and close pr
 :param int [since]: timestamp in ms of the earliest candle to fetch
 :param int [limit]: the maximum amount of candles to fetch
 :param dict [params]: extra parameters specific to the exchange API endpoint
-:param str [params.price]: "mark" or "index" for mark price and index price candles
+:param str [params.price]: banexg.ParamPrice, PriceMark/PriceIndex/PricePremiumIndex for linear/inverse
 :param int [params.until]: timestamp in ms of the latest candle to fetch
 :param boolean [params.paginate]: default False, when True will automatically paginate by calling self endpoint multiple times. See in the docs all the [availble parameters](https://github.com/ccxt/ccxt/wiki/Manual#pagination-params)
 :returns int[][]: A list of candles ordered, open, high, low, close, volume
@@ -583,7 +671,10 @@ func (e *Binance) FetchOHLCV(symbol, timeframe string, since int64, limit int, p
 	if err != nil {
 		return nil, err
 	}
-	priceType := utils.PopMapVal(args, "price", "")
+	priceType := utils.PopMapVal(args, banexg.ParamPrice, "")
+	if priceType != "" && !market.Linear && !market.Inverse {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "%s klines only support linear/inverse", priceType)
+	}
 	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
 	//binance docs say that the default limit 500, max 1500 for futures, max 1000 for spot markets
 	//the reality is that the time range wider than 500 candles won't work right
@@ -594,8 +685,12 @@ func (e *Binance) FetchOHLCV(symbol, timeframe string, since int64, limit int, p
 	}
 	args["interval"] = e.GetTimeFrame(timeframe)
 	args["limit"] = limit
-	if priceType == "index" {
-		args["pair"] = market.ID
+	if priceType == banexg.PriceIndex {
+		pair := utils.GetMapVal(market.Info, "pair", "")
+		if pair == "" {
+			pair, _, _ = strings.Cut(market.ID, "_")
+		}
+		args["pair"] = pair
 	} else {
 		args["symbol"] = market.ID
 	}
@@ -618,18 +713,26 @@ func (e *Binance) FetchOHLCV(symbol, timeframe string, since int64, limit int, p
 	method := MethodPublicGetKlines
 	if market.Option {
 		method = MethodEapiPublicGetKlines
-	} else if priceType == "mark" {
+	} else if priceType == banexg.PriceMark {
 		if market.Inverse {
 			method = MethodDapiPublicGetMarkPriceKlines
 		} else {
 			method = MethodFapiPublicGetMarkPriceKlines
 		}
-	} else if priceType == "index" {
+	} else if priceType == banexg.PriceIndex {
 		if market.Inverse {
 			method = MethodDapiPublicGetIndexPriceKlines
 		} else {
 			method = MethodFapiPublicGetIndexPriceKlines
 		}
+	} else if priceType == banexg.PricePremiumIndex {
+		if market.Inverse {
+			method = MethodDapiPublicGetPremiumIndexKlines
+		} else {
+			method = MethodFapiPublicGetPremiumIndexKlines
+		}
+	} else if priceType != "" {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport %s: %s", banexg.ParamPrice, priceType)
 	} else if market.Linear {
 		method = MethodFapiPublicGetKlines
 	} else if market.Inverse {
@@ -696,6 +799,143 @@ func (e *Binance) SetLeverage(leverage float64, symbol string, params map[string
 	return res, nil
 }
 
+/*
+SetMarginMode
+set margin type of symbol to cross or isolated
+
+	:see: https://binance-docs.github.io/apidocs/futures/en/#change-margin-type-trade
+	:see: https://binance-docs.github.io/apidocs/delivery/en/#change-margin-type-trade
+*/
+func (e *Binance) SetMarginMode(mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if symbol == "" {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbol is required for %v.SetMarginMode", e.Name)
+	}
+	var marginType string
+	if mode == banexg.MarginCross {
+		marginType = "CROSSED"
+	} else if mode == banexg.MarginIsolated {
+		marginType = "ISOLATED"
+	} else {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid margin mode: %s", mode)
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	if market.Linear {
+		method = MethodFapiPrivatePostMarginType
+	} else if market.Inverse {
+		method = MethodDapiPrivatePostMarginType
+	} else {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "%v SetMarginMode supports linear and inverse contracts only", e.Name)
+	}
+	args["symbol"] = market.ID
+	args["marginType"] = marginType
+	tryNum := e.GetRetryNum("SetMarginMode", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		// -4046: No need to change margin type
+		if rsp.Error.BizCode == -4046 {
+			return map[string]interface{}{}, nil
+		}
+		return nil, rsp.Error
+	}
+	return decodeRspMap(e, rsp)
+}
+
+/*
+SetPositionMode
+set position mode of all symbols in linear (default) or inverse market
+
+	:see: https://binance-docs.github.io/apidocs/futures/en/#change-position-mode-trade
+	:see: https://binance-docs.github.io/apidocs/delivery/en/#change-position-mode-trade
+*/
+func (e *Binance) SetPositionMode(hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType, _, err := e.LoadArgsMarketType(args)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	if marketType == banexg.MarketLinear {
+		method = MethodFapiPrivatePostPositionSideDual
+	} else if marketType == banexg.MarketInverse {
+		method = MethodDapiPrivatePostPositionSideDual
+	} else {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "%v SetPositionMode supports linear and inverse contracts only", e.Name)
+	}
+	args["dualSidePosition"] = strconv.FormatBool(hedged)
+	tryNum := e.GetRetryNum("SetPositionMode", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		// -4059: No need to change position side
+		if rsp.Error.BizCode == -4059 {
+			return map[string]interface{}{}, nil
+		}
+		return nil, rsp.Error
+	}
+	return decodeRspMap(e, rsp)
+}
+
+/*
+AddMargin
+:see: https://binance-docs.github.io/apidocs/futures/en/#modify-isolated-position-margin-trade
+:see: https://binance-docs.github.io/apidocs/delivery/en/#modify-isolated-position-margin-trade
+*/
+func (e *Binance) AddMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, amount, 1, params)
+}
+
+/*
+ReduceMargin
+:see: https://binance-docs.github.io/apidocs/futures/en/#modify-isolated-position-margin-trade
+:see: https://binance-docs.github.io/apidocs/delivery/en/#modify-isolated-position-margin-trade
+*/
+func (e *Binance) ReduceMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, amount, 2, params)
+}
+
+// modifyMargin type 1 is add, 2 is reduce
+func (e *Binance) modifyMargin(symbol string, amount float64, modType int, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if amount <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid margin amount: %v", amount)
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	if market.Linear {
+		method = MethodFapiPrivatePostPositionMargin
+	} else if market.Inverse {
+		method = MethodDapiPrivatePostPositionMargin
+	} else {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "%v position margin supports linear and inverse contracts only", e.Name)
+	}
+	args["symbol"] = market.ID
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["type"] = modType
+	if posSide := utils.PopMapVal(args, banexg.ParamPositionSide, ""); posSide != "" {
+		args["positionSide"] = strings.ToUpper(posSide)
+	}
+	// not idempotent, never retry
+	rsp := e.RequestApiRetry(context.Background(), method, args, 0)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	return decodeRspMap(e, rsp)
+}
+
+func decodeRspMap(e *Binance, rsp *banexg.HttpRes) (map[string]interface{}, *errs.Error) {
+	var res = make(map[string]interface{})
+	err := utils.UnmarshalString(rsp.Content, &res, utils.JsonNumAuto)
+	if err != nil {
+		return nil, errs.NewFull(errs.CodeUnmarshalFail, err, "%s decode rsp fail", e.Name)
+	}
+	return res, nil
+}
+
 func (e *Binance) LoadLeverageBrackets(reload bool, params map[string]interface{}) *errs.Error {
 	if len(e.LeverageBrackets) > 0 && !reload {
 		return nil
@@ -964,7 +1204,31 @@ func (e *Binance) ReSubsAll(client *banexg.WsClient, connID int) *errs.Error {
 }
 
 func (e *Binance) regReplayHandles() {
+	// replay Watch* methods which only take symbols
+	watchSymbols := func(name string, watch func(symbols []string) *errs.Error) func(item *banexg.WsLog) *errs.Error {
+		return func(item *banexg.WsLog) *errs.Error {
+			var symbols = make([]string, 0)
+			err_ := utils.UnmarshalString(item.Content, &symbols, utils.JsonNumDefault)
+			if err_ != nil {
+				return errs.New(errs.CodeUnmarshalFail, err_)
+			}
+			log.Debug("replay "+name, zap.Strings("codes", symbols))
+			return watch(symbols)
+		}
+	}
 	e.WsReplayFn = map[string]func(item *banexg.WsLog) *errs.Error{
+		"WatchTickers": watchSymbols("WatchTickers", func(symbols []string) *errs.Error {
+			_, err := e.WatchTickers(symbols, nil)
+			return err
+		}),
+		"WatchLiquidations": watchSymbols("WatchLiquidations", func(symbols []string) *errs.Error {
+			_, err := e.WatchLiquidations(symbols, nil)
+			return err
+		}),
+		"WatchGreeks": watchSymbols("WatchGreeks", func(symbols []string) *errs.Error {
+			_, err := e.WatchGreeks(symbols, nil)
+			return err
+		}),
 		"WatchOrderBooks": func(item *banexg.WsLog) *errs.Error {
 			var symbols = make([]string, 0)
 			err_ := utils.UnmarshalString(item.Content, &symbols, utils.JsonNumDefault)
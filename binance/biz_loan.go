@@ -0,0 +1,197 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+/*
+Borrow
+borrow on cross margin account, or isolated margin account of symbol
+在全仓杠杆账户借币，传入symbol时在该交易对的逐仓账户借币
+:see: https://developers.binance.com/docs/margin_trading/borrow-and-repay/Margin-Account-Borrow-Repay
+*/
+func (e *Binance) Borrow(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay("BORROW", code, amount, symbol, params)
+}
+
+/*
+Repay
+:see: https://developers.binance.com/docs/margin_trading/borrow-and-repay/Margin-Account-Borrow-Repay
+*/
+func (e *Binance) Repay(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay("REPAY", code, amount, symbol, params)
+}
+
+func (e *Binance) borrowRepay(loanType, code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	args := utils.SafeParams(params)
+	marginMode, err := banexg.GetLoanMarginMode(symbol, args)
+	if err != nil {
+		return nil, err
+	}
+	args["asset"] = code
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["type"] = loanType
+	args["isIsolated"] = "FALSE"
+	if marginMode == banexg.MarginIsolated {
+		market, err := e.GetMarket(symbol)
+		if err != nil {
+			return nil, err
+		}
+		args["isIsolated"] = "TRUE"
+		args["symbol"] = market.ID
+	}
+	// not idempotent, never retry
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiPostMarginBorrowRepay, args, 0)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		TranId int64 `json:"tranId"`
+	}{}
+	info, err2 := utils.UnmarshalStringMap(rsp.Content, &res)
+	if err2 != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err2)
+	}
+	if marginMode != banexg.MarginIsolated {
+		symbol = ""
+	}
+	return &banexg.MarginLoan{
+		ID:         strconv.FormatInt(res.TranId, 10),
+		Code:       code,
+		Amount:     amount,
+		Symbol:     symbol,
+		MarginMode: marginMode,
+		Timestamp:  e.MilliSeconds(),
+		Info:       info,
+	}, nil
+}
+
+/*
+FetchBorrowRate
+hourly rate of cross margin, set ParamMarginMode to isolated for isolated margin
+全仓杠杆的小时利率，ParamMarginMode设为isolated时返回逐仓利率
+:see: https://developers.binance.com/docs/margin_trading/account/Get-future-hourly-interest-rate
+*/
+func (e *Binance) FetchBorrowRate(code string, params map[string]interface{}) (*banexg.BorrowRate, *errs.Error) {
+	rates, err := e.FetchBorrowRates([]string{code}, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "borrow rate of %s not found", code)
+	}
+	return rates[0], nil
+}
+
+/*
+FetchBorrowRates
+codes is required, at most 20 codes in a request
+:see: https://developers.binance.com/docs/margin_trading/account/Get-future-hourly-interest-rate
+*/
+func (e *Binance) FetchBorrowRates(codes []string, params map[string]interface{}) ([]*banexg.BorrowRate, *errs.Error) {
+	if len(codes) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "codes required for binance.FetchBorrowRates")
+	}
+	args := utils.SafeParams(params)
+	marginMode := utils.PopMapVal(args, banexg.ParamMarginMode, "")
+	args["isIsolated"] = "FALSE"
+	if marginMode == banexg.MarginIsolated {
+		args["isIsolated"] = "TRUE"
+	}
+	tryNum := e.GetRetryNum("FetchBorrowRates", 1)
+	result := make([]*banexg.BorrowRate, 0, len(codes))
+	for start := 0; start < len(codes); start += 20 {
+		batch := codes[start:min(start+20, len(codes))]
+		args["assets"] = strings.Join(batch, ",")
+		rsp := e.RequestApiRetry(context.Background(), MethodSapiGetMarginNextHourlyInterestRate, args, tryNum)
+		if rsp.Error != nil {
+			return nil, rsp.Error
+		}
+		var data = make([]*struct {
+			Asset                  string `json:"asset"`
+			NextHourlyInterestRate string `json:"nextHourlyInterestRate"`
+		}, 0)
+		infos, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+		if err != nil {
+			return nil, errs.New(errs.CodeUnmarshalFail, err)
+		}
+		stamp := e.MilliSeconds()
+		for i, it := range data {
+			rate, _ := strconv.ParseFloat(it.NextHourlyInterestRate, 64)
+			result = append(result, &banexg.BorrowRate{
+				Code:      e.SafeCurrencyCode(it.Asset),
+				Rate:      rate,
+				Period:    3600000,
+				Timestamp: stamp,
+				Info:      infos[i],
+			})
+		}
+	}
+	return result, nil
+}
+
+/*
+FetchBorrowInterest
+:see: https://developers.binance.com/docs/margin_trading/borrow-and-repay/Get-Interest-History
+*/
+func (e *Binance) FetchBorrowInterest(code, symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.BorrowInterest, *errs.Error) {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args["asset"] = code
+	}
+	if symbol != "" {
+		market, err := e.GetMarket(symbol)
+		if err != nil {
+			return nil, err
+		}
+		args["isolatedSymbol"] = market.ID
+	}
+	if since > 0 {
+		args["startTime"] = since
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args["endTime"] = until
+	}
+	if limit > 0 {
+		args["size"] = min(limit, 100)
+	}
+	tryNum := e.GetRetryNum("FetchBorrowInterest", 1)
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiGetMarginInterestHistory, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var data = struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}{}
+	if err := utils.UnmarshalString(rsp.Content, &data, utils.JsonNumAuto); err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	var items []MarginInterest
+	if err := utils.DecodeStructMap(data.Rows, &items, "json"); err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	res := make([]*banexg.BorrowInterest, 0, len(items))
+	for i, it := range items {
+		item := &banexg.BorrowInterest{
+			Code:       e.SafeCurrencyCode(it.Asset),
+			MarginMode: banexg.MarginCross,
+			Timestamp:  it.InterestAccuredTime,
+			Info:       data.Rows[i],
+		}
+		item.Interest, _ = strconv.ParseFloat(it.Interest, 64)
+		item.Rate, _ = strconv.ParseFloat(it.InterestRate, 64)
+		item.Amount, _ = strconv.ParseFloat(it.Principal, 64)
+		if it.IsolatedSymbol != "" {
+			item.MarginMode = banexg.MarginIsolated
+			item.Symbol = e.SafeSymbol(it.IsolatedSymbol, "", banexg.MarketMargin)
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
@@ -0,0 +1,212 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+// periods supported by futures data apis, the data of latest 30 days is available
+var dataPeriods = map[string]bool{
+	"5m": true, "15m": true, "30m": true, "1h": true, "2h": true, "4h": true, "6h": true, "12h": true, "1d": true,
+}
+
+/*
+FetchOpenInterest
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Open-Interest
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/market-data/Open-Interest
+*/
+func (e *Binance) FetchOpenInterest(symbol string, params map[string]interface{}) (*banexg.OpenInterest, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args["symbol"] = market.ID
+	var method string
+	if market.Linear {
+		method = MethodFapiPublicGetOpenInterest
+	} else if market.Inverse {
+		method = MethodDapiPublicGetOpenInterest
+	} else {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "market not support: %s", market.Type)
+	}
+	tryNum := e.GetRetryNum("FetchOpenInterest", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		OpenInterest string `json:"openInterest"`
+		Time         int64  `json:"time"`
+	}{}
+	info, err_ := utils.UnmarshalStringMap(rsp.Content, &res)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	amount, _ := strconv.ParseFloat(res.OpenInterest, 64)
+	return &banexg.OpenInterest{
+		Symbol:    market.Symbol,
+		Amount:    amount,
+		Timestamp: res.Time,
+		Info:      info,
+	}, nil
+}
+
+/*
+FetchOpenInterestHistory
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Open-Interest-Statistics
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/market-data/Open-Interest-Statistics
+*/
+func (e *Binance) FetchOpenInterestHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.OpenInterest, *errs.Error) {
+	args, market, err := e.loadFuturesDataArgs(symbol, timeframe, since, limit, params)
+	if err != nil {
+		return nil, err
+	}
+	method := MethodFapiDataGetOpenInterestHist
+	if market.Inverse {
+		method = MethodDapiDataGetOpenInterestHist
+	}
+	tryNum := e.GetRetryNum("FetchOpenInterestHistory", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var items = make([]*OpenInterestHist, 0)
+	infos, err_ := utils.UnmarshalStringMapArr(rsp.Content, &items)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	res := make([]*banexg.OpenInterest, 0, len(items))
+	for i, it := range items {
+		amount, _ := strconv.ParseFloat(it.SumOpenInterest, 64)
+		value, _ := strconv.ParseFloat(it.SumOpenInterestValue, 64)
+		res = append(res, &banexg.OpenInterest{
+			Symbol:    market.Symbol,
+			Amount:    amount,
+			Value:     value,
+			Timestamp: it.Timestamp,
+			Info:      infos[i],
+		})
+	}
+	return res, nil
+}
+
+/*
+FetchLongShortRatioHistory
+global long/short account ratio, or taker buy/sell volume when ParamRatioType is RatioTaker
+全市场多空账户数比，ParamRatioType为RatioTaker时返回主动买卖量
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Long-Short-Ratio
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Taker-BuySell-Volume
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/market-data/Long-Short-Ratio
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/market-data/Taker-Buy-Sell-Volume
+*/
+func (e *Binance) FetchLongShortRatioHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.LongShortRatio, *errs.Error) {
+	args := utils.SafeParams(params)
+	ratioType := utils.PopMapVal(args, banexg.ParamRatioType, banexg.RatioAccount)
+	args, market, err := e.loadFuturesDataArgs(symbol, timeframe, since, limit, args)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	switch ratioType {
+	case banexg.RatioAccount:
+		method = MethodFapiDataGetGlobalLongShortAccountRatio
+		if market.Inverse {
+			method = MethodDapiDataGetGlobalLongShortAccountRatio
+		}
+	case banexg.RatioTaker:
+		method = MethodFapiDataGetTakerlongshortRatio
+		if market.Inverse {
+			method = MethodDapiDataGetTakerBuySellVol
+		}
+	default:
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport %s: %s", banexg.ParamRatioType, ratioType)
+	}
+	if market.Inverse && ratioType == banexg.RatioAccount {
+		// globalLongShortAccountRatio of coin-m only accepts pair
+		delete(args, "contractType")
+	}
+	tryNum := e.GetRetryNum("FetchLongShortRatioHistory", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var items = make([]*LongShortRatio, 0)
+	infos, err_ := utils.UnmarshalStringMapArr(rsp.Content, &items)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	res := make([]*banexg.LongShortRatio, 0, len(items))
+	for i, it := range items {
+		res = append(res, it.ToStd(market.Symbol, timeframe, ratioType, infos[i]))
+	}
+	return res, nil
+}
+
+/*
+loadFuturesDataArgs
+set symbol (or pair & contractType for coin-m), period and time range for futures data apis
+为合约数据接口设置symbol(币本位为pair和contractType)、周期和时间范围
+*/
+func (e *Binance) loadFuturesDataArgs(symbol, timeframe string, since int64, limit int, params map[string]interface{}) (map[string]interface{}, *banexg.Market, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !dataPeriods[timeframe] {
+		return nil, nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport timeframe: %s", timeframe)
+	}
+	if market.Linear {
+		args["symbol"] = market.ID
+	} else if market.Inverse {
+		pair := utils.GetMapVal(market.Info, "pair", "")
+		if pair == "" {
+			pair, _, _ = strings.Cut(market.ID, "_")
+		}
+		args["pair"] = pair
+		args["contractType"] = utils.GetMapVal(market.Info, "contractType", "PERPETUAL")
+	} else {
+		return nil, nil, errs.NewMsg(errs.CodeNotSupport, "market not support: %s", market.Type)
+	}
+	args["period"] = timeframe
+	if limit > 0 {
+		args["limit"] = min(limit, 500)
+	}
+	if since > 0 {
+		args["startTime"] = since
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args["endTime"] = until
+	}
+	return args, market, nil
+}
+
+func (r *LongShortRatio) ToStd(symbol, timeframe, ratioType string, info map[string]interface{}) *banexg.LongShortRatio {
+	res := &banexg.LongShortRatio{
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		Type:      ratioType,
+		Timestamp: r.Timestamp,
+		Info:      info,
+	}
+	parse := func(text string) float64 {
+		val, _ := strconv.ParseFloat(text, 64)
+		return val
+	}
+	if ratioType == banexg.RatioTaker {
+		// buyVol/sellVol for usd-m, takerBuyVol/takerSellVol for coin-m
+		res.SetTakerVolume(parse(r.BuyVol)+parse(r.TakerBuyVol), parse(r.SellVol)+parse(r.TakerSellVol))
+		if ratio := parse(r.BuySellRatio); ratio > 0 {
+			res.Ratio = ratio
+		}
+	} else {
+		res.Ratio = parse(r.LongShortRatio)
+		res.LongRatio = parse(r.LongAccount)
+		res.ShortRatio = parse(r.ShortAccount)
+	}
+	return res
+}
@@ -0,0 +1,179 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+	"github.com/banbox/bntp"
+)
+
+/*
+FetchGreeks
+greeks and implied volatility of options, all options are returned when symbols is empty
+期权的希腊值和隐含波动率，symbols为空时返回所有期权
+:see: https://developers.binance.com/docs/derivatives/option/market-data/Option-Mark-Price
+*/
+func (e *Binance) FetchGreeks(symbols []string, params map[string]interface{}) ([]*banexg.Greeks, *errs.Error) {
+	args := utils.SafeParams(params)
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 1 {
+		market, err := e.GetMarket(symbols[0])
+		if err != nil {
+			return nil, err
+		}
+		args["symbol"] = market.ID
+	}
+	tryNum := e.GetRetryNum("FetchGreeks", 1)
+	rsp := e.RequestApiRetry(context.Background(), MethodEapiPublicGetMark, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var items = make([]*OptionMark, 0)
+	infos, err_ := utils.UnmarshalStringMapArr(rsp.Content, &items)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	var symbolSet map[string]bool
+	if len(symbols) > 1 {
+		symbolSet = make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			symbolSet[s] = true
+		}
+	}
+	res := make([]*banexg.Greeks, 0, len(items))
+	for i, it := range items {
+		symbol := e.SafeSymbol(it.Symbol, "", banexg.MarketOption)
+		if symbol == "" || symbolSet != nil && !symbolSet[symbol] {
+			continue
+		}
+		item := it.ToStd(infos[i])
+		item.Symbol = symbol
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (m *OptionMark) ToStd(info map[string]interface{}) *banexg.Greeks {
+	parse := func(text string) float64 {
+		val, _ := strconv.ParseFloat(text, 64)
+		return val
+	}
+	return &banexg.Greeks{
+		Symbol:    m.Symbol,
+		Delta:     parse(m.Delta),
+		Gamma:     parse(m.Gamma),
+		Vega:      parse(m.Vega),
+		Theta:     parse(m.Theta),
+		MarkIV:    parse(m.MarkIV),
+		BidIV:     parse(m.BidIV),
+		AskIV:     parse(m.AskIV),
+		MarkPrice: parse(m.MarkPrice),
+		Info:      info,
+	}
+}
+
+/*
+WatchGreeks
+subscribe mark price stream of underlying, only greeks of given symbols are pushed
+订阅标的的期权标记价格流，仅推送指定币种的希腊值
+:see: https://developers.binance.com/docs/derivatives/option/websocket-market-streams/Mark-Price
+*/
+func (e *Binance) WatchGreeks(symbols []string, params map[string]interface{}) (chan *banexg.Greeks, *errs.Error) {
+	chanKey, args, err := e.prepareWatchGreeks(true, symbols, params)
+	if err != nil {
+		return nil, err
+	}
+	create := func(cap int) chan *banexg.Greeks { return make(chan *banexg.Greeks, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, symbols...)
+	e.DumpWS("WatchGreeks", symbols)
+	return out, nil
+}
+
+func (e *Binance) UnWatchGreeks(symbols []string, params map[string]interface{}) *errs.Error {
+	_, _, err := e.prepareWatchGreeks(false, symbols, params)
+	return err
+}
+
+func (e *Binance) prepareWatchGreeks(isSub bool, symbols []string, params map[string]interface{}) (string, map[string]interface{}, *errs.Error) {
+	if len(symbols) == 0 {
+		return "", nil, errs.NewMsg(errs.CodeParamRequired, "symbols required for WatchGreeks")
+	}
+	args := utils.SafeParams(params)
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	msgHash := banexg.MarketOption + "@greeks"
+	client, err := e.GetWsClient(banexg.MarketOption, msgHash)
+	if err != nil {
+		return "", nil, err
+	}
+	chanKey := client.Prefix(msgHash)
+	// mark price stream is pushed by underlying, e.g. BTC@markPrice
+	streams := make([]string, 0, 1)
+	exists := make(map[string]bool)
+	for _, symbol := range symbols {
+		market, err := e.GetMarket(symbol)
+		if err != nil {
+			return "", nil, err
+		}
+		if !market.Option {
+			return "", nil, errs.NewMsg(errs.CodeUnsupportMarket, "WatchGreeks support option only, got: %s", symbol)
+		}
+		key := market.Base + "@markPrice"
+		if !exists[key] {
+			exists[key] = true
+			streams = append(streams, key)
+		}
+	}
+	if !isSub {
+		// keep the stream if any other symbol of the chan is still watched
+		if e.DelWsChanRefs(chanKey, symbols...) > 0 {
+			return chanKey, args, nil
+		}
+	}
+	err = e.WriteWSMsg(client, 0, isSub, streams, nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return chanKey, args, nil
+}
+
+func (e *Binance) handleGreeks(client *banexg.WsClient, msgList []map[string]string) {
+	chanKey := client.Prefix(banexg.MarketOption + "@greeks")
+	for _, msg := range msgList {
+		item := parseWsGreeks(msg)
+		market := e.GetMarketById(item.Symbol, banexg.MarketOption)
+		if market == nil {
+			continue
+		}
+		client.SetSubsKeyStamp(market.Base+"@markPrice", bntp.UTCStamp())
+		if !e.HasWsChanRef(chanKey, market.Symbol) {
+			continue
+		}
+		item.Symbol = market.Symbol
+		banexg.WriteOutChan(e.Exchange, chanKey, item, true)
+	}
+}
+
+func parseWsGreeks(msg map[string]string) *banexg.Greeks {
+	marketId, _ := utils.SafeMapVal(msg, "s", "")
+	res := &banexg.Greeks{Symbol: marketId}
+	res.Delta, _ = utils.SafeMapVal(msg, "d", float64(0))
+	res.Gamma, _ = utils.SafeMapVal(msg, "g", float64(0))
+	res.Vega, _ = utils.SafeMapVal(msg, "v", float64(0))
+	res.Theta, _ = utils.SafeMapVal(msg, "t", float64(0))
+	res.MarkIV, _ = utils.SafeMapVal(msg, "vo", float64(0))
+	res.BidIV, _ = utils.SafeMapVal(msg, "b", float64(0))
+	res.AskIV, _ = utils.SafeMapVal(msg, "a", float64(0))
+	res.MarkPrice, _ = utils.SafeMapVal(msg, "mp", float64(0))
+	res.Timestamp, _ = utils.SafeMapVal(msg, "E", int64(0))
+	return res
+}
@@ -186,6 +186,237 @@ func (e *Binance) doFetchOrders(args map[string]interface{}, method string, mark
 	}
 }
 
+/*
+FetchMyTrades 获取账户成交明细
+symbol: 必填，币种
+params: ParamAfter 返回此成交ID之后的记录；ParamBefore 返回此成交ID之前的记录；ParamUntil 截止时间
+
+:see: https://developers.binance.com/docs/binance-spot-api-docs/rest-api/account-endpoints#account-trade-list-user_data
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Account-Trade-List
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/trade/rest-api/Account-Trade-List
+:see: https://developers.binance.com/docs/margin_trading/trade/Query-Margin-Account-Trade-List
+*/
+func (e *Binance) FetchMyTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.MyTrade, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args["symbol"] = market.ID
+	marginMode := utils.PopMapVal(args, banexg.ParamMarginMode, "")
+	method := MethodPrivateGetMyTrades
+	if market.Option {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "FetchMyTrades not support option")
+	} else if market.Linear {
+		method = MethodFapiPrivateGetUserTrades
+	} else if market.Inverse {
+		method = MethodDapiPrivateGetUserTrades
+	} else if market.Type == banexg.MarketMargin || marginMode != "" {
+		method = MethodSapiGetMarginMyTrades
+		if marginMode == banexg.MarginIsolated {
+			args["isIsolated"] = true
+		}
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+	limit = min(limit, 1000)
+	args["limit"] = limit
+	// fromId can not be sent together with startTime/endTime
+	// fromId不能和startTime/endTime同时使用
+	after := utils.PopMapVal(args, banexg.ParamAfter, "")
+	before := utils.PopMapVal(args, banexg.ParamBefore, "")
+	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
+	if after != "" {
+		afterId, err_ := strconv.ParseInt(after, 10, 64)
+		if err_ != nil {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid %s: %s", banexg.ParamAfter, after)
+		}
+		args["fromId"] = afterId + 1
+	} else if before != "" {
+		beforeId, err_ := strconv.ParseInt(before, 10, 64)
+		if err_ != nil {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid %s: %s", banexg.ParamBefore, before)
+		}
+		delete(args, "limit")
+		return e.fetchMyTradesBefore(method, market, args, beforeId, limit)
+	} else {
+		if since > 0 {
+			args["startTime"] = since
+		}
+		if until > 0 {
+			args["endTime"] = until
+		}
+	}
+	return e.requestMyTrades(method, market, args)
+}
+
+func (e *Binance) requestMyTrades(method string, market *banexg.Market, args map[string]interface{}) ([]*banexg.MyTrade, *errs.Error) {
+	tryNum := e.GetRetryNum("FetchMyTrades", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	if method == MethodFapiPrivateGetUserTrades || method == MethodDapiPrivateGetUserTrades {
+		return parseMyTrades[*FutureMyTrade](market, rsp)
+	}
+	return parseMyTrades[*SpotMyTrade](market, rsp)
+}
+
+const (
+	myTradesWindow   = int64(24 * 60 * 60 * 1000) // max range of startTime/endTime for myTrades of all markets
+	maxMyTradesPages = 30                         // limits the requests of one FetchMyTrades with ParamBefore
+)
+
+/*
+fetchMyTradesBefore
+trade ids are exchange wide, so the trades of account before an id can't be located by fromId.
+take the time of the before trade, then page backwards by startTime/endTime windows, a full window is
+completed by fromId. Return CodeDataTruncated with the fetched trades when maxMyTradesPages is reached.
+成交ID是交易所全局的，无法通过fromId定位账户在某ID之前的成交。先获取before成交的时间，再按时间窗口向前翻页，
+窗口满时通过fromId补全。达到maxMyTradesPages时返回已获取的成交和CodeDataTruncated
+*/
+func (e *Binance) fetchMyTradesBefore(method string, market *banexg.Market, args map[string]interface{}, beforeId int64, limit int) ([]*banexg.MyTrade, *errs.Error) {
+	query := utils.SafeParams(args)
+	query["fromId"] = beforeId
+	query["limit"] = 1
+	first, err := e.requestMyTrades(method, market, query)
+	if err != nil {
+		return nil, err
+	}
+	end := e.MilliSeconds()
+	if len(first) > 0 {
+		end = first[0].Timestamp
+	}
+	tradeId := func(t *banexg.MyTrade) int64 {
+		tid, _ := strconv.ParseInt(t.ID, 10, 64)
+		return tid
+	}
+	var result []*banexg.MyTrade
+	pages := 1
+	for pages < maxMyTradesPages && len(result) < limit {
+		start := end - myTradesWindow + 1
+		query = utils.SafeParams(args)
+		query["startTime"] = start
+		query["endTime"] = end
+		query["limit"] = 1000
+		var window []*banexg.MyTrade
+		for {
+			items, err := e.requestMyTrades(method, market, query)
+			if err != nil {
+				return nil, err
+			}
+			pages += 1
+			window = append(window, items...)
+			if len(items) < 1000 {
+				break
+			}
+			last := items[len(items)-1]
+			if last.Timestamp > end || tradeId(last) >= beforeId {
+				break
+			}
+			if pages >= maxMyTradesPages {
+				return tailTrades(result, limit), errs.NewMsg(errs.CodeDataTruncated,
+					"too many trades in window %d-%d", start, end)
+			}
+			// more trades in this window, continue by id
+			query = utils.SafeParams(args)
+			query["fromId"] = tradeId(last) + 1
+			query["limit"] = 1000
+		}
+		filtered := make([]*banexg.MyTrade, 0, len(window))
+		for _, t := range window {
+			if t.Timestamp >= start && t.Timestamp <= end && tradeId(t) < beforeId {
+				filtered = append(filtered, t)
+			}
+		}
+		result = append(filtered, result...)
+		end = start - 1
+	}
+	return tailTrades(result, limit), nil
+}
+
+func tailTrades(items []*banexg.MyTrade, limit int) []*banexg.MyTrade {
+	if len(items) > limit {
+		return items[len(items)-limit:]
+	}
+	return items
+}
+
+func parseMyTrades[T IBnbMyTrade](m *banexg.Market, rsp *banexg.HttpRes) ([]*banexg.MyTrade, *errs.Error) {
+	var data = make([]T, 0)
+	rawList, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	var result = make([]*banexg.MyTrade, len(data))
+	for i, item := range data {
+		result[i] = item.ToStdTrade(m, rawList[i])
+	}
+	return result, nil
+}
+
+func newBnbMyTrade(m *banexg.Market, id, orderId int64, side, price, qty, fee, feeCurr string, stamp int64,
+	maker bool, info map[string]interface{}) *banexg.MyTrade {
+	priceVal, _ := strconv.ParseFloat(price, 64)
+	amount, _ := strconv.ParseFloat(qty, 64)
+	feeCost, _ := strconv.ParseFloat(fee, 64)
+	res := &banexg.MyTrade{
+		Trade: banexg.Trade{
+			ID:        strconv.FormatInt(id, 10),
+			Symbol:    m.Symbol,
+			Side:      strings.ToLower(side),
+			Amount:    amount,
+			Price:     priceVal,
+			Cost:      priceVal * amount,
+			Order:     strconv.FormatInt(orderId, 10),
+			Timestamp: stamp,
+			Maker:     maker,
+			Fee: &banexg.Fee{
+				IsMaker:  maker,
+				Currency: feeCurr,
+				Cost:     feeCost,
+			},
+			Info: info,
+		},
+		Filled:  amount,
+		Average: priceVal,
+		State:   banexg.OdStatusFilled,
+		Info:    info,
+	}
+	// fee paid in other assets like BNB can't be converted here, leave QuoteCost as 0
+	if feeCurr != "" {
+		if feeCurr == m.Quote {
+			res.Fee.QuoteCost = feeCost
+		} else if feeCurr == m.Base {
+			res.Fee.QuoteCost = feeCost * priceVal
+		}
+	}
+	return res
+}
+
+func (t *SpotMyTrade) ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.MyTrade {
+	side := banexg.OdSideSell
+	if t.IsBuyer {
+		side = banexg.OdSideBuy
+	}
+	res := newBnbMyTrade(m, t.ID, t.OrderId, side, t.Price, t.Qty, t.Commission, t.CommissionAsset, t.Time,
+		t.IsMaker, info)
+	if quoteQty, err := strconv.ParseFloat(t.QuoteQty, 64); err == nil && quoteQty > 0 {
+		res.Cost = quoteQty
+	}
+	return res
+}
+
+func (t *FutureMyTrade) ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.MyTrade {
+	res := newBnbMyTrade(m, t.ID, t.OrderId, t.Side, t.Price, t.Qty, t.Commission, t.CommissionAsset, t.Time,
+		t.Maker, info)
+	res.PosSide = strings.ToLower(t.PositionSide)
+	if quoteQty, err := strconv.ParseFloat(t.QuoteQty, 64); err == nil && quoteQty > 0 {
+		res.Cost = quoteQty
+	}
+	return res
+}
+
 /*
 FetchOpenOrders
 
@@ -306,7 +537,7 @@ func (e *Binance) EditOrder(symbol, orderId, side string, amount, price float64,
 		return nil, errs.NewMsg(errs.CodeParamInvalid, "EditOrder not available in spot/margin market")
 	}
 	tryNum := e.GetRetryNum("EditOrder", 1)
-	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	rsp := e.requestTrade(method, args, tryNum)
 	if rsp.Error != nil {
 		return nil, rsp.Error
 	}
@@ -359,7 +590,7 @@ func (e *Binance) CancelOrder(id string, symbol string, params map[string]interf
 	} else if market.Linear {
 		isAlgoOrder := utils.PopMapVal(args, banexg.ParamAlgoOrder, false)
 		if isAlgoOrder || strings.HasPrefix(id, "algo:") {
-			return e.cancelAlgoOrder(id, clientOrderId, market)
+			return e.cancelAlgoOrder(id, clientOrderId, market, args)
 		}
 		method = MethodFapiPrivateDeleteOrder
 	} else if market.Inverse {
@@ -371,7 +602,7 @@ func (e *Binance) CancelOrder(id string, symbol string, params map[string]interf
 		}
 	}
 	tryNum := e.GetRetryNum("CancelOrder", 1)
-	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	rsp := e.requestTrade(method, args, tryNum)
 	if rsp.Error != nil {
 		return nil, rsp.Error
 	}
@@ -426,6 +657,20 @@ var orderStateMap = map[string]string{
 	OdStatusExpiredInMatch:  banexg.OdStatusExpired,
 }
 
+// algoStateMap states of algo orders besides orderStateMap, the algo order is done once triggered
+var algoStateMap = map[string]string{
+	"TRIGGERING": banexg.OdStatusOpen,
+	"TRIGGERED":  banexg.OdStatusFilled,
+	"FINISHED":   banexg.OdStatusFilled,
+}
+
+func mapAlgoOrderStatus(status string) string {
+	if val, ok := algoStateMap[status]; ok {
+		return val
+	}
+	return mapOrderStatus(status)
+}
+
 func mapOrderStatus(status string) string {
 	if val, ok := orderStateMap[status]; ok {
 		return val
@@ -607,7 +852,7 @@ func (o *AlgoOrder) ToStdOrder(mapSymbol func(string) string, info map[string]in
 		Side:                strings.ToLower(o.Side),
 		Price:               price,
 		Amount:              amount,
-		Status:              mapOrderStatus(o.AlgoStatus),
+		Status:              mapAlgoOrderStatus(o.AlgoStatus),
 		Symbol:              mapSymbol(o.Symbol),
 		ReduceOnly:          o.ReduceOnly,
 		TriggerPrice:        stopPrice,
@@ -114,8 +114,12 @@ func (e *Binance) fetchAlgoOpenOrders(args map[string]interface{}, market *banex
 	return parseOrders[*AlgoOrder](mapSymbol, rsp)
 }
 
-func (e *Binance) cancelAlgoOrder(id string, clientOrderId string, market *banexg.Market) (*banexg.Order, *errs.Error) {
-	args := make(map[string]interface{})
+func (e *Binance) cancelAlgoOrder(id string, clientOrderId string, market *banexg.Market, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	// keys of normal orders set by CancelOrder
+	delete(args, "orderId")
+	delete(args, "origClientOrderId")
+	delete(args, "clientOrderId")
 	args["symbol"] = market.ID
 	if clientOrderId != "" {
 		args["clientAlgoId"] = clientOrderId
@@ -0,0 +1,320 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+const (
+	batchCreateMax = 5  // max orders of batchOrders POST for usd-m/coin-m futures
+	batchCancelMax = 10 // max orders of batchOrders DELETE for usd-m/coin-m futures
+)
+
+var batchOrderMethods = map[string]string{
+	MethodFapiPrivatePostOrder: MethodFapiPrivatePostBatchOrders,
+	MethodDapiPrivatePostOrder: MethodDapiPrivatePostBatchOrders,
+}
+
+/*
+CreateOrders
+batchOrders is used for usd-m/coin-m futures (5 orders per request), others are created one by one.
+U本位/币本位合约使用batchOrders批量下单(每次最多5个)，其他订单逐个创建
+
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Place-Multiple-Orders
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/trade/Place-Multiple-Orders
+*/
+func (e *Binance) CreateOrders(orders []*banexg.OrderArgs, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	result := make([]*banexg.OrderRes, len(orders))
+	batches := make(map[string][]int)
+	built := make([]*bnbOrderArgs, len(orders))
+	for i, od := range orders {
+		result[i] = &banexg.OrderRes{}
+		args := banexg.MergeOrderParams(params, od.Params)
+		item, err := e.newOrderArgs(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+		if err != nil {
+			result[i].Err = err
+			continue
+		}
+		batchMethod, ok := batchOrderMethods[item.method]
+		if !ok || item.isAlgo() {
+			result[i].Order, result[i].Err = e.CreateOrder(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+			continue
+		}
+		built[i] = item
+		batches[batchMethod] = append(batches[batchMethod], i)
+	}
+	tryNum := e.GetRetryNum("CreateOrders", 1)
+	for method, idxList := range batches {
+		for start := 0; start < len(idxList); start += batchCreateMax {
+			chunk := idxList[start:min(start+batchCreateMax, len(idxList))]
+			items := make([]map[string]interface{}, 0, len(chunk))
+			for _, i := range chunk {
+				items = append(items, banexg.CleanBatchOrder(built[i].args))
+			}
+			itemsText, err_ := utils.MarshalString(items)
+			if err_ != nil {
+				return result, errs.New(errs.CodeMarshalFail, err_)
+			}
+			args := banexg.BatchOuterArgs(params)
+			args["batchOrders"] = itemsText
+			rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+			var list []*banexg.OrderRes
+			err := rsp.Error
+			if err == nil {
+				marketType := built[chunk[0]].market.Type
+				if method == MethodDapiPrivatePostBatchOrders {
+					list, err = parseBatchOrders[*InverseOrder](e.batchMapSymbol(marketType), rsp)
+				} else {
+					list, err = parseBatchOrders[*FutureOrder](e.batchMapSymbol(marketType), rsp)
+				}
+			}
+			for j, i := range chunk {
+				if err != nil {
+					result[i].Err = err
+				} else if j < len(list) {
+					result[i] = list[j]
+				} else {
+					result[i].Err = errs.NewMsg(errs.CodeInvalidResponse, "batch order result missing")
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+/*
+CancelOrders
+batchOrders is used for usd-m/coin-m futures (10 orders per request), others are canceled one by one.
+U本位/币本位合约使用batchOrders批量撤单(每次最多10个)，其他订单逐个取消
+
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Cancel-Multiple-Orders
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/trade/Cancel-Multiple-Orders
+*/
+func (e *Binance) CancelOrders(ids []string, symbol string, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	if !market.Linear && !market.Inverse || utils.GetMapVal(args, banexg.ParamAlgoOrder, false) {
+		return e.Exchange.CancelOrders(ids, symbol, params)
+	}
+	result := make([]*banexg.OrderRes, len(ids))
+	var normalIdx []int
+	for i, id := range ids {
+		if strings.HasPrefix(id, "algo:") {
+			order, err := e.CancelOrder(id, symbol, params)
+			result[i] = &banexg.OrderRes{ID: id, Order: order, Err: err}
+			continue
+		}
+		normalIdx = append(normalIdx, i)
+	}
+	method := MethodFapiPrivateDeleteBatchOrders
+	if market.Inverse {
+		method = MethodDapiPrivateDeleteBatchOrders
+	}
+	args["symbol"] = market.ID
+	mapSymbol := func(mid string) string {
+		return market.Symbol
+	}
+	tryNum := e.GetRetryNum("CancelOrders", 1)
+	for start := 0; start < len(normalIdx); start += batchCancelMax {
+		chunk := normalIdx[start:min(start+batchCancelMax, len(normalIdx))]
+		chunkIds := make([]string, 0, len(chunk))
+		for _, i := range chunk {
+			chunkIds = append(chunkIds, ids[i])
+		}
+		args[banexg.ParamOrderIds] = chunkIds
+		rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+		var list []*banexg.OrderRes
+		err = rsp.Error
+		if err == nil {
+			if market.Inverse {
+				list, err = parseBatchOrders[*InverseOrder](mapSymbol, rsp)
+			} else {
+				list, err = parseBatchOrders[*FutureOrder](mapSymbol, rsp)
+			}
+		}
+		for j, i := range chunk {
+			res := &banexg.OrderRes{Err: err}
+			if err == nil {
+				if j < len(list) {
+					res = list[j]
+				} else {
+					res.Err = errs.NewMsg(errs.CodeInvalidResponse, "batch order result missing")
+				}
+			}
+			res.ID = ids[i]
+			result[i] = res
+		}
+	}
+	return result, nil
+}
+
+/*
+CancelAllOrders
+Cancel all open orders of symbol. Emulated with FetchOpenOrders+CancelOrders when symbol is empty.
+For futures/options the exchange returns no order details, so open orders are fetched before canceling.
+取消symbol的所有挂单，symbol为空时通过FetchOpenOrders+CancelOrders模拟。
+合约和期权不返回订单详情，故撤单前先获取挂单
+
+:see: https://developers.binance.com/docs/binance-spot-api-docs/rest-api/trading-endpoints#cancel-all-open-orders-on-a-symbol-trade
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Cancel-All-Open-Orders
+:see: https://developers.binance.com/docs/margin_trading/trade/Margin-Account-Cancel-All-Open-Orders
+*/
+func (e *Binance) CancelAllOrders(symbol string, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	if symbol == "" {
+		return e.Exchange.CancelAllOrders(symbol, params)
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	if market.Linear && utils.GetMapVal(args, banexg.ParamAlgoOrder, false) {
+		return e.Exchange.CancelAllOrders(symbol, params)
+	}
+	marginMode := utils.PopMapVal(args, banexg.ParamMarginMode, "")
+	args["symbol"] = market.ID
+	var method string
+	var opens []*banexg.Order
+	if market.Contract || market.Option {
+		method = MethodFapiPrivateDeleteAllOpenOrders
+		if market.Option {
+			method = MethodEapiPrivateDeleteAllOpenOrders
+		} else if market.Inverse {
+			method = MethodDapiPrivateDeleteAllOpenOrders
+		}
+		opens, err = e.FetchOpenOrders(symbol, 0, 0, params)
+		if err != nil {
+			return nil, err
+		}
+		if len(opens) == 0 {
+			return nil, nil
+		}
+	} else if market.Type == banexg.MarketMargin || marginMode != "" {
+		method = MethodSapiDeleteMarginOpenOrders
+		if marginMode == banexg.MarginIsolated {
+			args["isIsolated"] = true
+		}
+	} else {
+		method = MethodPrivateDeleteOpenOrders
+	}
+	tryNum := e.GetRetryNum("CancelAllOrders", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	if opens == nil {
+		var mapSymbol = func(mid string) string {
+			return market.Symbol
+		}
+		var orders []*banexg.Order
+		if method == MethodSapiDeleteMarginOpenOrders {
+			orders, err = parseOrders[*MarginOrder](mapSymbol, rsp)
+		} else {
+			orders, err = parseOrders[*SpotOrder](mapSymbol, rsp)
+		}
+		if err != nil {
+			return nil, err
+		}
+		opens = orders
+	} else {
+		for _, od := range opens {
+			od.Status = banexg.OdStatusCanceled
+		}
+	}
+	result := make([]*banexg.OrderRes, 0, len(opens))
+	for _, od := range opens {
+		result = append(result, &banexg.OrderRes{ID: od.ID, Order: od})
+	}
+	return result, nil
+}
+
+/*
+SetCancelAllAfter
+countdownCancelAll of symbol for linear and inverse contracts, timeoutMs 0 to disarm.
+call it again before the countdown ends to refresh the timer
+合约的countdownCancelAll，timeoutMs为0时取消。倒计时结束前再次调用以刷新计时器
+
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Auto-Cancel-All-Open-Orders
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/trade/Auto-Cancel-All-Open-Orders
+*/
+func (e *Binance) SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if symbol == "" {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbol is required for %v.SetCancelAllAfter", e.Name)
+	}
+	if timeoutMs < 0 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid timeoutMs: %v", timeoutMs)
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	if market.Linear {
+		method = MethodFapiPrivatePostCountdownCancelAll
+	} else if market.Inverse {
+		method = MethodDapiPrivatePostCountdownCancelAll
+	} else {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "%v SetCancelAllAfter supports linear and inverse contracts only", e.Name)
+	}
+	args["symbol"] = market.ID
+	args["countdownTime"] = timeoutMs
+	tryNum := e.GetRetryNum("SetCancelAllAfter", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	return decodeRspMap(e, rsp)
+}
+
+// batchMapSymbol map market id to symbol, the raw id is used for unknown markets
+func (e *Binance) batchMapSymbol(marketType string) func(string) string {
+	var symbolMap = make(map[string]string)
+	return func(mid string) string {
+		if symbol, ok := symbolMap[mid]; ok {
+			return symbol
+		}
+		symbol := mid
+		if market := e.GetMarketById(mid, marketType); market != nil {
+			symbol = market.Symbol
+		}
+		symbolMap[mid] = symbol
+		return symbol
+	}
+}
+
+/*
+parseBatchOrders
+parse the result of batchOrders, each item is either an order or an error like {"code":-2022,"msg":"..."}
+解析batchOrders结果，每项为订单或错误
+*/
+func parseBatchOrders[T IBnbOrder](mapSymbol func(string) string, rsp *banexg.HttpRes) ([]*banexg.OrderRes, *errs.Error) {
+	var data = make([]T, 0)
+	rawList, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	var result = make([]*banexg.OrderRes, len(data))
+	for i, item := range data {
+		raw := rawList[i]
+		if msg, ok := raw["msg"]; ok {
+			code, _ := raw["code"].(int64)
+			errCode := mapBizErr(int(code), fmt.Sprintf("%v", msg))
+			if errCode == 0 {
+				errCode = errs.CodeRunTime
+			}
+			bizErr := errs.NewMsg(errCode, "%v", msg)
+			bizErr.BizCode = int(code)
+			result[i] = &banexg.OrderRes{Err: bizErr}
+			continue
+		}
+		od := item.ToStdOrder(mapSymbol, raw)
+		result[i] = &banexg.OrderRes{ID: od.ID, Order: od}
+	}
+	return result, nil
+}
@@ -1,7 +1,6 @@
 package binance
 
 import (
-	"context"
 	"strings"
 
 	"github.com/banbox/banexg"
@@ -38,6 +37,62 @@ CreateOrder 提交订单到交易所
 	:returns dict: an `order structure <https://docs.ccxt.com/#/?id=order-structure>`
 */
 func (e *Binance) CreateOrder(symbol, odType, side string, amount float64, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	od, err := e.newOrderArgs(symbol, odType, side, amount, price, params)
+	if err != nil {
+		return nil, err
+	}
+	tryNum := utils.PopMapVal(params, banexg.ParamRetry, -1)
+	if tryNum < 0 {
+		tryNum = e.GetRetryNum("CreateOrder", 3)
+	}
+	market, args, method := od.market, od.args, od.method
+	if od.isAlgo() {
+		return e.createAlgoOrder(market, args, tryNum)
+	}
+
+	rsp := e.requestTrade(method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var mapSymbol = func(mid string) string {
+		return market.Symbol
+	}
+	if method == MethodFapiPrivatePostOrder {
+		return parseOrder[*FutureOrder](mapSymbol, rsp)
+	} else if method == MethodDapiPrivatePostOrder {
+		return parseOrder[*InverseOrder](mapSymbol, rsp)
+	} else if method == MethodEapiPrivatePostOrder {
+		return parseOrder[*OptionOrder](mapSymbol, rsp)
+	} else {
+		// spot margin sor
+		return parseOrder[*SpotOrder](mapSymbol, rsp)
+	}
+}
+
+// bnbOrderArgs request args of a new order, built by newOrderArgs
+type bnbOrderArgs struct {
+	market *banexg.Market
+	args   map[string]interface{}
+	method string
+	odType string
+}
+
+// isAlgo whether it should be placed by the algo order api of usd-m futures
+func (o *bnbOrderArgs) isAlgo() bool {
+	if !o.market.Linear {
+		return false
+	}
+	return o.odType == banexg.OdTypeStop || o.odType == banexg.OdTypeStopMarket ||
+		o.odType == banexg.OdTypeTakeProfit || o.odType == banexg.OdTypeTakeProfitMarket ||
+		o.odType == banexg.OdTypeTrailingStopMarket
+}
+
+/*
+newOrderArgs
+validate the order and build the request args for CreateOrder/CreateOrders
+校验订单并构建CreateOrder/CreateOrders的请求参数
+*/
+func (e *Binance) newOrderArgs(symbol, odType, side string, amount float64, price float64, params map[string]interface{}) (*bnbOrderArgs, *errs.Error) {
 	args, market, err := e.LoadArgsMarket(symbol, params)
 	if err != nil {
 		return nil, err
@@ -273,34 +328,5 @@ func (e *Binance) CreateOrder(symbol, odType, side string, amount float64, price
 			method += "Test"
 		}
 	}
-	tryNum := utils.PopMapVal(params, banexg.ParamRetry, -1)
-	if tryNum < 0 {
-		tryNum = e.GetRetryNum("CreateOrder", 3)
-	}
-
-	if market.Linear {
-		if odType == banexg.OdTypeStop || odType == banexg.OdTypeStopMarket ||
-			odType == banexg.OdTypeTakeProfit || odType == banexg.OdTypeTakeProfitMarket ||
-			odType == banexg.OdTypeTrailingStopMarket {
-			return e.createAlgoOrder(market, args, tryNum)
-		}
-	}
-
-	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
-	if rsp.Error != nil {
-		return nil, rsp.Error
-	}
-	var mapSymbol = func(mid string) string {
-		return market.Symbol
-	}
-	if method == MethodFapiPrivatePostOrder {
-		return parseOrder[*FutureOrder](mapSymbol, rsp)
-	} else if method == MethodDapiPrivatePostOrder {
-		return parseOrder[*InverseOrder](mapSymbol, rsp)
-	} else if method == MethodEapiPrivatePostOrder {
-		return parseOrder[*OptionOrder](mapSymbol, rsp)
-	} else {
-		// spot margin sor
-		return parseOrder[*SpotOrder](mapSymbol, rsp)
-	}
+	return &bnbOrderArgs{market: market, args: args, method: method, odType: odType}, nil
 }
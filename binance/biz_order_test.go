@@ -145,3 +145,50 @@ func TestCalcelOrder(t *testing.T) {
 	resStr, _ := utils.MarshalString(res)
 	log.Info("cancel order", zap.String("res", resStr))
 }
+
+func TestFetchMyTrades(t *testing.T) {
+	exg := getBinance(nil)
+	cases := []map[string]interface{}{
+		//{"market": banexg.MarketSpot},
+		{"market": banexg.MarketLinear},
+		//{"market": banexg.MarketLinear, banexg.ParamAfter: "123456"},
+	}
+	symbol := "XRP/USDT:USDT"
+	since := time.Now().UnixMilli() - 86400000*3
+	for _, item := range cases {
+		text, _ := utils.MarshalString(item)
+		res, err := exg.FetchMyTrades(symbol, since, 0, item)
+		if err != nil {
+			panic(fmt.Errorf("%s Error: %v", text, err))
+		}
+		resText, _ := utils.MarshalString(res)
+		t.Logf("%s result: %s", text, resText)
+	}
+}
+
+func TestParseMyTradeFee(t *testing.T) {
+	mar := &banexg.Market{Symbol: "ETH/USDT", Base: "ETH", Quote: "USDT"}
+	items := []struct {
+		asset     string
+		quoteCost float64
+	}{
+		{"USDT", 0.2},
+		{"ETH", 400},
+		{"BNB", 0},
+	}
+	for _, it := range items {
+		text := `{"symbol":"ETHUSDT","id":28457,"orderId":100234,"price":"2000","qty":"0.5","quoteQty":"1000",` +
+			`"commission":"0.2","commissionAsset":"` + it.asset + `","time":1499865549590,"isBuyer":true,"isMaker":false}`
+		var trade SpotMyTrade
+		if err := utils.UnmarshalString(text, &trade, utils.JsonNumDefault); err != nil {
+			t.Fatalf("unmarshal trade fail: %v", err)
+		}
+		res := trade.ToStdTrade(mar, nil)
+		if res.Fee.Currency != it.asset || res.Fee.Cost != 0.2 {
+			t.Errorf("fee in %s unexpected: %+v", it.asset, res.Fee)
+		}
+		if res.Fee.QuoteCost != it.quoteCost {
+			t.Errorf("fee in %s QuoteCost: %v, expect: %v", it.asset, res.Fee.QuoteCost, it.quoteCost)
+		}
+	}
+}
@@ -309,6 +309,20 @@ func TestFetchOHLCV(t *testing.T) {
 	}
 }
 
+func TestFetchTrades(t *testing.T) {
+	exg := getBinance(nil)
+	startMs := int64(1719014400000)
+	res, err := exg.FetchTrades("BTC/USDT:USDT", startMs, 1500, map[string]interface{}{
+		banexg.ParamUntil: startMs + 60000,
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, tr := range res {
+		fmt.Printf("%v %v %v %v %v\n", tr.ID, tr.Timestamp, tr.Side, tr.Price, tr.Amount)
+	}
+}
+
 func TestFetchBalances(t *testing.T) {
 	exg := getBinance(nil)
 	cases := []map[string]interface{}{
@@ -552,3 +566,24 @@ func TestFetchAccountPositions(t *testing.T) {
 	text, _ := utils.MarshalString(posList)
 	fmt.Println(text)
 }
+
+func TestParseBatchOrders(t *testing.T) {
+	exg := &Binance{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	rsp := &banexg.HttpRes{Content: `[{"orderId":11,"symbol":"XYZUSDT","status":"NEW","side":"BUY","type":"LIMIT",` +
+		`"price":"1.5","origQty":"10"},{"code":-2022,"msg":"ReduceOnly Order is rejected."}]`}
+	items, err := parseBatchOrders[*FutureOrder](exg.batchMapSymbol(banexg.MarketLinear), rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Order == nil || items[0].Order.Symbol != "XYZUSDT" {
+		t.Fatalf("unknown market should use raw id: %+v", items)
+	}
+	if items[1].Err == nil || items[1].Err.BizCode != -2022 {
+		t.Fatalf("unexpected batch error: %+v", items[1])
+	}
+	item := banexg.CleanBatchOrder(map[string]interface{}{"symbol": "XYZUSDT", banexg.ParamAccount: "a1"})
+	outer := banexg.BatchOuterArgs(map[string]interface{}{"symbol": "XYZUSDT", banexg.ParamAccount: "a1"})
+	if _, ok := item[banexg.ParamAccount]; ok || len(outer) != 1 || outer[banexg.ParamAccount] != "a1" {
+		t.Fatalf("unexpected batch args: %v %v", item, outer)
+	}
+}
@@ -0,0 +1,243 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+const (
+	aggTradesMaxMS = int64(3600000) // aggTrades: startTime~endTime must be less than 1 hour
+	tradesPageMax  = 1000
+	maxTradesPages = 30 // limits the requests to locate trades before ParamUntil
+)
+
+/*
+FetchTrades 获取公开成交记录
+aggTrades is used by default, the same as WatchTrades; pass ParamRawTrades to use trades/historicalTrades.
+since/ParamUntil for time range, ParamAfter/ParamBefore as trade id cursor.
+trades are paged forward from since; with only ParamUntil, the newest trades before it are returned.
+默认使用aggTrades，与WatchTrades一致；传入ParamRawTrades时使用trades/historicalTrades。
+从since向后翻页；仅指定ParamUntil时返回其之前最新的成交
+
+:see: https://developers.binance.com/docs/binance-spot-api-docs/rest-api/market-data-endpoints#compressedaggregate-trades-list
+:see: https://developers.binance.com/docs/binance-spot-api-docs/rest-api/market-data-endpoints#old-trade-lookup
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Compressed-Aggregate-Trades-List
+*/
+func (e *Binance) FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Trade, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args["symbol"] = market.ID
+	rawTrades := utils.PopMapVal(args, ParamRawTrades, false)
+	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
+	after, err := popTradeCursor(args, banexg.ParamAfter)
+	if err != nil {
+		return nil, err
+	}
+	before, err := popTradeCursor(args, banexg.ParamBefore)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+	var aggMethod, rawMethod, hisMethod string
+	if market.Option {
+		rawTrades = true
+		rawMethod, hisMethod = MethodEapiPublicGetTrades, MethodEapiPublicGetHistoricalTrades
+	} else if market.Linear {
+		aggMethod = MethodFapiPublicGetAggTrades
+		rawMethod, hisMethod = MethodFapiPublicGetTrades, MethodFapiPublicGetHistoricalTrades
+	} else if market.Inverse {
+		aggMethod = MethodDapiPublicGetAggTrades
+		rawMethod, hisMethod = MethodDapiPublicGetTrades, MethodDapiPublicGetHistoricalTrades
+	} else {
+		aggMethod = MethodPublicGetAggTrades
+		rawMethod, hisMethod = MethodPublicGetTrades, MethodPublicGetHistoricalTrades
+	}
+	method := aggMethod
+	if rawTrades {
+		if after == 0 && before == 0 {
+			if since > 0 || until > 0 {
+				return nil, errs.NewMsg(errs.CodeParamInvalid, "raw trades only support id cursor, not since/until")
+			}
+			// recent trades
+			args["limit"] = min(limit, tradesPageMax)
+			list, err := e.fetchTradesPage(rawMethod, market, args)
+			if err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+		method = hisMethod
+	}
+	if after == 0 && before == 0 && since <= 0 && until > 0 && !rawTrades {
+		// newest trades before until: agg trade ids are consecutive, locate the last one and go back by id
+		lastId, err := e.lastAggIdBefore(method, market, args, until)
+		if err != nil || lastId == 0 {
+			return []*banexg.Trade{}, err
+		}
+		before = lastId + 1
+	}
+	if after > 0 {
+		args["fromId"] = after + 1
+	} else if before > 0 {
+		args["fromId"] = max(0, before-int64(limit))
+	} else if since > 0 {
+		args["startTime"] = since
+		if until > 0 && until-since < aggTradesMaxMS {
+			args["endTime"] = until
+		}
+	}
+	result := make([]*banexg.Trade, 0, limit)
+	var lastId int64
+	for {
+		args["limit"] = min(tradesPageMax, limit-len(result))
+		list, err := e.fetchTradesPage(method, market, args)
+		if err != nil {
+			return result, err
+		}
+		done := len(list) < args["limit"].(int)
+		for _, t := range list {
+			tid, _ := strconv.ParseInt(t.ID, 10, 64)
+			if tid <= lastId {
+				continue
+			}
+			lastId = tid
+			if before > 0 && tid >= before || until > 0 && t.Timestamp > until {
+				done = true
+				break
+			}
+			result = append(result, t)
+		}
+		if done || len(result) >= limit || lastId == 0 {
+			break
+		}
+		// continue from the last trade, endTime is also checked on each trade above
+		delete(args, "startTime")
+		delete(args, "endTime")
+		args["fromId"] = lastId + 1
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+/*
+lastAggIdBefore
+id of the last agg trade at or before until, 0 if there is no trade in the hour before until.
+binary search the startTime of a window ending at until which holds less than a full page.
+返回until及之前最后一笔聚合成交的ID，until前一小时内无成交时返回0。二分查找以until结尾且不满一页的窗口起始时间
+*/
+func (e *Binance) lastAggIdBefore(method string, market *banexg.Market, args map[string]interface{}, until int64) (int64, *errs.Error) {
+	first := until - aggTradesMaxMS + 1
+	lo, hi := first, until
+	start := lo
+	for i := 0; i < maxTradesPages; i++ {
+		query := utils.SafeParams(args)
+		query["startTime"] = start
+		query["endTime"] = until
+		query["limit"] = tradesPageMax
+		list, err := e.fetchTradesPage(method, market, query)
+		if err != nil {
+			return 0, err
+		}
+		if len(list) > 0 && len(list) < tradesPageMax {
+			lastId, err_ := strconv.ParseInt(list[len(list)-1].ID, 10, 64)
+			if err_ != nil {
+				return 0, errs.New(errs.CodeInvalidData, err_)
+			}
+			return lastId, nil
+		}
+		if len(list) == 0 {
+			if start == first {
+				return 0, nil
+			}
+			hi = start - 1
+		} else {
+			lo = start + 1
+		}
+		if lo > hi {
+			break
+		}
+		start = lo + (hi-lo)/2
+	}
+	return 0, errs.NewMsg(errs.CodeDataTruncated, "too many trades around %d", until)
+}
+
+func popTradeCursor(args map[string]interface{}, key string) (int64, *errs.Error) {
+	text := utils.PopMapVal(args, key, "")
+	if text == "" {
+		return 0, nil
+	}
+	val, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, errs.NewMsg(errs.CodeParamInvalid, "invalid %s: %s", key, text)
+	}
+	return val, nil
+}
+
+func (e *Binance) fetchTradesPage(method string, market *banexg.Market, args map[string]interface{}) ([]*banexg.Trade, *errs.Error) {
+	tryNum := e.GetRetryNum("FetchTrades", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	switch method {
+	case MethodPublicGetAggTrades, MethodFapiPublicGetAggTrades, MethodDapiPublicGetAggTrades:
+		return parsePubTrades[*AggTrade](market, rsp)
+	default:
+		return parsePubTrades[*RawTrade](market, rsp)
+	}
+}
+
+func parsePubTrades[T IBnbTrade](m *banexg.Market, rsp *banexg.HttpRes) ([]*banexg.Trade, *errs.Error) {
+	var data = make([]T, 0)
+	rawList, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	var result = make([]*banexg.Trade, len(data))
+	for i, item := range data {
+		result[i] = item.ToStdTrade(m, rawList[i])
+	}
+	return result, nil
+}
+
+func newBnbPubTrade(m *banexg.Market, id int64, price, qty string, stamp int64, isBuyerMaker bool,
+	info map[string]interface{}) *banexg.Trade {
+	priceVal, _ := strconv.ParseFloat(price, 64)
+	amount, _ := strconv.ParseFloat(qty, 64)
+	side := banexg.OdSideBuy
+	if isBuyerMaker {
+		side = banexg.OdSideSell
+	}
+	return &banexg.Trade{
+		ID:        strconv.FormatInt(id, 10),
+		Symbol:    m.Symbol,
+		Side:      side,
+		Amount:    amount,
+		Price:     priceVal,
+		Cost:      priceVal * amount,
+		Timestamp: stamp,
+		Info:      info,
+	}
+}
+
+func (t *AggTrade) ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.Trade {
+	return newBnbPubTrade(m, t.ID, t.Price, t.Qty, t.Time, t.IsBuyerMaker, info)
+}
+
+func (t *RawTrade) ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.Trade {
+	isBuyerMaker := t.IsBuyerMaker
+	if t.Side != 0 {
+		isBuyerMaker = t.Side < 0
+	}
+	return newBnbPubTrade(m, t.ID, t.Price, t.Qty, t.Time, isBuyerMaker, info)
+}
@@ -0,0 +1,252 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+var (
+	// account names of universal transfer type, e.g. MAIN_UMFUTURE
+	transferAccMap = map[string]string{
+		banexg.MarketSpot:     "MAIN",
+		banexg.MarketMargin:   "MARGIN",
+		banexg.MarketLinear:   "UMFUTURE",
+		banexg.MarketInverse:  "CMFUTURE",
+		banexg.MarketOption:   "OPTION",
+		banexg.AccountFunding: "FUNDING",
+	}
+	// account types of sub account universal transfer
+	subTransferAccMap = map[string]string{
+		banexg.MarketSpot:    "SPOT",
+		banexg.MarketMargin:  "MARGIN",
+		banexg.MarketLinear:  "USDT_FUTURE",
+		banexg.MarketInverse: "COIN_FUTURE",
+	}
+	transferStatusMap = map[string]string{
+		"CONFIRMED": banexg.TransferOk,
+		"SUCCESS":   banexg.TransferOk,
+		"PENDING":   banexg.TransferPending,
+		"PROCESS":   banexg.TransferPending,
+		"FAILED":    banexg.TransferFailed,
+		"FAILURE":   banexg.TransferFailed,
+	}
+)
+
+/*
+Transfer
+transfer between account types of current account by universal transfer. When ParamFromSubAcc or ParamToSubAcc
+is given, sub-account universal transfer is used with email (OptSubAccId) of sub accounts, master api key required.
+ParamClientOrderId is sent as clientTranId of sub-account transfer. The request is never retried.
+通过万向划转在当前账户的不同账户类型间划转。传入ParamFromSubAcc或ParamToSubAcc时，使用子账户万向划转，
+需母账户api key，子账户使用其邮箱(OptSubAccId)。ParamClientOrderId作为子账户划转的clientTranId，请求不会重试
+:see: https://developers.binance.com/docs/wallet/asset/user-universal-transfer
+:see: https://developers.binance.com/docs/sub_account/asset-management/Universal-Transfer
+*/
+func (e *Binance) Transfer(code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromSub := utils.PopMapVal(args, banexg.ParamFromSubAcc, "")
+	toSub := utils.PopMapVal(args, banexg.ParamToSubAcc, "")
+	args["asset"] = code
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	var method string
+	if fromSub != "" || toSub != "" {
+		fromType, ok1 := subTransferAccMap[fromAccount]
+		toType, ok2 := subTransferAccMap[toAccount]
+		if !ok1 || !ok2 {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport sub account transfer: %s -> %s", fromAccount, toAccount)
+		}
+		if fromSub != "" {
+			args["fromEmail"] = e.GetSubAccId(fromSub, nil)
+		}
+		if toSub != "" {
+			args["toEmail"] = e.GetSubAccId(toSub, nil)
+		}
+		args["fromAccountType"] = fromType
+		args["toAccountType"] = toType
+		if clientId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clientId != "" {
+			args["clientTranId"] = clientId
+		}
+		method = MethodSapiPostSubAccountUniversalTransfer
+	} else {
+		tranType, err := getTransferType(fromAccount, toAccount)
+		if err != nil {
+			return nil, err
+		}
+		args["type"] = tranType
+		method = MethodSapiPostAssetTransfer
+	}
+	// not idempotent, a retry after timeout may transfer twice
+	rsp := e.RequestApiRetry(context.Background(), method, args, 0)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		TranID int64 `json:"tranId"`
+	}{}
+	info, err_ := utils.UnmarshalStringMap(rsp.Content, &res)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	return &banexg.Transfer{
+		ID:          strconv.FormatInt(res.TranID, 10),
+		Timestamp:   e.MilliSeconds(),
+		Code:        code,
+		Amount:      amount,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		FromSubAcc:  fromSub,
+		ToSubAcc:    toSub,
+		Status:      banexg.TransferOk,
+		Info:        info,
+	}, nil
+}
+
+/*
+FetchTransfers
+query universal transfer history, ParamFromAccount and ParamToAccount are required.
+Sub-account transfers are queried when ParamFromSubAcc or ParamToSubAcc is given.
+查询万向划转历史，ParamFromAccount和ParamToAccount必填。传入ParamFromSubAcc或ParamToSubAcc时查询子账户划转
+:see: https://developers.binance.com/docs/wallet/asset/query-user-universal-transfer
+:see: https://developers.binance.com/docs/sub_account/asset-management/Query-Universal-Transfer-History
+*/
+func (e *Binance) FetchTransfers(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromSub := utils.PopMapVal(args, banexg.ParamFromSubAcc, "")
+	toSub := utils.PopMapVal(args, banexg.ParamToSubAcc, "")
+	fromAccount := utils.PopMapVal(args, banexg.ParamFromAccount, "")
+	toAccount := utils.PopMapVal(args, banexg.ParamToAccount, "")
+	if since > 0 {
+		args["startTime"] = since
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args["endTime"] = until
+	}
+	isSub := fromSub != "" || toSub != ""
+	var method string
+	if isSub {
+		if fromSub != "" {
+			args["fromEmail"] = e.GetSubAccId(fromSub, nil)
+		}
+		if toSub != "" {
+			args["toEmail"] = e.GetSubAccId(toSub, nil)
+		}
+		if limit > 0 {
+			args["limit"] = min(limit, 500)
+		}
+		method = MethodSapiGetSubAccountUniversalTransfer
+	} else {
+		tranType, err := getTransferType(fromAccount, toAccount)
+		if err != nil {
+			return nil, err
+		}
+		args["type"] = tranType
+		if limit > 0 {
+			args["size"] = min(limit, 100)
+		}
+		method = MethodSapiGetAssetTransfer
+	}
+	tryNum := e.GetRetryNum("FetchTransfers", 1)
+	rsp := e.RequestApiRetry(context.Background(), method, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		Rows   []map[string]interface{} `json:"rows"`
+		Result []map[string]interface{} `json:"result"`
+	}{}
+	err_ := utils.UnmarshalString(rsp.Content, &res, utils.JsonNumAuto)
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	var result []*banexg.Transfer
+	if isSub {
+		result, err_ = parseSubAccTransfers(e, res.Result)
+	} else {
+		result, err_ = parseAssetTransfers(e, res.Rows)
+	}
+	if err_ != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	return banexg.FilterTransfers(result, code, "", ""), nil
+}
+
+func getTransferType(fromAccount, toAccount string) (string, *errs.Error) {
+	fromName, ok1 := transferAccMap[fromAccount]
+	toName, ok2 := transferAccMap[toAccount]
+	if !ok1 || !ok2 || fromAccount == toAccount {
+		return "", errs.NewMsg(errs.CodeParamInvalid, "unsupport transfer: %s -> %s", fromAccount, toAccount)
+	}
+	return fromName + "_" + toName, nil
+}
+
+// parseTransferType split universal transfer type like MAIN_UMFUTURE into account types
+func parseTransferType(tranType string) (string, string) {
+	for acc, name := range transferAccMap {
+		rest, ok := strings.CutPrefix(tranType, name+"_")
+		if !ok {
+			continue
+		}
+		for acc2, name2 := range transferAccMap {
+			if rest == name2 {
+				return acc, acc2
+			}
+		}
+	}
+	return "", ""
+}
+
+func parseAssetTransfers(e *Binance, rows []map[string]interface{}) ([]*banexg.Transfer, error) {
+	var items []AssetTransfer
+	if err := utils.DecodeStructMap(rows, &items, "json"); err != nil {
+		return nil, err
+	}
+	res := make([]*banexg.Transfer, 0, len(items))
+	for i, it := range items {
+		amount, _ := strconv.ParseFloat(it.Amount, 64)
+		fromAcc, toAcc := parseTransferType(it.Type)
+		res = append(res, &banexg.Transfer{
+			ID:          strconv.FormatInt(it.TranID, 10),
+			Timestamp:   it.Timestamp,
+			Code:        e.SafeCurrencyCode(it.Asset),
+			Amount:      amount,
+			FromAccount: fromAcc,
+			ToAccount:   toAcc,
+			Status:      transferStatusMap[it.Status],
+			Info:        rows[i],
+		})
+	}
+	return res, nil
+}
+
+func parseSubAccTransfers(e *Binance, rows []map[string]interface{}) ([]*banexg.Transfer, error) {
+	var items []SubAccTransfer
+	if err := utils.DecodeStructMap(rows, &items, "json"); err != nil {
+		return nil, err
+	}
+	accTypes := make(map[string]string, len(subTransferAccMap))
+	for acc, name := range subTransferAccMap {
+		accTypes[name] = acc
+	}
+	res := make([]*banexg.Transfer, 0, len(items))
+	for i, it := range items {
+		amount, _ := strconv.ParseFloat(it.Amount, 64)
+		res = append(res, &banexg.Transfer{
+			ID:          strconv.FormatInt(it.TranID, 10),
+			Timestamp:   it.CreateTimeStamp,
+			Code:        e.SafeCurrencyCode(it.Asset),
+			Amount:      amount,
+			FromAccount: accTypes[it.FromAccountType],
+			ToAccount:   accTypes[it.ToAccountType],
+			FromSubAcc:  it.FromEmail,
+			ToSubAcc:    it.ToEmail,
+			Status:      transferStatusMap[it.Status],
+			Info:        rows[i],
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,219 @@
+package binance
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+var (
+	depositStatusMap = map[int]string{
+		0: banexg.TransferPending,
+		1: banexg.TransferOk,
+		2: banexg.TransferFailed,
+		6: banexg.TransferOk, // credited but cannot withdraw
+		7: banexg.TransferFailed,
+		8: banexg.TransferPending,
+	}
+	withdrawStatusMap = map[int]string{
+		0: banexg.TransferPending,
+		1: banexg.TransferCanceled,
+		2: banexg.TransferPending,
+		3: banexg.TransferFailed,
+		4: banexg.TransferPending,
+		5: banexg.TransferFailed,
+		6: banexg.TransferOk,
+	}
+)
+
+/*
+FetchDepositAddress
+:see: https://developers.binance.com/docs/wallet/capital/deposite-address
+*/
+func (e *Binance) FetchDepositAddress(code string, params map[string]interface{}) (*banexg.DepositAddress, *errs.Error) {
+	args := utils.SafeParams(params)
+	network := utils.PopMapVal(args, banexg.ParamNetwork, "")
+	args["coin"] = code
+	if network != "" {
+		args["network"] = network
+	}
+	tryNum := e.GetRetryNum("FetchDepositAddress", 1)
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiGetCapitalDepositAddress, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		Address string `json:"address"`
+		Coin    string `json:"coin"`
+		Tag     string `json:"tag"`
+	}{}
+	info, err := utils.UnmarshalStringMap(rsp.Content, &res)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	return &banexg.DepositAddress{
+		Code:    e.SafeCurrencyCode(res.Coin),
+		Network: network,
+		Address: res.Address,
+		Tag:     res.Tag,
+		Info:    info,
+	}, nil
+}
+
+/*
+FetchDeposits
+:see: https://developers.binance.com/docs/wallet/capital/deposite-history
+*/
+func (e *Binance) FetchDeposits(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	args := setWalletHistoryArgs(code, since, limit, params)
+	tryNum := e.GetRetryNum("FetchDeposits", 1)
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiGetCapitalDepositHisrec, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var data = make([]*DepositRecord, 0)
+	infos, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	res := make([]*banexg.Transaction, 0, len(data))
+	for i, it := range data {
+		amount, _ := strconv.ParseFloat(it.Amount, 64)
+		res = append(res, &banexg.Transaction{
+			ID:        it.ID,
+			TxID:      it.TxId,
+			Type:      banexg.TxDeposit,
+			Code:      e.SafeCurrencyCode(it.Coin),
+			Amount:    amount,
+			Network:   it.Network,
+			Address:   it.Address,
+			Tag:       it.AddressTag,
+			Status:    depositStatusMap[it.Status],
+			Timestamp: it.InsertTime,
+			Info:      infos[i],
+		})
+	}
+	return res, nil
+}
+
+/*
+FetchWithdrawals
+:see: https://developers.binance.com/docs/wallet/capital/withdraw-history
+*/
+func (e *Binance) FetchWithdrawals(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	args := setWalletHistoryArgs(code, since, limit, params)
+	tryNum := e.GetRetryNum("FetchWithdrawals", 1)
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiGetCapitalWithdrawHistory, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var data = make([]*WithdrawRecord, 0)
+	infos, err := utils.UnmarshalStringMapArr(rsp.Content, &data)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	res := make([]*banexg.Transaction, 0, len(data))
+	for i, it := range data {
+		code := e.SafeCurrencyCode(it.Coin)
+		amount, _ := strconv.ParseFloat(it.Amount, 64)
+		fee, _ := strconv.ParseFloat(it.TransactionFee, 64)
+		res = append(res, &banexg.Transaction{
+			ID:        it.ID,
+			TxID:      it.TxId,
+			Type:      banexg.TxWithdrawal,
+			Code:      code,
+			Amount:    amount,
+			Network:   it.Network,
+			Address:   it.Address,
+			Tag:       it.AddressTag,
+			Status:    withdrawStatusMap[it.Status],
+			Fee:       &banexg.Fee{Currency: code, Cost: fee},
+			Timestamp: parseWalletTime(it.ApplyTime),
+			Updated:   parseWalletTime(it.CompleteTime),
+			Info:      infos[i],
+		})
+	}
+	return res, nil
+}
+
+/*
+Withdraw
+ParamClientOrderId is sent as withdrawOrderId, the request is never retried
+ParamClientOrderId作为withdrawOrderId发送，请求不会重试
+:see: https://developers.binance.com/docs/wallet/capital/withdraw
+*/
+func (e *Binance) Withdraw(code string, amount float64, address, tag, network string, params map[string]interface{}) (*banexg.Transaction, *errs.Error) {
+	if err := e.CheckWithdraw(code, network, amount, params); err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	args["coin"] = code
+	args["address"] = address
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	if tag != "" {
+		args["addressTag"] = tag
+	}
+	if network != "" {
+		args["network"] = network
+	}
+	if clientId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clientId != "" {
+		args["withdrawOrderId"] = clientId
+	}
+	// not idempotent, a retry after timeout may withdraw twice
+	rsp := e.RequestApiRetry(context.Background(), MethodSapiPostCapitalWithdrawApply, args, 0)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	var res = struct {
+		ID string `json:"id"`
+	}{}
+	info, err := utils.UnmarshalStringMap(rsp.Content, &res)
+	if err != nil {
+		return nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	return &banexg.Transaction{
+		ID:        res.ID,
+		Type:      banexg.TxWithdrawal,
+		Code:      code,
+		Amount:    amount,
+		Network:   network,
+		Address:   address,
+		Tag:       tag,
+		Status:    banexg.TransferPending,
+		Timestamp: e.MilliSeconds(),
+		Info:      info,
+	}, nil
+}
+
+func setWalletHistoryArgs(code string, since int64, limit int, params map[string]interface{}) map[string]interface{} {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args["coin"] = code
+	}
+	if since > 0 {
+		args["startTime"] = since
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args["endTime"] = until
+	}
+	if limit > 0 {
+		args["limit"] = min(limit, 1000)
+	}
+	return args
+}
+
+// parseWalletTime parse utc time like "2019-10-12 11:12:02" of wallet apis to 13 digit timestamp
+func parseWalletTime(text string) int64 {
+	if text == "" {
+		return 0
+	}
+	t, err := time.Parse(time.DateTime, text)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
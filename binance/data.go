@@ -1,5 +1,7 @@
 package binance
 
+import "github.com/banbox/banexg"
+
 const (
 	HostDApiPublic    = "dapiPublic"
 	HostDApiPrivate   = "dapiPrivate"
@@ -21,6 +23,7 @@ const (
 	HostFApiData      = "fapiData"
 	HostPApi          = "papi"
 	WssApi            = "ws"
+	WssFApi           = "wsFapi"
 )
 
 const (
@@ -280,6 +283,7 @@ const (
 	MethodSapiPostMarginTransfer                                      = "sapiPostMarginTransfer"
 	MethodSapiPostMarginLoan                                          = "sapiPostMarginLoan"
 	MethodSapiPostMarginRepay                                         = "sapiPostMarginRepay"
+	MethodSapiPostMarginBorrowRepay                                   = "sapiPostMarginBorrowRepay"
 	MethodSapiPostMarginOrder                                         = "sapiPostMarginOrder"
 	MethodSapiPostMarginOrderOco                                      = "sapiPostMarginOrderOco"
 	MethodSapiPostMarginDust                                          = "sapiPostMarginDust"
@@ -466,6 +470,7 @@ const (
 	MethodFapiPublicGetContinuousKlines                               = "fapiPublicGetContinuousKlines"
 	MethodFapiPublicGetMarkPriceKlines                                = "fapiPublicGetMarkPriceKlines"
 	MethodFapiPublicGetIndexPriceKlines                               = "fapiPublicGetIndexPriceKlines"
+	MethodFapiPublicGetPremiumIndexKlines                             = "fapiPublicGetPremiumIndexKlines"
 	MethodFapiPublicGetFundingRate                                    = "fapiPublicGetFundingRate"
 	MethodFapiPublicGetFundingInfo                                    = "fapiPublicGetFundingInfo"
 	MethodFapiPublicGetPremiumIndex                                   = "fapiPublicGetPremiumIndex"
@@ -706,3 +711,42 @@ const (
 	MethodPapiDeleteMarginOrderList                                   = "papiDeleteMarginOrderList"
 	MethodPapiDeleteListenKey                                         = "papiDeleteListenKey"
 )
+
+const (
+	// ParamRawTrades FetchTrades use trades/historicalTrades instead of aggTrades
+	ParamRawTrades = "rawTrades"
+)
+
+const (
+	HeaderUsedWeight1M  = "X-MBX-USED-WEIGHT-1M"
+	HeaderOrderCount10S = "X-MBX-ORDER-COUNT-10S"
+	HeaderOrderCount1M  = "X-MBX-ORDER-COUNT-1M"
+)
+
+var (
+	spotHosts = []string{HostPublic, HostPrivate, HostV1}
+	fapiHosts = []string{HostFApiPublic, HostFApiPublicV2, HostFApiPrivate, HostFApiPrivateV2}
+	dapiHosts = []string{HostDApiPublic, HostDApiPrivate, HostDApiPrivateV2}
+	postOnly  = []string{"POST"}
+)
+
+/*
+weightRules
+Binance ip weight and per-account order count limits. Spot Entry.Cost is weight/5, futures Cost equals weight.
+币安的IP权重和账户下单数量限制。现货Entry.Cost是权重的1/5，合约Cost等于权重
+*/
+func weightRules() []*banexg.WeightRule {
+	return []*banexg.WeightRule{
+		{Header: HeaderUsedWeight1M, Hosts: spotHosts, Limit: 6000, Interval: 60000, Ratio: 5},
+		{Header: HeaderOrderCount10S, Hosts: []string{HostPrivate}, Methods: postOnly, Limit: 100, Interval: 10000,
+			Fixed: 1, Account: true},
+		{Header: HeaderUsedWeight1M, Hosts: fapiHosts, Limit: 2400, Interval: 60000},
+		{Header: HeaderOrderCount10S, Hosts: []string{HostFApiPrivate}, Methods: postOnly, Limit: 300,
+			Interval: 10000, Fixed: 1, Account: true},
+		{Header: HeaderOrderCount1M, Hosts: []string{HostFApiPrivate}, Methods: postOnly, Limit: 1200,
+			Interval: 60000, Fixed: 1, Account: true},
+		{Header: HeaderUsedWeight1M, Hosts: dapiHosts, Limit: 2400, Interval: 60000},
+		{Header: HeaderOrderCount1M, Hosts: []string{HostDApiPrivate}, Methods: postOnly, Limit: 1200,
+			Interval: 60000, Fixed: 1, Account: true},
+	}
+}
@@ -9,9 +9,10 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 	exg := &Binance{
 		Exchange: &banexg.Exchange{
 			ExgInfo: &banexg.ExgInfo{
-				ID:        "binance",
-				Name:      "Binance",
-				Countries: []string{"JP", "MT"},
+				ID:         "binance",
+				Name:       "Binance",
+				Countries:  []string{"JP", "MT"},
+				OHLCVLimit: 1000,
 			},
 			RateLimit: 50,
 			Options:   Options,
@@ -33,6 +34,7 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 					banexg.MarketInverse: "wss://dstream.binancefuture.com/ws",
 					banexg.MarketOption:  "wss://nbstream.binancefuture.com/eoptions",
 					WssApi:               "wss://testnet.binance.vision/ws-api/v3",
+					WssFApi:              "wss://testnet.binancefuture.com/ws-fapi/v1",
 				},
 				Prod: map[string]string{
 					HostSApi:             "https://api.binance.com/sapi/v1",
@@ -60,6 +62,7 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 					banexg.MarketInverse: "wss://dstream.binance.com/ws",
 					banexg.MarketOption:  "wss://nbstream.binance.com/eoptions",
 					WssApi:               "wss://ws-api.binance.com:443/ws-api/v3",
+					WssFApi:              "wss://ws-fapi.binance.com/ws-fapi/v1",
 				},
 				Www: "https://www.binance.com",
 				Doc: []string{
@@ -375,17 +378,18 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 				MethodSapiGetSimpleEarnFlexibleHistoryCollateralRecord:            {Path: "simple-earn/flexible/history/collateralRecord", Host: HostSApi, Method: "GET", Cost: 0.1},
 				MethodSapiPostAssetDust:                                           {Path: "asset/dust", Host: HostSApi, Method: "POST", Cost: 0.06667},
 				MethodSapiPostAssetDustBtc:                                        {Path: "asset/dust-btc", Host: HostSApi, Method: "POST", Cost: 0.1},
-				MethodSapiPostAssetTransfer:                                       {Path: "asset/transfer", Host: HostSApi, Method: "POST", Cost: 6.0003},
+				MethodSapiPostAssetTransfer:                                       {Path: "asset/transfer", Host: HostSApi, Method: "POST", Cost: 6.0003, Risky: true},
 				MethodSapiPostAssetGetFundingAsset:                                {Path: "asset/get-funding-asset", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostAssetConvertTransfer:                                {Path: "asset/convert-transfer", Host: HostSApi, Method: "POST", Cost: 0.033335},
 				MethodSapiPostAccountDisableFastWithdrawSwitch:                    {Path: "account/disableFastWithdrawSwitch", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostAccountEnableFastWithdrawSwitch:                     {Path: "account/enableFastWithdrawSwitch", Host: HostSApi, Method: "POST", Cost: 0.1},
-				MethodSapiPostCapitalWithdrawApply:                                {Path: "capital/withdraw/apply", Host: HostSApi, Method: "POST", Cost: 4.0002},
+				MethodSapiPostCapitalWithdrawApply:                                {Path: "capital/withdraw/apply", Host: HostSApi, Method: "POST", Cost: 4.0002, Risky: true},
 				MethodSapiPostCapitalContractConvertibleCoins:                     {Path: "capital/contract/convertible-coins", Host: HostSApi, Method: "POST", Cost: 4.0002},
 				MethodSapiPostCapitalDepositCreditApply:                           {Path: "capital/deposit/credit-apply", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostMarginTransfer:                                      {Path: "margin/transfer", Host: HostSApi, Method: "POST", Cost: 4.0002},
 				MethodSapiPostMarginLoan:                                          {Path: "margin/loan", Host: HostSApi, Method: "POST", Cost: 20.001},
 				MethodSapiPostMarginRepay:                                         {Path: "margin/repay", Host: HostSApi, Method: "POST", Cost: 20.001},
+				MethodSapiPostMarginBorrowRepay:                                   {Path: "margin/borrow-repay", Host: HostSApi, Method: "POST", Cost: 20.001, Risky: true},
 				MethodSapiPostMarginOrder:                                         {Path: "margin/order", Host: HostSApi, Method: "POST", Cost: 0.040002},
 				MethodSapiPostMarginOrderOco:                                      {Path: "margin/order/oco", Host: HostSApi, Method: "POST", Cost: 0.040002},
 				MethodSapiPostMarginDust:                                          {Path: "margin/dust", Host: HostSApi, Method: "POST", Cost: 20.001},
@@ -402,7 +406,7 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 				MethodSapiPostSubAccountFuturesInternalTransfer:                   {Path: "sub-account/futures/internalTransfer", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostSubAccountTransferSubToSub:                          {Path: "sub-account/transfer/subToSub", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostSubAccountTransferSubToMaster:                       {Path: "sub-account/transfer/subToMaster", Host: HostSApi, Method: "POST", Cost: 0.1},
-				MethodSapiPostSubAccountUniversalTransfer:                         {Path: "sub-account/universalTransfer", Host: HostSApi, Method: "POST", Cost: 0.1},
+				MethodSapiPostSubAccountUniversalTransfer:                         {Path: "sub-account/universalTransfer", Host: HostSApi, Method: "POST", Cost: 0.1, Risky: true},
 				MethodSapiPostSubAccountOptionsEnable:                             {Path: "sub-account/options/enable", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostManagedSubaccountDeposit:                            {Path: "managed-subaccount/deposit", Host: HostSApi, Method: "POST", Cost: 0.1},
 				MethodSapiPostManagedSubaccountWithdraw:                           {Path: "managed-subaccount/withdraw", Host: HostSApi, Method: "POST", Cost: 0.1},
@@ -572,6 +576,7 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 				MethodFapiPublicGetContinuousKlines:                               {Path: "continuousKlines", Host: HostFApiPublic, Method: "GET", Cost: 1, More: map[string]interface{}{"byLimit": []int{99, 1, 499, 2, 1000, 5, 10000, 10}}},
 				MethodFapiPublicGetMarkPriceKlines:                                {Path: "markPriceKlines", Host: HostFApiPublic, Method: "GET", Cost: 1, More: map[string]interface{}{"byLimit": []int{99, 1, 499, 2, 1000, 5, 10000, 10}}},
 				MethodFapiPublicGetIndexPriceKlines:                               {Path: "indexPriceKlines", Host: HostFApiPublic, Method: "GET", Cost: 1, More: map[string]interface{}{"byLimit": []int{99, 1, 499, 2, 1000, 5, 10000, 10}}},
+				MethodFapiPublicGetPremiumIndexKlines:                             {Path: "premiumIndexKlines", Host: HostFApiPublic, Method: "GET", Cost: 1, More: map[string]interface{}{"byLimit": []int{99, 1, 499, 2, 1000, 5, 10000, 10}}},
 				MethodFapiPublicGetFundingRate:                                    {Path: "fundingRate", Host: HostFApiPublic, Method: "GET", Cost: 1},
 				MethodFapiPublicGetFundingInfo:                                    {Path: "fundingInfo", Host: HostFApiPublic, Method: "GET", Cost: 1},
 				MethodFapiPublicGetPremiumIndex:                                   {Path: "premiumIndex", Host: HostFApiPublic, Method: "GET", Cost: 1},
@@ -814,6 +819,18 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 			},
 			Has: map[string]map[string]int{
 				"": {
+					banexg.ApiFetchTime:             banexg.HasOk,
+					banexg.ApiTransfer:              banexg.HasOk,
+					banexg.ApiFetchTransfers:        banexg.HasOk,
+					banexg.ApiFetchDepositAddress:   banexg.HasOk,
+					banexg.ApiFetchDeposits:         banexg.HasOk,
+					banexg.ApiFetchWithdrawals:      banexg.HasOk,
+					banexg.ApiWithdraw:              banexg.HasOk,
+					banexg.ApiBorrow:                banexg.HasOk,
+					banexg.ApiRepay:                 banexg.HasOk,
+					banexg.ApiFetchBorrowRate:       banexg.HasOk,
+					banexg.ApiFetchBorrowRates:      banexg.HasOk,
+					banexg.ApiFetchBorrowInterest:   banexg.HasOk,
 					banexg.ApiFetchTicker:           banexg.HasOk,
 					banexg.ApiFetchTickers:          banexg.HasOk,
 					banexg.ApiFetchTickerPrice:      banexg.HasOk,
@@ -827,10 +844,30 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 					banexg.ApiFetchAccountPositions: banexg.HasOk,
 					banexg.ApiFetchPositions:        banexg.HasOk,
 					banexg.ApiFetchOpenOrders:       banexg.HasOk,
+					banexg.ApiFetchMyTrades:         banexg.HasOk,
+					banexg.ApiFetchTrades:           banexg.HasOk,
 					banexg.ApiCreateOrder:           banexg.HasOk,
 					banexg.ApiEditOrder:             banexg.HasOk,
 					banexg.ApiCancelOrder:           banexg.HasOk,
+					banexg.ApiCreateOrders:          banexg.HasEmulated,
+					banexg.ApiCancelOrders:          banexg.HasEmulated,
+					banexg.ApiCancelAllOrders:       banexg.HasOk,
 					banexg.ApiSetLeverage:           banexg.HasOk,
+					banexg.ApiSetMarginMode:         banexg.HasOk,
+					banexg.ApiSetPositionMode:       banexg.HasOk,
+					banexg.ApiAddMargin:             banexg.HasOk,
+					banexg.ApiReduceMargin:          banexg.HasOk,
+					banexg.ApiFetchOpenInterest:     banexg.HasOk,
+					banexg.ApiFetchOpenInterestHist: banexg.HasOk,
+					banexg.ApiFetchLongShortRatio:   banexg.HasOk,
+					banexg.ApiWatchLiquidations:     banexg.HasOk,
+					banexg.ApiUnWatchLiquidations:   banexg.HasOk,
+					banexg.ApiFetchOptionChain:      banexg.HasOk,
+					banexg.ApiFetchGreeks:           banexg.HasOk,
+					banexg.ApiWatchGreeks:           banexg.HasOk,
+					banexg.ApiUnWatchGreeks:         banexg.HasOk,
+					banexg.ApiFetchOHLCVRange:       banexg.HasOk,
+					banexg.ApiSetCancelAllAfter:     banexg.HasOk,
 					banexg.ApiCalcMaintMargin:       banexg.HasOk,
 					banexg.ApiWatchOrderBooks:       banexg.HasOk,
 					banexg.ApiUnWatchOrderBooks:     banexg.HasOk,
@@ -840,25 +877,39 @@ func New(Options map[string]interface{}) (*Binance, *errs.Error) {
 					banexg.ApiUnWatchMarkPrices:     banexg.HasOk,
 					banexg.ApiWatchTrades:           banexg.HasOk,
 					banexg.ApiUnWatchTrades:         banexg.HasOk,
+					banexg.ApiWatchTickers:          banexg.HasOk,
+					banexg.ApiUnWatchTickers:        banexg.HasOk,
 					banexg.ApiWatchMyTrades:         banexg.HasOk,
+					banexg.ApiWatchOrders:           banexg.HasOk,
 					banexg.ApiWatchBalance:          banexg.HasOk,
 					banexg.ApiWatchPositions:        banexg.HasOk,
 					banexg.ApiWatchAccountConfig:    banexg.HasOk,
 				},
+				banexg.MarketLinear: {
+					banexg.ApiCreateOrders: banexg.HasOk,
+					banexg.ApiCancelOrders: banexg.HasOk,
+				},
+				banexg.MarketInverse: {
+					banexg.ApiCreateOrders: banexg.HasOk,
+					banexg.ApiCancelOrders: banexg.HasOk,
+				},
 			},
-			CredKeys: map[string]bool{"ApiKey": true, "Secret": true},
+			CredKeys:    map[string]bool{"ApiKey": true, "Secret": true},
+			WeightRules: weightRules(),
 		},
 		newOrderRespType: map[string]string{
 			banexg.OdTypeMarket: "FULL",
 			banexg.OdTypeLimit:  "FULL",
 		},
 	}
+	exg.Self = exg
 	exg.Sign = makeSign(exg)
 	exg.FetchCurrencies = makeFetchCurr(exg)
 	exg.FetchMarkets = makeFetchMarkets(exg)
 	exg.OnWsMsg = makeHandleWsMsg(exg)
 	exg.OnWsReCon = makeHandleWsReCon(exg)
 	exg.GetRetryWait = makeGetRetryWait(exg)
+	exg.MapBizErr = mapBizErr
 	exg.AuthWS = exg.postListenKey
 	exg.CheckWsTimeout = makeCheckWsTimeout(exg)
 	err := exg.Init()
@@ -432,6 +432,64 @@ type Income struct {
 	TradeID    string `json:"tradeId"`
 }
 
+type AssetTransfer struct {
+	Asset     string `json:"asset"`
+	Amount    string `json:"amount"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	TranID    int64  `json:"tranId"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type DepositRecord struct {
+	ID         string `json:"id"`
+	Amount     string `json:"amount"`
+	Coin       string `json:"coin"`
+	Network    string `json:"network"`
+	Status     int    `json:"status"`
+	Address    string `json:"address"`
+	AddressTag string `json:"addressTag"`
+	TxId       string `json:"txId"`
+	InsertTime int64  `json:"insertTime"`
+}
+
+type WithdrawRecord struct {
+	ID              string `json:"id"`
+	Amount          string `json:"amount"`
+	TransactionFee  string `json:"transactionFee"`
+	Coin            string `json:"coin"`
+	Status          int    `json:"status"`
+	Address         string `json:"address"`
+	AddressTag      string `json:"addressTag"`
+	TxId            string `json:"txId"`
+	ApplyTime       string `json:"applyTime"`
+	CompleteTime    string `json:"completeTime"`
+	Network         string `json:"network"`
+	WithdrawOrderId string `json:"withdrawOrderId"`
+}
+
+type MarginInterest struct {
+	InterestAccuredTime int64  `json:"interestAccuredTime"`
+	Asset               string `json:"asset"`
+	Principal           string `json:"principal"`
+	Interest            string `json:"interest"`
+	InterestRate        string `json:"interestRate"`
+	Type                string `json:"type"`
+	IsolatedSymbol      string `json:"isolatedSymbol"`
+}
+
+type SubAccTransfer struct {
+	TranID          int64  `json:"tranId"`
+	FromEmail       string `json:"fromEmail"`
+	ToEmail         string `json:"toEmail"`
+	Asset           string `json:"asset"`
+	Amount          string `json:"amount"`
+	CreateTimeStamp int64  `json:"createTimeStamp"`
+	FromAccountType string `json:"fromAccountType"`
+	ToAccountType   string `json:"toAccountType"`
+	Status          string `json:"status"`
+}
+
 /*
 *****************************   Private Rows   ***********************************
  */
@@ -596,6 +654,79 @@ type SpotFill struct {
 	MatchType       string `json:"matchType"` // sor
 }
 
+/*
+SpotMyTrade 现货/杠杆账户成交: /myTrades & /margin/myTrades
+*/
+type SpotMyTrade struct {
+	Symbol          string `json:"symbol"`
+	ID              int64  `json:"id"`
+	OrderId         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+}
+
+/*
+FutureMyTrade U本位/币本位合约成交: /userTrades
+*/
+type FutureMyTrade struct {
+	Symbol          string `json:"symbol"`
+	ID              int64  `json:"id"`
+	OrderId         int64  `json:"orderId"`
+	Side            string `json:"side"`
+	PositionSide    string `json:"positionSide"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"` // U本位
+	BaseQty         string `json:"baseQty"`  // 币本位
+	RealizedPnl     string `json:"realizedPnl"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	Buyer           bool   `json:"buyer"`
+	Maker           bool   `json:"maker"`
+}
+
+/*
+AggTrade 归集交易: /aggTrades
+*/
+type AggTrade struct {
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Qty          string `json:"q"`
+	FirstId      int64  `json:"f"`
+	LastId       int64  `json:"l"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+	IsBestMatch  bool   `json:"M"`
+}
+
+/*
+RawTrade 逐笔交易: /trades & /historicalTrades
+*/
+type RawTrade struct {
+	ID           int64  `json:"id"`
+	Price        string `json:"price"`
+	Qty          string `json:"qty"`
+	QuoteQty     string `json:"quoteQty"`
+	Time         int64  `json:"time"`
+	IsBuyerMaker bool   `json:"isBuyerMaker"`
+	Side         int    `json:"side"` // 期权：-1卖 1买
+}
+
+type IBnbTrade interface {
+	ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.Trade
+}
+
+type IBnbMyTrade interface {
+	ToStdTrade(m *banexg.Market, info map[string]interface{}) *banexg.MyTrade
+}
+
 type IBnbOrder interface {
 	ToStdOrder(func(string) string, map[string]interface{}) *banexg.Order
 }
@@ -809,9 +940,39 @@ type FundingRateCur struct {
 	Time                 int64  `json:"time"`
 }
 
+type OpenInterestHist struct {
+	SumOpenInterest      string `json:"sumOpenInterest"`
+	SumOpenInterestValue string `json:"sumOpenInterestValue"`
+	Timestamp            int64  `json:"timestamp"`
+}
+
+type LongShortRatio struct {
+	LongShortRatio string `json:"longShortRatio"`
+	LongAccount    string `json:"longAccount"`
+	ShortAccount   string `json:"shortAccount"`
+	BuySellRatio   string `json:"buySellRatio"`
+	BuyVol         string `json:"buyVol"`
+	SellVol        string `json:"sellVol"`
+	TakerBuyVol    string `json:"takerBuyVol"`
+	TakerSellVol   string `json:"takerSellVol"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
 type LastPrice struct {
 	Symbol string `json:"symbol"`
 	Price  string `json:"price"`
 	Time   int64  `json:"time,omitempty"` // linear/inverse
 	PS     string `json:"ps,omitempty"`   //inverse
 }
+
+type OptionMark struct {
+	Symbol    string `json:"symbol"`
+	MarkPrice string `json:"markPrice"`
+	BidIV     string `json:"bidIV"`
+	AskIV     string `json:"askIV"`
+	MarkIV    string `json:"markIV"`
+	Delta     string `json:"delta"`
+	Theta     string `json:"theta"`
+	Gamma     string `json:"gamma"`
+	Vega      string `json:"vega"`
+}
@@ -27,6 +27,9 @@ func makeHandleWsMsg(e *Binance) banexg.FuncOnWsMsg {
 				} else {
 					log.Debug("ws job ok", zap.String("job", item.ID))
 				}
+			} else if _, ok := item.Object["b"]; ok && item.Object["u"] != "" {
+				// spot bookTicker has no event field
+				e.handleTickers(client, []map[string]string{item.Object}, "bookTicker")
 			} else {
 				log.Warn("no event ws msg", zap.String("msg", item.Text))
 			}
@@ -56,17 +59,20 @@ func makeHandleWsMsg(e *Binance) banexg.FuncOnWsMsg {
 		case "markPrice":
 			// option
 			e.handleMarkPrices(client, msgList, item.IsArray)
+			e.handleGreeks(client, msgList)
 		case "24hrTicker":
 			//spot/linear/inverse/option
-			e.handleTickers(client, msgList)
+			e.handleTickers(client, msgList, "ticker")
 		case "24hrMiniTicker":
 			//spot/linear/inverse
-			e.handleTickers(client, msgList)
+			e.handleTickers(client, msgList, "miniTicker")
 		case "bookTicker":
-			e.handleTickers(client, msgList)
+			e.handleTickers(client, msgList, "bookTicker")
 		case "openInterest":
 			// option 合约持仓量
 			break
+		case "forceOrder":
+			e.handleLiquidation(client, msg)
 		case "outboundAccountPosition":
 			e.handleBalance(client, msg)
 		case "balanceUpdate":
@@ -77,6 +83,8 @@ func makeHandleWsMsg(e *Binance) banexg.FuncOnWsMsg {
 			e.handleOrderUpdate(client, msg)
 		case "ORDER_TRADE_UPDATE":
 			e.handleOrderUpdate(client, msg)
+		case "ALGO_UPDATE":
+			e.handleAlgoUpdate(client, msg)
 		case "ACCOUNT_CONFIG_UPDATE":
 			e.handleAccountConfigUpdate(client, msg)
 		case "TRADE_LITE":
@@ -551,6 +559,19 @@ func (e *Binance) prepareOHLCVSub(isSub bool, jobs [][2]string, params map[strin
 		return "", nil, nil, err
 	}
 	name := utils.PopMapVal(args, banexg.ParamName, "kline")
+	if priceType := utils.PopMapVal(args, banexg.ParamPrice, ""); priceType != "" {
+		if !market.Linear && !market.Inverse {
+			return "", nil, nil, errs.NewMsg(errs.CodeUnsupportMarket, "%s klines only support linear/inverse", priceType)
+		}
+		switch priceType {
+		case banexg.PriceMark:
+			name = "markPriceKline"
+		case banexg.PriceIndex:
+			name = "indexPriceKline"
+		default:
+			return "", nil, nil, errs.NewMsg(errs.CodeNotSupport, "unsupport %s for ws: %s", banexg.ParamPrice, priceType)
+		}
+	}
 	msgHash := market.Type + "@" + name
 	client, err := e.GetWsClient(market.Type, msgHash)
 	if err != nil {
@@ -576,8 +597,77 @@ func (e *Binance) prepareOHLCVSub(isSub bool, jobs [][2]string, params map[strin
 	return chanKey, symbols, args, nil
 }
 
-func (e *Binance) handleTickers(client *banexg.WsClient, msgList []map[string]string) {
+func (e *Binance) handleTickers(client *banexg.WsClient, msgList []map[string]string, name string) {
+	chanKey := client.Prefix(client.MarketType + "@" + name)
+	allKey := allTickersStream(name)
+	hasAll := client.HasSubKeyPrefix(allKey)
+	if hasAll {
+		client.SetSubsKeyStamp(allKey, bntp.UTCStamp())
+	}
+	for _, msg := range msgList {
+		ticker := parseWsTicker(msg)
+		market := e.GetMarketById(ticker.Symbol, client.MarketType)
+		if market == nil {
+			continue
+		}
+		subKey := market.LowercaseID + "@" + name
+		if !hasAll || client.HasSubKeyPrefix(subKey) {
+			client.SetSubsKeyStamp(subKey, bntp.UTCStamp())
+		}
+		ticker.Symbol = market.Symbol
+		banexg.WriteOutChan(e.Exchange, chanKey, e.UpdateWsTicker(ticker), true)
+	}
+}
+
+func (e *Binance) handleLiquidation(client *banexg.WsClient, msg map[string]string) {
+	objText, _ := utils.SafeMapVal(msg, "o", "")
+	var obj = map[string]interface{}{}
+	err := utils.UnmarshalString(objText, &obj, utils.JsonNumStr)
+	if err != nil {
+		log.Error("unmarshal forceOrder fail", zap.String("o", objText), zap.Error(err))
+		return
+	}
+	item := parseWsLiquidation(utils.MapValStr(obj), obj)
+	market := e.GetMarketById(item.Symbol, client.MarketType)
+	if market == nil {
+		return
+	}
+	item.Symbol = market.Symbol
+	// quantity of coin-m is contracts
+	item.Amount = market.BaseAmount(item.Amount, item.Price)
+	stamp := bntp.UTCStamp()
+	allKey := "!forceOrder@arr"
+	hasAll := client.HasSubKeyPrefix(allKey)
+	if hasAll {
+		client.SetSubsKeyStamp(allKey, stamp)
+	}
+	if subKey := market.LowercaseID + "@forceOrder"; !hasAll || client.HasSubKeyPrefix(subKey) {
+		client.SetSubsKeyStamp(subKey, stamp)
+	}
+	chanKey := client.Prefix(client.MarketType + "@forceOrder")
+	banexg.WriteOutChan(e.Exchange, chanKey, item, true)
+}
 
+func parseWsLiquidation(msg map[string]string, info map[string]interface{}) *banexg.Liquidation {
+	marketId, _ := utils.SafeMapVal(msg, "s", "")
+	side, _ := utils.SafeMapVal(msg, "S", "")
+	amount, _ := utils.SafeMapVal(msg, "z", float64(0))
+	if amount == 0 {
+		amount, _ = utils.SafeMapVal(msg, "q", float64(0))
+	}
+	price, _ := utils.SafeMapVal(msg, "ap", float64(0))
+	if price == 0 {
+		price, _ = utils.SafeMapVal(msg, "p", float64(0))
+	}
+	stamp, _ := utils.SafeMapVal(msg, "T", int64(0))
+	return &banexg.Liquidation{
+		Symbol:    marketId,
+		Side:      strings.ToLower(side),
+		Price:     price,
+		Amount:    amount,
+		Timestamp: stamp,
+		Info:      info,
+	}
 }
 
 /*
@@ -717,8 +807,10 @@ func (e *Binance) handleAccountUpdate(client *banexg.WsClient, msg map[string]st
 			walletBalance, _ := strconv.ParseFloat(item.WalletBalance, 64)
 			if ok {
 				asset.Free = walletBalance
+				// keep Total consistent with rest (marginBalance), so wallet balance is Total-UPol
+				asset.Total = walletBalance + asset.UPol
 			} else {
-				asset = &banexg.Asset{Code: code, Free: walletBalance}
+				asset = &banexg.Asset{Code: code, Free: walletBalance, Total: walletBalance}
 				balances.Assets[code] = asset
 			}
 		}
@@ -797,6 +889,39 @@ func (e *Binance) handleOrderUpdate(client *banexg.WsClient, msg map[string]stri
 	}
 
 	banexg.WriteOutChan(e.Exchange, client.Prefix("mytrades"), &trade, false)
+
+	order := parseWsOrder(msg)
+	order.Symbol = market.Symbol
+	banexg.WriteOutChan(e.Exchange, client.Prefix("orders"), order, false)
+}
+
+/*
+handleAlgoUpdate
+conditional orders of usd-m futures are placed by the algo service, which pushes ALGO_UPDATE for new, triggered,
+canceled and expired events. ID is prefixed by "algo:", the same as CreateOrder/FetchOrder/CancelOrder.
+U本位合约的条件单由策略服务下单，新建、触发、取消、过期时推送ALGO_UPDATE。ID带"algo:"前缀，与CreateOrder/FetchOrder/CancelOrder一致
+
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/user-data-streams/Event-Algo-Order-Update
+*/
+func (e *Binance) handleAlgoUpdate(client *banexg.WsClient, msg map[string]string) {
+	objText, _ := utils.SafeMapVal(msg, "o", "")
+	var obj = map[string]interface{}{}
+	err := utils.UnmarshalString(objText, &obj, utils.JsonNumStr)
+	if err != nil {
+		log.Error("unmarshal ALGO_UPDATE fail", zap.String("o", objText), zap.Error(err))
+		return
+	}
+	order := parseWsAlgoOrder(utils.MapValStr(obj))
+	order.LastUpdateTimestamp, _ = utils.SafeMapVal(msg, "T", int64(0))
+	order.Timestamp = order.LastUpdateTimestamp
+	order.Datetime = utils.ISO8601(order.Timestamp)
+	market := e.GetMarketById(order.Symbol, client.MarketType)
+	if market == nil {
+		log.Error("no market found for algo order", zap.String("symbol", order.Symbol))
+		return
+	}
+	order.Symbol = market.Symbol
+	banexg.WriteOutChan(e.Exchange, client.Prefix("orders"), order, false)
 }
 
 func (e *Binance) handleAccountConfigUpdate(client *banexg.WsClient, msg map[string]string) {
@@ -1,7 +1,6 @@
 package binance
 
 import (
-	"errors"
 	"fmt"
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
@@ -152,7 +151,8 @@ func (e *Binance) getExgWsParams(offset int, symbols []string, cvt func(m *banex
 	for i, sym := range symbols {
 		mar, err := e.GetMarket(sym)
 		if err != nil {
-			if strings.Contains(sym, "@") {
+			// raw stream names, e.g. btcusdt@depth, !bookTicker, !ticker@arr
+			if strings.Contains(sym, "@") || strings.HasPrefix(sym, "!") {
 				exgParams = append(exgParams, sym)
 				continue
 			}
@@ -278,6 +278,136 @@ func (e *Binance) prepareWatchTrades(isSub bool, symbols []string, params map[st
 	return chanKey, args, nil
 }
 
+/*
+WatchTickers
+watches 24hr rolling window tickers of symbols, all symbols of the market if empty.
+Pass ParamName as "bookTicker" for best bid/ask, or "miniTicker" for lighter 24hr tickers.
+Updates of different streams are merged into the same ticker.
+监听24小时滚动窗口ticker，symbols为空时监听市场所有品种。ParamName传bookTicker时监听最优挂单，
+传miniTicker时监听精简ticker。不同推送合并到同一ticker中
+
+:see: https://developers.binance.com/docs/binance-spot-api-docs/web-socket-streams#individual-symbol-ticker-streams
+:see: https://developers.binance.com/docs/binance-spot-api-docs/web-socket-streams#individual-symbol-book-ticker-streams
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/websocket-market-streams/Individual-Symbol-Ticker-Streams
+*/
+func (e *Binance) WatchTickers(symbols []string, params map[string]interface{}) (chan *banexg.Ticker, *errs.Error) {
+	chanKey, refKeys, args, err := e.prepareWatchTickers(true, symbols, params)
+	if err != nil {
+		return nil, err
+	}
+	create := func(cap int) chan *banexg.Ticker { return make(chan *banexg.Ticker, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKeys...)
+	e.DumpWS("WatchTickers", symbols)
+	return out, nil
+}
+
+func (e *Binance) UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error {
+	chanKey, refKeys, _, err := e.prepareWatchTickers(false, symbols, params)
+	if err != nil {
+		return err
+	}
+	e.DelWsChanRefs(chanKey, refKeys...)
+	return nil
+}
+
+func (e *Binance) prepareWatchTickers(isSub bool, symbols []string, params map[string]interface{}) (string, []string, map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType, _, err := e.LoadArgsMarketType(args, symbols...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	name := utils.PopMapVal(args, banexg.ParamName, "ticker")
+	if name != "ticker" && name != "miniTicker" && name != "bookTicker" {
+		return "", nil, nil, errs.NewMsg(errs.CodeParamInvalid, "ParamName must be ticker/miniTicker/bookTicker")
+	}
+	msgHash := marketType + "@" + name
+	client, err := e.GetWsClient(marketType, msgHash)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	refKeys := symbols
+	if len(symbols) == 0 {
+		symbols = []string{allTickersStream(name)}
+		refKeys = symbols
+	}
+	err = e.WriteWSMsg(client, 0, isSub, symbols, func(m *banexg.Market, _ int) string {
+		return m.LowercaseID + "@" + name
+	}, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	chanKey := client.Prefix(msgHash)
+	return chanKey, refKeys, args, nil
+}
+
+// allTickersStream the all market stream of ticker/miniTicker/bookTicker
+func allTickersStream(name string) string {
+	if name == "bookTicker" {
+		return "!bookTicker"
+	}
+	return "!" + name + "@arr"
+}
+
+/*
+WatchLiquidations
+watches forced liquidation orders of linear/inverse symbols, all symbols of the market if empty.
+Only the latest liquidation in 1000ms of each symbol is pushed.
+监听U本位/币本位合约的强平订单，symbols为空时监听市场所有品种。每个品种1秒内仅推送最新一笔
+
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/websocket-market-streams/Liquidation-Order-Streams
+:see: https://developers.binance.com/docs/derivatives/coin-margined-futures/websocket-market-streams/Liquidation-Order-Streams
+*/
+func (e *Binance) WatchLiquidations(symbols []string, params map[string]interface{}) (chan *banexg.Liquidation, *errs.Error) {
+	chanKey, refKeys, args, err := e.prepareWatchLiquidations(true, symbols, params)
+	if err != nil {
+		return nil, err
+	}
+	create := func(cap int) chan *banexg.Liquidation { return make(chan *banexg.Liquidation, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKeys...)
+	e.DumpWS("WatchLiquidations", symbols)
+	return out, nil
+}
+
+func (e *Binance) UnWatchLiquidations(symbols []string, params map[string]interface{}) *errs.Error {
+	chanKey, refKeys, _, err := e.prepareWatchLiquidations(false, symbols, params)
+	if err != nil {
+		return err
+	}
+	e.DelWsChanRefs(chanKey, refKeys...)
+	return nil
+}
+
+func (e *Binance) prepareWatchLiquidations(isSub bool, symbols []string, params map[string]interface{}) (string, []string, map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType, _, err := e.LoadArgsMarketType(args, symbols...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if !e.IsContract(marketType) {
+		return "", nil, nil, errs.NewMsg(errs.CodeUnsupportMarket, "WatchLiquidations support linear/inverse, current: %s", marketType)
+	}
+	msgHash := marketType + "@forceOrder"
+	client, err := e.GetWsClient(marketType, msgHash)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	refKeys := symbols
+	if len(symbols) == 0 {
+		symbols = []string{"!forceOrder@arr"}
+		refKeys = symbols
+	}
+	err = e.WriteWSMsg(client, 0, isSub, symbols, func(m *banexg.Market, _ int) string {
+		return m.LowercaseID + "@forceOrder"
+	}, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	chanKey := client.Prefix(msgHash)
+	return chanKey, refKeys, args, nil
+}
+
 /*
 WatchMyTrades
 
@@ -296,9 +426,31 @@ func (e *Binance) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyT
 	}
 	args := utils.SafeParams(params)
 	chanKey := client.Prefix("mytrades")
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
 	create := func(cap int) chan *banexg.MyTrade { return make(chan *banexg.MyTrade, cap) }
 	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
-	e.AddWsChanRefs(chanKey, "account")
+	e.AddWsChanRefs(chanKey, refKey)
+	return out, nil
+}
+
+/*
+WatchOrders
+watches full order snapshots on every update, including new, canceled, expired and triggered orders
+监听订单的每次更新，包括新建、取消、过期和触发的订单
+
+:param dict [params]: extra parameters specific to the exchange API endpoint
+*/
+func (e *Binance) WatchOrders(params map[string]interface{}) (chan *banexg.Order, *errs.Error) {
+	_, client, err := e.getAuthClient(params)
+	if err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	chanKey := client.Prefix("orders")
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
+	create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKey)
 	return out, nil
 }
 
@@ -392,7 +544,7 @@ func (e *Binance) handleOrderBook(client *banexg.WsClient, msg map[string]string
 	U, _ := utils.SafeMapVal(msg, "U", zero) // 上次推送至今新增的第一个id，应恰好等于上一次u+1
 	u, _ := utils.SafeMapVal(msg, "u", zero) // 上次推送至今新增的最后一个id
 	pu, _ := utils.SafeMapVal(msg, "pu", zero)
-	var err_ error
+	var reason string
 	if pu == 0 {
 		// spot
 		// 4. Drop any event where u is <= lastUpdateId in the snapshot
@@ -408,11 +560,13 @@ func (e *Binance) handleOrderBook(client *banexg.WsClient, msg map[string]string
 			}
 			if valid {
 				e.applyDepthMsg(msg, book)
-				if nonce < book.Nonce {
+				if book.IsCrossed() {
+					reason = banexg.OdBookResetCrossed
+				} else if nonce < book.Nonce {
 					banexg.WriteOutChan(e.Exchange, chanKey, book, true)
 				}
 			} else {
-				err_ = errors.New("out of date")
+				reason = banexg.OdBookResetGap
 			}
 		}
 	} else {
@@ -423,19 +577,26 @@ func (e *Binance) handleOrderBook(client *banexg.WsClient, msg map[string]string
 			// 6. While listening to the stream, each new event's pu should be equal to the previous event's u, otherwise initialize the process from step 3
 			if U <= nonce || pu == nonce {
 				e.applyDepthMsg(msg, book)
-				if nonce < book.Nonce {
+				if book.IsCrossed() {
+					reason = banexg.OdBookResetCrossed
+				} else if nonce < book.Nonce {
 					banexg.WriteOutChan(e.Exchange, chanKey, book, true)
 				}
 			} else {
-				err_ = errors.New("out of date")
+				reason = banexg.OdBookResetGap
 			}
 		}
 	}
-	if err_ != nil {
-		// order book is out of date, refresh from rest-api
-		log.Warn("ws order book out-of-date, refresh", urlZap, zap.String("code", symbol),
-			zap.Int64("cur", nonce), zap.Int64("latest", u))
-		book.Reset()
+	if reason != "" {
+		// order book is out of date or broken, refresh from rest-api
+		evt := &banexg.OdBookReset{Symbol: symbol, MarketType: market.Type, Reason: reason}
+		if reason == banexg.OdBookResetGap {
+			evt.Expect, evt.Actual = nonce, pu
+			if pu == 0 {
+				evt.Actual = U - 1
+			}
+		}
+		e.ResetOdBook(client, evt)
 		book.Cache = append(book.Cache, msg)
 		go refresh()
 	}
@@ -588,6 +749,111 @@ func parseMyTrade(msg map[string]string) banexg.MyTrade {
 	return res
 }
 
+/*
+parseWsOrder
+将executionReport/ORDER_TRADE_UPDATE转为Order，注意Symbol和fee.Currency未进行标准化
+*/
+func parseWsOrder(msg map[string]string) *banexg.Order {
+	zeroFlt := float64(0)
+	zeroInt := int64(0)
+	res := &banexg.Order{}
+	res.ID, _ = utils.SafeMapVal(msg, "i", "")
+	res.ClientOrderID, _ = utils.SafeMapVal(msg, "c", "")
+	odState, _ := utils.SafeMapVal(msg, "X", "")
+	res.Status = mapOrderStatus(odState)
+	if origID, _ := utils.SafeMapVal(msg, "C", ""); origID != "" {
+		// spot: c is the id of cancel request, C is the original client order id
+		res.ClientOrderID = origID
+	}
+	res.Symbol, _ = utils.SafeMapVal(msg, "s", "")
+	odType, _ := utils.SafeMapVal(msg, "o", "")
+	res.Type = strings.ToLower(odType)
+	if res.Type == banexg.OdTypeLimitMaker {
+		res.Type = banexg.OdTypeLimit
+		res.PostOnly = true
+	}
+	res.TimeInForce, _ = utils.SafeMapVal(msg, "f", "")
+	if res.TimeInForce == "GTX" {
+		res.TimeInForce = banexg.TimeInForcePO
+	}
+	if res.TimeInForce == banexg.TimeInForcePO {
+		res.PostOnly = true
+	}
+	side, _ := utils.SafeMapVal(msg, "S", "")
+	res.Side = strings.ToLower(side)
+	posSide, _ := utils.SafeMapVal(msg, "ps", "")
+	res.PositionSide = strings.ToLower(posSide)
+	res.Price, _ = utils.SafeMapVal(msg, "p", zeroFlt)
+	res.Amount, _ = utils.SafeMapVal(msg, "q", zeroFlt)
+	res.Filled, _ = utils.SafeMapVal(msg, "z", zeroFlt)
+	res.Remaining = max(0, res.Amount-res.Filled)
+	res.ReduceOnly, _ = utils.SafeMapVal(msg, "R", false)
+	if _, ok := msg["sp"]; ok {
+		// usd-m/coin-m
+		res.TriggerPrice, _ = utils.SafeMapVal(msg, "sp", zeroFlt)
+		res.Average, _ = utils.SafeMapVal(msg, "ap", zeroFlt)
+		res.Cost = res.Average * res.Filled
+	} else {
+		// spot: cumulative quote qty
+		res.TriggerPrice, _ = utils.SafeMapVal(msg, "P", zeroFlt)
+		res.Cost, _ = utils.SafeMapVal(msg, "Z", zeroFlt)
+		if res.Filled > 0 {
+			res.Average = res.Cost / res.Filled
+		}
+	}
+	res.StopPrice = res.TriggerPrice
+	res.LastUpdateTimestamp, _ = utils.SafeMapVal(msg, "T", zeroInt)
+	res.Timestamp, _ = utils.SafeMapVal(msg, "O", zeroInt)
+	if res.Timestamp == 0 {
+		res.Timestamp = res.LastUpdateTimestamp
+	}
+	res.Datetime = utils.ISO8601(res.Timestamp)
+	if lastAmt, _ := utils.SafeMapVal(msg, "l", zeroFlt); lastAmt > 0 {
+		res.LastTradeTimestamp = res.LastUpdateTimestamp
+	}
+	res.Info = utils.ToStdMap(msg)
+	return res
+}
+
+/*
+parseWsTicker
+将24hrTicker/24hrMiniTicker/bookTicker转为Ticker，未推送的字段为0，Symbol未进行标准化
+*/
+func parseWsTicker(msg map[string]string) *banexg.Ticker {
+	zeroFlt := float64(0)
+	res := &banexg.Ticker{}
+	res.Symbol, _ = utils.SafeMapVal(msg, "s", "")
+	res.TimeStamp, _ = utils.SafeMapVal(msg, "E", int64(0))
+	if res.TimeStamp == 0 {
+		res.TimeStamp, _ = utils.SafeMapVal(msg, "T", int64(0))
+	}
+	res.Bid, _ = utils.SafeMapVal(msg, "b", zeroFlt)
+	res.BidVolume, _ = utils.SafeMapVal(msg, "B", zeroFlt)
+	res.Ask, _ = utils.SafeMapVal(msg, "a", zeroFlt)
+	res.AskVolume, _ = utils.SafeMapVal(msg, "A", zeroFlt)
+	res.High, _ = utils.SafeMapVal(msg, "h", zeroFlt)
+	res.Low, _ = utils.SafeMapVal(msg, "l", zeroFlt)
+	res.Open, _ = utils.SafeMapVal(msg, "o", zeroFlt)
+	res.Close, _ = utils.SafeMapVal(msg, "c", zeroFlt)
+	res.Last = res.Close
+	res.Change, _ = utils.SafeMapVal(msg, "p", zeroFlt)
+	res.Percentage, _ = utils.SafeMapVal(msg, "P", zeroFlt)
+	res.Vwap, _ = utils.SafeMapVal(msg, "w", zeroFlt)
+	res.PreviousClose, _ = utils.SafeMapVal(msg, "x", zeroFlt)
+	res.BaseVolume, _ = utils.SafeMapVal(msg, "v", zeroFlt)
+	res.QuoteVolume, _ = utils.SafeMapVal(msg, "q", zeroFlt)
+	if _, ok := msg["bo"]; ok {
+		// option: b/a are implied volatility, bo/ao are best prices
+		res.Bid, _ = utils.SafeMapVal(msg, "bo", zeroFlt)
+		res.BidVolume, _ = utils.SafeMapVal(msg, "bq", zeroFlt)
+		res.Ask, _ = utils.SafeMapVal(msg, "ao", zeroFlt)
+		res.AskVolume, _ = utils.SafeMapVal(msg, "aq", zeroFlt)
+		res.MarkPrice, _ = utils.SafeMapVal(msg, "mp", zeroFlt)
+	}
+	res.Info = utils.ToStdMap(msg)
+	return res
+}
+
 func parsePubTrade(msg map[string]string) banexg.Trade {
 	var res = banexg.Trade{}
 	zeroFlt := float64(0)
@@ -603,3 +869,33 @@ func parsePubTrade(msg map[string]string) banexg.Trade {
 	res.Info = utils.ToStdMap(msg)
 	return res
 }
+
+// parseWsAlgoOrder parse the order object of ALGO_UPDATE, timestamps are set by the caller
+func parseWsAlgoOrder(msg map[string]string) *banexg.Order {
+	zeroFlt := float64(0)
+	res := &banexg.Order{}
+	algoId, _ := utils.SafeMapVal(msg, "aid", "")
+	res.ID = "algo:" + algoId
+	res.ClientOrderID, _ = utils.SafeMapVal(msg, "caid", "")
+	odState, _ := utils.SafeMapVal(msg, "X", "")
+	res.Status = mapAlgoOrderStatus(odState)
+	res.Symbol, _ = utils.SafeMapVal(msg, "s", "")
+	odType, _ := utils.SafeMapVal(msg, "o", "")
+	res.Type = strings.ToLower(odType)
+	res.TimeInForce, _ = utils.SafeMapVal(msg, "f", "")
+	side, _ := utils.SafeMapVal(msg, "S", "")
+	res.Side = strings.ToLower(side)
+	posSide, _ := utils.SafeMapVal(msg, "ps", "")
+	res.PositionSide = strings.ToLower(posSide)
+	res.Price, _ = utils.SafeMapVal(msg, "p", zeroFlt)
+	res.Amount, _ = utils.SafeMapVal(msg, "q", zeroFlt)
+	res.TriggerPrice, _ = utils.SafeMapVal(msg, "tp", zeroFlt)
+	res.StopPrice = res.TriggerPrice
+	res.Filled, _ = utils.SafeMapVal(msg, "aq", zeroFlt)
+	res.Average, _ = utils.SafeMapVal(msg, "ap", zeroFlt)
+	res.Cost = res.Average * res.Filled
+	res.Remaining = max(0, res.Amount-res.Filled)
+	res.ReduceOnly, _ = utils.SafeMapVal(msg, "R", false)
+	res.Info = utils.ToStdMap(msg)
+	return res
+}
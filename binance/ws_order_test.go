@@ -276,3 +276,155 @@ mainFor:
 		}
 	}
 }
+
+func TestParseWsOrder(t *testing.T) {
+	msg := map[string]string{
+		"s": "BTCUSDT", "c": "cancel-1", "C": "my-1", "S": "BUY", "o": "LIMIT", "f": "GTC",
+		"q": "0.2", "p": "30000", "P": "0", "X": "CANCELED", "i": "123", "l": "0",
+		"z": "0.1", "Z": "3000", "T": "1700000001000", "O": "1700000000000",
+	}
+	od := parseWsOrder(msg)
+	if od.ID != "123" || od.ClientOrderID != "my-1" || od.Status != banexg.OdStatusCanceled {
+		t.Fatalf("unexpected order: %+v", od)
+	}
+	if od.Average != 30000 || od.Remaining != 0.1 || od.Timestamp != 1700000000000 {
+		t.Fatalf("unexpected amounts: %+v", od)
+	}
+	msg = map[string]string{
+		"s": "BTCUSDT", "c": "my-2", "S": "SELL", "o": "STOP_MARKET", "f": "GTX", "q": "1",
+		"p": "0", "ap": "0", "sp": "29000", "X": "NEW", "i": "124", "z": "0", "T": "1700000002000",
+		"R": "true", "ps": "SHORT",
+	}
+	od = parseWsOrder(msg)
+	if od.Type != banexg.OdTypeStopMarket || od.TriggerPrice != 29000 || !od.PostOnly || !od.ReduceOnly {
+		t.Fatalf("unexpected future order: %+v", od)
+	}
+	if od.PositionSide != banexg.PosSideShort || od.Timestamp != 1700000002000 {
+		t.Fatalf("unexpected future order: %+v", od)
+	}
+}
+
+func TestParseWsAlgoUpdate(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new binance: %v", err)
+	}
+	mar := &banexg.Market{ID: "BNBUSDT", Symbol: "BNB/USDT:USDT", Type: banexg.MarketLinear}
+	exg.MarketsById = map[string][]*banexg.Market{mar.ID: {mar}}
+	client := &banexg.WsClient{AccName: "acc1", URL: "wss://fstream.binance.com", MarketType: banexg.MarketLinear}
+	create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+	out := banexg.GetWsOutChan(exg.Exchange, client.Prefix("orders"), create, nil)
+	items := []struct {
+		state  string
+		status string
+	}{
+		{"NEW", banexg.OdStatusOpen},
+		{"TRIGGERED", banexg.OdStatusFilled},
+		{"CANCELED", banexg.OdStatusCanceled},
+		{"EXPIRED", banexg.OdStatusExpired},
+	}
+	for _, it := range items {
+		msg := map[string]string{
+			"e": "ALGO_UPDATE", "T": "1750515742297", "E": "1750515742303",
+			"o": `{"caid":"my-algo","aid":2148719,"at":"CONDITIONAL","o":"TAKE_PROFIT","s":"BNBUSDT","S":"SELL",` +
+				`"ps":"BOTH","f":"GTC","q":"0.01","X":"` + it.state + `","ai":"","ap":"0","aq":"0","tp":"750",` +
+				`"p":"750","wt":"CONTRACT_PRICE","R":true}`,
+		}
+		exg.handleAlgoUpdate(client, msg)
+		var od *banexg.Order
+		select {
+		case od = <-out:
+		default:
+			t.Fatalf("no order for ALGO_UPDATE %s", it.state)
+		}
+		if od.ID != "algo:2148719" || od.ClientOrderID != "my-algo" || od.Symbol != mar.Symbol {
+			t.Fatalf("unexpected algo order: %+v", od)
+		}
+		if od.Status != it.status {
+			t.Errorf("ALGO_UPDATE %s status: %s, expect: %s", it.state, od.Status, it.status)
+		}
+		if od.Type != banexg.OdTypeTakeProfit || od.Side != banexg.OdSideSell || od.TriggerPrice != 750 ||
+			od.Amount != 0.01 || !od.ReduceOnly || od.LastUpdateTimestamp != 1750515742297 {
+			t.Fatalf("unexpected algo order: %+v", od)
+		}
+	}
+}
+
+func TestParseWsTicker(t *testing.T) {
+	msg := map[string]string{
+		"e": "24hrTicker", "E": "1700000000000", "s": "BTCUSDT", "p": "100", "P": "0.5",
+		"o": "20000", "h": "20500", "l": "19800", "c": "20100", "v": "12", "q": "241200",
+	}
+	res := parseWsTicker(msg)
+	if res.Symbol != "BTCUSDT" || res.Last != 20100 || res.Change != 100 || res.TimeStamp != 1700000000000 {
+		t.Fatalf("unexpected ticker: %+v", res)
+	}
+	msg = map[string]string{
+		"e": "24hrTicker", "E": "1700000000000", "s": "BTC-240329-70000-C", "c": "1500",
+		"b": "0.6", "a": "0.7", "bo": "1490", "ao": "1510", "bq": "2", "aq": "3", "mp": "1502",
+	}
+	res = parseWsTicker(msg)
+	if res.Bid != 1490 || res.Ask != 1510 || res.AskVolume != 3 || res.MarkPrice != 1502 {
+		t.Fatalf("unexpected option ticker: %+v", res)
+	}
+	cur := &banexg.Ticker{Symbol: "BTCUSDT", Last: 20100, High: 20500}
+	cur.Merge(&banexg.Ticker{Bid: 20099, Ask: 20101})
+	if cur.Last != 20100 || cur.Bid != 20099 || cur.High != 20500 {
+		t.Fatalf("unexpected merged ticker: %+v", cur)
+	}
+}
+
+func TestParseWsLiquidation(t *testing.T) {
+	msg := map[string]string{
+		"s": "BTCUSDT", "S": "SELL", "o": "LIMIT", "q": "0.014", "p": "9910", "ap": "9911",
+		"X": "FILLED", "z": "0.014", "T": "1568014460893",
+	}
+	res := parseWsLiquidation(msg, nil)
+	if res.Symbol != "BTCUSDT" || res.Side != banexg.OdSideSell || res.Price != 9911 || res.Amount != 0.014 {
+		t.Fatalf("unexpected liquidation: %+v", res)
+	}
+	if res.Timestamp != 1568014460893 {
+		t.Fatalf("unexpected liquidation time: %d", res.Timestamp)
+	}
+}
+
+func TestParseWsGreeks(t *testing.T) {
+	msg := map[string]string{
+		"e": "markPrice", "E": "1663685112308", "s": "BTC-220930-18000-C", "mp": "2000.0",
+		"d": "0.55937056", "t": "-4.31", "g": "0.00011", "v": "2.34", "vo": "0.5", "b": "0.48", "a": "0.52",
+	}
+	res := parseWsGreeks(msg)
+	if res.Symbol != "BTC-220930-18000-C" || res.Delta != 0.55937056 || res.Theta != -4.31 || res.Vega != 2.34 {
+		t.Fatalf("unexpected greeks: %+v", res)
+	}
+	if res.MarkIV != 0.5 || res.BidIV != 0.48 || res.AskIV != 0.52 || res.MarkPrice != 2000 || res.Timestamp != 1663685112308 {
+		t.Fatalf("unexpected greeks iv/price: %+v", res)
+	}
+}
+
+func TestWsSignPayload(t *testing.T) {
+	args := map[string]interface{}{
+		"symbol":    "BTCUSDT",
+		"timestamp": int64(1700000000000),
+		"quantity":  "0.01",
+		"apiKey":    "key",
+	}
+	text := wsSignPayload(args)
+	if text != "apiKey=key&quantity=0.01&symbol=BTCUSDT&timestamp=1700000000000" {
+		t.Fatalf("unexpected payload: %s", text)
+	}
+}
+
+func TestParseWsAllTickersStream(t *testing.T) {
+	exg := &Binance{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{Markets: banexg.MarketMap{}}}}
+	streams := []string{allTickersStream("bookTicker"), allTickersStream("miniTicker")}
+	res, err := exg.getExgWsParams(0, streams, func(m *banexg.Market, _ int) string {
+		return m.LowercaseID + "@bookTicker"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0] != "!bookTicker" || res[1] != "!miniTicker@arr" {
+		t.Fatalf("all market streams should be passed through: %v", res)
+	}
+}
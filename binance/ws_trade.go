@@ -0,0 +1,137 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+// rest api: [host, method] of websocket api, only spot and usd-m futures support trading by websocket
+var wsTradeMethods = map[string][2]string{
+	MethodPrivatePostOrder:       {WssApi, "order.place"},
+	MethodPrivateDeleteOrder:     {WssApi, "order.cancel"},
+	MethodFapiPrivatePostOrder:   {WssFApi, "order.place"},
+	MethodFapiPrivateDeleteOrder: {WssFApi, "order.cancel"},
+	MethodFapiPrivatePutOrder:    {WssFApi, "order.modify"},
+}
+
+/*
+requestTrade
+send the order request by websocket api when ParamWsTrade/OptWsTrade is set, fallback to rest when the socket is down.
+every request is signed like rest api, so hmac/rsa/ed25519 keys are all supported without session.logon.
+the result of websocket api is the same as rest, the returned HttpRes.Content can be parsed by parseOrder
+设置ParamWsTrade/OptWsTrade时通过websocket接口发送订单请求，连接不可用时回退到rest。
+每个请求都像rest一样签名，无需session.logon即可支持hmac/rsa/ed25519密钥
+:see: https://developers.binance.com/docs/binance-spot-api-docs/websocket-api/trading-requests
+:see: https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/websocket-api
+*/
+func (e *Binance) requestTrade(method string, args map[string]interface{}, tryNum int) *banexg.HttpRes {
+	if e.IsWsTrade(args) {
+		if item, ok := wsTradeMethods[method]; ok {
+			rsp := e.wsRequestTrade(method, item[0], item[1], args)
+			if rsp.Error == nil || rsp.Error.Code != errs.CodeConnectFail {
+				return rsp
+			}
+			log.Warn("binance ws trade unavailable, use rest", zap.String("api", method), zap.String("err", rsp.Error.Short()))
+		}
+	}
+	return e.RequestApiRetry(context.Background(), method, args, tryNum)
+}
+
+func (e *Binance) wsRequestTrade(api, hostKey, wsMethod string, params map[string]interface{}) *banexg.HttpRes {
+	args := utils.SafeParams(params)
+	ctx := banexg.ParamsCtx(args)
+	delete(args, banexg.ParamCtx)
+	delete(args, banexg.ParamNoCache)
+	accName := e.PopAccName(args)
+	res := &banexg.HttpRes{AccName: accName}
+	if res.Error = e.CheckRiskyAllowed(e.Apis[api], accName); res.Error != nil {
+		return res
+	}
+	accName, creds, err := e.GetAccountCreds(accName)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	wsUrl := e.GetHost(hostKey)
+	if wsUrl == "" {
+		res.Error = errs.NewMsg(errs.CodeConnectFail, "ws host missing: %s", hostKey)
+		return res
+	}
+	client, err := e.GetClient(wsUrl, hostKey, accName)
+	if err != nil {
+		res.Error = errs.NewFull(errs.CodeConnectFail, err, "binance ws trade connect fail")
+		return res
+	}
+	args["apiKey"] = creds.ApiKey
+	args["timestamp"] = e.Nonce()
+	if e.RecvWindow > 0 {
+		args["recvWindow"] = e.RecvWindow
+	}
+	for k, v := range args {
+		if val, ok := v.(float64); ok {
+			args[k] = strconv.FormatFloat(val, 'f', -1, 64)
+		}
+	}
+	args["signature"], err = signQuery(creds.Secret, wsSignPayload(args))
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	id := banexg.NewWsReqID()
+	msg := map[string]interface{}{
+		"id":     id,
+		"method": wsMethod,
+		"params": args,
+	}
+	rsp, err := client.Request(ctx, id, msg)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	res.Url = client.URL
+	var data struct {
+		Status int             `json:"status"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		} `json:"error"`
+	}
+	if err_ := utils.UnmarshalString(rsp.Text, &data, utils.JsonNumDefault); err_ != nil {
+		res.Error = errs.New(errs.CodeUnmarshalFail, err_)
+		return res
+	}
+	res.Status = data.Status
+	if data.Error != nil {
+		res.Content = rsp.Text
+		res.Error = errs.NewMsg(data.Status, "%s: %s %s", accName, wsMethod, data.Error.Msg)
+		res.Error.BizCode = data.Error.Code
+		if code := mapBizErr(data.Error.Code, data.Error.Msg); code != 0 {
+			res.Error.Code = code
+		}
+		return res
+	}
+	res.Content = string(data.Result)
+	return res
+}
+
+// wsSignPayload params sorted by key, joined like a query string
+func wsSignPayload(args map[string]interface{}) string {
+	keys := utils.KeysOfMap(args)
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, args[k]))
+	}
+	return strings.Join(parts, "&")
+}
@@ -176,6 +176,9 @@ func (e *Exchange) Init() *errs.Error {
 	utils.SetFieldBy(&e.DebugAPI, e.Options, OptDebugApi, false)
 	utils.SetFieldBy(&e.WsBatchSize, e.Options, OptDumpBatchSize, 1000)
 	utils.SetFieldBy(&e.WsTimeout, e.Options, OptWsTimeout, 15000)
+	utils.SetFieldBy(&e.WsTrade, e.Options, OptWsTrade, false)
+	e.stopSyncTime()
+	e.startSyncTime(utils.GetMapVal(e.Options, OptSyncTimeSecs, 0))
 	e.CurrByCodeLock.Lock()
 	e.CurrByIdLock.Lock()
 	e.CurrCodeMap = DefCurrCodeMap
@@ -186,6 +189,7 @@ func (e *Exchange) Init() *errs.Error {
 	e.WsChanRefs = map[string]map[string]struct{}{}
 	e.OrderBooks = map[string]*OrderBook{}
 	e.MarkPrices = map[string]map[string]float64{}
+	e.Tickers = map[string]*Ticker{}
 	e.KeyTimeStamps = map[string]int64{}
 	e.ExgInfo.Min1mHole = 1
 	e.CurrByIdLock.Unlock()
@@ -259,7 +263,7 @@ func (e *Exchange) SafeCurrencyCode(currId string) string {
 	return e.SafeCurrency(currId).Code
 }
 
-func doLoadMarkets(e *Exchange, params map[string]interface{}) {
+func doLoadMarkets(e *Exchange, wait chan interface{}, params map[string]interface{}) {
 	var markets MarketMap
 	var currencies CurrencyMap
 	var err *errs.Error
@@ -267,7 +271,7 @@ func doLoadMarkets(e *Exchange, params map[string]interface{}) {
 	if markets == nil {
 		markets, currencies, err = e.fetchMarketsCurrs(params)
 		if err != nil {
-			e.MarketsWait <- err
+			wait <- err
 			return
 		}
 	}
@@ -275,7 +279,7 @@ func doLoadMarkets(e *Exchange, params map[string]interface{}) {
 	e.setMarkets(markets)
 	// 更新currencies
 	e.setCurrencies(currencies, markets)
-	e.MarketsWait <- markets
+	wait <- markets
 }
 
 func (e *Exchange) fetchMarketsCurrs(params map[string]interface{}) (MarketMap, CurrencyMap, *errs.Error) {
@@ -502,12 +506,27 @@ func (e *Exchange) getAllCareMarkets() []string {
 
 func (e *Exchange) LoadMarkets(reload bool, params map[string]interface{}) (MarketMap, *errs.Error) {
 	if reload || e.Markets == nil {
-		if e.MarketsWait == nil {
-			e.MarketsWait = make(chan interface{})
+		wait := e.MarketsWait
+		if wait == nil {
+			wait = make(chan interface{})
+			e.MarketsWait = wait
 			log.Debug("try load markets", zap.Int64("stamp", e.MilliSeconds()))
-			go doLoadMarkets(e, params)
+			go doLoadMarkets(e, wait, params)
+		}
+		ctx := ParamsCtx(params)
+		var result interface{}
+		select {
+		case result = <-wait:
+		case <-ctx.Done():
+			// markets are still saved by the loading goroutine, receive its result so it won't block forever
+			if e.MarketsWait == wait {
+				e.MarketsWait = nil
+			}
+			go func() {
+				<-wait
+			}()
+			return nil, CtxError(ctx)
 		}
-		result := <-e.MarketsWait
 		e.MarketsWait = nil
 		if mars, ok := result.(MarketMap); ok && mars != nil {
 			return mars, nil
@@ -770,6 +789,54 @@ func (e *Exchange) FetchIncomeHistory(inType string, symbol string, since int64,
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) FetchMyTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*MyTrade, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) Transfer(code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*Transfer, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchTransfers(code string, since int64, limit int, params map[string]interface{}) ([]*Transfer, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchDepositAddress(code string, params map[string]interface{}) (*DepositAddress, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchDeposits(code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchWithdrawals(code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) Withdraw(code string, amount float64, address, tag, network string, params map[string]interface{}) (*Transaction, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) Borrow(code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) Repay(code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchBorrowRate(code string, params map[string]interface{}) (*BorrowRate, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchBorrowRates(codes []string, params map[string]interface{}) ([]*BorrowRate, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchBorrowInterest(code, symbol string, since int64, limit int, params map[string]interface{}) ([]*BorrowInterest, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) FetchFundingRate(symbol string, params map[string]interface{}) (*FundingRateCur, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -782,6 +849,22 @@ func (e *Exchange) FetchFundingRateHistory(symbol string, since int64, limit int
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) FetchOpenInterest(symbol string, params map[string]interface{}) (*OpenInterest, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchOpenInterestHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*OpenInterest, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchLongShortRatioHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*LongShortRatio, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) FetchGreeks(symbols []string, params map[string]interface{}) ([]*Greeks, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) FetchLastPrices(symbols []string, params map[string]interface{}) ([]*LastPrice, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -790,6 +873,10 @@ func (e *Exchange) FetchOrderBook(symbol string, limit int, params map[string]in
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*Trade, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) CreateOrder(symbol, odType, side string, amount float64, price float64, params map[string]interface{}) (*Order, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -806,6 +893,26 @@ func (e *Exchange) SetLeverage(leverage float64, symbol string, params map[strin
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) SetMarginMode(mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) SetPositionMode(hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) AddMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) ReduceMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) LoadLeverageBrackets(reload bool, params map[string]interface{}) *errs.Error {
 	return errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -867,10 +974,38 @@ func (e *Exchange) UnWatchTrades(symbols []string, params map[string]interface{}
 	return errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) WatchTickers(symbols []string, params map[string]interface{}) (chan *Ticker, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error {
+	return errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) WatchLiquidations(symbols []string, params map[string]interface{}) (chan *Liquidation, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) UnWatchLiquidations(symbols []string, params map[string]interface{}) *errs.Error {
+	return errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) WatchGreeks(symbols []string, params map[string]interface{}) (chan *Greeks, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
+func (e *Exchange) UnWatchGreeks(symbols []string, params map[string]interface{}) *errs.Error {
+	return errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) WatchMyTrades(params map[string]interface{}) (chan *MyTrade, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) WatchOrders(params map[string]interface{}) (chan *Order, *errs.Error) {
+	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) WatchBalance(params map[string]interface{}) (chan *Balances, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -879,6 +1014,10 @@ func (e *Exchange) WatchPositions(params map[string]interface{}) (chan []*Positi
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
 
+func (e *Exchange) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	return 0, errs.NewMsg(errs.CodeNotImplement, "method not implement")
+}
+
 func (e *Exchange) WatchAccountConfig(params map[string]interface{}) (chan *AccountConfig, *errs.Error) {
 	return nil, errs.NewMsg(errs.CodeNotImplement, "method not implement")
 }
@@ -896,6 +1035,10 @@ func (e *Exchange) CloseWsFile() {
 func (e *Exchange) SetDump(path string) *errs.Error {
 	if path == "" {
 		if e.WsEncoder != nil {
+			e.dumpLock.Lock()
+			defer e.dumpLock.Unlock()
+			e.wsCacheLock.Lock()
+			defer e.wsCacheLock.Unlock()
 			if len(e.WsCache) > 0 {
 				err_ := e.WsEncoder.Encode(e.WsCache)
 				if err_ != nil {
@@ -915,7 +1058,8 @@ func (e *Exchange) SetDump(path string) *errs.Error {
 	if e.WsDecoder != nil {
 		return errs.NewMsg(errs.CodeRunTime, "cannot dump in replay mode")
 	}
-	// dump websocket messages for replay later
+	// dump websocket messages and rest responses for replay later
+	path = e.dumpFilePath(path)
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return errs.New(errs.CodeIOWriteFail, err)
@@ -937,6 +1081,10 @@ func (e *Exchange) SetReplay(path string) *errs.Error {
 			}
 			e.WsReader = nil
 			e.CloseWsFile()
+			e.apiReplayLock.Lock()
+			e.ApiReplays = nil
+			e.apiReplayLock.Unlock()
+			e.NetDisable = e.replayNetOff
 		}
 		return nil
 	}
@@ -944,6 +1092,11 @@ func (e *Exchange) SetReplay(path string) *errs.Error {
 	if e.WsEncoder != nil {
 		return errs.NewMsg(errs.CodeRunTime, "cannot set replay in dump mode !")
 	}
+	path = e.dumpFilePath(path)
+	apiLogs, firstMS, err2 := loadApiReplays(path)
+	if err2 != nil {
+		return err2
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return errs.New(errs.CodeIOReadFail, err)
@@ -954,7 +1107,17 @@ func (e *Exchange) SetReplay(path string) *errs.Error {
 	if err != nil {
 		return errs.New(errs.CodeIOReadFail, err)
 	}
+	if e.WsDecoder == nil {
+		e.replayNetOff = e.NetDisable
+	}
 	e.WsDecoder = gob.NewDecoder(e.WsReader)
+	e.apiReplayLock.Lock()
+	e.ApiReplays = apiLogs
+	e.apiReplayLock.Unlock()
+	// replay never touches network, and clock starts from the first record
+	e.NetDisable = true
+	e.WsNextMS = 0
+	e.WsReplayTo = firstMS
 	return nil
 }
 
@@ -972,6 +1135,8 @@ func (e *Exchange) DumpWS(name string, data interface{}) {
 		TimeMS:  bntp.UTCStamp(),
 		Content: dataStr,
 	}
+	e.dumpLock.Lock()
+	defer e.dumpLock.Unlock()
 	e.WsCache = append(e.WsCache, item)
 	if len(e.WsCache) > e.WsBatchSize {
 		rows := e.WsCache
@@ -1013,10 +1178,12 @@ func (e *Exchange) ReplayOne() *errs.Error {
 	e.WsCache = e.WsCache[1:]
 	e.WsNextMS = 0
 	e.WsReplayTo = item.TimeMS
-	handle, ok := e.WsReplayFn[item.Name]
+	handle, ok := e.getReplayFn(item.Name)
 	if !ok {
 		log.Warn("no ws replay handle found", zap.String("for", item.Name), zap.String("exg", e.Name))
 		return nil
+	} else if handle == nil {
+		return nil
 	}
 	return handle(item)
 }
@@ -1032,10 +1199,12 @@ func (e *Exchange) ReplayAll() *errs.Error {
 			oldNum, _ := counts[item.Name]
 			counts[item.Name] = oldNum + 1
 			e.WsReplayTo = item.TimeMS
-			handle, ok := e.WsReplayFn[item.Name]
+			handle, ok := e.getReplayFn(item.Name)
 			if !ok {
 				bads[item.Name] = true
 				continue
+			} else if handle == nil {
+				continue
 			}
 			err := handle(item)
 			if err != nil {
@@ -1060,6 +1229,43 @@ func (e *Exchange) SetOnWsChan(cb FuncOnWsChan) {
 	e.OnWsChan = cb
 }
 
+func (e *Exchange) SetOnOdBookReset(cb FuncOnOdBookReset) {
+	e.OnOdBookReset = cb
+}
+
+/*
+SetWsReConHook
+register a listener called after OnWsReCon when any ws connection is reconnected, nil cb to remove.
+OnWsReCon is used by exchanges to restore subscriptions, hooks are for callers like OrderTracker.
+注册ws连接重连后在OnWsReCon之后调用的监听，cb为nil时移除。OnWsReCon供交易所恢复订阅，钩子供OrderTracker等调用方使用
+*/
+func (e *Exchange) SetWsReConHook(key string, cb FuncOnWsReCon) {
+	e.reConLock.Lock()
+	defer e.reConLock.Unlock()
+	if cb == nil {
+		delete(e.reConHooks, key)
+		return
+	}
+	if e.reConHooks == nil {
+		e.reConHooks = make(map[string]FuncOnWsReCon)
+	}
+	e.reConHooks[key] = cb
+}
+
+func (e *Exchange) runWsReConHooks(client *WsClient, connID int) {
+	e.reConLock.Lock()
+	hooks := make([]FuncOnWsReCon, 0, len(e.reConHooks))
+	for _, cb := range e.reConHooks {
+		hooks = append(hooks, cb)
+	}
+	e.reConLock.Unlock()
+	for _, cb := range hooks {
+		if err := cb(client, connID); err != nil {
+			log.Warn("ws reconnect hook fail", zap.String("url", client.URL), zap.Error(err))
+		}
+	}
+}
+
 func (e *Exchange) CalculateFee(symbol, odType, side string, amount float64, price float64, isMaker bool,
 	params map[string]interface{}) (*Fee, *errs.Error) {
 	if odType == OdTypeMarket && isMaker {
@@ -1162,7 +1368,7 @@ func (e *Exchange) MilliSeconds() int64 {
 }
 
 func (e *Exchange) Nonce() int64 {
-	return e.MilliSeconds() - e.TimeDelay
+	return e.MilliSeconds() - e.GetTimeDelay()
 }
 
 func (e *Exchange) setReqHeaders(head *http.Header) {
@@ -1182,19 +1388,28 @@ func (e *Exchange) setReqHeaders(head *http.Header) {
 RequestApi
 Request exchange API without checking cache
 Concurrency control: Same host, default concurrent 3 times at the same time
+Rate control: fixed interval by RateLimit, and token buckets by WeightRules synced from response headers
 
 请求交易所API，不检查缓存
 并发控制：同一个host，默认同时并发3
+速率控制：按RateLimit的固定间隔，以及按WeightRules从响应头同步的令牌桶
 */
 func (e *Exchange) RequestApi(ctx context.Context, cacheKey string, api *Entry, params map[string]interface{}, cache, debug bool) *HttpRes {
 	if e.NetDisable {
 		err := errs.NewMsg(errs.CodeNetDisable, fmt.Sprintf("net disabled for %v, fail: %v", e.Name, api.Url))
 		return &HttpRes{Error: err}
 	}
+	if err := CtxError(ctx); err != nil {
+		return &HttpRes{Url: api.Url, Error: err}
+	}
 	// Traffic control, block if concurrency is full
 	// 流量控制，如果并发已满则阻塞
 	sem := GetHostFlowChan(api.RawHost)
-	sem <- struct{}{}
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return &HttpRes{Error: CtxError(ctx)}
+	}
 	defer func() {
 		<-sem
 	}()
@@ -1202,15 +1417,27 @@ func (e *Exchange) RequestApi(ctx context.Context, cacheKey string, api *Entry,
 	// 检查是否出现429或418需要等待
 	waitMS := GetHostRetryWait(api.RawHost, true)
 	if waitMS > 0 {
-		time.Sleep(time.Millisecond * time.Duration(waitMS))
+		if err := SleepCtx(ctx, time.Millisecond*time.Duration(waitMS)); err != nil {
+			return &HttpRes{Error: err}
+		}
 	}
+	var accKey string
 	if e.EnableRateLimit == BoolTrue {
+		cost := e.CalcRateLimiterCost(api, params)
+		// Weight limits reported by exchange, block before reaching
+		// 交易所返回的权重限制，在达到前阻塞
+		accKey = e.rateAccKey(params)
+		if err := e.waitWeightLimits(ctx, api, cost, accKey); err != nil {
+			return &HttpRes{Error: err}
+		}
 		e.rateM.Lock()
 		elapsed := e.MilliSeconds() - e.lastRequestMS
-		cost := e.CalcRateLimiterCost(api, params)
 		sleepMS := int64(math.Round(float64(e.RateLimit) * cost))
 		if elapsed < sleepMS {
-			time.Sleep(time.Duration(sleepMS-elapsed) * time.Millisecond)
+			if err := SleepCtx(ctx, time.Duration(sleepMS-elapsed)*time.Millisecond); err != nil {
+				e.rateM.Unlock()
+				return &HttpRes{Error: err}
+			}
 		}
 		e.lastRequestMS = e.MilliSeconds()
 		e.rateM.Unlock()
@@ -1241,9 +1468,15 @@ func (e *Exchange) RequestApi(ctx context.Context, cacheKey string, api *Entry,
 	}
 	rsp, err := e.HttpClient.Do(req)
 	if err != nil {
+		if ctxErr := CtxError(ctx); ctxErr != nil {
+			return &HttpRes{Url: sign.Url, AccName: sign.AccName, Error: ctxErr}
+		}
 		return &HttpRes{Url: sign.Url, AccName: sign.AccName, Error: errs.New(errs.CodeNetFail, err)}
 	}
 	defer rsp.Body.Close()
+	if e.EnableRateLimit == BoolTrue {
+		e.syncWeightLimits(api, accKey, rsp.Header)
+	}
 	var result = HttpRes{Url: sign.Url, AccName: sign.AccName, Status: rsp.StatusCode, Headers: rsp.Header,
 		CacheKey: cacheKey}
 	rspData, err := io.ReadAll(rsp.Body)
@@ -1276,6 +1509,12 @@ func (e *Exchange) RequestApi(ctx context.Context, cacheKey string, api *Entry,
 					}
 				}
 			}
+			if result.Error.BizCode != 0 && e.MapBizErr != nil {
+				bizMsg, _ := resData["msg"].(string)
+				if code := e.MapBizErr(result.Error.BizCode, bizMsg); code != 0 {
+					result.Error.Code = code
+				}
+			}
 		}
 		if result.Status == 429 || result.Status == 418 {
 			waitStr := rsp.Header.Get("Retry-After")
@@ -1330,13 +1569,14 @@ func (e *Exchange) cacheApiRes(api *Entry, res *HttpRes) {
 	}
 }
 
-func (e *Exchange) RequestApiRetryAdv(ctx context.Context, endpoint string, params map[string]interface{}, retryNum int, readCache, writeCache bool) *HttpRes {
+func (e *Exchange) requestApiRetryAdv(ctx context.Context, endpoint string, params map[string]interface{}, retryNum int, readCache, writeCache bool) *HttpRes {
 	api, ok := e.Apis[endpoint]
 	if !ok {
 		log.Panic("invalid api", zap.String("endpoint", endpoint))
 		return &HttpRes{Error: errs.NewMsg(errs.CodeApiNotSupport, "api not support")}
 	}
 	debug := utils.PopMapVal(params, ParamDebug, false)
+	ctx, params = popParamCtx(ctx, params)
 	// 检查是否有缓存
 	var cacheKey string
 	if readCache && api.CacheSecs > 0 {
@@ -1375,14 +1615,25 @@ func (e *Exchange) RequestApiRetryAdv(ctx context.Context, endpoint string, para
 	tryNum := retryNum + 1
 	var rsp *HttpRes
 	var sleep = 0
+	var resynced = false
 	for i := 0; i < tryNum; i++ {
 		if sleep > 0 {
-			time.Sleep(time.Second * time.Duration(sleep))
+			if err := SleepCtx(ctx, time.Second*time.Duration(sleep)); err != nil {
+				rsp = &HttpRes{Url: api.Url, Error: err}
+				break
+			}
 			sleep = 0
 		}
 		rsp = e.RequestApi(ctx, cacheKey, api, params, writeCache, debug)
 		if rsp.Error != nil {
-			if rsp.Error.Code == errs.CodeNetFail {
+			if rsp.Error.Code == errs.CodeCancel || rsp.Error.Code == errs.CodeTimeout {
+				break
+			} else if !resynced && e.ResyncOnNonce(ctx, rsp.Error) {
+				// 时间戳超出recvWindow，同步服务器时间后重新签名，额外重试一次
+				resynced = true
+				tryNum += 1
+				continue
+			} else if rsp.Error.Code == errs.CodeNetFail {
 				// 网络错误等待3s重试
 				sleep = 3
 				log.Warn(fmt.Sprintf("net fail, retry after: %v", sleep))
@@ -1584,6 +1835,19 @@ func (e *Exchange) PopAccName(params map[string]interface{}) string {
 	return utils.PopMapVal(params, ParamAccount, e.DefAccName)
 }
 
+/*
+IsWsTrade
+pop ParamWsTrade from params, return whether the order request should be sent by websocket trading api.
+OptWsTrade is used when ParamWsTrade is absent.
+从params中弹出ParamWsTrade，返回订单请求是否应通过websocket交易接口发送，未指定时使用OptWsTrade
+*/
+func (e *Exchange) IsWsTrade(params map[string]interface{}) bool {
+	if params == nil {
+		return e.WsTrade
+	}
+	return utils.PopMapVal(params, ParamWsTrade, e.WsTrade)
+}
+
 func (e *Exchange) GetAccName(params map[string]interface{}) string {
 	if params == nil {
 		return e.DefAccName
@@ -1630,6 +1894,128 @@ func (e *Exchange) GetAccountCreds(id string) (string, *Credential, *errs.Error)
 	return acc.Name, nil, errs.NewMsg(errs.CodeCredsRequired, "Creds not exits")
 }
 
+/*
+GetSubAccId
+exchange id of sub account for transfer. name is looked up in Accounts and OptSubAccId of its Creds is used,
+otherwise name is taken as the exchange id itself. Empty name means the current account from params.
+子账户在交易所的id，用于划转。name在Accounts中存在时使用其Creds中的OptSubAccId，否则name本身即交易所id。
+name为空时取params中的当前账户
+*/
+func (e *Exchange) GetSubAccId(name string, params map[string]interface{}) string {
+	if name == "" {
+		acc, err := e.GetAccount(e.GetAccName(params))
+		if err != nil {
+			return ""
+		}
+		return utils.GetMapVal(acc.Data, OptSubAccId, "")
+	}
+	if acc, ok := e.Accounts[name]; ok {
+		if id := utils.GetMapVal(acc.Data, OptSubAccId, ""); id != "" {
+			return id
+		}
+	}
+	return name
+}
+
+/*
+GetNetwork
+chain network of currency matched by ID or Network, nil if currencies are not loaded or not found
+按ID或Network查找币种的链网络，币种未加载或未找到时返回nil
+*/
+func (e *Exchange) GetNetwork(code, network string) *ChainNetwork {
+	e.CurrByCodeLock.Lock()
+	curr, ok := e.CurrenciesByCode[code]
+	e.CurrByCodeLock.Unlock()
+	if !ok || network == "" {
+		return nil
+	}
+	for _, net := range curr.Networks {
+		if strings.EqualFold(net.ID, network) || strings.EqualFold(net.Network, network) {
+			return net
+		}
+	}
+	return nil
+}
+
+/*
+CheckWithdraw
+check network and amount of withdrawal with loaded Currency and Currency.Networks, skipped when unknown.
+The permission of api key is checked by FetchAccountAccess with ParamAccount/ParamCtx in params.
+使用已加载的Currency和Currency.Networks检查提现的网络和数量，未知时跳过。
+使用params中的ParamAccount/ParamCtx调用FetchAccountAccess检查api key的提现权限
+*/
+func (e *Exchange) CheckWithdraw(code, network string, amount float64, params map[string]interface{}) *errs.Error {
+	if amount <= 0 {
+		return errs.NewMsg(errs.CodeParamInvalid, "invalid withdraw amount: %v", amount)
+	}
+	if err := e.checkWithdrawNetwork(code, network, amount); err != nil {
+		return err
+	}
+	accArgs := map[string]interface{}{}
+	for _, key := range []string{ParamAccount, ParamCtx} {
+		if val, ok := params[key]; ok {
+			accArgs[key] = val
+		}
+	}
+	acc, err := e.self().FetchAccountAccess(accArgs)
+	if err != nil {
+		log.Warn("fetch account access for withdraw fail", zap.String("exg", e.Name), zap.Error(err))
+	} else if acc != nil && acc.WithdrawKnown && !acc.WithdrawAllowed {
+		return errs.NewMsg(errs.CodeForbidden, "withdraw is not allowed for api key")
+	}
+	return nil
+}
+
+func (e *Exchange) checkWithdrawNetwork(code, network string, amount float64) *errs.Error {
+	e.CurrByCodeLock.Lock()
+	curr, ok := e.CurrenciesByCode[code]
+	e.CurrByCodeLock.Unlock()
+	if !ok || len(curr.Networks) == 0 {
+		return nil
+	}
+	if !curr.Withdraw {
+		return errs.NewMsg(errs.CodeNotSupport, "withdraw of %s is disabled", code)
+	}
+	if network == "" {
+		return nil
+	}
+	net := e.GetNetwork(code, network)
+	if net == nil {
+		return errs.NewMsg(errs.CodeParamInvalid, "network %s not found for %s", network, code)
+	}
+	if !net.Withdraw {
+		return errs.NewMsg(errs.CodeNotSupport, "withdraw of %s on %s is disabled", code, network)
+	}
+	if net.Limits != nil && net.Limits.Withdraw != nil && net.Limits.Withdraw.Min > 0 &&
+		amount < net.Limits.Withdraw.Min {
+		return errs.NewMsg(errs.CodeParamInvalid, "withdraw amount %v below min %v for %s on %s", amount,
+			net.Limits.Withdraw.Min, code, network)
+	}
+	return nil
+}
+
+/*
+GetLoanMarginMode
+pop ParamMarginMode from params for Borrow/Repay, default isolated if symbol is given, otherwise cross
+从params中取出借还币的保证金模式，默认有symbol时为逐仓，否则为全仓
+*/
+func GetLoanMarginMode(symbol string, params map[string]interface{}) (string, *errs.Error) {
+	mode := utils.PopMapVal(params, ParamMarginMode, "")
+	if mode == "" {
+		if symbol != "" {
+			return MarginIsolated, nil
+		}
+		return MarginCross, nil
+	}
+	if mode != MarginCross && mode != MarginIsolated {
+		return "", errs.NewMsg(errs.CodeParamInvalid, "invalid margin mode: %s", mode)
+	}
+	if mode == MarginIsolated && symbol == "" {
+		return "", errs.NewMsg(errs.CodeParamRequired, "symbol is required for isolated margin")
+	}
+	return mode, nil
+}
+
 // CheckRiskyAllowed 检查当前账户是否允许执行危险操作
 // 如果api.Risky为true且账户NoTrade为true，返回错误
 func (e *Exchange) CheckRiskyAllowed(api *Entry, accID string) *errs.Error {
@@ -1733,6 +2119,8 @@ func (e *Exchange) GetExg() *Exchange {
 }
 
 func (e *Exchange) Close() *errs.Error {
+	e.stopCancelAfters()
+	e.stopSyncTime()
 	if e.MarketsWait != nil {
 		close(e.MarketsWait)
 		e.MarketsWait = nil
@@ -1745,6 +2133,12 @@ func (e *Exchange) Close() *errs.Error {
 		}
 		delete(e.WsOutChans, key)
 	}
+	for _, taps := range e.WsChanTaps {
+		for _, tap := range taps {
+			reflect.ValueOf(tap).Close()
+		}
+	}
+	e.WsChanTaps = nil
 	e.lockOutChan.Unlock()
 	for _, client := range e.WSClients {
 		client.Close()
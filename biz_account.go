@@ -183,3 +183,19 @@ func ParseBool(val interface{}) (bool, bool) {
 	}
 	return false, false
 }
+
+// FilterTransfers keep transfers matching code and account types, empty value matches all
+func FilterTransfers(items []*Transfer, code, fromAccount, toAccount string) []*Transfer {
+	if code == "" && fromAccount == "" && toAccount == "" {
+		return items
+	}
+	res := make([]*Transfer, 0, len(items))
+	for _, t := range items {
+		if code != "" && t.Code != code || fromAccount != "" && t.FromAccount != fromAccount ||
+			toAccount != "" && t.ToAccount != toAccount {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package banexg
+
+import (
+	"github.com/banbox/banexg/errs"
+)
+
+/*
+Batch order operations. The methods of Exchange emulate them by calling the single order api one by one,
+adapters override them with native batch endpoints when available.
+批量订单操作。Exchange中的实现通过逐个调用单订单接口模拟，交易所适配器在支持时使用原生批量接口覆盖
+*/
+
+/*
+CreateOrders
+Create orders one by one, the result has the same order as orders.
+逐个创建订单，结果与orders顺序一致
+*/
+func (e *Exchange) CreateOrders(orders []*OrderArgs, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	exg := e.self()
+	ctx := ParamsCtx(params)
+	result := make([]*OrderRes, len(orders))
+	for i, od := range orders {
+		res := &OrderRes{}
+		result[i] = res
+		if res.Err = CtxError(ctx); res.Err != nil {
+			continue
+		}
+		res.Order, res.Err = exg.CreateOrder(od.Symbol, od.OdType, od.Side, od.Amount, od.Price,
+			MergeOrderParams(params, od.Params))
+	}
+	return result, nil
+}
+
+/*
+CancelOrders
+Cancel orders of symbol one by one, the result has the same order as ids.
+逐个取消symbol的订单，结果与ids顺序一致
+*/
+func (e *Exchange) CancelOrders(ids []string, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	exg := e.self()
+	ctx := ParamsCtx(params)
+	result := make([]*OrderRes, len(ids))
+	for i, id := range ids {
+		res := &OrderRes{ID: id}
+		result[i] = res
+		if res.Err = CtxError(ctx); res.Err != nil {
+			continue
+		}
+		res.Order, res.Err = exg.CancelOrder(id, symbol, params)
+	}
+	return result, nil
+}
+
+/*
+CancelAllOrders
+Fetch open orders of symbol (all symbols if empty) and cancel them with CancelOrders
+获取symbol(为空时所有品种)的所有挂单，并通过CancelOrders取消
+*/
+func (e *Exchange) CancelAllOrders(symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	exg := e.self()
+	orders, err := exg.FetchOpenOrders(symbol, 0, 0, params)
+	if err != nil {
+		return nil, err
+	}
+	var symbols []string
+	groups := make(map[string][]string)
+	for _, od := range orders {
+		if _, ok := groups[od.Symbol]; !ok {
+			symbols = append(symbols, od.Symbol)
+		}
+		groups[od.Symbol] = append(groups[od.Symbol], od.ID)
+	}
+	result := make([]*OrderRes, 0, len(orders))
+	for _, sym := range symbols {
+		items, err := exg.CancelOrders(groups[sym], sym, params)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, items...)
+	}
+	return result, nil
+}
+
+/*
+MergeOrderParams
+return a copy of common params overridden by the params of one order
+返回公共参数的副本，并使用单个订单的参数覆盖
+*/
+func MergeOrderParams(common, item map[string]interface{}) map[string]interface{} {
+	args := make(map[string]interface{}, len(common)+len(item))
+	for k, v := range common {
+		args[k] = v
+	}
+	for k, v := range item {
+		args[k] = v
+	}
+	return args
+}
+
+/*
+SplitOrderRes
+split batch results into succeed orders and the first error
+将批量结果拆分为成功的订单和第一个错误
+*/
+func SplitOrderRes(items []*OrderRes) ([]*Order, *errs.Error) {
+	var firstErr *errs.Error
+	orders := make([]*Order, 0, len(items))
+	for _, it := range items {
+		if it.Err != nil {
+			if firstErr == nil {
+				firstErr = it.Err
+			}
+			continue
+		}
+		if it.Order != nil {
+			orders = append(orders, it.Order)
+		}
+	}
+	return orders, firstErr
+}
+
+// BatchMetaKeys keys which are used locally or sent in the outer request, not as fields of a batch order
+var BatchMetaKeys = []string{ParamCtx, ParamAccount, ParamRetry, ParamDebug, ParamNoCache, ParamBrokerId}
+
+/*
+CleanBatchOrder
+return a copy of the args of one order without BatchMetaKeys, for the items of native batch requests
+返回单个订单参数去除BatchMetaKeys后的副本，用于原生批量请求的子项
+*/
+func CleanBatchOrder(args map[string]interface{}) map[string]interface{} {
+	item := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		item[k] = v
+	}
+	for _, key := range BatchMetaKeys {
+		delete(item, key)
+	}
+	return item
+}
+
+/*
+BatchOuterArgs
+keep only BatchMetaKeys of params for the outer batch request
+仅保留params中的BatchMetaKeys，用于外层批量请求
+*/
+func BatchOuterArgs(params map[string]interface{}) map[string]interface{} {
+	args := make(map[string]interface{})
+	for _, key := range BatchMetaKeys {
+		if val, ok := params[key]; ok {
+			args[key] = val
+		}
+	}
+	return args
+}
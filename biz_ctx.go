@@ -0,0 +1,318 @@
+package banexg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+/*
+Context-aware variants of BanExchange methods.
+The context is passed down to RequestApiRetryAdv through ParamCtx, so cancellations and deadlines
+abort HTTP requests, retry sleeps and rate limit waits.
+带context的BanExchange方法，context通过ParamCtx传递到RequestApiRetryAdv，可中断http请求、重试等待和限流等待
+*/
+
+/*
+WithCtx
+return a copy of params carrying ctx under ParamCtx
+返回携带ctx的params副本
+*/
+func WithCtx(ctx context.Context, params map[string]interface{}) map[string]interface{} {
+	args := utils.SafeParams(params)
+	if ctx != nil {
+		args[ParamCtx] = ctx
+	}
+	return args
+}
+
+/*
+ParamsCtx
+return the context carried by params, or context.Background() if absent
+*/
+func ParamsCtx(params map[string]interface{}) context.Context {
+	if c, ok := params[ParamCtx].(context.Context); ok && c != nil {
+		return c
+	}
+	return context.Background()
+}
+
+/*
+popParamCtx
+take the context out of params if exists. params is copied instead of modified,
+so callers can reuse it for next pages.
+从params中取出context，params会被复制而非修改，以便调用方复用
+*/
+func popParamCtx(ctx context.Context, params map[string]interface{}) (context.Context, map[string]interface{}) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	val, ok := params[ParamCtx]
+	if !ok {
+		return ctx, params
+	}
+	args := utils.SafeParams(params)
+	delete(args, ParamCtx)
+	if c, ok := val.(context.Context); ok && c != nil {
+		return c, args
+	}
+	return ctx, args
+}
+
+/*
+CtxError
+convert the error of a done context to *errs.Error, return nil if ctx is still active
+*/
+func CtxError(ctx context.Context) *errs.Error {
+	if ctx == nil {
+		return nil
+	}
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errs.New(errs.CodeTimeout, err)
+	}
+	return errs.New(errs.CodeCancel, err)
+}
+
+/*
+SleepCtx
+sleep for the given duration, return early with error when ctx is done
+*/
+func SleepCtx(ctx context.Context, dur time.Duration) *errs.Error {
+	if ctx == nil {
+		time.Sleep(dur)
+		return nil
+	}
+	if dur <= 0 {
+		return CtxError(ctx)
+	}
+	timer := time.NewTimer(dur)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return CtxError(ctx)
+	}
+}
+
+func (e *Exchange) self() BanExchange {
+	if e.Self != nil {
+		return e.Self
+	}
+	return e
+}
+
+func (e *Exchange) LoadMarketsCtx(ctx context.Context, reload bool, params map[string]interface{}) (MarketMap, *errs.Error) {
+	return e.self().LoadMarkets(reload, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTimeCtx(ctx context.Context, params map[string]interface{}) (int64, *errs.Error) {
+	return e.self().FetchTime(WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTickerCtx(ctx context.Context, symbol string, params map[string]interface{}) (*Ticker, *errs.Error) {
+	return e.self().FetchTicker(symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTickersCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Ticker, *errs.Error) {
+	return e.self().FetchTickers(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTickerPriceCtx(ctx context.Context, symbol string, params map[string]interface{}) (map[string]float64, *errs.Error) {
+	return e.self().FetchTickerPrice(symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) LoadLeverageBracketsCtx(ctx context.Context, reload bool, params map[string]interface{}) *errs.Error {
+	return e.self().LoadLeverageBrackets(reload, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOHLCVCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*Kline, *errs.Error) {
+	return e.self().FetchOHLCV(symbol, timeframe, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOHLCVRangeCtx(ctx context.Context, symbol, timeframe string, since, until int64, params map[string]interface{}) ([]*Kline, *errs.Error) {
+	return e.self().FetchOHLCVRange(symbol, timeframe, since, until, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOrderBookCtx(ctx context.Context, symbol string, limit int, params map[string]interface{}) (*OrderBook, *errs.Error) {
+	return e.self().FetchOrderBook(symbol, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTradesCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Trade, *errs.Error) {
+	return e.self().FetchTrades(symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchLastPricesCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*LastPrice, *errs.Error) {
+	return e.self().FetchLastPrices(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchFundingRateCtx(ctx context.Context, symbol string, params map[string]interface{}) (*FundingRateCur, *errs.Error) {
+	return e.self().FetchFundingRate(symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchFundingRatesCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*FundingRateCur, *errs.Error) {
+	return e.self().FetchFundingRates(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchFundingRateHistoryCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*FundingRate, *errs.Error) {
+	return e.self().FetchFundingRateHistory(symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOpenInterestCtx(ctx context.Context, symbol string, params map[string]interface{}) (*OpenInterest, *errs.Error) {
+	return e.self().FetchOpenInterest(symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOpenInterestHistoryCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*OpenInterest, *errs.Error) {
+	return e.self().FetchOpenInterestHistory(symbol, timeframe, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchLongShortRatioHistoryCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*LongShortRatio, *errs.Error) {
+	return e.self().FetchLongShortRatioHistory(symbol, timeframe, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOptionChainCtx(ctx context.Context, underlying string, expiry int64, params map[string]interface{}) ([]*OptionChainItem, *errs.Error) {
+	return e.self().FetchOptionChain(underlying, expiry, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchGreeksCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Greeks, *errs.Error) {
+	return e.self().FetchGreeks(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOrderCtx(ctx context.Context, symbol, id string, params map[string]interface{}) (*Order, *errs.Error) {
+	return e.self().FetchOrder(symbol, id, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOrdersCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error) {
+	return e.self().FetchOrders(symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchBalanceCtx(ctx context.Context, params map[string]interface{}) (*Balances, *errs.Error) {
+	return e.self().FetchBalance(WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchAccountAccessCtx(ctx context.Context, params map[string]interface{}) (*AccountAccess, *errs.Error) {
+	return e.self().FetchAccountAccess(WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchAccountPositionsCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Position, *errs.Error) {
+	return e.self().FetchAccountPositions(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchPositionsCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Position, *errs.Error) {
+	return e.self().FetchPositions(symbols, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchOpenOrdersCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error) {
+	return e.self().FetchOpenOrders(symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchIncomeHistoryCtx(ctx context.Context, inType string, symbol string, since int64, limit int, params map[string]interface{}) ([]*Income, *errs.Error) {
+	return e.self().FetchIncomeHistory(inType, symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchMyTradesCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*MyTrade, *errs.Error) {
+	return e.self().FetchMyTrades(symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) TransferCtx(ctx context.Context, code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*Transfer, *errs.Error) {
+	return e.self().Transfer(code, amount, fromAccount, toAccount, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchTransfersCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transfer, *errs.Error) {
+	return e.self().FetchTransfers(code, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchDepositAddressCtx(ctx context.Context, code string, params map[string]interface{}) (*DepositAddress, *errs.Error) {
+	return e.self().FetchDepositAddress(code, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchDepositsCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error) {
+	return e.self().FetchDeposits(code, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchWithdrawalsCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error) {
+	return e.self().FetchWithdrawals(code, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) WithdrawCtx(ctx context.Context, code string, amount float64, address, tag, network string, params map[string]interface{}) (*Transaction, *errs.Error) {
+	return e.self().Withdraw(code, amount, address, tag, network, WithCtx(ctx, params))
+}
+
+func (e *Exchange) BorrowCtx(ctx context.Context, code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error) {
+	return e.self().Borrow(code, amount, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) RepayCtx(ctx context.Context, code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error) {
+	return e.self().Repay(code, amount, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchBorrowRateCtx(ctx context.Context, code string, params map[string]interface{}) (*BorrowRate, *errs.Error) {
+	return e.self().FetchBorrowRate(code, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchBorrowRatesCtx(ctx context.Context, codes []string, params map[string]interface{}) ([]*BorrowRate, *errs.Error) {
+	return e.self().FetchBorrowRates(codes, WithCtx(ctx, params))
+}
+
+func (e *Exchange) FetchBorrowInterestCtx(ctx context.Context, code, symbol string, since int64, limit int, params map[string]interface{}) ([]*BorrowInterest, *errs.Error) {
+	return e.self().FetchBorrowInterest(code, symbol, since, limit, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CreateOrderCtx(ctx context.Context, symbol, odType, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error) {
+	return e.self().CreateOrder(symbol, odType, side, amount, price, WithCtx(ctx, params))
+}
+
+func (e *Exchange) EditOrderCtx(ctx context.Context, symbol, orderId, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error) {
+	return e.self().EditOrder(symbol, orderId, side, amount, price, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CancelOrderCtx(ctx context.Context, id string, symbol string, params map[string]interface{}) (*Order, *errs.Error) {
+	return e.self().CancelOrder(id, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CreateOrdersCtx(ctx context.Context, orders []*OrderArgs, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	return e.self().CreateOrders(orders, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CancelOrdersCtx(ctx context.Context, ids []string, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	return e.self().CancelOrders(ids, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CancelAllOrdersCtx(ctx context.Context, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error) {
+	return e.self().CancelAllOrders(symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) SetCancelAllAfterCtx(ctx context.Context, timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().SetCancelAllAfter(timeoutMs, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) SetLeverageCtx(ctx context.Context, leverage float64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().SetLeverage(leverage, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) SetMarginModeCtx(ctx context.Context, mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().SetMarginMode(mode, symbol, WithCtx(ctx, params))
+}
+
+func (e *Exchange) SetPositionModeCtx(ctx context.Context, hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().SetPositionMode(hedged, WithCtx(ctx, params))
+}
+
+func (e *Exchange) AddMarginCtx(ctx context.Context, symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().AddMargin(symbol, amount, WithCtx(ctx, params))
+}
+
+func (e *Exchange) ReduceMarginCtx(ctx context.Context, symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.self().ReduceMargin(symbol, amount, WithCtx(ctx, params))
+}
+
+func (e *Exchange) CallCtx(ctx context.Context, method string, params map[string]interface{}) (*HttpRes, *errs.Error) {
+	return e.self().Call(method, WithCtx(ctx, params))
+}
@@ -1,7 +1,18 @@
 package banexg
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
 )
 
 func TestSetOptions(t *testing.T) {
@@ -69,3 +80,1033 @@ func TestCalcFee(t *testing.T) {
 		t.Errorf("maker fee: %v", fee)
 	}
 }
+
+func TestRequestApiCtx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second * 3):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	exg := &Exchange{
+		ExgInfo: &ExgInfo{ID: "test", Name: "test"},
+		Hosts:   &ExgHosts{Prod: map[string]string{"public": server.URL}},
+		Apis: map[string]*Entry{
+			"slow": {Path: "slow", Host: "public", Method: "GET", Cost: 1},
+		},
+		HttpClient: &http.Client{},
+		Sign: func(api *Entry, params map[string]interface{}) *HttpReq {
+			if _, ok := params[ParamCtx]; ok {
+				t.Errorf("ParamCtx should be removed before Sign")
+			}
+			return &HttpReq{Url: api.Url, Method: api.Method, Headers: http.Header{}}
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	start := time.Now()
+	rsp, err := exg.CallCtx(ctx, "slow", map[string]interface{}{ParamRetry: 3})
+	if err == nil || err.Code != errs.CodeTimeout {
+		t.Fatalf("expect timeout error, got: %v %v", rsp, err)
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("ctx deadline not respected, cost: %v", cost)
+	}
+
+	canceled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	_, err = exg.CallCtx(canceled, "slow", nil)
+	if err == nil || err.Code != errs.CodeCancel {
+		t.Fatalf("expect cancel error, got: %v", err)
+	}
+
+	// markets loading in progress, the waiter should return on ctx done
+	wait := make(chan interface{})
+	exg.MarketsWait = wait
+	ctx3, cancel3 := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel3()
+	_, err = exg.LoadMarketsCtx(ctx3, false, nil)
+	if err == nil || err.Code != errs.CodeTimeout {
+		t.Fatalf("expect timeout error for LoadMarkets, got: %v", err)
+	}
+	if exg.MarketsWait != nil {
+		t.Fatalf("MarketsWait should be reset after ctx done")
+	}
+	select {
+	case wait <- MarketMap{}:
+	case <-time.After(time.Second):
+		t.Fatalf("result of loading goroutine not received")
+	}
+}
+
+func TestSleepCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SleepCtx(ctx, time.Second); err == nil || err.Code != errs.CodeCancel {
+		t.Errorf("expect cancel error, got: %v", err)
+	}
+	if err := SleepCtx(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRateBucket(t *testing.T) {
+	b := &RateBucket{Capacity: 10, Interval: 1000, tokens: 10, lastMS: 1000}
+	if wait := b.Reserve(6, 1000); wait != 0 {
+		t.Errorf("expect no wait, got %d", wait)
+	}
+	// 4 tokens left, 8 needs 4 more, refill 10 per 1000ms
+	if wait := b.Reserve(8, 1000); wait != 400 {
+		t.Errorf("expect wait 400, got %d", wait)
+	}
+	b.Refund(8)
+	// exchange reports nothing remaining, reset after 2s
+	b.Sync(0, 0, 3000, 1000)
+	if wait := b.Reserve(1, 1000); wait != 2000 {
+		t.Errorf("expect wait until reset 2000, got %d", wait)
+	}
+}
+
+func TestWeightLimitHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Used-Weight", "9")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	exg := &Exchange{
+		ExgInfo: &ExgInfo{ID: "weight_test", Name: "test"},
+		Hosts:   &ExgHosts{Prod: map[string]string{"public": server.URL}},
+		Apis: map[string]*Entry{
+			"heavy": {Path: "heavy", Host: "public", Method: "GET", Cost: 1},
+		},
+		EnableRateLimit:     BoolTrue,
+		CalcRateLimiterCost: func(api *Entry, params map[string]interface{}) float64 { return api.Cost },
+		WeightRules: []*WeightRule{
+			{Header: "X-Used-Weight", Limit: 10, Interval: 500, Ratio: 2},
+		},
+		HttpClient: &http.Client{},
+		Sign: func(api *Entry, params map[string]interface{}) *HttpReq {
+			return &HttpReq{Url: api.Url, Method: api.Method, Headers: http.Header{}}
+		},
+	}
+	if _, err := exg.Call("heavy", nil); err != nil {
+		t.Fatal(err)
+	}
+	// 1 remaining after header sync, the next request costs 2 and must wait for refill
+	start := time.Now()
+	if _, err := exg.Call("heavy", nil); err != nil {
+		t.Fatal(err)
+	}
+	if cost := time.Since(start); cost < time.Millisecond*40 {
+		t.Errorf("weight limit not applied, cost: %v", cost)
+	}
+}
+
+type batchStubExg struct {
+	*Exchange
+	canceled []string
+}
+
+func (e *batchStubExg) FetchOpenOrders(symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error) {
+	return []*Order{
+		{ID: "1", Symbol: "BTC/USDT"},
+		{ID: "2", Symbol: "ETH/USDT"},
+		{ID: "3", Symbol: "BTC/USDT"},
+	}, nil
+}
+
+func (e *batchStubExg) CancelOrder(id string, symbol string, params map[string]interface{}) (*Order, *errs.Error) {
+	if id == "3" {
+		return nil, errs.NewMsg(errs.CodeRunTime, "unknown order")
+	}
+	e.canceled = append(e.canceled, symbol+":"+id)
+	return &Order{ID: id, Symbol: symbol, Status: OdStatusCanceled}, nil
+}
+
+func TestCancelAllOrdersEmulated(t *testing.T) {
+	exg := &batchStubExg{Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "batch_test"}}}
+	exg.Self = exg
+	res, err := exg.CancelAllOrders("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 || res[0].ID != "1" || res[1].ID != "3" || res[2].ID != "2" {
+		t.Fatalf("unexpected result order: %+v", res)
+	}
+	if res[1].Err == nil {
+		t.Errorf("expect error for order 3")
+	}
+	orders, err := SplitOrderRes(res)
+	if len(orders) != 2 || err == nil {
+		t.Errorf("expect 2 orders and first error, got %d %v", len(orders), err)
+	}
+	if len(exg.canceled) != 2 || exg.canceled[1] != "ETH/USDT:2" {
+		t.Errorf("unexpected canceled: %v", exg.canceled)
+	}
+}
+
+type optionStubExg struct {
+	*Exchange
+}
+
+func (e *optionStubExg) FetchGreeks(symbols []string, params map[string]interface{}) ([]*Greeks, *errs.Error) {
+	res := make([]*Greeks, 0, len(symbols))
+	for _, s := range symbols {
+		res = append(res, &Greeks{Symbol: s, Delta: 0.5})
+	}
+	return res, nil
+}
+
+func TestFetchOptionChain(t *testing.T) {
+	exp1, exp2 := int64(1735286400000), int64(1735891200000)
+	exg := &optionStubExg{Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "option_test"}}}
+	exg.Self = exg
+	exg.Markets = MarketMap{
+		"p2": {Symbol: "p2", Base: "BTC", Option: true, Active: true, Strike: 100, OptionType: "put", Expiry: exp1},
+		"c2": {Symbol: "c2", Base: "BTC", Option: true, Active: true, Strike: 100, OptionType: "call", Expiry: exp1},
+		"c1": {Symbol: "c1", Base: "BTC", Option: true, Active: true, Strike: 90, OptionType: "call", Expiry: exp1},
+		"c3": {Symbol: "c3", Base: "BTC", Option: true, Active: true, Strike: 80, OptionType: "call", Expiry: exp2},
+		"e1": {Symbol: "e1", Base: "ETH", Option: true, Active: true, Strike: 90, OptionType: "call", Expiry: exp1},
+		"s1": {Symbol: "s1", Base: "BTC", Active: true},
+	}
+	res, err := exg.FetchOptionChain("BTC", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range res {
+		got = append(got, it.Symbol)
+		if it.Greeks == nil || it.Greeks.Delta != 0.5 {
+			t.Errorf("missing greeks for %s", it.Symbol)
+		}
+	}
+	if strings.Join(got, ",") != "c1,c2,p2,c3" {
+		t.Errorf("unexpected chain order: %v", got)
+	}
+	res, err = exg.FetchOptionChain("BTC", exp2+3600000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Symbol != "c3" {
+		t.Errorf("unexpected chain of expiry: %+v", res)
+	}
+}
+
+func TestDumpReplayApi(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits += 1
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"n":%d}`, hits)))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	newExg := func(opts map[string]interface{}) *Exchange {
+		opts[OptProxy] = "no"
+		exg := &Exchange{
+			ExgInfo: &ExgInfo{ID: "test", Name: "test"},
+			Hosts:   &ExgHosts{Prod: map[string]string{"public": server.URL}},
+			Apis: map[string]*Entry{
+				"ping": {Path: "ping", Host: "public", Method: "GET", Cost: 1},
+			},
+			Options: opts,
+			Sign: func(api *Entry, params map[string]interface{}) *HttpReq {
+				return &HttpReq{Url: api.Url, Method: api.Method, Headers: http.Header{}}
+			},
+		}
+		if err := exg.Init(); err != nil {
+			t.Fatalf("init exchange fail: %v", err)
+		}
+		return exg
+	}
+	exg := newExg(map[string]interface{}{OptDumpPath: dir})
+	for i := 1; i <= 2; i++ {
+		rsp, err := exg.Call("ping", map[string]interface{}{"symbol": "BTCUSDT"})
+		if err != nil || rsp.Content != fmt.Sprintf(`{"n":%d}`, i) {
+			t.Fatalf("unexpected response: %v %v", rsp, err)
+		}
+	}
+	if err := exg.SetDump(""); err != nil {
+		t.Fatalf("close dump fail: %v", err)
+	}
+
+	exg = newExg(map[string]interface{}{OptReplayPath: dir})
+	if !exg.NetDisable {
+		t.Fatalf("replay mode should disable network")
+	}
+	startMS := exg.MilliSeconds()
+	if startMS <= 0 || startMS > time.Now().UnixMilli() {
+		t.Fatalf("replay clock should start from first record, got %v", startMS)
+	}
+	for _, want := range []string{`{"n":1}`, `{"n":2}`, `{"n":2}`} {
+		rsp, err := exg.Call("ping", map[string]interface{}{"symbol": "BTCUSDT"})
+		if err != nil || rsp.Content != want {
+			t.Fatalf("unexpected replay response, want %s, got: %v %v", want, rsp, err)
+		}
+	}
+	if _, err := exg.Call("ping", map[string]interface{}{"symbol": "ETHUSDT"}); err == nil || err.Code != errs.CodeDataNotFound {
+		t.Fatalf("expect not found for unrecorded request, got: %v", err)
+	}
+	if hits != 2 {
+		t.Fatalf("replay should not request network, hits: %v", hits)
+	}
+	if err := exg.ReplayAll(); err != nil {
+		t.Fatalf("replay all fail: %v", err)
+	}
+	if err := exg.SetReplay(""); err != nil {
+		t.Fatalf("stop replay fail: %v", err)
+	}
+	if exg.NetDisable {
+		t.Fatalf("network should be restored after replay")
+	}
+}
+
+func TestMapBizErr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":-2010,"msg":"Account has insufficient balance for requested action."}`))
+	}))
+	defer server.Close()
+	exg := &Exchange{
+		ExgInfo: &ExgInfo{ID: "test", Name: "test"},
+		Hosts:   &ExgHosts{Prod: map[string]string{"private": server.URL}},
+		Apis: map[string]*Entry{
+			"order": {Path: "order", Host: "private", Method: "POST", Cost: 1},
+		},
+		HttpClient: &http.Client{},
+		Sign: func(api *Entry, params map[string]interface{}) *HttpReq {
+			return &HttpReq{Url: api.Url, Method: api.Method, Headers: http.Header{}}
+		},
+	}
+	_, err := exg.Call("order", nil)
+	if err == nil || err.Code != http.StatusBadRequest || err.BizCode != -2010 {
+		t.Fatalf("expect raw http status without MapBizErr, got: %v", err)
+	}
+	exg.MapBizErr = func(bizCode int, msg string) int {
+		if bizCode == -2010 && strings.Contains(msg, "insufficient balance") {
+			return errs.CodeInsufficientBalance
+		}
+		return 0
+	}
+	_, err = exg.Call("order", nil)
+	if err == nil || err.Code != errs.CodeInsufficientBalance || err.BizCode != -2010 {
+		t.Fatalf("expect normalized code, got: %v", err)
+	}
+}
+
+type timeExchange struct {
+	*Exchange
+	serverMS func() int64
+}
+
+func (e *timeExchange) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	return e.serverMS(), nil
+}
+
+func TestResyncOnNonce(t *testing.T) {
+	var stamps []int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stamp, _ := strconv.ParseInt(r.Header.Get("ts"), 10, 64)
+		stamps = append(stamps, stamp)
+		if len(stamps) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	exg := &timeExchange{
+		Exchange: &Exchange{
+			ExgInfo: &ExgInfo{ID: "test", Name: "test"},
+			Hosts:   &ExgHosts{Prod: map[string]string{"private": server.URL}},
+			Apis: map[string]*Entry{
+				"order": {Path: "order", Host: "private", Method: "POST", Cost: 1},
+			},
+			HttpClient: &http.Client{},
+			MapBizErr: func(bizCode int, msg string) int {
+				if bizCode == -1021 {
+					return errs.CodeInvalidNonce
+				}
+				return 0
+			},
+		},
+		serverMS: func() int64 {
+			return time.Now().UnixMilli() - 5000
+		},
+	}
+	exg.Self = exg
+	exg.Sign = func(api *Entry, params map[string]interface{}) *HttpReq {
+		head := http.Header{}
+		head.Set("ts", strconv.FormatInt(exg.Nonce(), 10))
+		return &HttpReq{Url: api.Url, Method: api.Method, Headers: head}
+	}
+	if _, err := exg.Call("order", map[string]interface{}{ParamRetry: 0}); err != nil {
+		t.Fatalf("request should succeed after resync, got: %v", err)
+	}
+	if len(stamps) != 2 {
+		t.Fatalf("expect 2 requests, got %v", len(stamps))
+	}
+	if delay := exg.GetTimeDelay(); delay < 4900 || delay > 5100 {
+		t.Fatalf("unexpected time delay: %v", delay)
+	}
+	if diff := stamps[0] - stamps[1]; diff < 4900 || diff > 5100 {
+		t.Fatalf("request should be signed again with synced time, stamps: %v", stamps)
+	}
+}
+
+func TestCheckWithdraw(t *testing.T) {
+	exg := &Exchange{ExgInfo: &ExgInfo{
+		CurrenciesByCode: CurrencyMap{
+			"USDT": {ID: "USDT", Code: "USDT", Withdraw: true, Networks: []*ChainNetwork{
+				{ID: "TRX", Network: "TRX", Withdraw: true,
+					Limits: &CodeLimits{Withdraw: &LimitRange{Min: 10}}},
+				{ID: "BSC", Network: "BSC", Withdraw: false},
+			}},
+			"DOGE": {ID: "DOGE", Code: "DOGE", Withdraw: false, Networks: []*ChainNetwork{
+				{ID: "DOGE", Network: "DOGE", Withdraw: false},
+			}},
+		},
+	}}
+	cases := []struct {
+		code    string
+		network string
+		amount  float64
+		errCode int
+	}{
+		{"USDT", "trx", 20, 0},
+		{"USDT", "", 20, 0},
+		{"BTC", "BTC", 1, 0},
+		{"USDT", "TRX", 5, errs.CodeParamInvalid},
+		{"USDT", "BSC", 20, errs.CodeNotSupport},
+		{"USDT", "ETH", 20, errs.CodeParamInvalid},
+		{"USDT", "TRX", 0, errs.CodeParamInvalid},
+		{"DOGE", "", 20, errs.CodeNotSupport},
+	}
+	for _, c := range cases {
+		err := exg.CheckWithdraw(c.code, c.network, c.amount, nil)
+		code := 0
+		if err != nil {
+			code = err.Code
+		}
+		if code != c.errCode {
+			t.Errorf("CheckWithdraw(%s, %s, %v) = %v, expect code %d", c.code, c.network, c.amount, err, c.errCode)
+		}
+	}
+	exg.Self = &accessExchange{Exchange: exg, access: &AccountAccess{WithdrawKnown: true}}
+	if err := exg.CheckWithdraw("USDT", "TRX", 20, nil); err == nil || err.Code != errs.CodeForbidden {
+		t.Errorf("withdraw should be forbidden without permission, got %v", err)
+	}
+}
+
+type accessExchange struct {
+	*Exchange
+	access *AccountAccess
+}
+
+func (e *accessExchange) FetchAccountAccess(params map[string]interface{}) (*AccountAccess, *errs.Error) {
+	return e.access, nil
+}
+
+func TestGetLoanMarginMode(t *testing.T) {
+	cases := []struct {
+		symbol  string
+		mode    string
+		expect  string
+		errCode int
+	}{
+		{"", "", MarginCross, 0},
+		{"BTC/USDT", "", MarginIsolated, 0},
+		{"BTC/USDT", MarginCross, MarginCross, 0},
+		{"", MarginIsolated, "", errs.CodeParamRequired},
+		{"", "portfolio", "", errs.CodeParamInvalid},
+	}
+	for _, c := range cases {
+		params := map[string]interface{}{}
+		if c.mode != "" {
+			params[ParamMarginMode] = c.mode
+		}
+		mode, err := GetLoanMarginMode(c.symbol, params)
+		code := 0
+		if err != nil {
+			code = err.Code
+		}
+		if mode != c.expect || code != c.errCode {
+			t.Errorf("GetLoanMarginMode(%s, %s) = %s, %v", c.symbol, c.mode, mode, err)
+		}
+	}
+}
+
+type ohlcvStubExg struct {
+	*Exchange
+	calls int
+}
+
+// FetchOHLCV return 1m bars of [since-1m, until], bars of minute 10~24 are missing.
+// the latest limit bars before until are returned when since is 0
+func (e *ohlcvStubExg) FetchOHLCV(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*Kline, *errs.Error) {
+	e.calls += 1
+	until := params[ParamUntil].(int64)
+	res := make([]*Kline, 0, limit)
+	if since <= 0 {
+		for t := until - until%60000; t >= 0 && len(res) < limit; t -= 60000 {
+			if t < 600000 || t >= 1500000 {
+				res = append([]*Kline{{Time: t, Close: float64(t / 60000)}}, res...)
+			}
+		}
+		return res, nil
+	}
+	for t := since - 60000; t <= until && len(res) < limit+1; t += 60000 {
+		if t >= 600000 && t < 1500000 {
+			continue
+		}
+		res = append(res, &Kline{Time: t, Close: float64(t / 60000)})
+	}
+	return res, nil
+}
+
+func TestFetchOHLCVRange(t *testing.T) {
+	exg := &ohlcvStubExg{Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "ohlcv_test", OHLCVLimit: 5}}}
+	exg.Self = exg
+	res, err := exg.FetchOHLCVRange("BTC/USDT", "1m", 60000, 1800000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exg.calls != 6 {
+		t.Errorf("expect 6 pages, got %d", exg.calls)
+	}
+	if len(res) != 14 {
+		t.Fatalf("expect 14 bars, got %d", len(res))
+	}
+	for i, k := range res {
+		if i > 0 && k.Time <= res[i-1].Time {
+			t.Fatalf("bars not sorted or duplicated at %d", i)
+		}
+	}
+	if res[0].Time != 60000 || res[len(res)-1].Time != 1740000 {
+		t.Errorf("unexpected range: %d - %d", res[0].Time, res[len(res)-1].Time)
+	}
+	_, err = exg.FetchOHLCVRange("BTC/USDT", "1m", 0, 0, nil)
+	if err == nil || err.Code != errs.CodeParamRequired {
+		t.Errorf("expect since required, got %v", err)
+	}
+	// backward from until, across the hole of minute 10~24
+	exg.calls = 0
+	res, err = exg.FetchOHLCVRange("BTC/USDT", "1m", 0, 1800000, map[string]interface{}{ParamLimit: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 12 || res[0].Time != 180000 || res[len(res)-1].Time != 1740000 || exg.calls != 3 {
+		t.Fatalf("unexpected backward bars: %d, %d - %d, calls %d", len(res), res[0].Time, res[len(res)-1].Time, exg.calls)
+	}
+	for i, k := range res {
+		if i > 0 && k.Time <= res[i-1].Time {
+			t.Fatalf("bars not sorted or duplicated at %d", i)
+		}
+	}
+	res, err = exg.FetchOHLCVRange("BTC/USDT", "1m", 0, 300000, map[string]interface{}{ParamLimit: 100})
+	if err != nil || len(res) != 5 || res[0].Time != 0 {
+		t.Fatalf("backward paging should stop at the first bar, got %d, %v", len(res), err)
+	}
+	_, err = exg.FetchOHLCVRange("BTC/USDT", "1m", 0, 1800000, nil)
+	if err == nil || err.Code != errs.CodeParamRequired {
+		t.Errorf("expect limit required, got %v", err)
+	}
+}
+
+func TestWsRequestResult(t *testing.T) {
+	client := &WsClient{Exg: &Exchange{ExgInfo: &ExgInfo{ID: "ws_test"}}, JobInfos: make(map[string]*WsJobInfo)}
+	var other string
+	client.OnMessage = func(c *WsClient, msg *WsMsg) {
+		other = msg.ID
+	}
+	cases := map[string]string{
+		"a1": `{"id":"a1","status":200,"result":{"orderId":1}}`,
+		"b2": `{"reqId":"b2","retCode":0,"data":{"orderId":"2"}}`,
+	}
+	for id, text := range cases {
+		info := &WsJobInfo{ID: id, Result: make(chan *WsMsg, 1)}
+		client.JobInfos[id] = info
+		client.HandleRawMsg([]byte(text))
+		select {
+		case rsp := <-info.Result:
+			if rsp.Text != text {
+				t.Errorf("unexpected response of %s: %s", id, rsp.Text)
+			}
+		default:
+			t.Fatalf("response of %s not delivered", id)
+		}
+		if _, ok := client.JobInfos[id]; ok {
+			t.Errorf("job %s should be removed", id)
+		}
+	}
+	client.HandleRawMsg([]byte(`{"id":"c3","status":200}`))
+	if other != "c3" {
+		t.Errorf("msg without job should be passed to OnMessage, got %v", other)
+	}
+	if NewWsReqID() == NewWsReqID() {
+		t.Error("NewWsReqID should be unique")
+	}
+}
+
+func TestIsWsTrade(t *testing.T) {
+	e := &Exchange{WsTrade: true}
+	args := map[string]interface{}{ParamWsTrade: false}
+	if e.IsWsTrade(args) {
+		t.Error("ParamWsTrade should override WsTrade")
+	}
+	if _, ok := args[ParamWsTrade]; ok {
+		t.Error("ParamWsTrade should be removed")
+	}
+	if !e.IsWsTrade(nil) {
+		t.Error("expect WsTrade from options")
+	}
+}
+
+type cancelAfterStubExg struct {
+	*Exchange
+	lock     sync.Mutex
+	timeouts []int64
+	delay    time.Duration
+}
+
+func (e *cancelAfterStubExg) SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	time.Sleep(e.delay)
+	e.lock.Lock()
+	e.timeouts = append(e.timeouts, timeoutMs)
+	e.lock.Unlock()
+	return map[string]interface{}{}, nil
+}
+
+func (e *cancelAfterStubExg) snapshot() []int64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]int64{}, e.timeouts...)
+}
+
+func TestKeepCancelAllAfter(t *testing.T) {
+	oldIntv := cancelAfterMinIntv
+	cancelAfterMinIntv = 10 * time.Millisecond
+	defer func() { cancelAfterMinIntv = oldIntv }()
+	exg := &cancelAfterStubExg{Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "cancel_after_test"}}}
+	exg.Self = exg
+	if err := exg.KeepCancelAllAfter(30, "BTC/USDT:USDT", nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if err := exg.StopCancelAllAfter("BTC/USDT:USDT", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	calls := exg.snapshot()
+	if len(calls) < 4 || calls[0] != 30 {
+		t.Fatalf("expect arm and refresh calls, got %v", calls)
+	}
+	if calls[len(calls)-1] != 0 {
+		t.Errorf("expect disarm at last, got %v", calls)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if num := len(exg.snapshot()); num != len(calls) {
+		t.Errorf("keeper not stopped, calls %d -> %d", len(calls), num)
+	}
+	// Close stops keepers and disarms
+	if err := exg.KeepCancelAllAfter(30, "ETH/USDT:USDT", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := exg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	calls = exg.snapshot()
+	if calls[len(calls)-1] != 0 {
+		t.Errorf("expect disarm on Close, got %v", calls)
+	}
+	if err := exg.KeepCancelAllAfter(0, "ETH/USDT:USDT", nil); err == nil {
+		t.Error("expect error for zero timeout")
+	}
+	// account level timer: one keeper per account, and Stop waits for the slow refresh before disarming
+	exg.CancelAfterScope = func(symbol string, params map[string]interface{}) string {
+		return ""
+	}
+	exg.delay = 15 * time.Millisecond
+	for _, symbol := range []string{"BTC/USDT:USDT", "ETH/USDT:USDT"} {
+		if err := exg.KeepCancelAllAfter(30, symbol, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if num := len(exg.cancelAfters); num != 1 {
+		t.Errorf("expect 1 keeper for account scope, got %d", num)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := exg.StopCancelAllAfter("BTC/USDT:USDT", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	calls = exg.snapshot()
+	time.Sleep(40 * time.Millisecond)
+	if after := exg.snapshot(); len(after) != len(calls) || after[len(after)-1] != 0 {
+		t.Errorf("expect disarm at last after Stop, got %v", after)
+	}
+}
+
+const trackerChanKey = "acc1@orders"
+
+type trackerStubExg struct {
+	*Exchange
+	lock   sync.Mutex
+	opens  []*Order
+	orders map[string]*Order
+}
+
+func (e *trackerStubExg) WatchOrders(params map[string]interface{}) (chan *Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	refKey := utils.PopMapVal(args, ParamRefKey, "account")
+	create := func(cap int) chan *Order { return make(chan *Order, cap) }
+	out := GetWsOutChan(e.Exchange, trackerChanKey, create, args)
+	e.AddWsChanRefs(trackerChanKey, refKey)
+	return out, nil
+}
+
+func (e *trackerStubExg) FetchOpenOrders(symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.opens, nil
+}
+
+func (e *trackerStubExg) FetchOrder(symbol, id string, params map[string]interface{}) (*Order, *errs.Error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if od, ok := e.orders[id]; ok {
+		return od, nil
+	}
+	return nil, errs.NewMsg(errs.CodeDataNotFound, "order not found")
+}
+
+func TestOrderTracker(t *testing.T) {
+	exg := &trackerStubExg{
+		Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "tracker_test"},
+			Accounts:   map[string]*Account{"acc1": {Name: "acc1"}},
+			WsOutChans: map[string]interface{}{}, WsChanRefs: map[string]map[string]struct{}{}},
+		opens: []*Order{
+			{ID: "1", ClientOrderID: "c1", Symbol: "BTC/USDT", Status: OdStatusOpen, Amount: 1, Timestamp: 100, LastUpdateTimestamp: 100},
+			{ID: "2", ClientOrderID: "c2", Symbol: "BTC/USDT", Status: OdStatusOpen, Amount: 2, Timestamp: 200, LastUpdateTimestamp: 200},
+		},
+		orders: map[string]*Order{},
+	}
+	exg.Self = exg
+	tracker, err := NewOrderTracker(exg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker.ReconcileDelay = 10 * time.Millisecond
+	tracker.DoneKeep = 0
+	var lock sync.Mutex
+	changes := make(map[string]int)
+	tracker.OnChange = func(od, prev *Order) {
+		lock.Lock()
+		changes[od.ID] += 1
+		lock.Unlock()
+	}
+	// a user of WatchOrders on the same account receives every update too
+	userOut, err := exg.WatchOrders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tracker.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Stop()
+	if num := len(tracker.OpenOrders("BTC/USDT")); num != 2 {
+		t.Fatalf("expect 2 open orders after start, got %d", num)
+	}
+	// ws update in sequence, stale one is ignored
+	WriteOutChan(exg.Exchange, trackerChanKey, &Order{ID: "1", Status: OdStatusPartFilled, Filled: 0.5, LastUpdateTimestamp: 300}, true)
+	WriteOutChan(exg.Exchange, trackerChanKey, &Order{ID: "1", Status: OdStatusOpen, Filled: 0, LastUpdateTimestamp: 150}, true)
+	waitFor := func(check func() bool) bool {
+		for i := 0; i < 100; i++ {
+			if check() {
+				return true
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		return false
+	}
+	ok := waitFor(func() bool {
+		od := tracker.GetByClientID("c1")
+		return od != nil && od.Filled == 0.5
+	})
+	if !ok {
+		t.Fatalf("ws update not applied: %+v", tracker.Get("1"))
+	}
+	if od := tracker.Get("1"); od.Status != OdStatusPartFilled || od.Amount != 1 || od.Symbol != "BTC/USDT" {
+		t.Errorf("unexpected merged order: %+v", od)
+	}
+	// order 2 canceled while disconnected, found by reconcile after reconnect
+	exg.lock.Lock()
+	exg.opens = exg.opens[:1]
+	exg.orders["2"] = &Order{ID: "2", Symbol: "BTC/USDT", Status: OdStatusCanceled, Amount: 2, LastUpdateTimestamp: 400}
+	exg.lock.Unlock()
+	exg.runWsReConHooks(&WsClient{AccName: "other"}, 0)
+	exg.runWsReConHooks(&WsClient{AccName: "acc1"}, 0)
+	ok = waitFor(func() bool {
+		od := tracker.Get("2")
+		return od != nil && od.Status == OdStatusCanceled
+	})
+	if !ok {
+		t.Fatalf("missed cancel not reconciled: %+v", tracker.Get("2"))
+	}
+	if num := len(tracker.OpenOrders("")); num != 1 {
+		t.Errorf("expect 1 open order, got %d", num)
+	}
+	// done order is never reopened
+	if tracker.Apply(&Order{ID: "2", Status: OdStatusOpen, LastUpdateTimestamp: 500}) {
+		t.Error("done order should not be reopened")
+	}
+	lock.Lock()
+	if changes["1"] != 2 || changes["2"] != 2 {
+		t.Errorf("unexpected change counts: %v", changes)
+	}
+	lock.Unlock()
+	if num := tracker.Prune(1000); num != 1 || tracker.Get("2") != nil || tracker.GetByClientID("c2") != nil {
+		t.Errorf("prune done order fail: %d", num)
+	}
+	// trades covered by the filled of the order are skipped, filled never exceeds amount
+	trade := &MyTrade{Trade: Trade{ID: "t1", Order: "1", Amount: 0.5}, Filled: 0.5}
+	if tracker.ApplyTrade(trade) {
+		t.Error("trade covered by order filled should be skipped")
+	}
+	trade = &MyTrade{Trade: Trade{ID: "t2", Order: "1", Amount: 0.8}}
+	if !tracker.ApplyTrade(trade) {
+		t.Error("new trade should be applied")
+	}
+	if od := tracker.Get("1"); od.Filled != 1 {
+		t.Errorf("filled should be capped at amount, got %v", od.Filled)
+	}
+	if tracker.ApplyTrade(&MyTrade{Trade: Trade{ID: "t3", Order: "1", Amount: 0.1}}) {
+		t.Error("fully filled order should not take more trades")
+	}
+	if num := len(userOut); num != 2 {
+		t.Errorf("user of WatchOrders should receive all updates, got %d", num)
+	}
+	// Stop releases the tap only, the chan of the other user is kept
+	tracker.Stop()
+	if len(exg.WsChanTaps) > 0 || exg.GetWsChanKey(userOut) != trackerChanKey {
+		t.Error("tap should be removed and user chan kept on Stop")
+	}
+	// the out chan is closed when the tracker is the only consumer
+	exg.DelWsChanRefs(trackerChanKey, "account")
+	tracker2, err := NewOrderTracker(exg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tracker2.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if len(exg.WsOutChans) > 0 {
+		t.Error("out chan should not be kept for the tracker")
+	}
+	WriteOutChan(exg.Exchange, trackerChanKey, &Order{ID: "3", Symbol: "BTC/USDT", Status: OdStatusOpen, Amount: 1}, true)
+	if !waitFor(func() bool { return tracker2.Get("3") != nil }) {
+		t.Error("tracker should receive updates by tap without out chan")
+	}
+	tracker2.Stop()
+}
+
+type keeperStubExg struct {
+	*Exchange
+	positions []*Position
+	balances  *Balances
+}
+
+func (e *keeperStubExg) FetchPositions(symbols []string, params map[string]interface{}) ([]*Position, *errs.Error) {
+	return e.positions, nil
+}
+
+func (e *keeperStubExg) FetchBalance(params map[string]interface{}) (*Balances, *errs.Error) {
+	return e.balances, nil
+}
+
+func TestAccountKeeper(t *testing.T) {
+	acc := newAccount("acc1", nil)
+	future := time.Now().UnixMilli() + 3600000
+	acc.MarPositions[MarketLinear] = []*Position{
+		{Symbol: "BTC/USDT:USDT", Side: "long", Contracts: 1, EntryPrice: 100, TimeStamp: 10},
+		{Symbol: "ETH/USDT:USDT", Side: "long", Contracts: 2, EntryPrice: 10, TimeStamp: 10},
+		{Symbol: "XRP/USDT:USDT", Side: "long", Contracts: 5, TimeStamp: future},
+	}
+	acc.MarBalances[MarketLinear] = &Balances{TimeStamp: 10, Assets: map[string]*Asset{
+		"USDT": {Code: "USDT", Free: 100},
+	}}
+	exg := &keeperStubExg{
+		Exchange: &Exchange{ExgInfo: &ExgInfo{ID: "keeper_test"}, Accounts: map[string]*Account{"acc1": acc}},
+		positions: []*Position{
+			{Symbol: "BTC/USDT:USDT", Side: "long", Contracts: 1.5, EntryPrice: 100},
+			{Symbol: "SOL/USDT:USDT", Side: "long", Contracts: 3, EntryPrice: 20},
+			{Symbol: "DOGE/USDT:USDT", Side: "long", Contracts: 0},
+		},
+		balances: &Balances{Assets: map[string]*Asset{
+			"USDT": {Code: "USDT", Free: 90, Used: 10, Total: 110, UPol: 10},
+			"BTC":  {Code: "BTC", Free: 1, Total: 1},
+		}},
+	}
+	exg.Self = exg
+	keeper, err := NewAccountKeeper(exg, map[string]interface{}{ParamMarket: MarketLinear})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notified int
+	keeper.OnDrift = func(evt *DriftEvent) {
+		notified += 1
+	}
+	events, err := keeper.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]*DriftEvent)
+	for _, evt := range events {
+		got[evt.Kind+":"+evt.Key] = evt
+	}
+	if len(events) != 4 || notified != 4 {
+		t.Fatalf("expect 4 drifts, got %d: %v", len(events), got)
+	}
+	if evt := got["position:BTC/USDT:USDT#long"]; evt == nil || evt.Diffs["contracts"] != [2]float64{1, 1.5} {
+		t.Errorf("unexpected btc drift: %v", evt)
+	}
+	for _, key := range []string{"position:ETH/USDT:USDT#long", "position:SOL/USDT:USDT#long", "balance:BTC"} {
+		if got[key] == nil || !got[key].Healed {
+			t.Errorf("missing healed drift: %s", key)
+		}
+	}
+	if len(acc.MarPositions[MarketLinear]) != 3 || acc.MarBalances[MarketLinear] != exg.balances {
+		t.Errorf("local state not healed: %v", acc.MarPositions[MarketLinear])
+	}
+	events, err = keeper.Check()
+	if err != nil || len(events) != 0 {
+		t.Errorf("expect no drift after heal, got %v %v", events, err)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	symbol := "BTC/USDT:USDT"
+	exg := &Exchange{ExgInfo: &ExgInfo{ID: "validate_test", Markets: MarketMap{
+		symbol: {
+			Symbol: symbol, Type: MarketLinear, Contract: true, Linear: true, Swap: true, Active: true,
+			Precision: &Precision{Amount: 0.001, Price: 0.1, ModeAmount: PrecModeTickSize, ModePrice: PrecModeTickSize},
+			Limits: &MarketLimits{
+				Amount: &LimitRange{Min: 0.001, Max: 100},
+				Market: &LimitRange{Max: 10},
+				Price:  &LimitRange{Min: 1, Max: 1000000},
+				Cost:   &LimitRange{Min: 5},
+			},
+		},
+		"ETH/USDT": {Symbol: "ETH/USDT", Type: MarketSpot, Spot: true},
+	}}}
+	rules := func(res *OrderCheck) map[string]bool {
+		out := make(map[string]bool)
+		for _, v := range res.Violations {
+			out[v.Rule] = v.Fixed
+		}
+		return out
+	}
+	res, err := exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 0.00123, 30000.04, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rules(res)
+	if len(got) != 2 || got[VioPricePrec] || got[VioAmountPrec] || res.Valid() || res.Error() == nil {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+	res, err = exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 0.00123, 30000.04, map[string]interface{}{
+		ParamAdjust: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Valid() || res.Error() != nil || res.Amount != 0.001 || res.Price != 30000 {
+		t.Fatalf("adjust fail, amount %v price %v, %v", res.Amount, res.Price, rules(res))
+	}
+	res, err = exg.ValidateOrder(symbol, OdTypeMarket, OdSideSell, 20, 30000, map[string]interface{}{
+		ParamAdjust: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = rules(res); len(got) != 1 || !got[VioMarketMax] || res.Amount != 10 {
+		t.Fatalf("market max fail, amount %v, %v", res.Amount, got)
+	}
+	res, err = exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 0.0001, 30000, map[string]interface{}{
+		ParamAdjust: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = rules(res)
+	if fixed, ok := got[VioAmountMin]; !ok || fixed || !got[VioAmountPrec] || res.Valid() {
+		t.Fatalf("amount min should not be fixed: %v", got)
+	}
+	res, err = exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 1, 30000, map[string]interface{}{
+		ParamHedged: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rules(res)[VioPosSide]; !ok {
+		t.Fatalf("position side required in hedge mode")
+	}
+	res, err = exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 1, 30000, map[string]interface{}{
+		ParamHedged: true, ParamPositionSide: PosSideLong,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Violations) != 0 {
+		t.Fatalf("unexpected violations: %v", rules(res))
+	}
+	res, err = exg.ValidateOrder("ETH/USDT", OdTypeLimit, OdSideBuy, 1, 3000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rules(res)[VioInactive]; !ok {
+		t.Fatalf("inactive market should be reported")
+	}
+	// 22:00-02:00 UTC
+	times := [][2]int64{{22 * 3600000, 26 * 3600000}}
+	day := int64(86400000) * 20000
+	if !inTradeTimes(times, day+3600000, nil) || !inTradeTimes(times, day+23*3600000, nil) ||
+		inTradeTimes(times, day+12*3600000, nil) {
+		t.Fatalf("inTradeTimes fail")
+	}
+	// day is a friday. china: day session 09:00-10:15 and night session 21:00-01:00 local time
+	loc := time.FixedZone("CST", 8*3600)
+	times = [][2]int64{{1 * 3600000, 2*3600000 + 15*60000}, {13 * 3600000, 17 * 3600000}}
+	hour := int64(3600000)
+	cases := []struct {
+		stamp int64
+		open  bool
+	}{
+		{day + 90*60000, true},             // friday 09:30
+		{day + 16*hour, true},              // saturday 00:00 local, friday night session
+		{day + 24*hour + 90*60000, false},  // saturday 09:30
+		{day + 2*24*hour + 14*hour, false}, // sunday 22:00
+		{day + 3*24*hour + 90*60000, true}, // monday 09:30
+		{day + 3*24*hour + 14*hour, true},  // monday 22:00
+	}
+	for _, c := range cases {
+		if open := inTradeTimes(times, c.stamp, loc); open != c.open {
+			t.Errorf("inTradeTimes %v expect %v, got %v", time.UnixMilli(c.stamp).In(loc), c.open, open)
+		}
+	}
+	// sessions of a market with TimeLoc are checked by weekday
+	exg.TimeLoc = loc
+	exg.Markets[symbol].DayTimes = [][2]int64{{0, 24 * hour}}
+	res, err = exg.ValidateOrder(symbol, OdTypeLimit, OdSideBuy, 1, 30000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := exg.MilliSeconds()
+	// the session starts at 00:00 UTC today
+	wd := time.UnixMilli(now - now%(24*hour)).In(loc).Weekday()
+	weekend := wd == time.Saturday || wd == time.Sunday
+	if _, ok := rules(res)[VioSession]; ok != weekend {
+		t.Fatalf("session violation %v on %v", ok, wd)
+	}
+}
@@ -209,6 +209,230 @@ func TestFetchPositionsStub(t *testing.T) {
 	}
 }
 
+func TestTransferStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5AssetTransferInterTransfer, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["fromAccountType"] != "UNIFIED" || params["toAccountType"] != "FUND" {
+			t.Fatalf("unexpected account types: %v -> %v", params["fromAccountType"], params["toAccountType"])
+		}
+		if params["coin"] != "USDT" || params["amount"] != "12.5" {
+			t.Fatalf("unexpected coin/amount: %v %v", params["coin"], params["amount"])
+		}
+		if id, _ := params["transferId"].(string); len(id) != 36 {
+			t.Fatalf("transferId should be uuid, got %v", params["transferId"])
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"transferId":"42c0cfb0-6bca-c242-bc76-4e6df6cbcb16","status":"SUCCESS"},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	res, err := exg.Transfer("USDT", 12.5, banexg.MarketLinear, banexg.AccountFunding, nil)
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if res.ID != "42c0cfb0-6bca-c242-bc76-4e6df6cbcb16" || res.Status != banexg.TransferOk {
+		t.Fatalf("unexpected transfer: %+v", res)
+	}
+	if _, err = exg.Transfer("USDT", 1, banexg.MarketSpot, banexg.MarketLinear, nil); err == nil {
+		t.Fatal("transfer inside unified account should fail")
+	}
+}
+
+func TestSubAccTransferStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}, Accounts: map[string]*banexg.Account{
+		"main": {Name: "main", Data: map[string]interface{}{banexg.OptSubAccId: "1001"}},
+		"sub1": {Name: "sub1", Data: map[string]interface{}{banexg.OptSubAccId: "2002"}},
+	}}}
+	exg.DefAccName = "main"
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5AssetTransferUniversalTransfer, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["fromMemberId"] != "1001" || params["toMemberId"] != "2002" {
+			t.Fatalf("unexpected member ids: %v -> %v", params["fromMemberId"], params["toMemberId"])
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"transferId":"be7a2462-1138-4e27-80b1-62653f24925e","status":"PENDING"},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	res, err := exg.Transfer("USDT", 5, banexg.AccountFunding, banexg.AccountFunding,
+		map[string]interface{}{banexg.ParamToSubAcc: "sub1"})
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if res.Status != banexg.TransferPending || res.ToSubAcc != "sub1" {
+		t.Fatalf("unexpected transfer: %+v", res)
+	}
+}
+
+func TestFetchTransfersStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivateGetV5AssetTransferQueryInterTransferList, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["coin"] != "USDT" || params["startTime"] != int64(1700000000000) {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"list":[
+{"transferId":"a1","coin":"USDT","amount":"10","fromAccountType":"UNIFIED","toAccountType":"FUND","timestamp":"1700000001000","status":"SUCCESS"},
+{"transferId":"a2","coin":"USDT","amount":"3","fromAccountType":"FUND","toAccountType":"UNIFIED","timestamp":"1700000002000","status":"FAILED"}
+],"nextPageCursor":""},"time":1700000003000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	items, err := exg.FetchTransfers("USDT", 1700000000000, 0,
+		map[string]interface{}{banexg.ParamToAccount: banexg.AccountFunding})
+	if err != nil {
+		t.Fatalf("FetchTransfers failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 transfer to funding, got %d", len(items))
+	}
+	it := items[0]
+	if it.ID != "a1" || it.Amount != 10 || it.FromAccount != banexg.MarketSpot || it.Timestamp != 1700000001000 ||
+		it.Status != banexg.TransferOk {
+		t.Fatalf("unexpected transfer: %+v", it)
+	}
+}
+
+func TestFetchWithdrawalsStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivateGetV5AssetWithdrawQueryRecord, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["coin"] != "USDT" {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"rows":[
+{"coin":"USDT","chain":"ETH","amount":"8","txID":"0xabc","status":"success","toAddress":"0x99","tag":"","withdrawFee":"1.5","createTime":"1700000001000","updateTime":"1700000009000","withdrawId":"10197","withdrawType":0},
+{"coin":"USDT","chain":"TRX","amount":"3","txID":"","status":"CancelByUser","toAddress":"T99","tag":"","withdrawFee":"1","createTime":"1700000002000","updateTime":"1700000003000","withdrawId":"10198","withdrawType":0}
+],"nextPageCursor":""},"time":1700000010000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	items, err := exg.FetchWithdrawals("USDT", 0, 0, nil)
+	if err != nil {
+		t.Fatalf("FetchWithdrawals failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 withdrawals, got %d", len(items))
+	}
+	it := items[0]
+	if it.ID != "10197" || it.TxID != "0xabc" || it.Amount != 8 || it.Fee.Cost != 1.5 || it.Status != banexg.TransferOk ||
+		it.Network != "ETH" || it.Updated != 1700000009000 {
+		t.Fatalf("unexpected withdrawal: %+v", it)
+	}
+	if items[1].Status != banexg.TransferCanceled {
+		t.Fatalf("expected canceled, got %s", items[1].Status)
+	}
+}
+
+func TestWithdrawStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5AssetWithdrawCreate, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["chain"] != "TRX" || params["address"] != "T99" || params["amount"] != "20" || params["accountType"] != "FUND" {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		if _, ok := params["timestamp"]; !ok {
+			t.Fatal("timestamp is required")
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"id":"10195"},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	res, err := exg.Withdraw("USDT", 20, "T99", "", "TRX", nil)
+	if err != nil {
+		t.Fatalf("Withdraw failed: %v", err)
+	}
+	if res.ID != "10195" || res.Status != banexg.TransferPending {
+		t.Fatalf("unexpected withdrawal: %+v", res)
+	}
+}
+
+func TestBorrowStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5AccountBorrow, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["coin"] != "USDT" || params["amount"] != "100" {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":0,"retMsg":"success","result":{"coin":"USDT","amount":"100"},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	res, err := exg.Borrow("USDT", 100, "", nil)
+	if err != nil {
+		t.Fatalf("Borrow failed: %v", err)
+	}
+	if res.Code != "USDT" || res.MarginMode != banexg.MarginCross {
+		t.Fatalf("unexpected loan: %+v", res)
+	}
+	_, err = exg.Borrow("USDT", 100, "BTC/USDT", nil)
+	if err == nil || err.Code != errs.CodeNotSupport {
+		t.Fatalf("isolated borrow should be unsupported, got %v", err)
+	}
+}
+
+func TestFetchBorrowInterestStub(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{ExgInfo: &banexg.ExgInfo{}}}
+	setBybitTestRequestWithEndpoint(t, MethodPrivateGetV5AccountBorrowHistory, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["currency"] != "USDT" || params["startTime"] != int64(1700000000000) {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":0,"retMsg":"OK","result":{"list":[{"currency":"USDT","createdTime":1700003600000,` +
+			`"borrowCost":"0.05","hourlyBorrowRate":"0.00001","InterestBearingBorrowSize":"5000"}],"nextPageCursor":""},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	items, err := exg.FetchBorrowInterest("USDT", "", 1700000000000, 10, nil)
+	if err != nil {
+		t.Fatalf("FetchBorrowInterest failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	it := items[0]
+	if it.Interest != 0.05 || it.Amount != 5000 || it.Rate != 0.00001 || it.Timestamp != 1700003600000 {
+		t.Fatalf("unexpected interest: %+v", it)
+	}
+}
+
+func TestSetPositionModeStub(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5PositionSwitchMode, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["category"] != banexg.MarketLinear || params["coin"] != "USDT" || params["mode"] != 3 {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":110025,"retMsg":"Position mode is not modified","result":{},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	_, err := exg.SetPositionMode(true, map[string]interface{}{banexg.ParamMarket: banexg.MarketLinear})
+	if err != nil {
+		t.Fatalf("SetPositionMode failed: %v", err)
+	}
+}
+
+func TestSetCancelAllAfterStub(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5OrderDisconnectedCancelAll, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["product"] != "DERIVATIVES" || params["timeWindow"] != int64(10) {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		return &banexg.HttpRes{Content: `{"retCode":0,"retMsg":"success","result":{},"time":1700000000000}`}
+	})
+	if _, err := exg.SetCancelAllAfter(9500, "BTC/USDT:USDT", nil); err != nil {
+		t.Fatalf("SetCancelAllAfter failed: %v", err)
+	}
+	if _, err := exg.SetCancelAllAfter(0, "BTC/USDT:USDT", nil); err == nil || err.Code != errs.CodeNotSupport {
+		t.Fatalf("expect CodeNotSupport for disarm, got %v", err)
+	}
+	if _, err := exg.SetCancelAllAfter(1000, "BTC/USDT:USDT", nil); err == nil || err.Code != errs.CodeParamInvalid {
+		t.Fatalf("expect CodeParamInvalid for 1s, got %v", err)
+	}
+}
+
+func TestReduceMarginStub(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5PositionAddMargin, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["symbol"] != "BTCUSDT" || params["margin"] != "-5" || params["positionIdx"] != 1 {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		content := `{"retCode":0,"retMsg":"OK","result":{"symbol":"BTCUSDT","positionIM":"95"},"time":1700000000000}`
+		return &banexg.HttpRes{Content: content}
+	})
+	res, err := exg.ReduceMargin("BTC/USDT:USDT", 5, map[string]interface{}{banexg.ParamPositionSide: "long"})
+	if err != nil {
+		t.Fatalf("ReduceMargin failed: %v", err)
+	}
+	if res["positionIM"] != "95" {
+		t.Fatalf("unexpected result: %v", res)
+	}
+}
+
 func TestBuildBybitLeverageBrackets(t *testing.T) {
 	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
 	items := []RiskLimitInfo{
@@ -1,7 +1,9 @@
 package bybit
 
 import (
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
@@ -40,20 +42,25 @@ func (e *Bybit) FetchOHLCV(symbol, timeframe string, since int64, limit int, par
 		return nil, err
 	}
 	args["category"] = category
+	priceType := utils.PopMapVal(args, banexg.ParamPrice, "")
+	if priceType != "" && market.Spot {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "%s klines only support linear/inverse", priceType)
+	}
 	var method string
-	if market.Spot {
+	switch priceType {
+	case "":
 		method = MethodPublicGetV5MarketKline
-	} else {
-		price := utils.PopMapVal(args, "price", "")
-		if price == "mark" {
-			method = MethodPublicGetV5MarketMarkPriceKline
-		} else if price == "index" {
-			method = MethodPublicGetV5MarketIndexPriceKline
-		} else if price == "premiumIndex" {
-			method = MethodPublicGetV5MarketPremiumIndexPriceKline
-		} else {
-			method = MethodPublicGetV5MarketKline
+	case banexg.PriceMark:
+		method = MethodPublicGetV5MarketMarkPriceKline
+	case banexg.PriceIndex:
+		method = MethodPublicGetV5MarketIndexPriceKline
+	case banexg.PricePremiumIndex:
+		if !market.Linear {
+			return nil, errs.NewMsg(errs.CodeUnsupportMarket, "premium index klines only support linear")
 		}
+		method = MethodPublicGetV5MarketPremiumIndexPriceKline
+	default:
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport %s: %s", banexg.ParamPrice, priceType)
 	}
 	tryNum := e.GetRetryNum("FetchOHLCV", 1)
 	rsp := requestRetry[struct {
@@ -364,6 +371,69 @@ func (e *Bybit) FetchOrderBook(symbol string, limit int, params map[string]inter
 	return book, nil
 }
 
+/*
+FetchTrades
+Bybit only returns recent public trades, since/ParamUntil are used to filter the result.
+Bybit仅支持获取最近的公开成交，since/ParamUntil仅用于过滤结果
+
+:see: https://bybit-exchange.github.io/docs/v5/market/recent-trade
+*/
+func (e *Bybit) FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Trade, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args["symbol"] = market.ID
+	category, err := bybitCategoryFromMarket(market)
+	if err != nil {
+		return nil, err
+	}
+	args["category"] = category
+	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
+	maxLimit := 1000
+	if market.Spot {
+		maxLimit = 60
+	}
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	args["limit"] = limit
+	tryNum := e.GetRetryNum("FetchTrades", 1)
+	rsp := requestRetry[V5ListResult](e, MethodPublicGetV5MarketRecentTrade, args, tryNum)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+	return parseBybitTrades(e, rsp.Result.List, market.Type, since, until), nil
+}
+
+func parseBybitTrades(e *Bybit, items []map[string]interface{}, marketType string, since, until int64) []*banexg.Trade {
+	result := make([]*banexg.Trade, 0, len(items))
+	for _, item := range items {
+		marketID := bybitWsString(item["symbol"])
+		price := parseBybitNum(item["price"])
+		amount := parseBybitNum(item["size"])
+		stamp := parseBybitInt(item["time"])
+		if since > 0 && stamp < since || until > 0 && stamp > until {
+			continue
+		}
+		result = append(result, &banexg.Trade{
+			ID:        bybitWsString(item["execId"]),
+			Symbol:    bybitSafeSymbol(e, marketID, marketType),
+			Side:      strings.ToLower(bybitWsString(item["side"])),
+			Amount:    amount,
+			Price:     price,
+			Cost:      price * amount,
+			Timestamp: stamp,
+			Info:      item,
+		})
+	}
+	// bybit returns the newest first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result
+}
+
 func (e *Bybit) FetchFundingRate(symbol string, params map[string]interface{}) (*banexg.FundingRateCur, *errs.Error) {
 	args, market, err := e.LoadArgsMarket(symbol, params)
 	if err != nil {
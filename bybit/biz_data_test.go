@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
 	"github.com/banbox/banexg/utils"
 )
 
@@ -251,8 +252,9 @@ func TestFetchOHLCVPriceEndpoints(t *testing.T) {
 		price  string
 		method string
 	}{
-		{name: "index", price: "index", method: MethodPublicGetV5MarketIndexPriceKline},
-		{name: "premium", price: "premiumIndex", method: MethodPublicGetV5MarketPremiumIndexPriceKline},
+		{name: "mark", price: banexg.PriceMark, method: MethodPublicGetV5MarketMarkPriceKline},
+		{name: "index", price: banexg.PriceIndex, method: MethodPublicGetV5MarketIndexPriceKline},
+		{name: "premium", price: banexg.PricePremiumIndex, method: MethodPublicGetV5MarketPremiumIndexPriceKline},
 	}
 
 	for _, tc := range cases {
@@ -267,7 +269,7 @@ func TestFetchOHLCVPriceEndpoints(t *testing.T) {
 				return &banexg.HttpRes{Status: 200, Content: body}
 			})
 
-			params := map[string]interface{}{"price": tc.price}
+			params := map[string]interface{}{banexg.ParamPrice: tc.price}
 			klines, err := exg.FetchOHLCV("BTC/USDT:USDT", "1m", 0, 1, params)
 			if err != nil {
 				t.Fatalf("FetchOHLCV failed: %v", err)
@@ -302,6 +304,32 @@ func TestFetchOrderBookParams(t *testing.T) {
 	}
 }
 
+func TestFetchTradesParams(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT", banexg.MarketSpot)
+	setBybitTestRequest(t, func(_ context.Context, endpoint string, params map[string]interface{}, _ int, _ bool, _ bool) *banexg.HttpRes {
+		requireBybitReq(t, endpoint, params, MethodPublicGetV5MarketRecentTrade, banexg.MarketSpot, "BTCUSDT")
+		if params["limit"] != 60 {
+			t.Fatalf("unexpected limit: %v", params["limit"])
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[` +
+			`{"execId":"3","symbol":"BTCUSDT","price":"101","size":"2","side":"Sell","time":"1700000000300"},` +
+			`{"execId":"2","symbol":"BTCUSDT","price":"100","size":"1","side":"Buy","time":"1700000000200"},` +
+			`{"execId":"1","symbol":"BTCUSDT","price":"99","size":"1","side":"Buy","time":"1700000000100"}]},"retExtInfo":{},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+
+	trades, err := exg.FetchTrades("BTC/USDT", 1700000000150, 0, nil)
+	if err != nil {
+		t.Fatalf("FetchTrades failed: %v", err)
+	}
+	if len(trades) != 2 || trades[0].ID != "2" || trades[1].ID != "3" {
+		t.Fatalf("unexpected trades: %+v", trades)
+	}
+	if trades[1].Symbol != "BTC/USDT" || trades[1].Side != banexg.OdSideSell || trades[1].Cost != 202 {
+		t.Fatalf("unexpected trade: %+v", trades[1])
+	}
+}
+
 func TestFetchOHLCVParams(t *testing.T) {
 	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
 	setBybitTestRequest(t, func(_ context.Context, endpoint string, params map[string]interface{}, _ int, _ bool, _ bool) *banexg.HttpRes {
@@ -336,3 +364,52 @@ func TestFetchOHLCVParams(t *testing.T) {
 		t.Fatalf("unexpected kline order: %d,%d", klines[0].Time, klines[1].Time)
 	}
 }
+
+func TestFetchOpenInterestHistoryParams(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequest(t, func(_ context.Context, endpoint string, params map[string]interface{}, _ int, _ bool, _ bool) *banexg.HttpRes {
+		requireBybitReq(t, endpoint, params, MethodPublicGetV5MarketOpenInterest, banexg.MarketLinear, "BTCUSDT")
+		if params["intervalTime"] != "1h" || params["startTime"] != int64(123) || params["limit"] != 2 {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"symbol":"BTCUSDT","category":"linear","list":[` +
+			`{"openInterest":"200.5","timestamp":"7200000"},{"openInterest":"100","timestamp":"3600000"}],"nextPageCursor":""},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+	items, err := exg.FetchOpenInterestHistory("BTC/USDT:USDT", "1h", 123, 2, nil)
+	if err != nil {
+		t.Fatalf("FetchOpenInterestHistory failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Timestamp != 3600000 || items[1].Amount != 200.5 {
+		t.Fatalf("unexpected open interest: %+v", items)
+	}
+	if _, err = exg.FetchOpenInterestHistory("BTC/USDT:USDT", "2h", 0, 0, nil); err == nil {
+		t.Fatalf("expected error for unsupported timeframe")
+	}
+}
+
+func TestFetchLongShortRatioHistoryParams(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequest(t, func(_ context.Context, endpoint string, params map[string]interface{}, _ int, _ bool, _ bool) *banexg.HttpRes {
+		requireBybitReq(t, endpoint, params, MethodPublicGetV5MarketAccountRatio, banexg.MarketLinear, "BTCUSDT")
+		if params["period"] != "5min" {
+			t.Fatalf("unexpected period: %v", params["period"])
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"list":[` +
+			`{"symbol":"BTCUSDT","buyRatio":"0.75","sellRatio":"0.25","timestamp":"300000"}],"nextPageCursor":""},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+	items, err := exg.FetchLongShortRatioHistory("BTC/USDT:USDT", "5m", 0, 1, nil)
+	if err != nil {
+		t.Fatalf("FetchLongShortRatioHistory failed: %v", err)
+	}
+	if len(items) != 1 || items[0].LongRatio != 0.75 || items[0].ShortRatio != 0.25 || items[0].Ratio != 3 {
+		t.Fatalf("unexpected ratio: %+v", items)
+	}
+	_, err = exg.FetchLongShortRatioHistory("BTC/USDT:USDT", "5m", 0, 1, map[string]interface{}{
+		banexg.ParamRatioType: banexg.RatioTaker,
+	})
+	if err == nil || err.Code != errs.CodeNotSupport {
+		t.Fatalf("expected not support error, got %v", err)
+	}
+}
@@ -63,6 +63,114 @@ func (e *Bybit) SetLeverage(leverage float64, symbol string, params map[string]i
 	return res.Result, nil
 }
 
+/*
+SetMarginMode
+margin mode of unified trading account applies to all symbols, symbol is ignored
+统一交易账户的保证金模式作用于所有品种，忽略symbol
+:see: https://bybit-exchange.github.io/docs/v5/account/set-margin-mode
+*/
+func (e *Bybit) SetMarginMode(mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	if mode == banexg.MarginCross {
+		args["setMarginMode"] = "REGULAR_MARGIN"
+	} else if mode == banexg.MarginIsolated {
+		args["setMarginMode"] = "ISOLATED_MARGIN"
+	} else {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid margin mode: %s", mode)
+	}
+	tryNum := e.GetRetryNum("SetMarginMode", 1)
+	res := requestRetry[map[string]interface{}](e, MethodPrivatePostV5AccountSetMarginMode, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return res.Result, nil
+}
+
+/*
+SetPositionMode
+switch position mode of all symbols settled in ParamCurrency (USDT by default), or symbol in params
+切换以ParamCurrency(默认USDT)结算的所有品种的持仓模式，也可在params中传入symbol
+:see: https://bybit-exchange.github.io/docs/v5/position/position-mode
+*/
+func (e *Bybit) SetPositionMode(hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType, _, err := e.LoadArgsMarketType(args)
+	if err != nil {
+		return nil, err
+	}
+	if marketType != banexg.MarketLinear && marketType != banexg.MarketInverse {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "SetPositionMode supports linear/inverse only")
+	}
+	args["category"] = marketType
+	coin := utils.PopMapVal(args, banexg.ParamCurrency, "")
+	if _, ok := args["symbol"]; !ok {
+		if coin == "" {
+			coin = "USDT"
+		}
+		args["coin"] = coin
+	}
+	args["mode"] = 0
+	if hedged {
+		args["mode"] = 3
+	}
+	tryNum := e.GetRetryNum("SetPositionMode", 1)
+	res := requestRetry[map[string]interface{}](e, MethodPrivatePostV5PositionSwitchMode, args, tryNum)
+	if res.Error != nil {
+		// 110025: position mode is not modified
+		if res.Error.BizCode == 110025 {
+			return map[string]interface{}{}, nil
+		}
+		return nil, res.Error
+	}
+	return res.Result, nil
+}
+
+/*
+AddMargin
+:see: https://bybit-exchange.github.io/docs/v5/position/manual-add-margin
+*/
+func (e *Bybit) AddMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, amount, params)
+}
+
+/*
+ReduceMargin
+:see: https://bybit-exchange.github.io/docs/v5/position/manual-add-margin
+*/
+func (e *Bybit) ReduceMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, -amount, params)
+}
+
+// modifyMargin add margin to position when amount is positive, reduce when negative
+func (e *Bybit) modifyMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if amount == 0 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "margin amount is zero")
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	category, err := bybitCategoryFromMarket(market)
+	if err != nil {
+		return nil, err
+	}
+	if category != banexg.MarketLinear && category != banexg.MarketInverse {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "position margin supports linear/inverse only")
+	}
+	if err = ensureBybitPositionIdx(args); err != nil {
+		return nil, err
+	}
+	args["category"] = category
+	args["symbol"] = market.ID
+	args["margin"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	// not idempotent, never retry
+	res := requestRetry[map[string]interface{}](e, MethodPrivatePostV5PositionAddMargin, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return res.Result, nil
+}
+
 func (e *Bybit) LoadLeverageBrackets(reload bool, params map[string]interface{}) *errs.Error {
 	e.LeverageBracketsLock.Lock()
 	if !reload && len(e.LeverageBrackets) > 0 {
@@ -181,7 +289,11 @@ func (e *Bybit) GetLeverage(symbol string, notional float64, account string) (fl
 	if curLev <= 0 && market != nil {
 		category, err := bybitCategoryFromMarket(market)
 		if err == nil && (category == banexg.MarketLinear || category == banexg.MarketInverse) {
-			if lev, err := e.fetchCurrentLeverageFromPosition(market, account); err == nil && lev > 0 {
+			args := map[string]interface{}{}
+			if account != "" {
+				args[banexg.ParamAccount] = account
+			}
+			if lev, err := e.fetchCurrentLeverageFromPosition(market, args); err == nil && lev > 0 {
 				curLev = lev
 				if acc, ok := e.Accounts[account]; ok && acc != nil && acc.LockLeverage != nil {
 					acc.LockLeverage.Lock()
@@ -197,7 +309,7 @@ func (e *Bybit) GetLeverage(symbol string, notional float64, account string) (fl
 	return curLev, maxVal
 }
 
-func (e *Bybit) fetchCurrentLeverageFromPosition(market *banexg.Market, account string) (float64, *errs.Error) {
+func (e *Bybit) fetchCurrentLeverageFromPosition(market *banexg.Market, params map[string]interface{}) (float64, *errs.Error) {
 	if e == nil || market == nil || market.ID == "" {
 		return 0, errs.NewMsg(errs.CodeParamInvalid, "market is required")
 	}
@@ -208,13 +320,9 @@ func (e *Bybit) fetchCurrentLeverageFromPosition(market *banexg.Market, account
 	if category != banexg.MarketLinear && category != banexg.MarketInverse {
 		return 0, errs.NewMsg(errs.CodeUnsupportMarket, "GetLeverage supports linear/inverse only")
 	}
-	args := map[string]interface{}{
-		"category": category,
-		"symbol":   market.ID,
-	}
-	if account != "" {
-		args[banexg.ParamAccount] = account
-	}
+	args := utils.SafeParams(params)
+	args["category"] = category
+	args["symbol"] = market.ID
 	tryNum := e.GetRetryNum("GetLeverage", 1)
 	res := requestRetry[V5ListResult](e, MethodPrivateGetV5PositionList, args, tryNum)
 	if res.Error != nil {
@@ -0,0 +1,151 @@
+package bybit
+
+import (
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+/*
+Borrow
+manual borrow of unified trading account, only cross margin is supported
+统一交易账户手动借币，仅支持全仓
+:see: https://bybit-exchange.github.io/docs/v5/account/borrow
+*/
+func (e *Bybit) Borrow(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay(MethodPrivatePostV5AccountBorrow, code, amount, symbol, params)
+}
+
+/*
+Repay
+:see: https://bybit-exchange.github.io/docs/v5/account/repay
+*/
+func (e *Bybit) Repay(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay(MethodPrivatePostV5AccountRepay, code, amount, symbol, params)
+}
+
+func (e *Bybit) borrowRepay(method, code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	args := utils.SafeParams(params)
+	marginMode, err := banexg.GetLoanMarginMode(symbol, args)
+	if err != nil {
+		return nil, err
+	}
+	if marginMode == banexg.MarginIsolated {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "isolated margin loan is not supported by bybit unified account")
+	}
+	args["coin"] = code
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	// not idempotent, never retry
+	res := requestRetry[map[string]interface{}](e, method, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &banexg.MarginLoan{
+		Code:       code,
+		Amount:     amount,
+		MarginMode: banexg.MarginCross,
+		Timestamp:  e.MilliSeconds(),
+		Info:       res.Result,
+	}, nil
+}
+
+/*
+FetchBorrowRate
+:see: https://bybit-exchange.github.io/docs/v5/account/collateral-info
+*/
+func (e *Bybit) FetchBorrowRate(code string, params map[string]interface{}) (*banexg.BorrowRate, *errs.Error) {
+	rates, err := e.FetchBorrowRates([]string{code}, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "borrow rate of %s not found", code)
+	}
+	return rates[0], nil
+}
+
+/*
+FetchBorrowRates
+hourly rates of all currencies are returned if codes is empty
+codes为空时返回所有币种的小时利率
+:see: https://bybit-exchange.github.io/docs/v5/account/collateral-info
+*/
+func (e *Bybit) FetchBorrowRates(codes []string, params map[string]interface{}) ([]*banexg.BorrowRate, *errs.Error) {
+	args := utils.SafeParams(params)
+	if len(codes) == 1 {
+		args["currency"] = codes[0]
+	}
+	tryNum := e.GetRetryNum("FetchBorrowRates", 1)
+	items, err := fetchV5List(e, MethodPrivateGetV5AccountCollateralInfo, args, tryNum, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[struct {
+		Currency         string `json:"currency"`
+		HourlyBorrowRate string `json:"hourlyBorrowRate"`
+	}](items)
+	if err != nil {
+		return nil, err
+	}
+	var codeSet map[string]bool
+	if len(codes) > 1 {
+		codeSet = make(map[string]bool, len(codes))
+		for _, c := range codes {
+			codeSet[c] = true
+		}
+	}
+	stamp := e.MilliSeconds()
+	result := make([]*banexg.BorrowRate, 0, len(arr))
+	for i, it := range arr {
+		code := bybitSafeCurrency(e, it.Currency)
+		if codeSet != nil && !codeSet[code] {
+			continue
+		}
+		result = append(result, &banexg.BorrowRate{
+			Code:      code,
+			Rate:      parseBybitNum(it.HourlyBorrowRate),
+			Period:    3600000,
+			Timestamp: stamp,
+			Info:      items[i],
+		})
+	}
+	return result, nil
+}
+
+/*
+FetchBorrowInterest
+symbol is ignored as unified account has no isolated margin loan
+统一账户无逐仓借币，忽略symbol
+:see: https://bybit-exchange.github.io/docs/v5/account/borrow-history
+*/
+func (e *Bybit) FetchBorrowInterest(code, symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.BorrowInterest, *errs.Error) {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args["currency"] = code
+	}
+	applyBybitTimeRange(args, since)
+	tryNum := e.GetRetryNum("FetchBorrowInterest", 1)
+	items, err := fetchV5List(e, MethodPrivateGetV5AccountBorrowHistory, args, tryNum, limit, 50)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[BorrowRecord](items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.BorrowInterest, 0, len(arr))
+	for i, it := range arr {
+		result = append(result, &banexg.BorrowInterest{
+			Code:       bybitSafeCurrency(e, it.Currency),
+			MarginMode: banexg.MarginCross,
+			Interest:   parseBybitNum(it.BorrowCost),
+			Rate:       parseBybitNum(it.HourlyBorrowRate),
+			Amount:     parseBybitNum(it.InterestBearingBorrowSize),
+			Timestamp:  it.CreatedTime,
+			Info:       items[i],
+		})
+	}
+	return result, nil
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
@@ -35,11 +36,34 @@ func (e *Bybit) Init() *errs.Error {
 	return nil
 }
 
+/*
+FetchTime
+get server time of bybit
+获取bybit服务器时间
+:see: https://bybit-exchange.github.io/docs/v5/market/time
+*/
+func (e *Bybit) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	args := utils.SafeParams(params)
+	tryNum := e.GetRetryNum("FetchTime", 1)
+	res := requestRetry[struct {
+		TimeSecond string `json:"timeSecond"`
+		TimeNano   string `json:"timeNano"`
+	}](e, MethodPublicGetV5MarketTime, args, tryNum)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	nanos, err := strconv.ParseInt(res.Result.TimeNano, 10, 64)
+	if err != nil {
+		return 0, errs.New(errs.CodeInvalidResponse, err)
+	}
+	return nanos / int64(time.Millisecond), nil
+}
+
 func markRiskyApis(e *Bybit) {
 	riskyPaths := []string{
 		"order", "cancel", "batch", "leverage", "margin",
 		"position/set", "position/switch", "position/trading",
-		"transfer", "withdraw", "loan", "repay",
+		"transfer", "withdraw", "loan", "borrow", "repay",
 	}
 	for _, api := range e.Apis {
 		if api.Method == "GET" {
@@ -58,6 +82,8 @@ func makeSign(e *Bybit) banexg.FuncSign {
 	return func(api *banexg.Entry, args map[string]interface{}) *banexg.HttpReq {
 		var params = utils.SafeParams(args)
 		accID := e.PopAccName(params)
+		// only used by websocket trading api
+		delete(params, banexg.ParamWsTrade)
 		if err := e.CheckRiskyAllowed(api, accID); err != nil {
 			return &banexg.HttpReq{Error: err, Private: true}
 		}
@@ -145,6 +171,15 @@ func makeSign(e *Bybit) banexg.FuncSign {
 
 func requestRetry[T any](e *Bybit, api string, params map[string]interface{}, tryNum int) *banexg.ApiRes[T] {
 	noCache := utils.PopMapVal(params, banexg.ParamNoCache, false)
+	res := doRequestRetry[T](e, api, params, tryNum, noCache)
+	if e.ResyncOnNonce(banexg.ParamsCtx(params), res.Error) {
+		// timestamp out of recvWindow, sign again with synced server time
+		res = doRequestRetry[T](e, api, params, tryNum, noCache)
+	}
+	return res
+}
+
+func doRequestRetry[T any](e *Bybit, api string, params map[string]interface{}, tryNum int, noCache bool) *banexg.ApiRes[T] {
 	res_ := e.RequestApiRetryAdv(context.Background(), api, params, tryNum, !noCache, false)
 	res := &banexg.ApiRes[T]{HttpRes: res_}
 	if res.Error != nil {
@@ -535,7 +570,7 @@ func (e *Bybit) fetchOptionMarkets(params map[string]interface{}) (banexg.Market
 		mar.Maker = e.Fees.Option.Maker
 		mar.FeeSide = "quote"
 		mar.Strike, _ = strconv.ParseFloat(codeArr[2], 64)
-		mar.OptionType = it.OptionsType
+		mar.OptionType = strings.ToLower(it.OptionsType)
 		minOrderQty, maxOrderQty, lotQtyStep := it.LotSizeFilter.parse()
 		mar.Precision.Amount = lotQtyStep
 		mar.Precision.ModeAmount = banexg.PrecModeTickSize
@@ -2554,3 +2554,18 @@ func TestApi_FetchFundingRateHistoryWithSince(t *testing.T) {
 		banexg.ParamUntil: until,
 	})
 }
+
+func TestFetchTime(t *testing.T) {
+	exg := &Bybit{Exchange: &banexg.Exchange{}}
+	setBybitTestRequestWithEndpoint(t, MethodPublicGetV5MarketTime, func(params map[string]interface{}) *banexg.HttpRes {
+		content := `{"retCode":0,"retMsg":"OK","result":{"timeSecond":"1688639403","timeNano":"1688639403423213947"},"retExtInfo":{},"time":1688639403423}`
+		return &banexg.HttpRes{Content: content}
+	})
+	stamp, err := exg.FetchTime(nil)
+	if err != nil {
+		t.Fatalf("FetchTime failed: %v", err)
+	}
+	if stamp != 1688639403423 {
+		t.Fatalf("unexpected server time: %d", stamp)
+	}
+}
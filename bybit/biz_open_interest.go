@@ -0,0 +1,145 @@
+package bybit
+
+import (
+	"sort"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+// intervals supported by open-interest and account-ratio
+var dataIntervals = map[string]string{
+	"5m": "5min", "15m": "15min", "30m": "30min", "1h": "1h", "4h": "4h", "1d": "1d",
+}
+
+/*
+FetchOpenInterest
+open interest of the latest 5 minutes, in base coin for linear and USD for inverse
+最近5分钟的持仓量，U本位以币计，币本位以USD计
+:see: https://bybit-exchange.github.io/docs/v5/market/open-interest
+*/
+func (e *Bybit) FetchOpenInterest(symbol string, params map[string]interface{}) (*banexg.OpenInterest, *errs.Error) {
+	list, err := e.FetchOpenInterestHistory(symbol, "5m", 0, 1, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty open interest result")
+	}
+	return list[len(list)-1], nil
+}
+
+/*
+FetchOpenInterestHistory
+:see: https://bybit-exchange.github.io/docs/v5/market/open-interest
+*/
+func (e *Bybit) FetchOpenInterestHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.OpenInterest, *errs.Error) {
+	args, market, err := e.loadMarketDataArgs(symbol, since, params)
+	if err != nil {
+		return nil, err
+	}
+	interval, ok := dataIntervals[timeframe]
+	if !ok {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport timeframe: %s", timeframe)
+	}
+	args["intervalTime"] = interval
+	if limit <= 0 {
+		limit = 200
+	}
+	tryNum := e.GetRetryNum("FetchOpenInterestHistory", 1)
+	items, err := fetchV5List(e, MethodPublicGetV5MarketOpenInterest, args, tryNum, limit, 200)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[OpenInterestItem](items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.OpenInterest, 0, len(arr))
+	for i, it := range arr {
+		result = append(result, &banexg.OpenInterest{
+			Symbol:    market.Symbol,
+			Amount:    parseBybitNum(it.OpenInterest),
+			Timestamp: parseBybitInt(it.Timestamp),
+			Info:      items[i],
+		})
+	}
+	// bybit returns the newest first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result, nil
+}
+
+/*
+FetchLongShortRatioHistory
+long/short accounts ratio, RatioTaker is not supported
+多空账户数比，不支持RatioTaker
+:see: https://bybit-exchange.github.io/docs/v5/market/long-short-ratio
+*/
+func (e *Bybit) FetchLongShortRatioHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.LongShortRatio, *errs.Error) {
+	args, market, err := e.loadMarketDataArgs(symbol, since, params)
+	if err != nil {
+		return nil, err
+	}
+	ratioType := utils.PopMapVal(args, banexg.ParamRatioType, banexg.RatioAccount)
+	if ratioType != banexg.RatioAccount {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "%s not support: %s", banexg.ParamRatioType, ratioType)
+	}
+	interval, ok := dataIntervals[timeframe]
+	if !ok {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport timeframe: %s", timeframe)
+	}
+	args["period"] = interval
+	if limit <= 0 {
+		limit = 500
+	}
+	tryNum := e.GetRetryNum("FetchLongShortRatioHistory", 1)
+	items, err := fetchV5List(e, MethodPublicGetV5MarketAccountRatio, args, tryNum, limit, 500)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[AccountRatioItem](items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.LongShortRatio, 0, len(arr))
+	for i, it := range arr {
+		item := &banexg.LongShortRatio{
+			Symbol:     market.Symbol,
+			Timeframe:  timeframe,
+			Type:       ratioType,
+			LongRatio:  parseBybitNum(it.BuyRatio),
+			ShortRatio: parseBybitNum(it.SellRatio),
+			Timestamp:  parseBybitInt(it.Timestamp),
+			Info:       items[i],
+		}
+		if item.ShortRatio > 0 {
+			item.Ratio = item.LongRatio / item.ShortRatio
+		}
+		result = append(result, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result, nil
+}
+
+func (e *Bybit) loadMarketDataArgs(symbol string, since int64, params map[string]interface{}) (map[string]interface{}, *banexg.Market, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if market.Type != banexg.MarketLinear && market.Type != banexg.MarketInverse {
+		return nil, nil, errs.NewMsg(errs.CodeNotSupport, "only linear/inverse support")
+	}
+	category, err := bybitCategoryFromMarket(market)
+	if err != nil {
+		return nil, nil, err
+	}
+	args["category"] = category
+	args["symbol"] = market.ID
+	applyBybitTimeRange(args, since)
+	return args, market, nil
+}
@@ -0,0 +1,146 @@
+package bybit
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+	"github.com/banbox/bntp"
+)
+
+/*
+FetchGreeks
+greeks and implied volatility from option tickers, requested by baseCoin
+从期权ticker中解析希腊值和隐含波动率，按baseCoin请求
+:see: https://bybit-exchange.github.io/docs/v5/market/tickers
+*/
+func (e *Bybit) FetchGreeks(symbols []string, params map[string]interface{}) ([]*banexg.Greeks, *errs.Error) {
+	groups, err := e.optionBaseGroups(symbols)
+	if err != nil {
+		return nil, err
+	}
+	symbolSet := banexg.BuildSymbolSet(symbols)
+	tryNum := e.GetRetryNum("FetchGreeks", 1)
+	result := make([]*banexg.Greeks, 0, len(symbols))
+	for _, base := range groups {
+		args := utils.SafeParams(params)
+		args["category"] = "option"
+		args["baseCoin"] = base
+		rsp := requestRetry[V5ListResult](e, MethodPublicGetV5MarketTickers, args, tryNum)
+		if rsp.Error != nil {
+			return nil, rsp.Error
+		}
+		timeStamp := e.MilliSeconds()
+		for _, item := range rsp.Result.List {
+			it := parseBybitGreeks(e, item)
+			if it == nil {
+				continue
+			}
+			if _, ok := symbolSet[it.Symbol]; !ok {
+				continue
+			}
+			it.Timestamp = timeStamp
+			result = append(result, it)
+		}
+	}
+	return result, nil
+}
+
+// optionBaseGroups return base coins of option symbols, keep the order of first occurrence
+func (e *Bybit) optionBaseGroups(symbols []string) ([]string, *errs.Error) {
+	if len(symbols) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbols required")
+	}
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return nil, err
+	}
+	bases := make([]string, 0, 1)
+	exists := make(map[string]bool)
+	for _, sym := range symbols {
+		market, err := e.GetMarket(sym)
+		if err != nil {
+			return nil, err
+		}
+		if !market.Option {
+			return nil, errs.NewMsg(errs.CodeUnsupportMarket, "only option supported, got: %s", sym)
+		}
+		if market.Base == "" {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "option symbol missing base coin: %v", sym)
+		}
+		if !exists[market.Base] {
+			exists[market.Base] = true
+			bases = append(bases, market.Base)
+		}
+	}
+	return bases, nil
+}
+
+/*
+parseBybitGreeks
+parse option ticker of rest api (bid1Iv/ask1Iv/markIv) or websocket (bidIv/askIv/markPriceIv)
+解析rest接口或websocket的期权ticker
+*/
+func parseBybitGreeks(e *Bybit, item map[string]interface{}) *banexg.Greeks {
+	symbolID := bybitWsString(item["symbol"])
+	symbol := bybitSafeSymbol(e, symbolID, banexg.MarketOption)
+	if symbol == "" {
+		return nil
+	}
+	pick := func(keys ...string) float64 {
+		for _, k := range keys {
+			if val, ok := item[k]; ok {
+				return parseBybitNum(val)
+			}
+		}
+		return 0
+	}
+	return &banexg.Greeks{
+		Symbol:          symbol,
+		Delta:           pick("delta"),
+		Gamma:           pick("gamma"),
+		Vega:            pick("vega"),
+		Theta:           pick("theta"),
+		MarkIV:          pick("markIv", "markPriceIv"),
+		BidIV:           pick("bid1Iv", "bidIv"),
+		AskIV:           pick("ask1Iv", "askIv"),
+		MarkPrice:       pick("markPrice"),
+		UnderlyingPrice: pick("underlyingPrice"),
+		Info:            item,
+	}
+}
+
+/*
+WatchGreeks
+option tickers topic is shared with WatchTickers and WatchMarkPrices
+期权的tickers主题与WatchTickers和WatchMarkPrices共用
+:see: https://bybit-exchange.github.io/docs/v5/websocket/public/ticker
+*/
+func (e *Bybit) WatchGreeks(symbols []string, params map[string]interface{}) (chan *banexg.Greeks, *errs.Error) {
+	if _, err := e.optionBaseGroups(symbols); err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	create := func(cap int) chan *banexg.Greeks { return make(chan *banexg.Greeks, cap) }
+	return watchBybitWsPublicSymbols(e, args, symbols, bybitWsTickerTopics, "greeks", "WatchGreeks", symbols, create)
+}
+
+func (e *Bybit) UnWatchGreeks(symbols []string, params map[string]interface{}) *errs.Error {
+	if _, err := e.optionBaseGroups(symbols); err != nil {
+		return err
+	}
+	args := utils.SafeParams(params)
+	return e.unwatchWsTickerSymbols(args, symbols, bybitWsTickerTopics, "greeks", "tickers", "markPrice")
+}
+
+func (e *Bybit) handleWsGreeks(client *banexg.WsClient, base *wsBaseMsg, items []map[string]interface{}) {
+	chanKey := client.Prefix("greeks")
+	for _, item := range items {
+		res := parseBybitGreeks(e, item)
+		if res == nil || !e.HasWsChanRef(chanKey, res.Symbol) {
+			continue
+		}
+		res.Timestamp = base.Ts
+		client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
+		banexg.WriteOutChan(e.Exchange, chanKey, res, true)
+	}
+}
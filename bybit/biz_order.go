@@ -819,6 +819,44 @@ func (e *Bybit) createBybitTradingStop(symbol, side string, amount, price float6
 }
 
 func (e *Bybit) CreateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	od, err := e.newOrderArgs(symbol, odType, side, amount, price, params)
+	if err != nil {
+		return nil, err
+	}
+	if od.trailing {
+		return e.createBybitTradingStop(symbol, side, amount, price, od.market, od.args)
+	}
+	tryNum := e.GetRetryNum("CreateOrder", 1)
+	res := requestTrade[OrderResult](e, MethodPrivatePostV5OrderCreate, od.args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &banexg.Order{
+		ID:            res.Result.OrderId,
+		ClientOrderID: res.Result.OrderLinkId,
+		Symbol:        symbol,
+		Type:          odType,
+		Side:          side,
+		Amount:        amount,
+		Price:         price,
+		Status:        banexg.OdStatusOpen,
+		Timestamp:     e.MilliSeconds(),
+	}, nil
+}
+
+// bybitOrderArgs request args of a new order, built by newOrderArgs
+type bybitOrderArgs struct {
+	market   *banexg.Market
+	args     map[string]interface{}
+	trailing bool // should be placed by createBybitTradingStop
+}
+
+/*
+newOrderArgs
+validate the order and build the request args for CreateOrder/CreateOrders
+校验订单并构建CreateOrder/CreateOrders的请求参数
+*/
+func (e *Bybit) newOrderArgs(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*bybitOrderArgs, *errs.Error) {
 	args, market, _, _, err := e.loadBybitOrderArgs(symbol, params)
 	if err != nil {
 		return nil, err
@@ -833,14 +871,11 @@ func (e *Bybit) CreateOrder(symbol, odType, side string, amount, price float64,
 		"trailingStop",
 		"activePrice",
 	)
-	if hasTrailing {
-		if odType != banexg.OdTypeTrailingStopMarket {
-			return nil, errs.NewMsg(errs.CodeParamInvalid, "trailing stop params only supported for trailing stop orders")
-		}
-		return e.createBybitTradingStop(symbol, side, amount, price, market, args)
+	if hasTrailing && odType != banexg.OdTypeTrailingStopMarket {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "trailing stop params only supported for trailing stop orders")
 	}
 	if odType == banexg.OdTypeTrailingStopMarket {
-		return e.createBybitTradingStop(symbol, side, amount, price, market, args)
+		return &bybitOrderArgs{market: market, args: args, trailing: true}, nil
 	}
 	closePosition := utils.PopMapVal(args, banexg.ParamClosePosition, false)
 	reduceOnly := utils.PopMapVal(args, banexg.ParamReduceOnly, false)
@@ -1008,22 +1043,7 @@ func (e *Bybit) CreateOrder(symbol, odType, side string, amount, price float64,
 		}
 		args["price"] = strconv.FormatFloat(precPrice, 'f', -1, 64)
 	}
-	tryNum := e.GetRetryNum("CreateOrder", 1)
-	res := requestRetry[OrderResult](e, MethodPrivatePostV5OrderCreate, args, tryNum)
-	if res.Error != nil {
-		return nil, res.Error
-	}
-	return &banexg.Order{
-		ID:            res.Result.OrderId,
-		ClientOrderID: res.Result.OrderLinkId,
-		Symbol:        symbol,
-		Type:          odType,
-		Side:          side,
-		Amount:        amount,
-		Price:         price,
-		Status:        banexg.OdStatusOpen,
-		Timestamp:     e.MilliSeconds(),
-	}, nil
+	return &bybitOrderArgs{market: market, args: args}, nil
 }
 
 func (e *Bybit) EditOrder(symbol, orderId, side string, amount, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
@@ -1067,7 +1087,7 @@ func (e *Bybit) EditOrder(symbol, orderId, side string, amount, price float64, p
 		popAndSetBybitFloatArgs(args, true, "orderIv")
 	}
 	tryNum := e.GetRetryNum("EditOrder", 1)
-	res := requestRetry[OrderResult](e, MethodPrivatePostV5OrderAmend, args, tryNum)
+	res := requestTrade[OrderResult](e, MethodPrivatePostV5OrderAmend, args, tryNum)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -1095,7 +1115,7 @@ func (e *Bybit) CancelOrder(id string, symbol string, params map[string]interfac
 		return nil, err
 	}
 	tryNum := e.GetRetryNum("CancelOrder", 1)
-	res := requestRetry[OrderResult](e, MethodPrivatePostV5OrderCancel, args, tryNum)
+	res := requestTrade[OrderResult](e, MethodPrivatePostV5OrderCancel, args, tryNum)
 	if res.Error != nil {
 		return nil, res.Error
 	}
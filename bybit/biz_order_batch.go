@@ -0,0 +1,273 @@
+package bybit
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+type batchOrderRes struct {
+	List []orderRef `json:"list"`
+}
+
+type batchRetExt struct {
+	List []struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"list"`
+}
+
+func bybitBatchMax(category string) int {
+	if category == banexg.MarketSpot {
+		return 10
+	}
+	return 20
+}
+
+/*
+CreateOrders
+create-batch is used for orders of the same category, trailing stop orders are created one by one.
+同一category的订单使用create-batch批量下单，跟踪止损单逐个创建
+
+:see: https://bybit-exchange.github.io/docs/v5/order/batch-place
+*/
+func (e *Bybit) CreateOrders(orders []*banexg.OrderArgs, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	result := make([]*banexg.OrderRes, len(orders))
+	var categories []string
+	batches := make(map[string][]int)
+	items := make([]map[string]interface{}, len(orders))
+	for i, od := range orders {
+		result[i] = &banexg.OrderRes{}
+		args := banexg.MergeOrderParams(params, od.Params)
+		item, err := e.newOrderArgs(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+		if err != nil {
+			result[i].Err = err
+			continue
+		}
+		if item.trailing {
+			result[i].Order, result[i].Err = e.CreateOrder(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+			continue
+		}
+		category := utils.GetMapVal(item.args, "category", "")
+		if _, ok := batches[category]; !ok {
+			categories = append(categories, category)
+		}
+		batches[category] = append(batches[category], i)
+		items[i] = banexg.CleanBatchOrder(item.args)
+		delete(items[i], "category")
+	}
+	tryNum := e.GetRetryNum("CreateOrders", 1)
+	stamp := e.MilliSeconds()
+	for _, category := range categories {
+		idxList := batches[category]
+		batchMax := bybitBatchMax(category)
+		for start := 0; start < len(idxList); start += batchMax {
+			chunk := idxList[start:min(start+batchMax, len(idxList))]
+			reqs := make([]map[string]interface{}, 0, len(chunk))
+			for _, i := range chunk {
+				reqs = append(reqs, items[i])
+			}
+			refs, errList, err := e.requestBatch(MethodPrivatePostV5OrderCreateBatch, category, reqs, params, tryNum)
+			for j, i := range chunk {
+				if err != nil {
+					result[i].Err = err
+				} else if errList[j] != nil {
+					result[i].Err = errList[j]
+				} else {
+					od := orders[i]
+					result[i].Order = &banexg.Order{
+						ID:            refs[j].OrderId,
+						ClientOrderID: refs[j].OrderLinkId,
+						Symbol:        od.Symbol,
+						Type:          od.OdType,
+						Side:          od.Side,
+						Amount:        od.Amount,
+						Price:         od.Price,
+						Status:        banexg.OdStatusOpen,
+						Timestamp:     stamp,
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+/*
+CancelOrders
+cancel orders of symbol with cancel-batch
+
+:see: https://bybit-exchange.github.io/docs/v5/order/batch-cancel
+*/
+func (e *Bybit) CancelOrders(ids []string, symbol string, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	_, market, _, category, err := e.loadBybitOrderArgs(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	if market == nil {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbol is required")
+	}
+	result := make([]*banexg.OrderRes, len(ids))
+	batchMax := bybitBatchMax(category)
+	tryNum := e.GetRetryNum("CancelOrders", 1)
+	stamp := e.MilliSeconds()
+	for start := 0; start < len(ids); start += batchMax {
+		stop := min(start+batchMax, len(ids))
+		reqs := make([]map[string]interface{}, 0, stop-start)
+		for _, id := range ids[start:stop] {
+			reqs = append(reqs, map[string]interface{}{"symbol": market.ID, "orderId": id})
+		}
+		refs, errList, err := e.requestBatch(MethodPrivatePostV5OrderCancelBatch, category, reqs, params, tryNum)
+		for j, id := range ids[start:stop] {
+			res := &banexg.OrderRes{ID: id, Err: err}
+			result[start+j] = res
+			if err != nil {
+				continue
+			}
+			if errList[j] != nil {
+				res.Err = errList[j]
+				continue
+			}
+			res.Order = &banexg.Order{
+				ID:            refs[j].OrderId,
+				ClientOrderID: refs[j].OrderLinkId,
+				Symbol:        symbol,
+				Status:        banexg.OdStatusCanceled,
+				Timestamp:     stamp,
+			}
+		}
+	}
+	return result, nil
+}
+
+/*
+CancelAllOrders
+cancel all open orders of symbol with cancel-all. Emulated with FetchOpenOrders+CancelOrders when symbol is empty.
+使用cancel-all取消symbol的所有挂单，symbol为空时通过FetchOpenOrders+CancelOrders模拟
+
+:see: https://bybit-exchange.github.io/docs/v5/order/cancel-all
+*/
+func (e *Bybit) CancelAllOrders(symbol string, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	if symbol == "" {
+		return e.Exchange.CancelAllOrders(symbol, params)
+	}
+	args, _, _, _, err := e.loadBybitOrderArgs(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	tryNum := e.GetRetryNum("CancelAllOrders", 1)
+	res := requestRetry[batchOrderRes](e, MethodPrivatePostV5OrderCancelAll, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	stamp := e.MilliSeconds()
+	result := make([]*banexg.OrderRes, 0, len(res.Result.List))
+	for _, ref := range res.Result.List {
+		result = append(result, &banexg.OrderRes{ID: ref.OrderId, Order: &banexg.Order{
+			ID:            ref.OrderId,
+			ClientOrderID: ref.OrderLinkId,
+			Symbol:        symbol,
+			Status:        banexg.OdStatusCanceled,
+			Timestamp:     stamp,
+		}})
+	}
+	return result, nil
+}
+
+/*
+SetCancelAllAfter
+Disconnected Cancel Protection: all orders of the product are cancelled when the private and trade websocket
+connections are lost for timeWindow (3 ~ 300 seconds). It can not be turned off by api, so timeoutMs 0 returns
+CodeNotSupport. Product is DERIVATIVES/SPOT/OPTIONS by market type of symbol or params.
+Close also closes the websockets, so orders of the product are still cancelled after timeWindow on a graceful exit.
+断线撤单保护：私有和交易websocket连接断开超过timeWindow(3~300秒)后撤销该产品的所有订单。
+无法通过接口关闭，timeoutMs为0时返回CodeNotSupport。产品由symbol或params的市场类型决定。
+Close会关闭websocket，因此正常退出后timeWindow到期时仍会撤销该产品的订单
+
+:see: https://bybit-exchange.github.io/docs/v5/order/dcp
+*/
+func (e *Bybit) SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if timeoutMs == 0 {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "%v disconnected cancel protection can not be disarmed by api", e.Name)
+	}
+	secs := (timeoutMs + 999) / 1000
+	if timeoutMs < 0 || secs < 3 || secs > 300 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "%v dcp timeWindow should be 3~300s, got %dms", e.Name, timeoutMs)
+	}
+	args := utils.SafeParams(params)
+	product, err := e.dcpProduct(symbol, args)
+	if err != nil {
+		return nil, err
+	}
+	args["product"] = product
+	args["timeWindow"] = secs
+	tryNum := e.GetRetryNum("SetCancelAllAfter", 1)
+	res := requestRetry[map[string]interface{}](e, MethodPrivatePostV5OrderDisconnectedCancelAll, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return res.Result, nil
+}
+
+/*
+requestBatch
+send a batch request of category, return the result and error of each item.
+Bybit puts the error of each item in retExtInfo.list, in the same order as request.
+发送批量请求，返回每项的结果和错误，Bybit在retExtInfo.list中按请求顺序返回每项错误
+*/
+func (e *Bybit) requestBatch(method, category string, reqs []map[string]interface{}, params map[string]interface{}, tryNum int) ([]orderRef, []*errs.Error, *errs.Error) {
+	args := banexg.BatchOuterArgs(params)
+	args["category"] = category
+	args["request"] = reqs
+	res := requestRetry[batchOrderRes](e, method, args, tryNum)
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	var rsp V5Resp[batchOrderRes]
+	var ext batchRetExt
+	if err := utils.UnmarshalString(res.Content, &rsp, utils.JsonNumDefault); err != nil {
+		return nil, nil, errs.New(errs.CodeUnmarshalFail, err)
+	}
+	if len(rsp.RetExtInfo) > 0 {
+		if err := utils.Unmarshal(rsp.RetExtInfo, &ext, utils.JsonNumDefault); err != nil {
+			return nil, nil, errs.New(errs.CodeUnmarshalFail, err)
+		}
+	}
+	refs := res.Result.List
+	if len(refs) != len(reqs) {
+		return nil, nil, errs.NewMsg(errs.CodeInvalidResponse, "batch result count mismatch: %d/%d", len(refs), len(reqs))
+	}
+	errList := make([]*errs.Error, len(reqs))
+	for i, it := range ext.List {
+		if i < len(errList) && it.Code != 0 {
+			errList[i] = mapBybitRetCode(it.Code, it.Msg)
+		}
+	}
+	return refs, errList, nil
+}
+
+// dcpProduct product of the disconnected cancel protection for symbol, the timer is per account and product
+func (e *Bybit) dcpProduct(symbol string, args map[string]interface{}) (string, *errs.Error) {
+	marketType, _, err := e.LoadArgsMarketType(args, symbol)
+	if err != nil {
+		return "", err
+	}
+	switch marketType {
+	case banexg.MarketSpot:
+		return "SPOT", nil
+	case banexg.MarketOption:
+		return "OPTIONS", nil
+	default:
+		return "DERIVATIVES", nil
+	}
+}
+
+func makeCancelAfterScope(e *Bybit) func(symbol string, params map[string]interface{}) string {
+	return func(symbol string, params map[string]interface{}) string {
+		product, err := e.dcpProduct(symbol, utils.SafeParams(params))
+		if err != nil {
+			return symbol
+		}
+		return product
+	}
+}
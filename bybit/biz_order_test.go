@@ -3758,3 +3758,64 @@ func TestApi_FetchOrderHistoryToCSV(t *testing.T) {
 		writeMapSliceToCSV(t, dataList, csvPath)
 	}
 }
+
+func TestCreateOrdersBatch(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	ensureBybitMarketPrecision(exg, "BTC/USDT:USDT")
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5OrderCreateBatch, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["category"] != banexg.MarketLinear {
+			t.Fatalf("unexpected category: %v", params["category"])
+		}
+		reqs, ok := params["request"].([]map[string]interface{})
+		if !ok || len(reqs) != 2 {
+			t.Fatalf("unexpected request: %v", params["request"])
+		}
+		for _, req := range reqs {
+			if req["symbol"] != "BTCUSDT" {
+				t.Fatalf("unexpected symbol: %v", req["symbol"])
+			}
+			if _, ok := req["category"]; ok {
+				t.Fatalf("category should not be in batch item")
+			}
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"list":[{"orderId":"o1","orderLinkId":"l1"},{"orderId":"","orderLinkId":"l2"}]},` +
+			`"retExtInfo":{"list":[{"code":0,"msg":"OK"},{"code":170131,"msg":"Insufficient balance."}]},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+
+	res, err := exg.CreateOrders([]*banexg.OrderArgs{
+		{Symbol: "BTC/USDT:USDT", OdType: banexg.OdTypeLimit, Side: banexg.OdSideBuy, Amount: 0.01, Price: 100},
+		{Symbol: "BTC/USDT:USDT", OdType: banexg.OdTypeLimit, Side: banexg.OdSideSell, Amount: 0.01, Price: 200},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateOrders failed: %v", err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("unexpected result count: %d", len(res))
+	}
+	if res[0].Err != nil || res[0].Order == nil || res[0].Order.ID != "o1" {
+		t.Fatalf("unexpected first result: %+v", res[0])
+	}
+	if res[1].Err == nil || res[1].Err.BizCode != 170131 {
+		t.Fatalf("expected biz error for second order, got %+v", res[1].Err)
+	}
+}
+
+func TestCancelAllOrders(t *testing.T) {
+	exg := newBybitWithMarket("BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	setBybitTestRequestWithEndpoint(t, MethodPrivatePostV5OrderCancelAll, func(params map[string]interface{}) *banexg.HttpRes {
+		if params["category"] != banexg.MarketLinear || params["symbol"] != "BTCUSDT" {
+			t.Fatalf("unexpected params: %v", params)
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"list":[{"orderId":"o1","orderLinkId":"l1"},{"orderId":"o2","orderLinkId":"l2"}]},"retExtInfo":{},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+
+	res, err := exg.CancelAllOrders("BTC/USDT:USDT", nil)
+	if err != nil {
+		t.Fatalf("CancelAllOrders failed: %v", err)
+	}
+	if len(res) != 2 || res[1].ID != "o2" || res[1].Order.Status != banexg.OdStatusCanceled {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -291,3 +291,38 @@ func TestFetchTickersOptionGroupByBaseCoin(t *testing.T) {
 		t.Fatalf("expected 1 request per baseCoin, got %+v", callCounts)
 	}
 }
+
+func TestFetchGreeksOption(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new bybit exchange failed: %v", err)
+	}
+	symbol := "BTC/USDT:USDT-30DEC22-18000-C"
+	seedMarketWithBase(exg, "BTC-30DEC22-18000-C", symbol, banexg.MarketOption, "BTC")
+	seedMarketWithBase(exg, "BTC-30DEC22-20000-P", "BTC/USDT:USDT-30DEC22-20000-P", banexg.MarketOption, "BTC")
+	setBybitTestRequest(t, func(_ context.Context, endpoint string, params map[string]interface{}, _ int, _ bool, _ bool) *banexg.HttpRes {
+		requireBybitReq(t, endpoint, params, MethodPublicGetV5MarketTickers, banexg.MarketOption, "")
+		if params["baseCoin"] != "BTC" {
+			t.Fatalf("unexpected baseCoin: %v", params["baseCoin"])
+		}
+		body := `{"retCode":0,"retMsg":"OK","result":{"category":"option","list":[` +
+			`{"symbol":"BTC-30DEC22-18000-C","markPrice":"25500","bid1Iv":"0.5","ask1Iv":"0.6","markIv":"0.55","underlyingPrice":"25000","delta":"0.4","gamma":"0.0001","vega":"20","theta":"-30"},` +
+			`{"symbol":"BTC-30DEC22-20000-P","markPrice":"100","delta":"-0.2"}]},"retExtInfo":{},"time":1700000000000}`
+		return &banexg.HttpRes{Status: 200, Content: body}
+	})
+
+	res, fetchErr := exg.FetchGreeks([]string{symbol}, nil)
+	if fetchErr != nil {
+		t.Fatalf("FetchGreeks failed: %v", fetchErr)
+	}
+	if len(res) != 1 {
+		t.Fatalf("expected 1 greeks, got %d", len(res))
+	}
+	g := res[0]
+	if g.Symbol != symbol || g.Delta != 0.4 || g.Vega != 20 || g.Theta != -30 {
+		t.Fatalf("unexpected greeks: %+v", g)
+	}
+	if g.MarkIV != 0.55 || g.BidIV != 0.5 || g.AskIV != 0.6 || g.UnderlyingPrice != 25000 {
+		t.Fatalf("unexpected iv: %+v", g)
+	}
+}
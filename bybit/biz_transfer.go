@@ -0,0 +1,169 @@
+package bybit
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+var transferStatusMap = map[string]string{
+	"SUCCESS": banexg.TransferOk,
+	"PENDING": banexg.TransferPending,
+	"FAILED":  banexg.TransferFailed,
+}
+
+// bybitTransferAcc map account to bybit accountType, all trading accounts are UNIFIED for UTA
+func bybitTransferAcc(account string) (string, *errs.Error) {
+	if account == banexg.AccountFunding {
+		return "FUND", nil
+	}
+	if _, ok := banexg.AllMarketTypes[account]; ok {
+		return "UNIFIED", nil
+	}
+	return "", errs.NewMsg(errs.CodeParamInvalid, "unsupport transfer account: %s", account)
+}
+
+func parseBybitTransferAcc(accType string) string {
+	if accType == "FUND" {
+		return banexg.AccountFunding
+	}
+	return banexg.MarketSpot
+}
+
+// newTransferId transferId of bybit must be UUID
+func newTransferId() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+/*
+Transfer
+transfer between unified trading account and funding account. When ParamFromSubAcc or ParamToSubAcc is given,
+universal transfer is used with member ids (OptSubAccId), the missing side is the current account.
+在统一交易账户和资金账户间划转。传入ParamFromSubAcc或ParamToSubAcc时使用万向划转，需成员id(OptSubAccId)，
+未传入的一方为当前账户
+:see: https://bybit-exchange.github.io/docs/v5/asset/transfer/create-inter-transfer
+:see: https://bybit-exchange.github.io/docs/v5/asset/transfer/unitransfer
+*/
+func (e *Bybit) Transfer(code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromSub := utils.PopMapVal(args, banexg.ParamFromSubAcc, "")
+	toSub := utils.PopMapVal(args, banexg.ParamToSubAcc, "")
+	fromType, err := bybitTransferAcc(fromAccount)
+	if err != nil {
+		return nil, err
+	}
+	toType, err := bybitTransferAcc(toAccount)
+	if err != nil {
+		return nil, err
+	}
+	method := MethodPrivatePostV5AssetTransferInterTransfer
+	if fromSub != "" || toSub != "" {
+		fromId := e.GetSubAccId(fromSub, args)
+		toId := e.GetSubAccId(toSub, args)
+		if fromId == "" || toId == "" {
+			return nil, errs.NewMsg(errs.CodeParamRequired, "%s of current account is required for sub account transfer",
+				banexg.OptSubAccId)
+		}
+		args["fromMemberId"] = fromId
+		args["toMemberId"] = toId
+		method = MethodPrivatePostV5AssetTransferUniversalTransfer
+	} else if fromType == toType {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "transfer in same account: %s -> %s", fromAccount, toAccount)
+	}
+	transferId := utils.PopMapVal(args, "transferId", "")
+	if transferId == "" {
+		transferId = newTransferId()
+	}
+	args["transferId"] = transferId
+	args["coin"] = code
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["fromAccountType"] = fromType
+	args["toAccountType"] = toType
+	res := requestRetry[map[string]interface{}](e, method, args, 1)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	status := transferStatusMap[utils.GetMapVal(res.Result, "status", "")]
+	if status == "" {
+		status = banexg.TransferPending
+	}
+	return &banexg.Transfer{
+		ID:          utils.GetMapVal(res.Result, "transferId", transferId),
+		Timestamp:   e.MilliSeconds(),
+		Code:        code,
+		Amount:      amount,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		FromSubAcc:  fromSub,
+		ToSubAcc:    toSub,
+		Status:      status,
+		Info:        res.Result,
+	}, nil
+}
+
+/*
+FetchTransfers
+inter transfer history of current account, or universal transfer history when ParamFromSubAcc or
+ParamToSubAcc is given, can be filtered by ParamFromAccount/ParamToAccount.
+查询当前账户的划转记录，传入ParamFromSubAcc或ParamToSubAcc时查询万向划转记录，可按ParamFromAccount/ParamToAccount过滤
+:see: https://bybit-exchange.github.io/docs/v5/asset/transfer/inter-transfer-list
+:see: https://bybit-exchange.github.io/docs/v5/asset/transfer/unitransfer-list
+*/
+func (e *Bybit) FetchTransfers(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromSub := utils.PopMapVal(args, banexg.ParamFromSubAcc, "")
+	toSub := utils.PopMapVal(args, banexg.ParamToSubAcc, "")
+	fromAccount := utils.PopMapVal(args, banexg.ParamFromAccount, "")
+	toAccount := utils.PopMapVal(args, banexg.ParamToAccount, "")
+	method := MethodPrivateGetV5AssetTransferQueryInterTransferList
+	if fromSub != "" || toSub != "" {
+		method = MethodPrivateGetV5AssetTransferQueryUniversalTransferList
+	}
+	if code != "" {
+		args["coin"] = code
+	}
+	applyBybitTimeRange(args, since)
+	tryNum := e.GetRetryNum("FetchTransfers", 1)
+	items, err := fetchV5List(e, method, args, tryNum, limit, 50)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[TransferRecord](items)
+	if err != nil {
+		return nil, err
+	}
+	fromId, toId := "", ""
+	if fromSub != "" {
+		fromId = e.GetSubAccId(fromSub, nil)
+	}
+	if toSub != "" {
+		toId = e.GetSubAccId(toSub, nil)
+	}
+	result := make([]*banexg.Transfer, 0, len(arr))
+	for i, it := range arr {
+		if fromId != "" && it.FromMemberId != fromId || toId != "" && it.ToMemberId != toId {
+			continue
+		}
+		result = append(result, &banexg.Transfer{
+			ID:          it.TransferId,
+			Timestamp:   parseBybitInt(it.Timestamp),
+			Code:        bybitSafeCurrency(e, it.Coin),
+			Amount:      parseBybitNum(it.Amount),
+			FromAccount: parseBybitTransferAcc(it.FromAccountType),
+			ToAccount:   parseBybitTransferAcc(it.ToAccountType),
+			FromSubAcc:  it.FromMemberId,
+			ToSubAcc:    it.ToMemberId,
+			Status:      transferStatusMap[it.Status],
+			Info:        items[i],
+		})
+	}
+	return banexg.FilterTransfers(result, "", fromAccount, toAccount), nil
+}
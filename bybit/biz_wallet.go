@@ -0,0 +1,201 @@
+package bybit
+
+import (
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+var (
+	depositStatusMap = map[int]string{
+		3:     banexg.TransferOk,
+		4:     banexg.TransferFailed,
+		10012: banexg.TransferOk,
+	}
+	withdrawStatusMap = map[string]string{
+		"success":             banexg.TransferOk,
+		"BlockchainConfirmed": banexg.TransferOk,
+		"CancelByUser":        banexg.TransferCanceled,
+		"Reject":              banexg.TransferFailed,
+		"Fail":                banexg.TransferFailed,
+	}
+)
+
+/*
+FetchDepositAddress
+deposit address of currency on ParamNetwork (chain of bybit), the first chain is returned if network is empty
+返回币种在ParamNetwork(bybit的chain)上的充值地址，未指定时返回第一个链
+:see: https://bybit-exchange.github.io/docs/v5/asset/deposit/master-deposit-addr
+*/
+func (e *Bybit) FetchDepositAddress(code string, params map[string]interface{}) (*banexg.DepositAddress, *errs.Error) {
+	args := utils.SafeParams(params)
+	network := utils.PopMapVal(args, banexg.ParamNetwork, "")
+	args["coin"] = code
+	if network != "" {
+		args["chainType"] = network
+	}
+	tryNum := e.GetRetryNum("FetchDepositAddress", 1)
+	res := requestRetry[struct {
+		Coin   string                   `json:"coin"`
+		Chains []map[string]interface{} `json:"chains"`
+	}](e, MethodPrivateGetV5AssetDepositQueryAddress, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	chains, err := decodeBybitList[DepositChain](res.Result.Chains)
+	if err != nil {
+		return nil, err
+	}
+	for i, ch := range chains {
+		if network != "" && ch.Chain != network && ch.ChainType != network {
+			continue
+		}
+		return &banexg.DepositAddress{
+			Code:    bybitSafeCurrency(e, res.Result.Coin),
+			Network: ch.Chain,
+			Address: ch.AddressDeposit,
+			Tag:     ch.TagDeposit,
+			Info:    res.Result.Chains[i],
+		}, nil
+	}
+	return nil, errs.NewMsg(errs.CodeDataNotFound, "no deposit address for %s %s", code, network)
+}
+
+/*
+FetchDeposits
+:see: https://bybit-exchange.github.io/docs/v5/asset/deposit/deposit-record
+*/
+func (e *Bybit) FetchDeposits(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args["coin"] = code
+	}
+	applyBybitTimeRange(args, since)
+	tryNum := e.GetRetryNum("FetchDeposits", 1)
+	items, err := fetchV5List(e, MethodPrivateGetV5AssetDepositQueryRecord, args, tryNum, limit, 50)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[DepositRecord](items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.Transaction, 0, len(arr))
+	for i, it := range arr {
+		status, ok := depositStatusMap[it.Status]
+		if !ok {
+			status = banexg.TransferPending
+		}
+		coin := bybitSafeCurrency(e, it.Coin)
+		result = append(result, &banexg.Transaction{
+			ID:        it.ID,
+			TxID:      it.TxID,
+			Type:      banexg.TxDeposit,
+			Code:      coin,
+			Amount:    parseBybitNum(it.Amount),
+			Network:   it.Chain,
+			Address:   it.ToAddress,
+			Tag:       it.Tag,
+			Status:    status,
+			Fee:       &banexg.Fee{Currency: coin, Cost: parseBybitNum(it.DepositFee)},
+			Timestamp: parseBybitInt(it.SuccessAt),
+			Info:      items[i],
+		})
+	}
+	return result, nil
+}
+
+/*
+FetchWithdrawals
+:see: https://bybit-exchange.github.io/docs/v5/asset/withdraw/withdraw-record
+*/
+func (e *Bybit) FetchWithdrawals(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args["coin"] = code
+	}
+	applyBybitTimeRange(args, since)
+	tryNum := e.GetRetryNum("FetchWithdrawals", 1)
+	items, err := fetchV5List(e, MethodPrivateGetV5AssetWithdrawQueryRecord, args, tryNum, limit, 50)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := decodeBybitList[WithdrawRecord](items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.Transaction, 0, len(arr))
+	for i, it := range arr {
+		status, ok := withdrawStatusMap[it.Status]
+		if !ok {
+			status = banexg.TransferPending
+		}
+		coin := bybitSafeCurrency(e, it.Coin)
+		result = append(result, &banexg.Transaction{
+			ID:        it.WithdrawId,
+			TxID:      it.TxID,
+			Type:      banexg.TxWithdrawal,
+			Code:      coin,
+			Amount:    parseBybitNum(it.Amount),
+			Network:   it.Chain,
+			Address:   it.ToAddress,
+			Tag:       it.Tag,
+			Status:    status,
+			Fee:       &banexg.Fee{Currency: coin, Cost: parseBybitNum(it.WithdrawFee)},
+			Timestamp: parseBybitInt(it.CreateTime),
+			Updated:   parseBybitInt(it.UpdateTime),
+			Info:      items[i],
+		})
+	}
+	return result, nil
+}
+
+/*
+Withdraw
+withdraw from funding account by default, set accountType in params to change.
+ParamClientOrderId is sent as requestId for idempotent check, the request is only retried with it.
+默认从资金账户提现，可在params中设置accountType。ParamClientOrderId作为requestId用于幂等校验，仅传入时重试
+:see: https://bybit-exchange.github.io/docs/v5/asset/withdraw
+*/
+func (e *Bybit) Withdraw(code string, amount float64, address, tag, network string, params map[string]interface{}) (*banexg.Transaction, *errs.Error) {
+	if err := e.CheckWithdraw(code, network, amount, params); err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	args["coin"] = code
+	args["address"] = address
+	args["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["timestamp"] = e.Nonce()
+	if tag != "" {
+		args["tag"] = tag
+	}
+	if network != "" {
+		args["chain"] = network
+	}
+	if _, ok := args["accountType"]; !ok {
+		args["accountType"] = "FUND"
+	}
+	tryNum := 0
+	if clientId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clientId != "" {
+		args["requestId"] = clientId
+		tryNum = 1
+	}
+	res := requestRetry[map[string]interface{}](e, MethodPrivatePostV5AssetWithdrawCreate, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &banexg.Transaction{
+		ID:        utils.GetMapVal(res.Result, "id", ""),
+		Type:      banexg.TxWithdrawal,
+		Code:      code,
+		Amount:    amount,
+		Network:   network,
+		Address:   address,
+		Tag:       tag,
+		Status:    banexg.TransferPending,
+		Timestamp: e.MilliSeconds(),
+		Info:      res.Result,
+	}, nil
+}
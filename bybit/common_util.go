@@ -36,6 +36,7 @@ type V5Resp[T any] struct {
 type V5ListResult struct {
 	Category       string                   `json:"category"`
 	List           []map[string]interface{} `json:"list"`
+	Rows           []map[string]interface{} `json:"rows"` // deposit/withdraw records use rows instead of list
 	NextPageCursor string                   `json:"nextPageCursor"`
 }
 
@@ -99,11 +100,20 @@ func mapBybitRetCode(retCode int, retMsg string) *errs.Error {
 	}
 	code := errs.CodeRunTime
 	switch retCode {
-	case -1, 10002:
+	case -1:
 		code = errs.CodeExpired
+	case 10002:
+		// request time exceeds the time window range
+		code = errs.CodeInvalidNonce
 	case 10000:
 		code = errs.CodeTimeout
-	case 10001, 10029, 110003, 110017, 110018, 110019, 110032, 110049, 110072, 110092, 110093, 110094, 110108, 110109, 110120, 110121:
+	case 10001:
+		if strings.Contains(retMsg, "position idx not match position mode") {
+			code = errs.CodePositionModeMismatch
+		} else {
+			code = errs.CodeParamInvalid
+		}
+	case 10029, 110003, 110018, 110019, 110032, 110049, 110072, 110092, 110093, 110094, 110108, 110109, 110120, 110121:
 		code = errs.CodeParamInvalid
 	case 10003, 33004, -2015:
 		code = errs.CodeAccKeyError
@@ -121,9 +131,18 @@ func mapBybitRetCode(retCode int, retMsg string) *errs.Error {
 		code = errs.CodeServerError
 	case 10027:
 		code = errs.CodeNoTrade
-	case 110001, 110031, 110034:
+	case 110031, 110034:
 		code = errs.CodeDataNotFound
-	case 110004, 110006, 110007, 110011, 110012, 110013, 110014, 110016, 110020, 110021, 110022, 110023, 110039, 110040, 110044, 110045, 110046, 110047, 110048, 110051, 110052, 110053, 110066, 110070, 110074, 170346, 170360:
+	case 110001, 170213:
+		// order does not exist
+		code = errs.CodeOrderNotFound
+	case 110004, 110006, 110007, 110012, 110014, 110044, 110045, 170131:
+		// wallet/available balance insufficient
+		code = errs.CodeInsufficientBalance
+	case 110017:
+		// reduce-only rule not satisfied
+		code = errs.CodeReduceOnlyRejected
+	case 110011, 110013, 110016, 110020, 110021, 110022, 110023, 110039, 110040, 110046, 110047, 110048, 110051, 110052, 110053, 110066, 110070, 110074, 170346, 170360:
 		code = errs.CodeNoTrade
 	case 3100181, 3100326:
 		code = errs.CodeParamRequired
@@ -179,13 +198,17 @@ func fetchV5List(e *Bybit, method string, args map[string]interface{}, tryNum in
 		if res.Error != nil {
 			return nil, res.Error
 		}
-		if len(res.Result.List) > 0 {
-			items = append(items, res.Result.List...)
+		list := res.Result.List
+		if len(list) == 0 {
+			list = res.Result.Rows
+		}
+		if len(list) > 0 {
+			items = append(items, list...)
 		}
 		if limit > 0 && len(items) >= limit {
 			return items[:limit], nil
 		}
-		if res.Result.NextPageCursor == "" || (pageLimit > 0 && len(res.Result.List) < pageLimit) {
+		if res.Result.NextPageCursor == "" || (pageLimit > 0 && len(list) < pageLimit) {
 			break
 		}
 		cursor = res.Result.NextPageCursor
@@ -381,11 +404,25 @@ func (e *Bybit) regReplayHandles() {
 			_, err = e.WatchMarkPrices(symbols, nil)
 			return err
 		},
+		"WatchTickers": func(item *banexg.WsLog) *errs.Error {
+			symbols, err := decodeWsLog[[]string](item)
+			if err != nil || len(symbols) == 0 {
+				return err
+			}
+			log.Debug("replay WatchTickers", zap.Strings("symbols", symbols))
+			_, err = e.WatchTickers(symbols, nil)
+			return err
+		},
 		"WatchMyTrades": func(item *banexg.WsLog) *errs.Error {
 			log.Debug("replay WatchMyTrades")
 			_, err := e.WatchMyTrades(nil)
 			return err
 		},
+		"WatchOrders": func(item *banexg.WsLog) *errs.Error {
+			log.Debug("replay WatchOrders")
+			_, err := e.WatchOrders(nil)
+			return err
+		},
 		"WatchBalance": func(item *banexg.WsLog) *errs.Error {
 			log.Debug("replay WatchBalance")
 			_, err := e.WatchBalance(nil)
@@ -108,8 +108,11 @@ func TestMapBybitRetCode(t *testing.T) {
 		{10005, errs.CodeUnauthorized},
 		{10006, errs.CodeSystemBusy},
 		{10028, errs.CodeForbidden},
-		{110001, errs.CodeDataNotFound},
-		{110004, errs.CodeNoTrade},
+		{10002, errs.CodeInvalidNonce},
+		{110001, errs.CodeOrderNotFound},
+		{110004, errs.CodeInsufficientBalance},
+		{110017, errs.CodeReduceOnlyRejected},
+		{110031, errs.CodeDataNotFound},
 		{3100326, errs.CodeParamRequired},
 	}
 	for _, tc := range cases {
@@ -1,5 +1,7 @@
 package bybit
 
+import "github.com/banbox/banexg"
+
 const (
 	HostPublic          = "public"
 	HostPrivate         = "private"
@@ -8,6 +10,7 @@ const (
 	HostWsPublicInverse = "ws_public_inverse"
 	HostWsPublicOption  = "ws_public_option"
 	HostWsPrivate       = "ws_private"
+	HostWsTrade         = "ws_trade"
 )
 
 var (
@@ -288,6 +291,8 @@ const (
 	MethodPrivatePostV5PositionConfirmPendingMmr                       = "privatePostV5PositionConfirmPendingMmr"
 	MethodPrivatePostV5AccountUpgradeToUta                             = "privatePostV5AccountUpgradeToUta"
 	MethodPrivatePostV5AccountQuickRepayment                           = "privatePostV5AccountQuickRepayment"
+	MethodPrivatePostV5AccountBorrow                                   = "privatePostV5AccountBorrow"
+	MethodPrivatePostV5AccountRepay                                    = "privatePostV5AccountRepay"
 	MethodPrivatePostV5AccountSetMarginMode                            = "privatePostV5AccountSetMarginMode"
 	MethodPrivatePostV5AccountSetHedgingMode                           = "privatePostV5AccountSetHedgingMode"
 	MethodPrivatePostV5AccountMmpModify                                = "privatePostV5AccountMmpModify"
@@ -322,3 +327,24 @@ const (
 	MethodPrivatePostV5AccountSetCollateralSwitchBatch                 = "privatePostV5AccountSetCollateralSwitchBatch"
 	MethodPrivatePostV5AccountDemoApplyMoney                           = "privatePostV5AccountDemoApplyMoney"
 )
+
+const (
+	HeaderLimitStatus = "X-Bapi-Limit-Status"
+	HeaderLimit       = "X-Bapi-Limit"
+	HeaderLimitReset  = "X-Bapi-Limit-Reset-Timestamp"
+)
+
+/*
+weightRules
+Bybit limits 600 requests per 5s for each IP, and private endpoints per UID per path,
+which returns the remaining count in X-Bapi-Limit-Status.
+Bybit每个IP限制5秒600次，私有接口按UID和路径限流，并在X-Bapi-Limit-Status中返回剩余次数
+*/
+func weightRules() []*banexg.WeightRule {
+	return []*banexg.WeightRule{
+		{Limit: 600, Interval: 5000, Fixed: 1},
+		{Header: HeaderLimitStatus, LimitHeader: HeaderLimit, ResetHeader: HeaderLimitReset,
+			Hosts: []string{HostPrivate}, Limit: 10, Interval: 1000, Fixed: 1, Remain: true, Account: true,
+			PerPath: true},
+	}
+}
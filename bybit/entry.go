@@ -21,9 +21,10 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 	exg := &Bybit{
 		Exchange: &banexg.Exchange{
 			ExgInfo: &banexg.ExgInfo{
-				ID:        "bybit",
-				Name:      "Bybit",
-				Countries: []string{"VG"},
+				ID:         "bybit",
+				Name:       "Bybit",
+				Countries:  []string{"VG"},
+				OHLCVLimit: 1000,
 			},
 			RateLimit:  20,
 			Options:    Options,
@@ -37,6 +38,7 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 					HostWsPublicInverse: wsTestBase + "/public/inverse",
 					HostWsPublicOption:  wsTestBase + "/public/option",
 					HostWsPrivate:       wsTestBase + "/private",
+					HostWsTrade:         wsTestBase + "/trade",
 				},
 				Prod: map[string]string{
 					HostPublic:          apiProd,
@@ -46,6 +48,7 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 					HostWsPublicInverse: wsProdBase + "/public/inverse",
 					HostWsPublicOption:  wsProdBase + "/public/option",
 					HostWsPrivate:       wsProdBase + "/private",
+					HostWsTrade:         wsProdBase + "/trade",
 				},
 				Www: "https://www.bybit.com",
 				Doc: []string{
@@ -352,6 +355,8 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 				MethodPrivatePostV5PositionConfirmPendingMmr:                       api("v5/position/confirm-pending-mmr", HostPrivate, "POST", 5),
 				MethodPrivatePostV5AccountUpgradeToUta:                             api("v5/account/upgrade-to-uta", HostPrivate, "POST", 5),
 				MethodPrivatePostV5AccountQuickRepayment:                           api("v5/account/quick-repayment", HostPrivate, "POST", 5),
+				MethodPrivatePostV5AccountBorrow:                                   api("v5/account/borrow", HostPrivate, "POST", 5),
+				MethodPrivatePostV5AccountRepay:                                    api("v5/account/repay", HostPrivate, "POST", 5),
 				MethodPrivatePostV5AccountSetMarginMode:                            api("v5/account/set-margin-mode", HostPrivate, "POST", 5),
 				MethodPrivatePostV5AccountSetHedgingMode:                           api("v5/account/set-hedging-mode", HostPrivate, "POST", 5),
 				MethodPrivatePostV5AccountMmpModify:                                api("v5/account/mmp-modify", HostPrivate, "POST", 5),
@@ -388,6 +393,18 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 			},
 			Has: map[string]map[string]int{
 				"": {
+					banexg.ApiFetchTime:             banexg.HasOk,
+					banexg.ApiTransfer:              banexg.HasOk,
+					banexg.ApiFetchTransfers:        banexg.HasOk,
+					banexg.ApiFetchDepositAddress:   banexg.HasOk,
+					banexg.ApiFetchDeposits:         banexg.HasOk,
+					banexg.ApiFetchWithdrawals:      banexg.HasOk,
+					banexg.ApiWithdraw:              banexg.HasOk,
+					banexg.ApiBorrow:                banexg.HasOk,
+					banexg.ApiRepay:                 banexg.HasOk,
+					banexg.ApiFetchBorrowRate:       banexg.HasOk,
+					banexg.ApiFetchBorrowRates:      banexg.HasOk,
+					banexg.ApiFetchBorrowInterest:   banexg.HasOk,
 					banexg.ApiFetchTicker:           banexg.HasOk,
 					banexg.ApiFetchTickers:          banexg.HasOk,
 					banexg.ApiFetchTickerPrice:      banexg.HasOk,
@@ -396,16 +413,36 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 					banexg.ApiGetLeverage:           banexg.HasOk,
 					banexg.ApiFetchOHLCV:            banexg.HasOk,
 					banexg.ApiFetchOrderBook:        banexg.HasOk,
+					banexg.ApiFetchTrades:           banexg.HasOk,
 					banexg.ApiFetchOrder:            banexg.HasOk,
 					banexg.ApiFetchOrders:           banexg.HasOk,
 					banexg.ApiFetchBalance:          banexg.HasOk,
 					banexg.ApiFetchAccountPositions: banexg.HasOk,
 					banexg.ApiFetchPositions:        banexg.HasOk,
 					banexg.ApiFetchOpenOrders:       banexg.HasOk,
+					banexg.ApiFetchMyTrades:         banexg.HasOk,
 					banexg.ApiCreateOrder:           banexg.HasOk,
 					banexg.ApiEditOrder:             banexg.HasOk,
 					banexg.ApiCancelOrder:           banexg.HasOk,
+					banexg.ApiCreateOrders:          banexg.HasOk,
+					banexg.ApiCancelOrders:          banexg.HasOk,
+					banexg.ApiCancelAllOrders:       banexg.HasOk,
 					banexg.ApiSetLeverage:           banexg.HasOk,
+					banexg.ApiSetMarginMode:         banexg.HasOk,
+					banexg.ApiSetPositionMode:       banexg.HasOk,
+					banexg.ApiAddMargin:             banexg.HasOk,
+					banexg.ApiReduceMargin:          banexg.HasOk,
+					banexg.ApiFetchOpenInterest:     banexg.HasOk,
+					banexg.ApiFetchOpenInterestHist: banexg.HasOk,
+					banexg.ApiFetchLongShortRatio:   banexg.HasOk,
+					banexg.ApiWatchLiquidations:     banexg.HasOk,
+					banexg.ApiUnWatchLiquidations:   banexg.HasOk,
+					banexg.ApiFetchOptionChain:      banexg.HasOk,
+					banexg.ApiFetchGreeks:           banexg.HasOk,
+					banexg.ApiWatchGreeks:           banexg.HasOk,
+					banexg.ApiUnWatchGreeks:         banexg.HasOk,
+					banexg.ApiFetchOHLCVRange:       banexg.HasOk,
+					banexg.ApiSetCancelAllAfter:     banexg.HasOk,
 					banexg.ApiCalcMaintMargin:       banexg.HasOk,
 					banexg.ApiWatchOrderBooks:       banexg.HasOk,
 					banexg.ApiUnWatchOrderBooks:     banexg.HasOk,
@@ -415,19 +452,29 @@ func New(Options map[string]interface{}) (*Bybit, *errs.Error) {
 					banexg.ApiUnWatchMarkPrices:     banexg.HasOk,
 					banexg.ApiWatchTrades:           banexg.HasOk,
 					banexg.ApiUnWatchTrades:         banexg.HasOk,
+					banexg.ApiWatchTickers:          banexg.HasOk,
+					banexg.ApiUnWatchTickers:        banexg.HasOk,
 					banexg.ApiWatchMyTrades:         banexg.HasOk,
+					banexg.ApiWatchOrders:           banexg.HasOk,
 					banexg.ApiWatchBalance:          banexg.HasOk,
 					banexg.ApiWatchPositions:        banexg.HasOk,
 					banexg.ApiWatchAccountConfig:    banexg.HasOk,
 				},
 			},
-			CredKeys: map[string]bool{"ApiKey": true, "Secret": true},
+			CredKeys:    map[string]bool{"ApiKey": true, "Secret": true},
+			WeightRules: weightRules(),
 		},
 	}
+	exg.Self = exg
 	exg.Sign = makeSign(exg)
 	exg.FetchCurrencies = makeFetchCurr(exg)
 	exg.FetchMarkets = makeFetchMarkets(exg)
 	exg.CheckWsTimeout = makeCheckWsTimeout(exg)
+	exg.AccStateKey = func(marketType string) string {
+		// unified account, positions and balances of all markets are pushed by the private connection
+		return wsPrivate
+	}
+	exg.CancelAfterScope = makeCancelAfterScope(exg)
 	err := exg.Init()
 	return exg, err
 }
@@ -333,6 +333,72 @@ type ExecutionInfo struct {
 	IsMaker       bool   `json:"isMaker"`
 }
 
+type TransferRecord struct {
+	TransferId      string `json:"transferId"`
+	Coin            string `json:"coin"`
+	Amount          string `json:"amount"`
+	FromMemberId    string `json:"fromMemberId"`
+	ToMemberId      string `json:"toMemberId"`
+	FromAccountType string `json:"fromAccountType"`
+	ToAccountType   string `json:"toAccountType"`
+	Timestamp       string `json:"timestamp"`
+	Status          string `json:"status"`
+}
+
+type DepositChain struct {
+	ChainType      string `json:"chainType"`
+	AddressDeposit string `json:"addressDeposit"`
+	TagDeposit     string `json:"tagDeposit"`
+	Chain          string `json:"chain"`
+}
+
+type DepositRecord struct {
+	ID         string `json:"id"`
+	Coin       string `json:"coin"`
+	Chain      string `json:"chain"`
+	Amount     string `json:"amount"`
+	TxID       string `json:"txID"`
+	Status     int    `json:"status"`
+	ToAddress  string `json:"toAddress"`
+	Tag        string `json:"tag"`
+	DepositFee string `json:"depositFee"`
+	SuccessAt  string `json:"successAt"`
+}
+
+type WithdrawRecord struct {
+	WithdrawId  string `json:"withdrawId"`
+	Coin        string `json:"coin"`
+	Chain       string `json:"chain"`
+	Amount      string `json:"amount"`
+	TxID        string `json:"txID"`
+	Status      string `json:"status"`
+	ToAddress   string `json:"toAddress"`
+	Tag         string `json:"tag"`
+	WithdrawFee string `json:"withdrawFee"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+type BorrowRecord struct {
+	Currency                  string `json:"currency"`
+	CreatedTime               int64  `json:"createdTime"`
+	BorrowCost                string `json:"borrowCost"`
+	HourlyBorrowRate          string `json:"hourlyBorrowRate"`
+	InterestBearingBorrowSize string `json:"InterestBearingBorrowSize"`
+}
+
+type OpenInterestItem struct {
+	OpenInterest string `json:"openInterest"`
+	Timestamp    string `json:"timestamp"`
+}
+
+type AccountRatioItem struct {
+	Symbol    string `json:"symbol"`
+	BuyRatio  string `json:"buyRatio"`
+	SellRatio string `json:"sellRatio"`
+	Timestamp string `json:"timestamp"`
+}
+
 type TransLogInfo struct {
 	ID              string `json:"id"`
 	Symbol          string `json:"symbol"`
@@ -105,11 +105,43 @@ func (e *Bybit) UnWatchTrades(symbols []string, params map[string]interface{}) *
 	return e.unwatchWsPublicSymbols(args, symbols, bybitWsTradeTopics, "trades", symbols)
 }
 
+/*
+WatchLiquidations
+all liquidations of symbols, symbols are required
+订阅币种的所有强平订单，symbols必填
+:see: https://bybit-exchange.github.io/docs/v5/websocket/public/all-liquidation
+*/
+func (e *Bybit) WatchLiquidations(symbols []string, params map[string]interface{}) (chan *banexg.Liquidation, *errs.Error) {
+	if len(symbols) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbols required for WatchLiquidations")
+	}
+	args := utils.SafeParams(params)
+	create := func(cap int) chan *banexg.Liquidation { return make(chan *banexg.Liquidation, cap) }
+	return watchBybitWsPublicSymbols(e, args, symbols, bybitWsLiquidationTopics, "liquidation", "WatchLiquidations", symbols, create)
+}
+
+func (e *Bybit) UnWatchLiquidations(symbols []string, params map[string]interface{}) *errs.Error {
+	if len(symbols) == 0 {
+		return errs.NewMsg(errs.CodeParamRequired, "symbols required for UnWatchLiquidations")
+	}
+	args := utils.SafeParams(params)
+	return e.unwatchWsPublicSymbols(args, symbols, bybitWsLiquidationTopics, "liquidation", symbols)
+}
+
+/*
+WatchOHLCVs
+bybit has no public topic of mark/index price klines, banexg.ParamPrice is not supported
+bybit没有标记/指数价格K线的公共主题，不支持banexg.ParamPrice
+:see: https://bybit-exchange.github.io/docs/v5/websocket/public/kline
+*/
 func (e *Bybit) WatchOHLCVs(jobs [][2]string, params map[string]interface{}) (chan *banexg.PairTFKline, *errs.Error) {
 	if len(jobs) == 0 {
 		return nil, errs.NewMsg(errs.CodeParamRequired, "jobs required for WatchOHLCVs")
 	}
 	args := utils.SafeParams(params)
+	if priceType := utils.PopMapVal(args, banexg.ParamPrice, ""); priceType != "" {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "unsupport %s for ws: %s", banexg.ParamPrice, priceType)
+	}
 	create := func(cap int) chan *banexg.PairTFKline { return make(chan *banexg.PairTFKline, cap) }
 	return watchBybitWsPublicJobs(e, args, jobs, bybitWsKlineTopics, "kline", "WatchOHLCVs", create)
 }
@@ -119,6 +151,9 @@ func (e *Bybit) UnWatchOHLCVs(jobs [][2]string, params map[string]interface{}) *
 		return errs.NewMsg(errs.CodeParamRequired, "jobs required for UnWatchOHLCVs")
 	}
 	args := utils.SafeParams(params)
+	if priceType := utils.PopMapVal(args, banexg.ParamPrice, ""); priceType != "" {
+		return errs.NewMsg(errs.CodeNotSupport, "unsupport %s for ws: %s", banexg.ParamPrice, priceType)
+	}
 	return e.unwatchWsPublicJobs(args, jobs, bybitWsKlineTopics, "kline")
 }
 
@@ -136,7 +171,31 @@ func (e *Bybit) UnWatchMarkPrices(symbols []string, params map[string]interface{
 		return errs.NewMsg(errs.CodeParamRequired, "symbols required for UnWatchMarkPrices")
 	}
 	args := utils.SafeParams(params)
-	return e.unwatchWsPublicSymbols(args, symbols, bybitWsMarkPriceTopics, "markPrice", symbols)
+	return e.unwatchWsTickerSymbols(args, symbols, bybitWsMarkPriceTopics, "markPrice", "tickers", "greeks")
+}
+
+/*
+WatchTickers
+watches tickers of symbols, delta pushes of linear/inverse are merged into the full ticker before output.
+线性/反向合约的增量推送会合并为完整ticker后输出
+
+:see: https://bybit-exchange.github.io/docs/v5/websocket/public/ticker
+*/
+func (e *Bybit) WatchTickers(symbols []string, params map[string]interface{}) (chan *banexg.Ticker, *errs.Error) {
+	if len(symbols) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbols required for WatchTickers")
+	}
+	args := utils.SafeParams(params)
+	create := func(cap int) chan *banexg.Ticker { return make(chan *banexg.Ticker, cap) }
+	return watchBybitWsPublicSymbols(e, args, symbols, bybitWsTickerTopics, "tickers", "WatchTickers", symbols, create)
+}
+
+func (e *Bybit) UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error {
+	if len(symbols) == 0 {
+		return errs.NewMsg(errs.CodeParamRequired, "symbols required for UnWatchTickers")
+	}
+	args := utils.SafeParams(params)
+	return e.unwatchWsTickerSymbols(args, symbols, bybitWsTickerTopics, "tickers", "markPrice", "greeks")
 }
 
 func (e *Bybit) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyTrade, *errs.Error) {
@@ -152,7 +211,28 @@ func (e *Bybit) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyTra
 		topic = "execution." + category
 	}
 	create := func(cap int) chan *banexg.MyTrade { return make(chan *banexg.MyTrade, cap) }
-	_, out, err := watchBybitWsPrivateTopic(e, args, topic, "mytrades", "WatchMyTrades", []string{"account"}, nil, create)
+	refKeys := []string{utils.PopMapVal(args, banexg.ParamRefKey, "account")}
+	_, out, err := watchBybitWsPrivateTopic(e, args, topic, "mytrades", "WatchMyTrades", refKeys, nil, create)
+	return out, err
+}
+
+/*
+WatchOrders
+watches full order snapshots from the order topic, including new, canceled, expired and triggered orders
+
+:see: https://bybit-exchange.github.io/docs/v5/websocket/private/order
+*/
+func (e *Bybit) WatchOrders(params map[string]interface{}) (chan *banexg.Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	topic := "order"
+	if marketType := utils.GetMapVal(args, banexg.ParamMarket, ""); marketType != "" {
+		if cat, err := bybitCategoryFromType(marketType); err == nil {
+			topic = "order." + cat
+		}
+	}
+	create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+	refKeys := []string{utils.PopMapVal(args, banexg.ParamRefKey, "account")}
+	_, out, err := watchBybitWsPrivateTopic(e, args, topic, "orders", "WatchOrders", refKeys, nil, create)
 	return out, err
 }
 
@@ -224,15 +304,32 @@ func (e *Bybit) handleWsOrderBook(client *banexg.WsClient, base *wsBaseMsg) {
 		market = e.SafeMarket(data.Symbol, "", client.MarketType)
 	}
 	action := normalizeBybitWsOrderBookAction(base.Type, &data)
-	book := applyBybitWsOrderBook(e, market, &data, action, depth)
+	book, reset := applyBybitWsOrderBook(e, market, &data, action, depth)
+	client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
+	if reset != nil {
+		e.ResetOdBook(client, reset)
+		go e.resubWsTopic(client, base.Topic)
+		return
+	}
 	if book == nil {
 		return
 	}
-	client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
 	chanKey := client.Prefix("orderbook")
 	banexg.WriteOutChan(e.Exchange, chanKey, book, true)
 }
 
+// resubWsTopic unsubscribe and subscribe the topic again to receive a new snapshot
+func (e *Bybit) resubWsTopic(client *banexg.WsClient, topic string) {
+	keys := []string{topic}
+	err := e.writeWsTopics(client, 0, false, keys)
+	if err == nil {
+		err = e.writeWsTopics(client, 0, true, keys)
+	}
+	if err != nil {
+		log.Error("bybit resubscribe topic fail", zap.String("topic", topic), zap.Error(err))
+	}
+}
+
 func (e *Bybit) handleWsTrades(client *banexg.WsClient, base *wsBaseMsg) {
 	items, ok := decodeBybitWsList(base.Data, "bybit ws trade decode fail")
 	if !ok {
@@ -249,6 +346,22 @@ func (e *Bybit) handleWsTrades(client *banexg.WsClient, base *wsBaseMsg) {
 	}
 }
 
+func (e *Bybit) handleWsLiquidations(client *banexg.WsClient, base *wsBaseMsg) {
+	items, ok := decodeBybitWsList(base.Data, "bybit ws liquidation decode fail")
+	if !ok {
+		return
+	}
+	client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
+	chanKey := client.Prefix("liquidation")
+	for _, item := range items {
+		liq := parseBybitWsLiquidation(e, item, client.MarketType)
+		if liq == nil {
+			continue
+		}
+		banexg.WriteOutChan(e.Exchange, chanKey, liq, true)
+	}
+}
+
 func (e *Bybit) handleWsOHLCV(client *banexg.WsClient, base *wsBaseMsg) {
 	items, ok := decodeBybitWsList(base.Data, "bybit ws kline decode fail")
 	if !ok {
@@ -314,6 +427,24 @@ func (e *Bybit) handleWsTickers(client *banexg.WsClient, base *wsBaseMsg) {
 		chanKey := client.Prefix("markPrice")
 		banexg.WriteOutChan(e.Exchange, chanKey, res, true)
 	}
+	e.handleWsTickerItems(client, base, items)
+	if client.MarketType == banexg.MarketOption {
+		e.handleWsGreeks(client, base, items)
+	}
+}
+
+func (e *Bybit) handleWsTickerItems(client *banexg.WsClient, base *wsBaseMsg, items []map[string]interface{}) {
+	chanKey := client.Prefix("tickers")
+	for _, ticker := range parseBybitWsTickers(e, items, client.MarketType) {
+		if ticker.Symbol == "" {
+			continue
+		}
+		if ticker.TimeStamp == 0 {
+			ticker.TimeStamp = base.Ts
+		}
+		client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
+		banexg.WriteOutChan(e.Exchange, chanKey, e.UpdateWsTicker(ticker), true)
+	}
 }
 
 func (e *Bybit) handleWsWallet(client *banexg.WsClient, base *wsBaseMsg) {
@@ -419,3 +550,34 @@ func (e *Bybit) handleWsExecutions(client *banexg.WsClient, base *wsBaseMsg) {
 		banexg.WriteOutChan(e.Exchange, chanKey, trade, true)
 	}
 }
+
+func (e *Bybit) handleWsOrders(client *banexg.WsClient, base *wsBaseMsg) {
+	items, ok := decodeBybitWsList(base.Data, "bybit ws order decode fail")
+	if !ok {
+		return
+	}
+	arr, err := decodeBybitList[OrderInfo](items)
+	if err != nil {
+		log.Error("bybit ws order map decode fail", zap.Error(err))
+		return
+	}
+	orders := make([]*banexg.Order, 0, len(arr))
+	for i := range arr {
+		marketType := bybitMarketTypeFromCategory(bybitWsString(items[i]["category"]))
+		if marketType == "" {
+			marketType = banexg.MarketLinear
+		}
+		order := parseBybitOrder(e, &arr[i], items[i], marketType)
+		if order != nil {
+			orders = append(orders, order)
+		}
+	}
+	if len(orders) == 0 {
+		return
+	}
+	client.SetSubsKeyStamp(base.Topic, bntp.UTCStamp())
+	chanKey := client.Prefix("orders")
+	for _, order := range orders {
+		banexg.WriteOutChan(e.Exchange, chanKey, order, true)
+	}
+}
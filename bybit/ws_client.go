@@ -15,6 +15,7 @@ import (
 
 const (
 	wsPrivate = "private"
+	wsTrade   = "trade"
 )
 
 type WsPendingRecon struct {
@@ -49,12 +50,16 @@ func makeHandleWsMsg(e *Bybit) banexg.FuncOnWsMsg {
 			e.handleWsOHLCV(client, &base)
 		case strings.HasPrefix(base.Topic, "tickers."):
 			e.handleWsTickers(client, &base)
+		case strings.HasPrefix(base.Topic, "allLiquidation."):
+			e.handleWsLiquidations(client, &base)
 		case base.Topic == "wallet":
 			e.handleWsWallet(client, &base)
 		case strings.HasPrefix(base.Topic, "position"):
 			e.handleWsPositions(client, &base)
 		case strings.HasPrefix(base.Topic, "execution"):
 			e.handleWsExecutions(client, &base)
+		case base.Topic == "order" || strings.HasPrefix(base.Topic, "order."):
+			e.handleWsOrders(client, &base)
 		default:
 			log.Debug("bybit ws unhandled topic", zap.String("topic", base.Topic))
 		}
@@ -66,6 +71,13 @@ func makeHandleWsReCon(e *Bybit) banexg.FuncOnWsReCon {
 		if client == nil {
 			return nil
 		}
+		if client.MarketType == wsTrade {
+			// no subscriptions on trade channel, login again before next request
+			e.WsAuthLock.Lock()
+			delete(e.WsAuthed, client.Key)
+			e.WsAuthLock.Unlock()
+			return nil
+		}
 		keys := client.GetSubKeys(connID)
 		if len(keys) == 0 {
 			return nil
@@ -215,7 +227,7 @@ func (e *Bybit) wsLoginAsync(client *banexg.WsClient, acc *banexg.Account, connI
 	if err != nil {
 		return err
 	}
-	expires := time.Now().UnixMilli() + 10000
+	expires := e.Nonce() + 10000
 	payload := "GET/realtime" + strconv.FormatInt(expires, 10)
 	sign, err2 := utils.Signature(payload, creds.Secret, "hmac", "sha256", "hex")
 	if err2 != nil {
@@ -413,6 +425,48 @@ func (e *Bybit) unwatchWsPublicSymbols(
 	return nil
 }
 
+/*
+unwatchWsTickerSymbols
+tickers, markPrice and greeks share the same tickers topic, only unsubscribe symbols not referenced by other chans.
+tickers、markPrice和greeks共用tickers主题，仅取消其他通道未引用的币种
+*/
+func (e *Bybit) unwatchWsTickerSymbols(
+	args map[string]interface{},
+	symbols []string,
+	topicFn func(*Bybit, []string) ([]string, *errs.Error),
+	chanPrefix string,
+	otherPrefixes ...string,
+) *errs.Error {
+	_, client, err := e.getWsPublicCategoryClient(args, symbols...)
+	if err != nil {
+		return err
+	}
+	unSubs := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		used := false
+		for _, prefix := range otherPrefixes {
+			if e.HasWsChanRef(client.Prefix(prefix), sym) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unSubs = append(unSubs, sym)
+		}
+	}
+	if len(unSubs) > 0 {
+		keys, err := topicFn(e, unSubs)
+		if err != nil {
+			return err
+		}
+		if err := e.writeWsTopics(client, 0, false, keys); err != nil {
+			return err
+		}
+	}
+	e.DelWsChanRefs(client.Prefix(chanPrefix), symbols...)
+	return nil
+}
+
 func watchBybitWsPublicJobs[T any](
 	e *Bybit,
 	args map[string]interface{},
@@ -21,3 +21,23 @@ func TestBybitWsBatchSize(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeWsTradeRes(t *testing.T) {
+	res := &banexg.ApiRes[map[string]interface{}]{HttpRes: &banexg.HttpRes{
+		Content: `{"reqId":"a1","retCode":0,"retMsg":"OK","op":"order.create","data":{"orderId":"123","orderLinkId":"x"}}`,
+	}}
+	decodeWsTradeRes(res)
+	if res.Error != nil {
+		t.Fatalf("unexpected error: %v", res.Error)
+	}
+	if res.Result["orderId"] != "123" {
+		t.Fatalf("unexpected result: %v", res.Result)
+	}
+	res = &banexg.ApiRes[map[string]interface{}]{HttpRes: &banexg.HttpRes{
+		Content: `{"reqId":"a2","retCode":110007,"retMsg":"insufficient balance","op":"order.create","data":{}}`,
+	}}
+	decodeWsTradeRes(res)
+	if res.Error == nil {
+		t.Fatal("expect error for non-zero retCode")
+	}
+}
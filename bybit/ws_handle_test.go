@@ -149,6 +149,36 @@ func TestHandleWsTickers(t *testing.T) {
 	}
 }
 
+func TestHandleWsTickersMerge(t *testing.T) {
+	exg, client := newBybitWsTest(t, "BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	topic := "tickers.BTCUSDT"
+	client.SubscribeKeys[topic] = 0
+	out := wsOutChan[*banexg.Ticker](exg, client, "tickers")
+	snap := map[string]interface{}{
+		"symbol":       "BTCUSDT",
+		"lastPrice":    "100",
+		"prevPrice24h": "80",
+		"price24hPcnt": "0.25",
+		"bid1Price":    "99.5",
+		"ask1Price":    "100.5",
+		"markPrice":    "100.1",
+	}
+	exg.handleWsTickers(client, &wsBaseMsg{Topic: topic, Type: "snapshot", Ts: 1700000000000, Data: mustJSON(t, snap)})
+	res := readChan(t, out)
+	if res.Symbol != "BTC/USDT:USDT" || res.Last != 100 || res.Percentage != 25 || res.TimeStamp != 1700000000000 {
+		t.Fatalf("unexpected ticker snapshot: %+v", res)
+	}
+	delta := map[string]interface{}{
+		"symbol":    "BTCUSDT",
+		"bid1Price": "101",
+	}
+	exg.handleWsTickers(client, &wsBaseMsg{Topic: topic, Type: "delta", Ts: 1700000001000, Data: mustJSON(t, delta)})
+	res = readChan(t, out)
+	if res.Bid != 101 || res.Ask != 100.5 || res.Last != 100 || res.MarkPrice != 100.1 || res.TimeStamp != 1700000001000 {
+		t.Fatalf("delta not merged: %+v", res)
+	}
+}
+
 func TestHandleWsWallet(t *testing.T) {
 	exg, client := newBybitWsTestWithClient(t, "BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear, wsPrivate)
 	client.SubscribeKeys["wallet"] = 0
@@ -247,6 +277,35 @@ func TestHandleWsExecutions(t *testing.T) {
 	}
 }
 
+func TestHandleWsOrders(t *testing.T) {
+	exg, client := newBybitWsTestWithClient(t, "BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear, wsPrivate)
+	client.SubscribeKeys["order"] = 0
+	out := wsOutChan[*banexg.Order](exg, client, "orders")
+	items := []map[string]interface{}{
+		{
+			"category":    "linear",
+			"symbol":      "BTCUSDT",
+			"orderId":     "o1",
+			"orderLinkId": "c1",
+			"side":        "Sell",
+			"orderType":   "Limit",
+			"orderStatus": "Cancelled",
+			"price":       "100",
+			"qty":         "0.2",
+			"cumExecQty":  "0",
+			"leavesQty":   "0",
+			"timeInForce": "GTC",
+			"createdTime": "1700000000000",
+			"updatedTime": "1700000001000",
+		},
+	}
+	exg.handleWsOrders(client, &wsBaseMsg{Topic: "order", Data: mustJSON(t, items)})
+	od := readChan(t, out)
+	if od.Symbol != "BTC/USDT:USDT" || od.ID != "o1" || od.Status != banexg.OdStatusCanceled || od.Amount != 0.2 {
+		t.Fatalf("unexpected order: %+v", od)
+	}
+}
+
 func TestHandleWsOpAuthSuccess(t *testing.T) {
 	exg, client := newBybitWsTestWithClient(t, "", "", banexg.MarketSpot, wsPrivate)
 	done := make(chan *errs.Error, 1)
@@ -276,3 +335,42 @@ func TestHandleWsOpAuthFail(t *testing.T) {
 		t.Fatal("expected ws authed state to be false")
 	}
 }
+
+func TestHandleWsOrderBookGapReset(t *testing.T) {
+	exg, client := newBybitWsTest(t, "BTCUSDT", "BTC/USDT", banexg.MarketSpot)
+	topic := "orderbook.50.BTCUSDT"
+	client.SubscribeKeys[topic] = 0
+	out := wsOutChan[*banexg.OrderBook](exg, client, "orderbook")
+	var resets []*banexg.OdBookReset
+	exg.OnOdBookReset = func(client *banexg.WsClient, evt *banexg.OdBookReset) {
+		resets = append(resets, evt)
+	}
+	snapshot := orderBookSnapshot{Symbol: "BTCUSDT", Bids: [][]string{{"100", "1"}}, Asks: [][]string{{"101", "2"}}, Update: 5}
+	exg.handleWsOrderBook(client, &wsBaseMsg{Topic: topic, Type: "snapshot", Data: mustJSON(t, snapshot)})
+	readChan(t, out)
+
+	gap := orderBookSnapshot{Symbol: "BTCUSDT", Bids: [][]string{{"100", "3"}}, Update: 7}
+	exg.handleWsOrderBook(client, &wsBaseMsg{Topic: topic, Type: "delta", Data: mustJSON(t, gap)})
+	if len(resets) != 1 || resets[0].Reason != banexg.OdBookResetGap || resets[0].Expect != 6 || resets[0].Actual != 7 {
+		t.Fatalf("unexpected reset events: %+v", resets)
+	}
+	book := exg.OrderBooks["BTC/USDT"]
+	if book.Nonce != 0 || len(book.Bids.Price) != 0 {
+		t.Fatalf("book should be reset: %+v", book)
+	}
+	// deltas before the new snapshot are dropped
+	next := orderBookSnapshot{Symbol: "BTCUSDT", Bids: [][]string{{"100", "4"}}, Update: 8}
+	exg.handleWsOrderBook(client, &wsBaseMsg{Topic: topic, Type: "delta", Data: mustJSON(t, next)})
+	if len(book.Bids.Price) != 0 || len(out) != 0 {
+		t.Fatalf("delta should be dropped before snapshot: %+v", book.Bids)
+	}
+
+	crossed := orderBookSnapshot{Symbol: "BTCUSDT", Bids: [][]string{{"100", "1"}}, Asks: [][]string{{"101", "2"}}, Update: 20}
+	exg.handleWsOrderBook(client, &wsBaseMsg{Topic: topic, Type: "snapshot", Data: mustJSON(t, crossed)})
+	readChan(t, out)
+	crossed = orderBookSnapshot{Symbol: "BTCUSDT", Bids: [][]string{{"102", "1"}}, Update: 21}
+	exg.handleWsOrderBook(client, &wsBaseMsg{Topic: topic, Type: "delta", Data: mustJSON(t, crossed)})
+	if len(resets) != 2 || resets[1].Reason != banexg.OdBookResetCrossed {
+		t.Fatalf("unexpected reset events: %+v", resets)
+	}
+}
@@ -224,6 +224,21 @@ func bybitWsTradeTopics(e *Bybit, symbols []string) ([]string, *errs.Error) {
 	return keys, nil
 }
 
+func bybitWsLiquidationTopics(e *Bybit, symbols []string) ([]string, *errs.Error) {
+	keys := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		market, err := e.GetMarket(sym)
+		if err != nil {
+			return nil, err
+		}
+		if !market.Linear && !market.Inverse {
+			return nil, errs.NewMsg(errs.CodeUnsupportMarket, "WatchLiquidations support linear/inverse, current: %s", sym)
+		}
+		keys = append(keys, "allLiquidation."+market.ID)
+	}
+	return keys, nil
+}
+
 func bybitWsTradeSymbol(market *banexg.Market) string {
 	if market == nil {
 		return ""
@@ -285,6 +300,53 @@ func bybitWsMarkPriceTopics(e *Bybit, symbols []string) ([]string, *errs.Error)
 	return keys, nil
 }
 
+func bybitWsTickerTopics(e *Bybit, symbols []string) ([]string, *errs.Error) {
+	keys := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		market, err := e.GetMarket(sym)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, "tickers."+market.ID)
+	}
+	return keys, nil
+}
+
+/*
+parseBybitWsTickers
+parse ticker items of the tickers topic, fields missing in delta pushes are left zero to be merged later.
+解析tickers推送，增量推送中缺失的字段保持为0，以便后续合并
+*/
+func parseBybitWsTickers(e *Bybit, items []map[string]interface{}, marketType string) []*banexg.Ticker {
+	var res []*banexg.Ticker
+	var err *errs.Error
+	switch marketType {
+	case banexg.MarketOption:
+		res, err = toStdTickers[*OptionTicker](e, items, marketType)
+	case banexg.MarketLinear, banexg.MarketInverse:
+		res, err = toStdTickers[*FutureTicker](e, items, marketType)
+	default:
+		res, err = toStdTickers[*SpotTicker](e, items, marketType)
+	}
+	if err != nil {
+		log.Error("bybit ws ticker parse fail", zap.Error(err))
+		return nil
+	}
+	return res
+}
+
+func toStdTickers[T ITicker](e *Bybit, items []map[string]interface{}, marketType string) ([]*banexg.Ticker, *errs.Error) {
+	arr, err := decodeBybitList[T](items)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*banexg.Ticker, 0, len(arr))
+	for i, item := range arr {
+		res = append(res, item.ToStdTicker(e, marketType, items[i]))
+	}
+	return res, nil
+}
+
 func fillBybitWsOrderBookTs(base *wsBaseMsg, data *orderBookSnapshot) {
 	if base == nil || data == nil {
 		return
@@ -294,16 +356,22 @@ func fillBybitWsOrderBookTs(base *wsBaseMsg, data *orderBookSnapshot) {
 	}
 }
 
-func applyBybitWsOrderBook(e *Bybit, market *banexg.Market, data *orderBookSnapshot, action string, depth int) *banexg.OrderBook {
+/*
+applyBybitWsOrderBook
+apply snapshot or delta to the local book. A delta whose u is not the last u+1, or makes the book crossed,
+returns a reset event. Deltas are dropped after reset until the snapshot is received from resubscribing.
+应用快照或增量。u不等于上次u+1或导致买卖交叉时返回重置事件，重置后丢弃增量直到重新订阅收到快照
+*/
+func applyBybitWsOrderBook(e *Bybit, market *banexg.Market, data *orderBookSnapshot, action string, depth int) (*banexg.OrderBook, *banexg.OdBookReset) {
 	if data == nil || market == nil {
-		return nil
+		return nil, nil
 	}
 	asks := bybitParseBookSide(data.Asks)
 	bids := bybitParseBookSide(data.Bids)
 	symbol := market.Symbol
 	e.OdBookLock.Lock()
 	book, ok := e.OrderBooks[symbol]
-	if !ok || action == "snapshot" {
+	if action == "snapshot" {
 		book = &banexg.OrderBook{
 			Symbol:    symbol,
 			TimeStamp: data.Ts,
@@ -315,9 +383,17 @@ func applyBybitWsOrderBook(e *Bybit, market *banexg.Market, data *orderBookSnaps
 		}
 		e.OrderBooks[symbol] = book
 		e.OdBookLock.Unlock()
-		return book
+		return book, nil
 	}
 	e.OdBookLock.Unlock()
+	if !ok || book.Nonce == 0 || data.Update <= book.Nonce {
+		// waiting for snapshot, or outdated delta
+		return nil, nil
+	}
+	if data.Update != book.Nonce+1 {
+		return nil, &banexg.OdBookReset{Symbol: symbol, Reason: banexg.OdBookResetGap,
+			Expect: book.Nonce + 1, Actual: data.Update}
+	}
 	if len(asks) > 0 {
 		book.Asks.Update(asks)
 	}
@@ -326,7 +402,10 @@ func applyBybitWsOrderBook(e *Bybit, market *banexg.Market, data *orderBookSnaps
 	}
 	book.TimeStamp = data.Ts
 	book.Nonce = data.Update
-	return book
+	if book.IsCrossed() {
+		return nil, &banexg.OdBookReset{Symbol: symbol, Reason: banexg.OdBookResetCrossed}
+	}
+	return book, nil
 }
 
 func normalizeBybitWsOrderBookAction(action string, data *orderBookSnapshot) string {
@@ -366,6 +445,39 @@ func parseBybitWsTradeItem(e *Bybit, item map[string]interface{}, marketType str
 	return trade
 }
 
+// parseBybitWsLiquidation S is side of the liquidated position, Buy means a long position is liquidated by selling
+func parseBybitWsLiquidation(e *Bybit, item map[string]interface{}, marketType string) *banexg.Liquidation {
+	if item == nil {
+		return nil
+	}
+	marketID := bybitWsString(item["s"])
+	if marketID == "" {
+		return nil
+	}
+	side := banexg.OdSideSell
+	if strings.ToLower(bybitWsString(item["S"])) == "sell" {
+		side = banexg.OdSideBuy
+	}
+	price := parseBybitNum(item["p"])
+	amount := parseBybitNum(item["v"])
+	if marketType == banexg.MarketInverse {
+		// v of inverse is value in USD
+		if price > 0 {
+			amount /= price
+		} else {
+			amount = 0
+		}
+	}
+	return &banexg.Liquidation{
+		Symbol:    bybitSafeSymbol(e, marketID, marketType),
+		Side:      side,
+		Price:     price,
+		Amount:    amount,
+		Timestamp: parseBybitInt(item["T"]),
+		Info:      item,
+	}
+}
+
 func parseBybitWsKlineItem(item map[string]interface{}) *banexg.Kline {
 	if item == nil {
 		return nil
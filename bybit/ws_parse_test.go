@@ -32,6 +32,34 @@ func TestParseBybitWsTradeItem(t *testing.T) {
 	}
 }
 
+func TestParseBybitWsLiquidation(t *testing.T) {
+	exg := mustNewBybit(t, "Bybit")
+	seedMarket(exg, "BTCUSDT", "BTC/USDT:USDT", banexg.MarketLinear)
+	item := map[string]interface{}{
+		"T": int64(1700000000000),
+		"s": "BTCUSDT",
+		"S": "Buy",
+		"v": "0.5",
+		"p": "30000",
+	}
+	liq := parseBybitWsLiquidation(exg, item, banexg.MarketLinear)
+	if liq == nil {
+		t.Fatalf("unexpected nil liquidation")
+	}
+	if liq.Symbol != "BTC/USDT:USDT" || liq.Price != 30000 || liq.Amount != 0.5 || liq.Timestamp != 1700000000000 {
+		t.Fatalf("unexpected liquidation: %+v", liq)
+	}
+	// long position liquidated by a sell order
+	if liq.Side != banexg.OdSideSell {
+		t.Fatalf("unexpected side: %s", liq.Side)
+	}
+	item["v"] = "15000"
+	liq = parseBybitWsLiquidation(exg, item, banexg.MarketInverse)
+	if liq == nil || liq.Amount != 0.5 {
+		t.Fatalf("inverse liquidation should be in base amount: %+v", liq)
+	}
+}
+
 func TestParseBybitWsKlineItem(t *testing.T) {
 	item := map[string]interface{}{
 		"start":    int64(1700000000000),
@@ -68,8 +96,8 @@ func TestApplyBybitWsOrderBook(t *testing.T) {
 		Ts:     1700000000000,
 		Update: 10,
 	}
-	book := applyBybitWsOrderBook(exg, market, data, "snapshot", 50)
-	if book == nil {
+	book, reset := applyBybitWsOrderBook(exg, market, data, "snapshot", 50)
+	if book == nil || reset != nil {
 		t.Fatalf("unexpected nil orderbook")
 	}
 	if book.Bids == nil || len(book.Bids.Price) != 1 || book.Bids.Price[0] != 100 {
@@ -0,0 +1,112 @@
+package bybit
+
+import (
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+// rest api: op of websocket trade api
+var wsTradeOps = map[string]string{
+	MethodPrivatePostV5OrderCreate: "order.create",
+	MethodPrivatePostV5OrderAmend:  "order.amend",
+	MethodPrivatePostV5OrderCancel: "order.cancel",
+}
+
+/*
+requestTrade
+send the order request by websocket when ParamWsTrade/OptWsTrade is set, fallback to rest when the socket is down
+设置ParamWsTrade/OptWsTrade时通过websocket发送订单请求，连接不可用时回退到rest
+:see: https://bybit-exchange.github.io/docs/v5/websocket/trade/guideline
+*/
+func requestTrade[T any](e *Bybit, api string, params map[string]interface{}, tryNum int) *banexg.ApiRes[T] {
+	if e.IsWsTrade(params) {
+		if op, ok := wsTradeOps[api]; ok {
+			res := wsRequestTrade[T](e, api, op, params)
+			if res.Error == nil || res.Error.Code != errs.CodeConnectFail {
+				return res
+			}
+			log.Warn("bybit ws trade unavailable, use rest", zap.String("api", api), zap.String("err", res.Error.Short()))
+		}
+	}
+	return requestRetry[T](e, api, params, tryNum)
+}
+
+func wsRequestTrade[T any](e *Bybit, api, op string, params map[string]interface{}) *banexg.ApiRes[T] {
+	args := utils.SafeParams(params)
+	ctx := banexg.ParamsCtx(args)
+	delete(args, banexg.ParamCtx)
+	delete(args, banexg.ParamNoCache)
+	accName := e.PopAccName(args)
+	res := &banexg.ApiRes[T]{HttpRes: &banexg.HttpRes{AccName: accName}}
+	if res.Error = e.CheckRiskyAllowed(e.Apis[api], accName); res.Error != nil {
+		return res
+	}
+	client, err := e.getTradeClient(accName)
+	if err != nil {
+		// login not finished, the order is not sent yet
+		res.Error = errs.NewFull(errs.CodeConnectFail, err, "bybit ws trade login fail")
+		return res
+	}
+	id := banexg.NewWsReqID()
+	msg := map[string]interface{}{
+		"reqId": id,
+		"header": map[string]string{
+			"X-BAPI-TIMESTAMP":   strconv.FormatInt(e.Nonce(), 10),
+			"X-BAPI-RECV-WINDOW": strconv.Itoa(e.RecvWindow),
+		},
+		"op":   op,
+		"args": []map[string]interface{}{args},
+	}
+	rsp, err := client.Request(ctx, id, msg)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	res.Url = client.URL
+	res.Content = rsp.Text
+	decodeWsTradeRes(res)
+	return res
+}
+
+func (e *Bybit) getTradeClient(accName string) (*banexg.WsClient, *errs.Error) {
+	acc, err := e.GetAccount(accName)
+	if err != nil {
+		return nil, err
+	}
+	wsUrl := e.GetHost(HostWsTrade)
+	if wsUrl == "" {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "ws host missing for trade")
+	}
+	client, err := e.GetClient(wsUrl, wsTrade, acc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.wsLogin(client, acc, 0); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// decodeWsTradeRes parse {retCode, retMsg, data}, data of trade api is the same as result of rest api
+func decodeWsTradeRes[T any](res *banexg.ApiRes[T]) {
+	var rsp struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Data    T      `json:"data"`
+	}
+	err := utils.UnmarshalString(res.Content, &rsp, utils.JsonNumDefault)
+	if err != nil {
+		res.Error = errs.New(errs.CodeUnmarshalFail, err)
+		return
+	}
+	if rsp.RetCode != 0 {
+		res.Error = mapBybitRetCode(rsp.RetCode, rsp.RetMsg)
+	} else {
+		res.Result = rsp.Data
+	}
+}
@@ -0,0 +1,175 @@
+package banexg
+
+import (
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"github.com/sasha-s/go-deadlock"
+	"go.uber.org/zap"
+)
+
+// min interval to refresh the timer of KeepCancelAllAfter
+var cancelAfterMinIntv = time.Second
+
+type cancelAfterJob struct {
+	timeoutMs int64
+	symbol    string
+	params    map[string]interface{}
+	stop      chan struct{}
+	done      chan struct{} // closed when the refresh goroutine exits
+	stopped   bool
+	lock      deadlock.Mutex // guards stopped, never held across requests
+}
+
+// cancelAfterKey keepers are keyed by account and scope of the exchange timer
+func (e *Exchange) cancelAfterKey(symbol string, params map[string]interface{}) string {
+	scope := symbol
+	if e.CancelAfterScope != nil {
+		scope = e.CancelAfterScope(symbol, params)
+	}
+	return e.GetAccName(params) + "@" + scope
+}
+
+/*
+KeepCancelAllAfter
+arm the exchange side dead man's switch by SetCancelAllAfter, and refresh it every timeoutMs/3 in background
+while the process is alive. If the process crashes or hangs, the exchange cancels all open orders when the
+timer expires. The keeper stops on StopCancelAllAfter, Close, or when ctx in params is done.
+Keepers are keyed by account and scope of the exchange timer (Exchange.CancelAfterScope, symbol by default),
+calling again in the same scope replaces the old keeper.
+通过SetCancelAllAfter启用交易所端的超时撤单，并在进程存活期间每timeoutMs/3后台刷新一次。
+进程崩溃或卡死时，计时器到期后交易所撤销所有挂单。调用StopCancelAllAfter、Close或params中的ctx结束时停止刷新。
+任务按账户和交易所计时器的范围(Exchange.CancelAfterScope，默认symbol)区分，相同范围重复调用会替换旧的任务
+*/
+func (e *Exchange) KeepCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) *errs.Error {
+	if timeoutMs <= 0 {
+		return errs.NewMsg(errs.CodeParamInvalid, "timeoutMs must > 0 for KeepCancelAllAfter")
+	}
+	exg := e.self()
+	if _, err := exg.SetCancelAllAfter(timeoutMs, symbol, utils.SafeParams(params)); err != nil {
+		return err
+	}
+	job := &cancelAfterJob{
+		timeoutMs: timeoutMs,
+		symbol:    symbol,
+		params:    utils.SafeParams(params),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	key := e.cancelAfterKey(symbol, params)
+	e.cancelLock.Lock()
+	if e.cancelAfters == nil {
+		e.cancelAfters = make(map[string]*cancelAfterJob)
+	}
+	old := e.cancelAfters[key]
+	e.cancelAfters[key] = job
+	e.cancelLock.Unlock()
+	if old != nil {
+		old.close()
+	}
+	intv := max(time.Duration(timeoutMs)*time.Millisecond/3, cancelAfterMinIntv)
+	ctx := ParamsCtx(params)
+	go func() {
+		defer close(job.done)
+		ticker := time.NewTicker(intv)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-job.stop:
+				return
+			case <-ctx.Done():
+				e.cancelLock.Lock()
+				if e.cancelAfters[key] == job {
+					delete(e.cancelAfters, key)
+				}
+				e.cancelLock.Unlock()
+				job.close()
+				return
+			case <-ticker.C:
+			}
+			if job.isStopped() {
+				return
+			}
+			// never hold the lock across the request, Stop waits on done before disarming
+			_, err := exg.SetCancelAllAfter(timeoutMs, symbol, utils.SafeParams(job.params))
+			if err != nil {
+				log.Warn("refresh cancel all after fail", zap.String("exg", e.Name),
+					zap.String("symbol", symbol), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+/*
+StopCancelAllAfter
+stop the keeper of symbol and account in params started by KeepCancelAllAfter, disarm the exchange timer if
+disarm is true, otherwise open orders are cancelled when the timer expires.
+停止KeepCancelAllAfter启动的params中账户和symbol的刷新任务，disarm为true时同时取消交易所的计时器，否则计时器到期后撤销挂单
+*/
+func (e *Exchange) StopCancelAllAfter(symbol string, disarm bool, params map[string]interface{}) *errs.Error {
+	key := e.cancelAfterKey(symbol, params)
+	e.cancelLock.Lock()
+	job, ok := e.cancelAfters[key]
+	delete(e.cancelAfters, key)
+	e.cancelLock.Unlock()
+	if !ok {
+		return nil
+	}
+	return e.stopCancelAfterJob(job, disarm)
+}
+
+func (e *Exchange) stopCancelAfterJob(job *cancelAfterJob, disarm bool) *errs.Error {
+	job.close()
+	// wait for the refresh in flight, or it may arm the timer again after disarm
+	<-job.done
+	if disarm {
+		_, err := e.self().SetCancelAllAfter(0, job.symbol, utils.SafeParams(job.params))
+		return err
+	}
+	return nil
+}
+
+/*
+stopCancelAfters
+stop all keepers and disarm timers on Close, a graceful exit should not cancel orders.
+timers which can't be disarmed (e.g. bybit dcp) still cancel open orders when they expire.
+Close时停止所有任务并取消计时器，正常退出不应撤单。无法取消的计时器(如bybit dcp)到期后仍会撤单
+*/
+func (e *Exchange) stopCancelAfters() {
+	e.cancelLock.Lock()
+	jobs := utils.ValsOfMap(e.cancelAfters)
+	e.cancelAfters = nil
+	e.cancelLock.Unlock()
+	noDisarm := false
+	for _, job := range jobs {
+		err := e.stopCancelAfterJob(job, true)
+		if err != nil && err.Code == errs.CodeNotSupport {
+			noDisarm = true
+		} else if err != nil {
+			log.Warn("disarm cancel all after fail", zap.String("exg", e.Name),
+				zap.String("symbol", job.symbol), zap.Error(err))
+		}
+	}
+	if noDisarm {
+		log.Info("cancel all after timer can't be disarmed, open orders are cancelled when it expires",
+			zap.String("exg", e.Name))
+	}
+}
+
+func (j *cancelAfterJob) close() {
+	j.lock.Lock()
+	if !j.stopped {
+		j.stopped = true
+		close(j.stop)
+	}
+	j.lock.Unlock()
+}
+
+func (j *cancelAfterJob) isStopped() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.stopped
+}
@@ -13,6 +13,7 @@ func New(Options map[string]interface{}) (*China, *errs.Error) {
 				Name:      "China",
 				Countries: []string{"CN"},
 				FixedLvg:  true,
+				TimeLoc:   defTimeLoc,
 			},
 			RateLimit: 50,
 			Options:   Options,
@@ -31,6 +32,18 @@ func New(Options map[string]interface{}) (*China, *errs.Error) {
 			},
 			Has: map[string]map[string]int{
 				"": {
+					banexg.ApiFetchTime:             banexg.HasFail,
+					banexg.ApiTransfer:              banexg.HasFail,
+					banexg.ApiFetchTransfers:        banexg.HasFail,
+					banexg.ApiFetchDepositAddress:   banexg.HasFail,
+					banexg.ApiFetchDeposits:         banexg.HasFail,
+					banexg.ApiFetchWithdrawals:      banexg.HasFail,
+					banexg.ApiWithdraw:              banexg.HasFail,
+					banexg.ApiBorrow:                banexg.HasFail,
+					banexg.ApiRepay:                 banexg.HasFail,
+					banexg.ApiFetchBorrowRate:       banexg.HasFail,
+					banexg.ApiFetchBorrowRates:      banexg.HasFail,
+					banexg.ApiFetchBorrowInterest:   banexg.HasFail,
 					banexg.ApiFetchTicker:           banexg.HasFail,
 					banexg.ApiFetchTickers:          banexg.HasFail,
 					banexg.ApiFetchTickerPrice:      banexg.HasFail,
@@ -38,16 +51,36 @@ func New(Options map[string]interface{}) (*China, *errs.Error) {
 					banexg.ApiGetLeverage:           banexg.HasOk,
 					banexg.ApiFetchOHLCV:            banexg.HasFail,
 					banexg.ApiFetchOrderBook:        banexg.HasFail,
+					banexg.ApiFetchTrades:           banexg.HasFail,
 					banexg.ApiFetchOrder:            banexg.HasFail,
 					banexg.ApiFetchOrders:           banexg.HasFail,
 					banexg.ApiFetchBalance:          banexg.HasFail,
 					banexg.ApiFetchAccountPositions: banexg.HasFail,
 					banexg.ApiFetchPositions:        banexg.HasFail,
 					banexg.ApiFetchOpenOrders:       banexg.HasFail,
+					banexg.ApiFetchMyTrades:         banexg.HasFail,
 					banexg.ApiCreateOrder:           banexg.HasFail,
 					banexg.ApiEditOrder:             banexg.HasFail,
 					banexg.ApiCancelOrder:           banexg.HasFail,
+					banexg.ApiCreateOrders:          banexg.HasFail,
+					banexg.ApiCancelOrders:          banexg.HasFail,
+					banexg.ApiCancelAllOrders:       banexg.HasFail,
 					banexg.ApiSetLeverage:           banexg.HasFail,
+					banexg.ApiSetMarginMode:         banexg.HasFail,
+					banexg.ApiSetPositionMode:       banexg.HasFail,
+					banexg.ApiAddMargin:             banexg.HasFail,
+					banexg.ApiReduceMargin:          banexg.HasFail,
+					banexg.ApiFetchOpenInterest:     banexg.HasFail,
+					banexg.ApiFetchOpenInterestHist: banexg.HasFail,
+					banexg.ApiFetchLongShortRatio:   banexg.HasFail,
+					banexg.ApiWatchLiquidations:     banexg.HasFail,
+					banexg.ApiUnWatchLiquidations:   banexg.HasFail,
+					banexg.ApiFetchOptionChain:      banexg.HasFail,
+					banexg.ApiFetchGreeks:           banexg.HasFail,
+					banexg.ApiWatchGreeks:           banexg.HasFail,
+					banexg.ApiUnWatchGreeks:         banexg.HasFail,
+					banexg.ApiFetchOHLCVRange:       banexg.HasFail,
+					banexg.ApiSetCancelAllAfter:     banexg.HasFail,
 					banexg.ApiCalcMaintMargin:       banexg.HasFail,
 					banexg.ApiWatchOrderBooks:       banexg.HasFail,
 					banexg.ApiUnWatchOrderBooks:     banexg.HasFail,
@@ -57,7 +90,10 @@ func New(Options map[string]interface{}) (*China, *errs.Error) {
 					banexg.ApiUnWatchMarkPrices:     banexg.HasFail,
 					banexg.ApiWatchTrades:           banexg.HasFail,
 					banexg.ApiUnWatchTrades:         banexg.HasFail,
+					banexg.ApiWatchTickers:          banexg.HasFail,
+					banexg.ApiUnWatchTickers:        banexg.HasFail,
 					banexg.ApiWatchMyTrades:         banexg.HasFail,
+					banexg.ApiWatchOrders:           banexg.HasFail,
 					banexg.ApiWatchBalance:          banexg.HasFail,
 					banexg.ApiWatchPositions:        banexg.HasFail,
 					banexg.ApiWatchAccountConfig:    banexg.HasFail,
@@ -65,6 +101,7 @@ func New(Options map[string]interface{}) (*China, *errs.Error) {
 			},
 		},
 	}
+	exg.Self = exg
 	err := exg.Init()
 	if err != nil {
 		return nil, err
@@ -2,6 +2,7 @@ package banexg
 
 import (
 	"fmt"
+	"hash/crc32"
 	"math"
 	"math/rand"
 	"sort"
@@ -156,6 +157,37 @@ func (obs *OdBookSide) Update(deltas [][2]float64) {
 	obs.Lock.Unlock()
 }
 
+/*
+UpdateTexts
+apply levels of raw price and size strings, the strings are kept in Texts so that Checksum uses the exact
+text pushed by exchange, e.g. "0.10" or "1e-05" can't be rebuilt from float64
+应用原始字符串的价格和数量档位，原始字符串保存在Texts中，供Checksum使用交易所推送的原文
+*/
+func (obs *OdBookSide) UpdateTexts(levels [][2]string) {
+	obs.Lock.Lock()
+	if obs.Texts == nil {
+		obs.Texts = make(map[float64][2]string)
+	}
+	for _, row := range levels {
+		price, _ := strconv.ParseFloat(row[0], 64)
+		size, _ := strconv.ParseFloat(row[1], 64)
+		obs.Set(price, size)
+		if size > 0 {
+			obs.Texts[price] = row
+		} else {
+			delete(obs.Texts, price)
+		}
+	}
+	if len(obs.Price) > obs.Depth {
+		for _, price := range obs.Price[obs.Depth:] {
+			delete(obs.Texts, price)
+		}
+		obs.Size = obs.Size[:obs.Depth]
+		obs.Price = obs.Price[:obs.Depth]
+	}
+	obs.Lock.Unlock()
+}
+
 func (obs *OdBookSide) Set(price, size float64) {
 	oldLen := len(obs.Price)
 	prices := obs.Price
@@ -313,6 +345,83 @@ func (b *OrderBook) Update(book *OrderBook) {
 	b.Asks.Lock.Unlock()
 }
 
+/*
+Checksum
+crc32 of the top `depth` levels, formatted as "bid1Px:bid1Sz:ask1Px:ask1Sz:bid2Px:...", the side with fewer levels is skipped.
+The raw strings in Texts are used if exist, otherwise prices and sizes are formatted with the shortest decimal text.
+前depth档的crc32校验和，买卖交替拼接，优先使用Texts中的原始字符串，否则使用最短十进制文本
+*/
+func (b *OrderBook) Checksum(depth int) int32 {
+	b.Asks.Lock.Lock()
+	b.Bids.Lock.Lock()
+	defer b.Bids.Lock.Unlock()
+	defer b.Asks.Lock.Unlock()
+	level := func(side *OdBookSide, i int) []string {
+		if text, ok := side.Texts[side.Price[i]]; ok {
+			return text[:]
+		}
+		return []string{strconv.FormatFloat(side.Price[i], 'f', -1, 64), strconv.FormatFloat(side.Size[i], 'f', -1, 64)}
+	}
+	parts := make([]string, 0, depth*4)
+	for i := 0; i < depth; i++ {
+		if i < len(b.Bids.Price) {
+			parts = append(parts, level(b.Bids, i)...)
+		}
+		if i < len(b.Asks.Price) {
+			parts = append(parts, level(b.Asks, i)...)
+		}
+	}
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(parts, ":"))))
+}
+
+/*
+IsCrossed
+whether best bid >= best ask, which means the local book is broken
+买一价不低于卖一价，说明本地订单簿已损坏
+*/
+func (b *OrderBook) IsCrossed() bool {
+	bid, _ := b.Bids.Level(0)
+	ask, _ := b.Asks.Level(0)
+	return bid > 0 && ask > 0 && bid >= ask
+}
+
+/*
+Merge
+merge the non-zero fields of u into t, for exchanges which push partial fields in ticker updates
+将u中的非零字段合并到t，用于只推送部分字段的ticker更新
+*/
+func (t *Ticker) Merge(u *Ticker) {
+	mergeFlt := func(dst *float64, val float64) {
+		if val != 0 {
+			*dst = val
+		}
+	}
+	if u.TimeStamp > 0 {
+		t.TimeStamp = u.TimeStamp
+	}
+	mergeFlt(&t.Bid, u.Bid)
+	mergeFlt(&t.BidVolume, u.BidVolume)
+	mergeFlt(&t.Ask, u.Ask)
+	mergeFlt(&t.AskVolume, u.AskVolume)
+	mergeFlt(&t.High, u.High)
+	mergeFlt(&t.Low, u.Low)
+	mergeFlt(&t.Open, u.Open)
+	mergeFlt(&t.Close, u.Close)
+	mergeFlt(&t.Last, u.Last)
+	mergeFlt(&t.Change, u.Change)
+	mergeFlt(&t.Percentage, u.Percentage)
+	mergeFlt(&t.Average, u.Average)
+	mergeFlt(&t.Vwap, u.Vwap)
+	mergeFlt(&t.BaseVolume, u.BaseVolume)
+	mergeFlt(&t.QuoteVolume, u.QuoteVolume)
+	mergeFlt(&t.PreviousClose, u.PreviousClose)
+	mergeFlt(&t.MarkPrice, u.MarkPrice)
+	mergeFlt(&t.IndexPrice, u.IndexPrice)
+	if u.Info != nil {
+		t.Info = u.Info
+	}
+}
+
 func (k *Kline) Clone() *Kline {
 	return &Kline{
 		Time:      k.Time,
@@ -436,6 +545,45 @@ func (m *Market) GetTradeTimes() [][2]int64 {
 	return times
 }
 
+/*
+SetTakerVolume
+set taker buy/sell volume for RatioTaker, LongRatio/ShortRatio are the share of them, Ratio is buy/sell
+设置主动买卖量，LongRatio/ShortRatio为二者占比，Ratio为买卖量之比
+*/
+func (r *LongShortRatio) SetTakerVolume(buy, sell float64) {
+	r.BuyVolume = buy
+	r.SellVolume = sell
+	if total := buy + sell; total > 0 {
+		r.LongRatio = buy / total
+		r.ShortRatio = sell / total
+	}
+	if sell > 0 {
+		r.Ratio = buy / sell
+	}
+}
+
+/*
+BaseAmount
+amount in base currency of contracts, contracts of inverse market are valued in quote currency, so price is required
+合约张数对应的基础币数量，反向合约面值为计价币，需要价格
+*/
+func (m *Market) BaseAmount(contracts, price float64) float64 {
+	if !m.Contract {
+		return contracts
+	}
+	size := m.ContractSize
+	if size <= 0 {
+		size = 1
+	}
+	if m.Inverse {
+		if price <= 0 {
+			return 0
+		}
+		return contracts * size / price
+	}
+	return contracts * size
+}
+
 func GetHostRetryWait(host string, randAdd bool) int64 {
 	var waitMS int64
 	hostWaitLock.Lock()
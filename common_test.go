@@ -2,6 +2,7 @@ package banexg
 
 import (
 	"fmt"
+	"hash/crc32"
 	"testing"
 )
 
@@ -45,3 +46,56 @@ func TestOdBookSide(t *testing.T) {
 		t.Errorf("SumVolTo fail")
 	}
 }
+
+func TestOrderBookChecksum(t *testing.T) {
+	book := &OrderBook{
+		Bids: NewOdBookSide(true, 400, [][2]float64{{3366.1, 7}, {3366, 6}}),
+		Asks: NewOdBookSide(false, 400, [][2]float64{{3366.8, 9}, {3368, 8}}),
+	}
+	if sum := book.Checksum(25); sum != -1881014294 {
+		t.Fatalf("unexpected checksum: %d", sum)
+	}
+	if book.IsCrossed() {
+		t.Fatalf("book should not be crossed")
+	}
+	book.Bids.Update([][2]float64{{3366.5, 1}})
+	if book.IsCrossed() {
+		t.Fatalf("book should not be crossed")
+	}
+	book.Bids.Update([][2]float64{{3366.8, 1}})
+	if !book.IsCrossed() {
+		t.Fatalf("book should be crossed")
+	}
+}
+
+func TestOrderBookChecksumTexts(t *testing.T) {
+	book := &OrderBook{
+		Bids: NewOdBookSide(true, 2, nil),
+		Asks: NewOdBookSide(false, 2, nil),
+	}
+	book.Bids.UpdateTexts([][2]string{{"3366.10", "7"}, {"3366", "6"}, {"3365", "1e-05"}})
+	book.Asks.UpdateTexts([][2]string{{"3366.8", "9.0"}, {"3368", "8"}})
+	text := "3366.10:7:3366.8:9.0:3366:6:3368:8"
+	if sum := book.Checksum(25); sum != int32(crc32.ChecksumIEEE([]byte(text))) {
+		t.Fatalf("checksum should use raw texts")
+	}
+	if len(book.Bids.Texts) != 2 {
+		t.Fatalf("texts of truncated levels should be removed, got %v", book.Bids.Texts)
+	}
+	book.Bids.UpdateTexts([][2]string{{"3366.1", "0"}})
+	if _, ok := book.Bids.Texts[3366.1]; ok || len(book.Bids.Price) != 1 {
+		t.Fatalf("removed level should drop text")
+	}
+}
+
+func TestMarketBaseAmount(t *testing.T) {
+	spot := &Market{}
+	linear := &Market{Contract: true, ContractSize: 0.01}
+	inverse := &Market{Contract: true, Inverse: true, ContractSize: 100}
+	if spot.BaseAmount(2, 100) != 2 || linear.BaseAmount(20, 30000) != 0.2 || inverse.BaseAmount(3, 300) != 1 {
+		t.Fatalf("unexpected base amount")
+	}
+	if inverse.BaseAmount(3, 0) != 0 {
+		t.Fatalf("inverse without price should be zero")
+	}
+}
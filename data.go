@@ -62,6 +62,24 @@ const (
 	ParamCurrency    = "currency"    // Currency code
 	ParamArchive     = "archive"     // Whether to use archive endpoint
 	ParamSettleCoins = "settleCoins" // Settlement coins for position queries
+	ParamFromAccount = "fromAccount" // account type transferred from, for FetchTransfers
+	ParamToAccount   = "toAccount"   // account type transferred to, for FetchTransfers
+	ParamFromSubAcc  = "fromSubAcc"  // sub account (name in Accounts or exchange id) transferred from
+	ParamToSubAcc    = "toSubAcc"    // sub account (name in Accounts or exchange id) transferred to
+	ParamNetwork     = "network"     // chain network id of currency, see Currency.Networks
+	ParamRatioType   = "ratioType"   // RatioAccount(default)/RatioTaker, for FetchLongShortRatioHistory
+	ParamPrice       = "price"       // kline price type: PriceMark/PriceIndex/PricePremiumIndex, for FetchOHLCV/WatchOHLCVs
+	ParamWsTrade     = "wsTrade"     // bool, send the order request by websocket trading api, override OptWsTrade
+	ParamHedged      = "hedged"      // bool, account is in hedge position mode, for ValidateOrder
+	ParamAdjust      = "adjust"      // bool, round and clip the order into the limits of market, for ValidateOrder
+	ParamRefKey      = "refKey"      // key referencing the out chan of WatchOrders/WatchMyTrades, "account" by default
+	// ParamCtx carries a context.Context down to RequestApiRetryAdv, set by the *Ctx methods.
+	ParamCtx = "ctx"
+)
+
+const (
+	WsLogWsMsg   = "wsMsg"   // raw websocket message, content: [url, marketType, accName, msg]
+	WsLogHttpRes = "httpRes" // rest response, content: ApiLog
 )
 
 var (
@@ -86,6 +104,8 @@ var (
 	hostWaitLock    deadlock.Mutex
 	hostFlowChans   = make(map[string]chan struct{})
 	hostFlowLock    deadlock.Mutex
+	rateBuckets     = make(map[string]*RateBucket) // shared by all exchanges in process, keyed by rule
+	rateBucketLock  deadlock.Mutex
 	HostHttpConcurr = 3 // Maximum concurrent number of HTTP requests per domain name 每个域名发起http请求最大并发数
 )
 
@@ -134,6 +154,9 @@ const (
 	OptEnv             = "Env"
 	OptWsTimeout       = "WsTimeout"
 	OptRecvWindow      = "RecvWindow"
+	OptSyncTimeSecs    = "SyncTimeSecs" // interval secs to sync server time, 0 to disable
+	OptSubAccId        = "SubAccId"     // id of sub account on exchange, set in Creds of each account
+	OptWsTrade         = "WsTrade"      // send create/edit/cancel order by websocket trading api, fallback to rest when the socket is down
 )
 
 const (
@@ -159,6 +182,33 @@ const (
 	MarketFuture = "future" // 有交割日的期货 for expiring futures contracts that have a delivery/settlement date
 )
 
+const (
+	AccountFunding = "funding" // 资金账户，其他账户类型使用MarketSpot/MarketMargin/MarketLinear等
+)
+
+const (
+	TransferOk       = "ok"
+	TransferPending  = "pending"
+	TransferFailed   = "failed"
+	TransferCanceled = "canceled"
+)
+
+const (
+	TxDeposit    = "deposit"
+	TxWithdrawal = "withdrawal"
+)
+
+const (
+	PriceMark         = "mark"         // mark price klines 标记价格K线
+	PriceIndex        = "index"        // index price klines 指数价格K线
+	PricePremiumIndex = "premiumIndex" // premium index klines 溢价指数K线
+)
+
+const (
+	RatioAccount = "account" // long/short accounts ratio 多空账户数比
+	RatioTaker   = "taker"   // taker buy/sell volume ratio 主动买卖量比
+)
+
 const (
 	MarginCross    = "cross"
 	MarginIsolated = "isolated"
@@ -226,6 +276,13 @@ const (
 )
 
 const (
+	OdBookResetGap      = "gap"      // update id is not continuous 更新序号不连续
+	OdBookResetChecksum = "checksum" // checksum of top levels mismatch 前若干档校验和不一致
+	OdBookResetCrossed  = "crossed"  // best bid >= best ask 买一价不低于卖一价
+)
+
+const (
+	ApiFetchTime             = "FetchTime"
 	ApiFetchTicker           = "FetchTicker"
 	ApiFetchTickers          = "FetchTickers"
 	ApiFetchTickerPrice      = "FetchTickerPrice"
@@ -234,15 +291,20 @@ const (
 	ApiGetLeverage           = "GetLeverage"
 	ApiFetchOHLCV            = "FetchOHLCV"
 	ApiFetchOrderBook        = "FetchOrderBook"
+	ApiFetchTrades           = "FetchTrades"
 	ApiFetchOrder            = "FetchOrder"
 	ApiFetchOrders           = "FetchOrders"
 	ApiFetchBalance          = "FetchBalance"
 	ApiFetchAccountPositions = "FetchAccountPositions"
 	ApiFetchPositions        = "FetchPositions"
 	ApiFetchOpenOrders       = "FetchOpenOrders"
+	ApiFetchMyTrades         = "FetchMyTrades"
 	ApiCreateOrder           = "CreateOrder"
 	ApiEditOrder             = "EditOrder"
 	ApiCancelOrder           = "CancelOrder"
+	ApiCreateOrders          = "CreateOrders"
+	ApiCancelOrders          = "CancelOrders"
+	ApiCancelAllOrders       = "CancelAllOrders"
 	ApiSetLeverage           = "SetLeverage"
 	ApiCalcMaintMargin       = "CalcMaintMargin"
 	ApiWatchOrderBooks       = "WatchOrderBooks"
@@ -253,10 +315,39 @@ const (
 	ApiUnWatchMarkPrices     = "UnWatchMarkPrices"
 	ApiWatchTrades           = "WatchTrades"
 	ApiUnWatchTrades         = "UnWatchTrades"
+	ApiWatchTickers          = "WatchTickers"
+	ApiUnWatchTickers        = "UnWatchTickers"
 	ApiWatchMyTrades         = "WatchMyTrades"
+	ApiWatchOrders           = "WatchOrders"
 	ApiWatchBalance          = "WatchBalance"
 	ApiWatchPositions        = "WatchPositions"
 	ApiWatchAccountConfig    = "WatchAccountConfig"
+	ApiTransfer              = "Transfer"
+	ApiFetchTransfers        = "FetchTransfers"
+	ApiFetchDepositAddress   = "FetchDepositAddress"
+	ApiFetchDeposits         = "FetchDeposits"
+	ApiFetchWithdrawals      = "FetchWithdrawals"
+	ApiWithdraw              = "Withdraw"
+	ApiBorrow                = "Borrow"
+	ApiRepay                 = "Repay"
+	ApiFetchBorrowRate       = "FetchBorrowRate"
+	ApiFetchBorrowRates      = "FetchBorrowRates"
+	ApiFetchBorrowInterest   = "FetchBorrowInterest"
+	ApiSetMarginMode         = "SetMarginMode"
+	ApiSetPositionMode       = "SetPositionMode"
+	ApiAddMargin             = "AddMargin"
+	ApiReduceMargin          = "ReduceMargin"
+	ApiFetchOpenInterest     = "FetchOpenInterest"
+	ApiFetchOpenInterestHist = "FetchOpenInterestHistory"
+	ApiFetchLongShortRatio   = "FetchLongShortRatioHistory"
+	ApiWatchLiquidations     = "WatchLiquidations"
+	ApiUnWatchLiquidations   = "UnWatchLiquidations"
+	ApiFetchOptionChain      = "FetchOptionChain"
+	ApiFetchGreeks           = "FetchGreeks"
+	ApiWatchGreeks           = "WatchGreeks"
+	ApiUnWatchGreeks         = "UnWatchGreeks"
+	ApiFetchOHLCVRange       = "FetchOHLCVRange"
+	ApiSetCancelAllAfter     = "SetCancelAllAfter"
 )
 
 var (
@@ -45,6 +45,15 @@ const (
 	CodeDataNotFound
 	CodeServerError
 	CodeNoTrade
+	// normalized trading errors mapped from exchange business codes
+	CodeInsufficientBalance
+	CodeOrderNotFound
+	CodeOrderWouldMatch // post-only/maker order would immediately match
+	CodeReduceOnlyRejected
+	CodeInvalidNonce // request timestamp out of recv window, or bad nonce
+	CodePositionModeMismatch
+	CodeMarketClosed
+	CodeDataTruncated // paging stopped by the max number of requests, the fetched part is returned
 )
 
 var (
@@ -93,4 +102,12 @@ var errCodeNames = map[int]string{
 	CodeDataNotFound:         "DataNotFound",
 	CodeServerError:          "ServerError",
 	CodeNoTrade:              "NoTrade",
+	CodeInsufficientBalance:  "InsufficientBalance",
+	CodeOrderNotFound:        "OrderNotFound",
+	CodeOrderWouldMatch:      "OrderWouldMatch",
+	CodeReduceOnlyRejected:   "ReduceOnlyRejected",
+	CodeInvalidNonce:         "InvalidNonce",
+	CodePositionModeMismatch: "PositionModeMismatch",
+	CodeMarketClosed:         "MarketClosed",
+	CodeDataTruncated:        "DataTruncated",
 }
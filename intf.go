@@ -1,6 +1,7 @@
 package banexg
 
 import (
+	"context"
 	"io"
 
 	"github.com/banbox/banexg/errs"
@@ -13,6 +14,10 @@ Exchange interface
 
 type BanExchange interface {
 	LoadMarkets(reload bool, params map[string]interface{}) (MarketMap, *errs.Error)
+	// FetchTime Get 13 digit timestamp of exchange server
+	FetchTime(params map[string]interface{}) (int64, *errs.Error)
+	// SyncTime Update TimeDelay by server time, signed requests use the synced Nonce
+	SyncTime(params map[string]interface{}) *errs.Error
 	GetCurMarkets() MarketMap
 	GetMarket(symbol string) (*Market, *errs.Error)
 	/*
@@ -30,11 +35,23 @@ type BanExchange interface {
 	Info() *ExgInfo
 
 	FetchOHLCV(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*Kline, *errs.Error)
+	// FetchOHLCVRange fetch klines of [since, until) page by page, or ParamLimit bars backward from until if since is omitted.
+	// de-duplicated and sorted by time
+	FetchOHLCVRange(symbol, timeframe string, since, until int64, params map[string]interface{}) ([]*Kline, *errs.Error)
 	FetchOrderBook(symbol string, limit int, params map[string]interface{}) (*OrderBook, *errs.Error)
+	// FetchTrades Get public trades, support ParamUntil and cursor paging with ParamAfter/ParamBefore
+	FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*Trade, *errs.Error)
 	FetchLastPrices(symbols []string, params map[string]interface{}) ([]*LastPrice, *errs.Error)
 	FetchFundingRate(symbol string, params map[string]interface{}) (*FundingRateCur, *errs.Error)
 	FetchFundingRates(symbols []string, params map[string]interface{}) ([]*FundingRateCur, *errs.Error)
 	FetchFundingRateHistory(symbol string, since int64, limit int, params map[string]interface{}) ([]*FundingRate, *errs.Error)
+	FetchOpenInterest(symbol string, params map[string]interface{}) (*OpenInterest, *errs.Error)
+	FetchOpenInterestHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*OpenInterest, *errs.Error)
+	// FetchLongShortRatioHistory accounts ratio by default, set ParamRatioType to RatioTaker for taker buy/sell volume
+	FetchLongShortRatioHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*LongShortRatio, *errs.Error)
+	// FetchOptionChain list options of underlying (base code), filter by expiry day when expiry > 0
+	FetchOptionChain(underlying string, expiry int64, params map[string]interface{}) ([]*OptionChainItem, *errs.Error)
+	FetchGreeks(symbols []string, params map[string]interface{}) ([]*Greeks, *errs.Error)
 
 	// FetchOrder query given order
 	FetchOrder(symbol, id string, params map[string]interface{}) (*Order, *errs.Error)
@@ -49,14 +66,55 @@ type BanExchange interface {
 	// FetchOpenOrders Get all open orders on a symbol or all symbol.
 	FetchOpenOrders(symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error)
 	FetchIncomeHistory(inType string, symbol string, since int64, limit int, params map[string]interface{}) ([]*Income, *errs.Error)
+	// FetchMyTrades Get fills of account, page with ParamAfter/ParamBefore
+	FetchMyTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*MyTrade, *errs.Error)
+	// Transfer Move funds between account types (AccountFunding or market type), or sub accounts by ParamFromSubAcc/ParamToSubAcc
+	Transfer(code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*Transfer, *errs.Error)
+	// FetchTransfers Get transfer history, code can be empty for all currencies
+	FetchTransfers(code string, since int64, limit int, params map[string]interface{}) ([]*Transfer, *errs.Error)
+	// FetchDepositAddress Get deposit address of currency, network can be set by ParamNetwork
+	FetchDepositAddress(code string, params map[string]interface{}) (*DepositAddress, *errs.Error)
+	FetchDeposits(code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error)
+	FetchWithdrawals(code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error)
+	// Withdraw Withdraw currency to address on network, network is checked with Currency.Networks if loaded
+	Withdraw(code string, amount float64, address, tag, network string, params map[string]interface{}) (*Transaction, *errs.Error)
+	// Borrow Borrow on margin account, isolated margin if symbol is given, can be overridden by ParamMarginMode
+	Borrow(code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error)
+	// Repay Repay margin loan, symbol is the same as Borrow
+	Repay(code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error)
+	// FetchBorrowRate Get current borrow rate of margin loan
+	FetchBorrowRate(code string, params map[string]interface{}) (*BorrowRate, *errs.Error)
+	FetchBorrowRates(codes []string, params map[string]interface{}) ([]*BorrowRate, *errs.Error)
+	// FetchBorrowInterest Get accrued interest history of margin loans, symbol is for isolated margin
+	FetchBorrowInterest(code, symbol string, since int64, limit int, params map[string]interface{}) ([]*BorrowInterest, *errs.Error)
 
 	CreateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error)
 	EditOrder(symbol, orderId, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error)
 	CancelOrder(id string, symbol string, params map[string]interface{}) (*Order, *errs.Error)
+	// CreateOrders Create orders in batch, results have the same order as orders; emulated by CreateOrder if not supported
+	CreateOrders(orders []*OrderArgs, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	// CancelOrders Cancel orders of symbol in batch, results have the same order as ids
+	CancelOrders(ids []string, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	// CancelAllOrders Cancel all open orders of symbol, or all symbols if empty (when supported)
+	CancelAllOrders(symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	// ValidateOrder Check an order against the rules of market before sending it, see Exchange.ValidateOrder
+	ValidateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*OrderCheck, *errs.Error)
+	// SetCancelAllAfter Arm the exchange side timer which cancels all open orders after timeoutMs, 0 to disarm
+	SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	// KeepCancelAllAfter Arm SetCancelAllAfter and refresh it in background until StopCancelAllAfter or Close
+	KeepCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) *errs.Error
+	StopCancelAllAfter(symbol string, disarm bool, params map[string]interface{}) *errs.Error
 
 	SetFees(fees map[string]map[string]float64)
 	CalculateFee(symbol, odType, side string, amount float64, price float64, isMaker bool, params map[string]interface{}) (*Fee, *errs.Error)
 	SetLeverage(leverage float64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	// SetMarginMode Switch to MarginCross or MarginIsolated for symbol, or for the account on exchanges without symbol level mode
+	SetMarginMode(mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	// SetPositionMode Switch between hedge (long/short) and one-way position mode of contracts
+	SetPositionMode(hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	// AddMargin Add margin to isolated position, set ParamPositionSide in hedge mode
+	AddMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	ReduceMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error)
 	CalcMaintMargin(symbol string, cost float64) (float64, *errs.Error)
 	Call(method string, params map[string]interface{}) (*HttpRes, *errs.Error)
 
@@ -68,7 +126,16 @@ type BanExchange interface {
 	UnWatchMarkPrices(symbols []string, params map[string]interface{}) *errs.Error
 	WatchTrades(symbols []string, params map[string]interface{}) (chan *Trade, *errs.Error)
 	UnWatchTrades(symbols []string, params map[string]interface{}) *errs.Error
+	WatchTickers(symbols []string, params map[string]interface{}) (chan *Ticker, *errs.Error)
+	UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error
+	// WatchLiquidations Watch forced liquidation orders of symbols 订阅币种的强平订单
+	WatchLiquidations(symbols []string, params map[string]interface{}) (chan *Liquidation, *errs.Error)
+	UnWatchLiquidations(symbols []string, params map[string]interface{}) *errs.Error
+	// WatchGreeks Watch greeks and implied volatility of options 订阅期权的希腊值和隐含波动率
+	WatchGreeks(symbols []string, params map[string]interface{}) (chan *Greeks, *errs.Error)
+	UnWatchGreeks(symbols []string, params map[string]interface{}) *errs.Error
 	WatchMyTrades(params map[string]interface{}) (chan *MyTrade, *errs.Error)
+	WatchOrders(params map[string]interface{}) (chan *Order, *errs.Error)
 	WatchBalance(params map[string]interface{}) (chan *Balances, *errs.Error)
 	WatchPositions(params map[string]interface{}) (chan []*Position, *errs.Error)
 	WatchAccountConfig(params map[string]interface{}) (chan *AccountConfig, *errs.Error)
@@ -85,6 +152,8 @@ type BanExchange interface {
 	ReplayAll() *errs.Error
 	// SetOnWsChan Trigger callback when creating a new websocket message chan 创建新websocket消息chan时触发回调
 	SetOnWsChan(cb FuncOnWsChan)
+	// SetOnOdBookReset Trigger callback when a ws order book failed the integrity check and was reset 订单簿校验失败被重置时触发回调
+	SetOnOdBookReset(cb FuncOnOdBookReset)
 
 	PrecAmount(m *Market, amount float64) (float64, *errs.Error)
 	PrecPrice(m *Market, price float64) (float64, *errs.Error)
@@ -103,6 +172,72 @@ type BanExchange interface {
 	Close() *errs.Error
 	GetNetDisable() bool
 	SetNetDisable(v bool)
+
+	BanExchangeCtx
+}
+
+/*
+BanExchangeCtx
+Context-first variants of the REST methods, cancellations and deadlines of ctx propagate into http requests,
+retry sleeps and rate limit waits.
+REST方法的context版本，ctx的取消和超时会传递到http请求、重试等待和限流等待
+*/
+type BanExchangeCtx interface {
+	LoadMarketsCtx(ctx context.Context, reload bool, params map[string]interface{}) (MarketMap, *errs.Error)
+	FetchTimeCtx(ctx context.Context, params map[string]interface{}) (int64, *errs.Error)
+	FetchTickerCtx(ctx context.Context, symbol string, params map[string]interface{}) (*Ticker, *errs.Error)
+	FetchTickersCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Ticker, *errs.Error)
+	FetchTickerPriceCtx(ctx context.Context, symbol string, params map[string]interface{}) (map[string]float64, *errs.Error)
+	LoadLeverageBracketsCtx(ctx context.Context, reload bool, params map[string]interface{}) *errs.Error
+
+	FetchOHLCVCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*Kline, *errs.Error)
+	FetchOHLCVRangeCtx(ctx context.Context, symbol, timeframe string, since, until int64, params map[string]interface{}) ([]*Kline, *errs.Error)
+	FetchOrderBookCtx(ctx context.Context, symbol string, limit int, params map[string]interface{}) (*OrderBook, *errs.Error)
+	FetchTradesCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Trade, *errs.Error)
+	FetchLastPricesCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*LastPrice, *errs.Error)
+	FetchFundingRateCtx(ctx context.Context, symbol string, params map[string]interface{}) (*FundingRateCur, *errs.Error)
+	FetchFundingRatesCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*FundingRateCur, *errs.Error)
+	FetchFundingRateHistoryCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*FundingRate, *errs.Error)
+	FetchOpenInterestCtx(ctx context.Context, symbol string, params map[string]interface{}) (*OpenInterest, *errs.Error)
+	FetchOpenInterestHistoryCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*OpenInterest, *errs.Error)
+	FetchLongShortRatioHistoryCtx(ctx context.Context, symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*LongShortRatio, *errs.Error)
+	FetchOptionChainCtx(ctx context.Context, underlying string, expiry int64, params map[string]interface{}) ([]*OptionChainItem, *errs.Error)
+	FetchGreeksCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Greeks, *errs.Error)
+
+	FetchOrderCtx(ctx context.Context, symbol, id string, params map[string]interface{}) (*Order, *errs.Error)
+	FetchOrdersCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error)
+	FetchBalanceCtx(ctx context.Context, params map[string]interface{}) (*Balances, *errs.Error)
+	FetchAccountAccessCtx(ctx context.Context, params map[string]interface{}) (*AccountAccess, *errs.Error)
+	FetchAccountPositionsCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Position, *errs.Error)
+	FetchPositionsCtx(ctx context.Context, symbols []string, params map[string]interface{}) ([]*Position, *errs.Error)
+	FetchOpenOrdersCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*Order, *errs.Error)
+	FetchIncomeHistoryCtx(ctx context.Context, inType string, symbol string, since int64, limit int, params map[string]interface{}) ([]*Income, *errs.Error)
+	FetchMyTradesCtx(ctx context.Context, symbol string, since int64, limit int, params map[string]interface{}) ([]*MyTrade, *errs.Error)
+	TransferCtx(ctx context.Context, code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*Transfer, *errs.Error)
+	FetchTransfersCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transfer, *errs.Error)
+	FetchDepositAddressCtx(ctx context.Context, code string, params map[string]interface{}) (*DepositAddress, *errs.Error)
+	FetchDepositsCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error)
+	FetchWithdrawalsCtx(ctx context.Context, code string, since int64, limit int, params map[string]interface{}) ([]*Transaction, *errs.Error)
+	WithdrawCtx(ctx context.Context, code string, amount float64, address, tag, network string, params map[string]interface{}) (*Transaction, *errs.Error)
+	BorrowCtx(ctx context.Context, code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error)
+	RepayCtx(ctx context.Context, code string, amount float64, symbol string, params map[string]interface{}) (*MarginLoan, *errs.Error)
+	FetchBorrowRateCtx(ctx context.Context, code string, params map[string]interface{}) (*BorrowRate, *errs.Error)
+	FetchBorrowRatesCtx(ctx context.Context, codes []string, params map[string]interface{}) ([]*BorrowRate, *errs.Error)
+	FetchBorrowInterestCtx(ctx context.Context, code, symbol string, since int64, limit int, params map[string]interface{}) ([]*BorrowInterest, *errs.Error)
+
+	CreateOrderCtx(ctx context.Context, symbol, odType, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error)
+	EditOrderCtx(ctx context.Context, symbol, orderId, side string, amount, price float64, params map[string]interface{}) (*Order, *errs.Error)
+	CancelOrderCtx(ctx context.Context, id string, symbol string, params map[string]interface{}) (*Order, *errs.Error)
+	CreateOrdersCtx(ctx context.Context, orders []*OrderArgs, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	CancelOrdersCtx(ctx context.Context, ids []string, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	CancelAllOrdersCtx(ctx context.Context, symbol string, params map[string]interface{}) ([]*OrderRes, *errs.Error)
+	SetCancelAllAfterCtx(ctx context.Context, timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	SetLeverageCtx(ctx context.Context, leverage float64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	SetMarginModeCtx(ctx context.Context, mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	SetPositionModeCtx(ctx context.Context, hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	AddMarginCtx(ctx context.Context, symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	ReduceMarginCtx(ctx context.Context, symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error)
+	CallCtx(ctx context.Context, method string, params map[string]interface{}) (*HttpRes, *errs.Error)
 }
 
 type WsConn interface {
@@ -0,0 +1,126 @@
+package banexg
+
+import (
+	"sort"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+// default bars of one page when ExgInfo.OHLCVLimit is not set
+const defOHLCVPageLimit = 500
+
+/*
+FetchOHLCVRange
+Fetch klines of [since, until) by calling FetchOHLCV page by page from since, each page requests a window of
+ExgInfo.OHLCVLimit bars with ParamUntil. Empty windows (holes or market closed) are skipped, bars on page
+boundaries are de-duplicated, the result is sorted by time asc. until defaults to now when <= 0.
+When since is omitted, pages go backward from until with ParamUntil only, until ParamLimit (required) bars are
+fetched or there are no earlier bars.
+Every page is a normal api request, so the rate limiter and ctx in params are respected.
+按页调用FetchOHLCV获取[since, until)的K线，每页通过ParamUntil请求ExgInfo.OHLCVLimit根K线的时间窗口。
+空窗口(空洞或休市)会被跳过，页边界重复的K线会去重，结果按时间升序。until<=0时默认为当前时间。
+未指定since时，仅使用ParamUntil从until向前翻页，直到获取ParamLimit(必填)根K线或没有更早的K线。
+每页都是普通的接口请求，会遵守限流器和params中的ctx
+*/
+func (e *Exchange) FetchOHLCVRange(symbol, timeframe string, since, until int64, params map[string]interface{}) ([]*Kline, *errs.Error) {
+	tfSecs, err_ := utils.TFToSecSafe(timeframe)
+	if err_ != nil || tfSecs <= 0 {
+		return nil, errs.NewMsg(errs.CodeInvalidTimeFrame, "invalid timeframe: %s", timeframe)
+	}
+	if since <= 0 {
+		return e.fetchOHLCVBackward(symbol, timeframe, until, params)
+	}
+	if until <= 0 {
+		until = e.MilliSeconds()
+	}
+	exg := e.self()
+	ctx := ParamsCtx(params)
+	pageLimit := e.ohlcvPageLimit()
+	tfMSecs := int64(tfSecs) * 1000
+	pageMSecs := tfMSecs * int64(pageLimit)
+	barMap := make(map[int64]*Kline)
+	for start := since; start < until; start += pageMSecs {
+		if err := CtxError(ctx); err != nil {
+			return nil, err
+		}
+		end := min(start+pageMSecs, until)
+		args := utils.SafeParams(params)
+		args[ParamUntil] = end - 1
+		bars, err := exg.FetchOHLCV(symbol, timeframe, start, pageLimit, args)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bars {
+			if b.Time < since || b.Time >= until {
+				continue
+			}
+			barMap[b.Time] = b
+		}
+	}
+	return sortedBars(barMap), nil
+}
+
+// fetchOHLCVBackward fetch the latest ParamLimit bars before until, page by page backward
+func (e *Exchange) fetchOHLCVBackward(symbol, timeframe string, until int64, params map[string]interface{}) ([]*Kline, *errs.Error) {
+	if until <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "since or until is required for FetchOHLCVRange")
+	}
+	args0 := utils.SafeParams(params)
+	total := utils.PopMapVal(args0, ParamLimit, 0)
+	if total <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "ParamLimit is required for FetchOHLCVRange without since")
+	}
+	exg := e.self()
+	ctx := ParamsCtx(params)
+	pageLimit := e.ohlcvPageLimit()
+	barMap := make(map[int64]*Kline)
+	end := until
+	for len(barMap) < total {
+		if err := CtxError(ctx); err != nil {
+			return nil, err
+		}
+		args := utils.SafeParams(args0)
+		args[ParamUntil] = end - 1
+		bars, err := exg.FetchOHLCV(symbol, timeframe, 0, min(pageLimit, total-len(barMap)), args)
+		if err != nil {
+			return nil, err
+		}
+		earliest := end
+		for _, b := range bars {
+			if b.Time >= end {
+				continue
+			}
+			barMap[b.Time] = b
+			earliest = min(earliest, b.Time)
+		}
+		if earliest >= end {
+			// no earlier bars
+			break
+		}
+		end = earliest
+	}
+	result := sortedBars(barMap)
+	if len(result) > total {
+		result = result[len(result)-total:]
+	}
+	return result, nil
+}
+
+func (e *Exchange) ohlcvPageLimit() int {
+	if e.OHLCVLimit > 0 {
+		return e.OHLCVLimit
+	}
+	return defOHLCVPageLimit
+}
+
+func sortedBars(barMap map[int64]*Kline) []*Kline {
+	result := make([]*Kline, 0, len(barMap))
+	for _, b := range barMap {
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time < result[j].Time
+	})
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,7 +30,7 @@ func (e *OKX) Init() *errs.Error {
 func markRiskyApis(e *OKX) {
 	riskyPaths := []string{
 		"order", "cancel", "amend", "leverage", "margin",
-		"position", "transfer", "withdraw", "loan", "repay",
+		"position", "isolated", "transfer", "withdraw", "loan", "repay",
 	}
 	for _, api := range e.Apis {
 		if api.Method == "GET" {
@@ -48,6 +49,8 @@ func makeSign(e *OKX) banexg.FuncSign {
 	return func(api *banexg.Entry, args map[string]interface{}) *banexg.HttpReq {
 		params := utils.SafeParams(args)
 		accID := e.PopAccName(params)
+		// only used by websocket trading api
+		delete(params, banexg.ParamWsTrade)
 		if err := e.CheckRiskyAllowed(api, accID); err != nil {
 			return &banexg.HttpReq{Error: err, Private: true}
 		}
@@ -66,14 +69,16 @@ func makeSign(e *OKX) banexg.FuncSign {
 				return &banexg.HttpReq{Error: err, Private: true}
 			}
 			passphrase := creds.Password
-			timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+			timestamp := time.UnixMilli(e.Nonce()).UTC().Format("2006-01-02T15:04:05.000Z")
 			requestPath := api.Path
 			if api.Method == "GET" && len(params) > 0 {
 				queryStr := utils.UrlEncodeMap(params, true)
 				url += "?" + queryStr
 				requestPath += "?" + queryStr
 			} else if api.Method == "POST" && len(params) > 0 {
-				if api.Path == "trade/cancel-algos" {
+				if items, ok := params[ArgBatchItems]; ok {
+					body, _ = utils.MarshalString(items)
+				} else if api.Path == "trade/cancel-algos" {
 					body, _ = utils.MarshalString([]map[string]interface{}{params})
 				} else {
 					body, _ = utils.MarshalString(params)
@@ -101,6 +106,15 @@ func requestRetry[T any](e *OKX, api string, params map[string]interface{}, tryN
 	if res.Error != nil {
 		return res
 	}
+	decodeApiRes(res)
+	if res.Error == nil {
+		e.CacheApiRes(api, res_)
+	}
+	return res
+}
+
+// decodeApiRes parse {code, msg, data} in res.Content, which is the same for rest and websocket trading api
+func decodeApiRes[T any](res *banexg.ApiRes[T]) {
 	var rsp = struct {
 		Code string `json:"code"`
 		Msg  string `json:"msg"`
@@ -109,24 +123,47 @@ func requestRetry[T any](e *OKX, api string, params map[string]interface{}, tryN
 	err := utils.UnmarshalString(res.Content, &rsp, utils.JsonNumDefault)
 	if err != nil {
 		res.Error = errs.New(errs.CodeUnmarshalFail, err)
-		return res
+		return
 	}
 	if rsp.Code != "0" {
 		// Extract detailed error from data[0].sCode/sMsg if available
-		errMsg := rsp.Msg
-		if detail := extractDetailError(res.Content); detail != "" {
-			errMsg = detail
+		code, msg := rsp.Code, rsp.Msg
+		if sCode, sMsg := extractDetailError(res.Content); sMsg != "" {
+			code, msg = sCode, sMsg
 		}
-		res.Error = errs.NewMsg(errs.CodeRunTime, "[%s] %s", rsp.Code, errMsg)
+		res.Error = newBizErr(code, msg)
 	} else {
 		res.Result = rsp.Data
-		e.CacheApiRes(api, res_)
 	}
-	return res
+}
+
+/*
+FetchTime
+get server time of OKX
+获取OKX服务器时间
+:see: https://www.okx.com/docs-v5/en/#public-data-rest-api-get-system-time
+*/
+func (e *OKX) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	args := utils.SafeParams(params)
+	tryNum := e.GetRetryNum("FetchTime", 1)
+	res := requestRetry[[]struct {
+		Ts string `json:"ts"`
+	}](e, MethodPublicGetTime, args, tryNum)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if len(res.Result) == 0 {
+		return 0, errs.NewMsg(errs.CodeDataNotFound, "empty server time result")
+	}
+	stamp, err := strconv.ParseInt(res.Result[0].Ts, 10, 64)
+	if err != nil {
+		return 0, errs.New(errs.CodeInvalidResponse, err)
+	}
+	return stamp, nil
 }
 
 // extractDetailError extracts detailed error from OKX response's data[0].sCode/sMsg
-func extractDetailError(content string) string {
+func extractDetailError(content string) (string, string) {
 	var resp struct {
 		Data []struct {
 			SCode string `json:"sCode"`
@@ -135,10 +172,58 @@ func extractDetailError(content string) string {
 	}
 	if utils.UnmarshalString(content, &resp, utils.JsonNumDefault) == nil {
 		if len(resp.Data) > 0 && resp.Data[0].SMsg != "" {
-			return "[" + resp.Data[0].SCode + "] " + resp.Data[0].SMsg
+			return resp.Data[0].SCode, resp.Data[0].SMsg
+		}
+	}
+	return "", ""
+}
+
+// newBizErr create error from OKX code/sCode, with BizCode set and Code normalized
+func newBizErr(code, msg string) *errs.Error {
+	bizCode, _ := strconv.Atoi(code)
+	errCode := mapBizErr(bizCode, msg)
+	if errCode == 0 {
+		errCode = errs.CodeRunTime
+	}
+	err := errs.NewMsg(errCode, "[%s] %s", code, msg)
+	err.BizCode = bizCode
+	return err
+}
+
+/*
+mapBizErr
+map OKX code/sCode to normalized errs code
+将OKX的code/sCode映射为标准错误码
+https://www.okx.com/docs-v5/en/#error-code
+*/
+func mapBizErr(bizCode int, msg string) int {
+	switch bizCode {
+	case 50102, 50112:
+		// Timestamp request expired. / Invalid OK-ACCESS-TIMESTAMP
+		return errs.CodeInvalidNonce
+	case 50111, 50119:
+		return errs.CodeAccKeyError
+	case 50113:
+		return errs.CodeSignFail
+	case 51008, 51119, 51127, 51131:
+		// Order failed. Insufficient balance.
+		return errs.CodeInsufficientBalance
+	case 51400, 51401, 51402, 51603:
+		// Order does not exist, or already canceled/filled
+		return errs.CodeOrderNotFound
+	case 51169, 51170:
+		// no positions in this direction to reduce / reduce-only order would increase position
+		return errs.CodeReduceOnlyRejected
+	case 51000:
+		if strings.Contains(msg, "posSide") {
+			// Parameter posSide error
+			return errs.CodePositionModeMismatch
 		}
+	case 51028, 51029, 51030:
+		// Contract under delivery / being settled
+		return errs.CodeMarketClosed
 	}
-	return ""
+	return 0
 }
 
 func makeFetchMarkets(e *OKX) banexg.FuncFetchMarkets {
@@ -225,8 +310,7 @@ func parseInstrument(e *OKX, inst *Instrument) *banexg.Market {
 	symbol := inst.InstId
 	if e != nil {
 		if mktType == banexg.MarketOption {
-			base := e.SafeCurrencyCode(inst.BaseCcy)
-			quote := e.SafeCurrencyCode(inst.QuoteCcy)
+			base, quote := instBaseQuote(e, inst)
 			if base != "" {
 				symbol = base
 				if quote != "" {
@@ -254,26 +338,7 @@ func parseInstrument(e *OKX, inst *Instrument) *banexg.Market {
 				symbol = symbol + "-" + parts[4]
 			}
 		} else if mktType == banexg.MarketLinear || mktType == banexg.MarketInverse {
-			base := e.SafeCurrencyCode(inst.BaseCcy)
-			quote := e.SafeCurrencyCode(inst.QuoteCcy)
-			// For derivatives, OKX may not set baseCcy/quoteCcy; parse from instFamily or uly instead
-			if base == "" || quote == "" {
-				familyOrUly := inst.InstFamily
-				if familyOrUly == "" {
-					familyOrUly = inst.Uly
-				}
-				if familyOrUly != "" {
-					parts := strings.Split(familyOrUly, "-")
-					if len(parts) >= 2 {
-						if base == "" {
-							base = e.SafeCurrencyCode(parts[0])
-						}
-						if quote == "" {
-							quote = e.SafeCurrencyCode(parts[1])
-						}
-					}
-				}
-			}
+			base, quote := instBaseQuote(e, inst)
 			if base != "" {
 				symbol = base
 				if quote != "" {
@@ -318,7 +383,7 @@ func parseInstrument(e *OKX, inst *Instrument) *banexg.Market {
 			"tradeQuoteCcyList": ccyMap,
 		}
 	}
-	return &banexg.Market{
+	market := &banexg.Market{
 		ID:           inst.InstId,
 		Symbol:       symbol,
 		Base:         inst.BaseCcy,
@@ -351,4 +416,41 @@ func parseInstrument(e *OKX, inst *Instrument) *banexg.Market {
 		Created: created,
 		Info:    info,
 	}
+	if isOption {
+		if e != nil {
+			market.Base, market.Quote = instBaseQuote(e, inst)
+		}
+		market.Strike = parseFloat(inst.Stk)
+		market.Expiry = parseInt(inst.ExpTime)
+		if inst.OptType == "C" {
+			market.OptionType = "call"
+		} else if inst.OptType == "P" {
+			market.OptionType = "put"
+		}
+	}
+	return market
+}
+
+// instBaseQuote OKX may not set baseCcy/quoteCcy for derivatives; parse from instFamily or uly instead
+func instBaseQuote(e *OKX, inst *Instrument) (string, string) {
+	base := e.SafeCurrencyCode(inst.BaseCcy)
+	quote := e.SafeCurrencyCode(inst.QuoteCcy)
+	if base == "" || quote == "" {
+		familyOrUly := inst.InstFamily
+		if familyOrUly == "" {
+			familyOrUly = inst.Uly
+		}
+		if familyOrUly != "" {
+			parts := strings.Split(familyOrUly, "-")
+			if len(parts) >= 2 {
+				if base == "" {
+					base = e.SafeCurrencyCode(parts[0])
+				}
+				if quote == "" {
+					quote = e.SafeCurrencyCode(parts[1])
+				}
+			}
+		}
+	}
+	return base, quote
 }
@@ -1,6 +1,7 @@
 package okx
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/banbox/banexg"
@@ -145,3 +146,41 @@ func TestShouldContinueFundRateHistory(t *testing.T) {
 		t.Fatalf("expected stop when no progress, got %v %v", next, ok)
 	}
 }
+
+func TestParseLongShortRows(t *testing.T) {
+	rows := [][]string{{"1700000300000", "20", "30"}, {"1700000000000", "10", "5"}}
+	res := parseLongShortRows(rows, "BTC/USDT:USDT", "5m", banexg.RatioTaker)
+	if len(res) != 2 || res[0].BuyVolume != 30 || res[0].SellVolume != 20 ||
+		res[0].LongRatio != 0.6 || res[0].ShortRatio != 0.4 || res[0].Ratio != 1.5 {
+		t.Fatalf("unexpected taker ratio: %+v", res[0])
+	}
+	res = parseLongShortRows([][]string{{"1700000000000", "1.25"}}, "BTC/USDT:USDT", "5m", banexg.RatioAccount)
+	if len(res) != 1 || res[0].Ratio != 1.25 || res[0].Timestamp != 1700000000000 {
+		t.Fatalf("unexpected account ratio: %+v", res)
+	}
+}
+
+func TestTransferBillTypesOf(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     string
+	}{
+		{banexg.MarketLinear, banexg.AccountFunding, "130"},
+		{banexg.AccountFunding, banexg.MarketSpot, "131"},
+		{banexg.AccountFunding, banexg.AccountFunding, "20,21,22,23"},
+		{"", banexg.MarketSpot, "131"},
+		{"", "", "130,131,20,21,22,23"},
+	}
+	for _, c := range cases {
+		types, err := transferBillTypesOf(c.from, c.to)
+		if err != nil {
+			t.Fatalf("%s -> %s: %v", c.from, c.to, err)
+		}
+		if got := strings.Join(types, ","); got != c.want {
+			t.Fatalf("%s -> %s: expect %s, got %s", c.from, c.to, c.want, got)
+		}
+	}
+	if _, err := transferBillTypesOf("bad", ""); err == nil {
+		t.Fatalf("invalid account should fail")
+	}
+}
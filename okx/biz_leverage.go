@@ -36,7 +36,10 @@ func (e *OKX) SetLeverage(leverage float64, symbol string, params map[string]int
 		}
 		args[FldCcy] = ccy
 	}
-	mgnMode := utils.PopMapVal(args, banexg.ParamMarginMode, banexg.MarginCross)
+	if market != nil {
+		symbol = market.Symbol
+	}
+	mgnMode := utils.PopMapVal(args, banexg.ParamMarginMode, e.defMarginMode(symbol, args))
 	args[FldMgnMode] = mgnMode
 	args[FldLever] = strconv.FormatFloat(leverage, 'f', -1, 64)
 
@@ -58,6 +61,154 @@ func (e *OKX) SetLeverage(leverage float64, symbol string, params map[string]int
 	return res.Result[0], nil
 }
 
+// marginModeKey key of Account.Data for margin mode set by SetMarginMode, empty symbol for all symbols
+func marginModeKey(symbol string) string {
+	return "marginMode:" + symbol
+}
+
+/*
+defMarginMode
+okx sets margin mode on each order by tdMode. default is the one set by SetMarginMode for symbol of the account in
+params, then the one set for all symbols, then Exchange.MarginMode, cross at last.
+okx由每个订单的tdMode指定保证金模式。默认依次为：params中账户对symbol设置的模式、对所有品种设置的模式、Exchange.MarginMode、全仓
+*/
+func (e *OKX) defMarginMode(symbol string, params map[string]interface{}) string {
+	if acc, err := e.GetAccount(e.GetAccName(params)); err == nil && acc.LockData != nil {
+		acc.LockData.Lock()
+		mode, _ := acc.Data[marginModeKey(symbol)].(string)
+		if mode == "" {
+			mode, _ = acc.Data[marginModeKey("")].(string)
+		}
+		acc.LockData.Unlock()
+		if mode != "" {
+			return mode
+		}
+	}
+	if e.MarginMode != "" {
+		return e.MarginMode
+	}
+	return banexg.MarginCross
+}
+
+/*
+SetMarginMode
+okx has no margin mode setting of symbol, it's given by tdMode of each order. This changes the default tdMode of
+later orders of symbol (all symbols if empty) for the account in params, and sets isoMode (automatic by default)
+of the market type of symbol for isolated mode.
+okx没有品种级别的保证金模式，由每个订单的tdMode指定。此方法修改params中账户后续该品种(为空时所有品种)订单默认的tdMode，
+逐仓时还会设置symbol所属市场的isoMode(默认automatic)
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-isolated-margin-trading-settings
+*/
+func (e *OKX) SetMarginMode(mode, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if mode != banexg.MarginCross && mode != banexg.MarginIsolated {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid margin mode: %s", mode)
+	}
+	acc, err := e.GetAccount(e.GetAccName(params))
+	if err != nil {
+		return nil, err
+	}
+	var market *banexg.Market
+	if symbol != "" {
+		market, err = e.GetMarket(symbol)
+		if err != nil {
+			return nil, err
+		}
+		symbol = market.Symbol
+	}
+	result := map[string]interface{}{}
+	if mode == banexg.MarginIsolated {
+		args := utils.SafeParams(params)
+		isoType := "CONTRACTS"
+		if market != nil && !market.Contract {
+			isoType = "MARGIN"
+		}
+		args[FldType] = isoType
+		args["isoMode"] = utils.GetMapVal(args, "isoMode", "automatic")
+		tryNum := e.GetRetryNum("SetMarginMode", 1)
+		res := requestRetry[[]map[string]interface{}](e, MethodAccountSetIsolatedMode, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if len(res.Result) > 0 {
+			result = res.Result[0]
+		}
+	}
+	acc.LockData.Lock()
+	if acc.Data == nil {
+		acc.Data = map[string]interface{}{}
+	}
+	if symbol == "" {
+		for key := range acc.Data {
+			if strings.HasPrefix(key, marginModeKey("")) {
+				delete(acc.Data, key)
+			}
+		}
+	}
+	acc.Data[marginModeKey(symbol)] = mode
+	acc.LockData.Unlock()
+	return result, nil
+}
+
+/*
+SetPositionMode
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-set-position-mode
+*/
+func (e *OKX) SetPositionMode(hedged bool, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	args := utils.SafeParams(params)
+	args["posMode"] = "net_mode"
+	if hedged {
+		args["posMode"] = "long_short_mode"
+	}
+	tryNum := e.GetRetryNum("SetPositionMode", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodAccountSetPositionMode, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return res.Result[0], nil
+}
+
+/*
+AddMargin
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-increase-decrease-margin
+*/
+func (e *OKX) AddMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, amount, "add", params)
+}
+
+/*
+ReduceMargin
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-increase-decrease-margin
+*/
+func (e *OKX) ReduceMargin(symbol string, amount float64, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	return e.modifyMargin(symbol, amount, "reduce", params)
+}
+
+func (e *OKX) modifyMargin(symbol string, amount float64, modType string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	if amount <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid margin amount: %v", amount)
+	}
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args[FldInstId] = market.ID
+	args[FldPosSide] = strings.ToLower(utils.PopMapVal(args, banexg.ParamPositionSide, "net"))
+	args[FldType] = modType
+	args["amt"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	// not idempotent, never retry
+	res := requestRetry[[]map[string]interface{}](e, MethodAccountPostMarginBalance, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty margin balance result")
+	}
+	return res.Result[0], nil
+}
+
 func (e *OKX) LoadLeverageBrackets(reload bool, params map[string]interface{}) *errs.Error {
 	// okx 不支持批量加载所有品种杠杆档位，只能单个加载；故改为在GetLeverage中加载并缓存
 	return nil
@@ -65,7 +216,11 @@ func (e *OKX) LoadLeverageBrackets(reload bool, params map[string]interface{}) *
 
 func (e *OKX) GetLeverage(symbol string, notional float64, account string) (float64, float64) {
 	e.LoadMarkets(false, nil)
-	info := e.findOrLoadLvgBracket(symbol)
+	accArgs := map[string]interface{}{}
+	if account != "" {
+		accArgs[banexg.ParamAccount] = account
+	}
+	info := e.findOrLoadLvgBracket(symbol, accArgs)
 	maxVal := 0.0
 	if info != nil && len(info.Brackets) > 0 {
 		for _, row := range info.Brackets {
@@ -90,7 +245,7 @@ func (e *OKX) GetLeverage(symbol string, notional float64, account string) (floa
 		acc.LockLeverage.Unlock()
 	}
 	if market, err := e.GetMarket(symbol); err == nil && market != nil {
-		if lev, err := e.fetchCurrentLeverage(market); err == nil && lev > 0 {
+		if lev, err := e.fetchCurrentLeverage(market, accArgs); err == nil && lev > 0 {
 			curLev = lev
 			if acc, ok := e.Accounts[account]; ok && acc != nil && acc.LockLeverage != nil {
 				acc.LockLeverage.Lock()
@@ -104,7 +259,7 @@ func (e *OKX) GetLeverage(symbol string, notional float64, account string) (floa
 
 // findOrLoadLvgBracket finds the leverage bracket for a symbol in cache,
 // or fetches it from the API and caches it if not found.
-func (e *OKX) findOrLoadLvgBracket(symbol string) *banexg.SymbolLvgBrackets {
+func (e *OKX) findOrLoadLvgBracket(symbol string, params map[string]interface{}) *banexg.SymbolLvgBrackets {
 	// First try to find in cache
 	info := findLeverageBracket(e, symbol)
 	if info != nil {
@@ -119,10 +274,9 @@ func (e *OKX) findOrLoadLvgBracket(symbol string) *banexg.SymbolLvgBrackets {
 	if err != nil {
 		return nil
 	}
-	args := map[string]interface{}{
-		FldInstType: instType,
-		FldTdMode:   banexg.MarginCross,
-	}
+	args := utils.SafeParams(params)
+	args[FldInstType] = instType
+	args[FldTdMode] = banexg.MarginCross
 	// For MARGIN, use instId; for derivatives, use instFamily
 	if instType == InstTypeMargin {
 		args[FldInstId] = market.ID
@@ -167,7 +321,7 @@ func marketToInstTypeForLeverage(market *banexg.Market) (string, *errs.Error) {
 }
 
 func (e *OKX) CalcMaintMargin(symbol string, cost float64) (float64, *errs.Error) {
-	info := e.findOrLoadLvgBracket(symbol)
+	info := e.findOrLoadLvgBracket(symbol, nil)
 	if info == nil || len(info.Brackets) == 0 {
 		return 0, errs.NewMsg(errs.CodeDataNotFound, "leverage bracket not found")
 	}
@@ -203,14 +357,13 @@ func (e *OKX) fetchPositionTiers(args map[string]interface{}) ([]PositionTier, *
 	return tiers, nil
 }
 
-func (e *OKX) fetchCurrentLeverage(market *banexg.Market) (float64, *errs.Error) {
+func (e *OKX) fetchCurrentLeverage(market *banexg.Market, params map[string]interface{}) (float64, *errs.Error) {
 	if market == nil {
 		return 0, errs.NewMsg(errs.CodeParamInvalid, "market required")
 	}
-	args := map[string]interface{}{
-		FldInstId:  market.ID,
-		FldMgnMode: banexg.MarginCross,
-	}
+	args := utils.SafeParams(params)
+	args[FldInstId] = market.ID
+	args[FldMgnMode] = banexg.MarginCross
 	tryNum := e.GetRetryNum("GetLeverage", 1)
 	res := requestRetry[[]map[string]interface{}](e, MethodAccountGetLeverageInfo, args, tryNum)
 	if res.Error != nil {
@@ -74,6 +74,47 @@ func TestGetLeverage(t *testing.T) {
 	}
 }
 
+func TestMarginModeByAccount(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT-SWAP", "BTC/USDT:USDT", banexg.MarketLinear)
+	seedMarket(exg, "ETH-USDT-SWAP", "ETH/USDT:USDT", banexg.MarketLinear)
+	newAcc := func(name string) *banexg.Account {
+		return &banexg.Account{Name: name, Data: map[string]interface{}{}, LockData: &deadlock.Mutex{}}
+	}
+	exg.Accounts = map[string]*banexg.Account{"a": newAcc("a"), "b": newAcc("b")}
+	exg.DefAccName = "a"
+	accB := map[string]interface{}{banexg.ParamAccount: "b"}
+	exg.Accounts["a"].Data[marginModeKey("BTC/USDT:USDT")] = banexg.MarginIsolated
+	if _, err := exg.SetMarginMode(banexg.MarginCross, "", accB); err != nil {
+		t.Fatalf("set margin mode: %v", err)
+	}
+	exg.Accounts["b"].Data[marginModeKey("ETH/USDT:USDT")] = banexg.MarginIsolated
+	cases := []struct {
+		symbol string
+		params map[string]interface{}
+		want   string
+	}{
+		{"BTC/USDT:USDT", nil, banexg.MarginIsolated},
+		{"ETH/USDT:USDT", nil, banexg.MarginCross},
+		{"BTC/USDT:USDT", accB, banexg.MarginCross},
+		{"ETH/USDT:USDT", accB, banexg.MarginIsolated},
+	}
+	for _, c := range cases {
+		if got := exg.defMarginMode(c.symbol, c.params); got != c.want {
+			t.Fatalf("%s %v: expect %s, got %s", c.symbol, c.params, c.want, got)
+		}
+	}
+	if _, err := exg.SetMarginMode(banexg.MarginCross, "", nil); err != nil {
+		t.Fatalf("set margin mode: %v", err)
+	}
+	if got := exg.defMarginMode("BTC/USDT:USDT", nil); got != banexg.MarginCross {
+		t.Fatalf("set for all symbols should clear symbol modes, got %s", got)
+	}
+}
+
 func TestMarketToInstTypeForLeverage(t *testing.T) {
 	tests := []struct {
 		name      string
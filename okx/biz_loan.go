@@ -0,0 +1,177 @@
+package okx
+
+import (
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+/*
+Borrow
+manual borrow of cross margin in spot mode, margin is borrowed automatically in other modes and isolated margin
+现货模式下全仓手动借币，其他账户模式和逐仓均为自动借币
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-manual-borrow-repay
+*/
+func (e *OKX) Borrow(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay("borrow", code, amount, symbol, params)
+}
+
+/*
+Repay
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-manual-borrow-repay
+*/
+func (e *OKX) Repay(code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	return e.borrowRepay("repay", code, amount, symbol, params)
+}
+
+func (e *OKX) borrowRepay(side, code string, amount float64, symbol string, params map[string]interface{}) (*banexg.MarginLoan, *errs.Error) {
+	args := utils.SafeParams(params)
+	marginMode, err := banexg.GetLoanMarginMode(symbol, args)
+	if err != nil {
+		return nil, err
+	}
+	if marginMode == banexg.MarginIsolated {
+		return nil, errs.NewMsg(errs.CodeNotSupport, "isolated margin is borrowed automatically on okx")
+	}
+	args[FldCcy] = code
+	args["side"] = side
+	args["amt"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	// not idempotent, never retry
+	res := requestRetry[[]map[string]interface{}](e, MethodAccountPostSpotBorrowRepay, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty %s result", side)
+	}
+	return &banexg.MarginLoan{
+		Code:       code,
+		Amount:     amount,
+		MarginMode: banexg.MarginCross,
+		Timestamp:  e.MilliSeconds(),
+		Info:       res.Result[0],
+	}, nil
+}
+
+/*
+FetchBorrowRate
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-get-interest-rate
+*/
+func (e *OKX) FetchBorrowRate(code string, params map[string]interface{}) (*banexg.BorrowRate, *errs.Error) {
+	rates, err := e.FetchBorrowRates([]string{code}, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "borrow rate of %s not found", code)
+	}
+	return rates[0], nil
+}
+
+/*
+FetchBorrowRates
+hourly rates of all currencies are returned if codes is empty
+codes为空时返回所有币种的小时利率
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-get-interest-rate
+*/
+func (e *OKX) FetchBorrowRates(codes []string, params map[string]interface{}) ([]*banexg.BorrowRate, *errs.Error) {
+	args := utils.SafeParams(params)
+	if len(codes) == 1 {
+		args[FldCcy] = codes[0]
+	}
+	tryNum := e.GetRetryNum("FetchBorrowRates", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodAccountGetInterestRate, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[struct {
+		Ccy          string `json:"ccy"`
+		InterestRate string `json:"interestRate"`
+	}](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	var codeSet map[string]bool
+	if len(codes) > 1 {
+		codeSet = make(map[string]bool, len(codes))
+		for _, c := range codes {
+			codeSet[c] = true
+		}
+	}
+	stamp := e.MilliSeconds()
+	result := make([]*banexg.BorrowRate, 0, len(arr))
+	for i, it := range arr {
+		code := e.SafeCurrencyCode(it.Ccy)
+		if codeSet != nil && !codeSet[code] {
+			continue
+		}
+		result = append(result, &banexg.BorrowRate{
+			Code:      code,
+			Rate:      parseFloat(it.InterestRate),
+			Period:    3600000,
+			Timestamp: stamp,
+			Info:      res.Result[i],
+		})
+	}
+	return result, nil
+}
+
+/*
+FetchBorrowInterest
+:see: https://www.okx.com/docs-v5/en/#trading-account-rest-api-get-interest-accrued-data
+*/
+func (e *OKX) FetchBorrowInterest(code, symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.BorrowInterest, *errs.Error) {
+	args := utils.SafeParams(params)
+	args[FldType] = "2"
+	if code != "" {
+		args[FldCcy] = code
+	}
+	if symbol != "" {
+		market, err := e.GetMarket(symbol)
+		if err != nil {
+			return nil, err
+		}
+		args[FldInstId] = market.ID
+		args[FldMgnMode] = banexg.MarginIsolated
+	}
+	if since > 0 {
+		args[FldBefore] = strconv.FormatInt(since-1, 10)
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args[FldAfter] = strconv.FormatInt(until, 10)
+	}
+	if limit > 0 {
+		args[FldLimit] = strconv.Itoa(min(limit, 100))
+	}
+	tryNum := e.GetRetryNum("FetchBorrowInterest", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodAccountGetInterestAccrued, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[InterestAccrued](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.BorrowInterest, 0, len(arr))
+	for i, it := range arr {
+		item := &banexg.BorrowInterest{
+			Code:       e.SafeCurrencyCode(it.Ccy),
+			MarginMode: it.MgnMode,
+			Interest:   parseFloat(it.Interest),
+			Rate:       parseFloat(it.InterestRate),
+			Amount:     parseFloat(it.Liab),
+			Timestamp:  parseInt(it.Ts),
+			Info:       res.Result[i],
+		}
+		if it.InstId != "" {
+			item.Symbol = it.InstId
+			if market := getMarketByIDAny(e, it.InstId, banexg.MarketMargin); market != nil {
+				item.Symbol = market.Symbol
+			}
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,170 @@
+package okx
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+/*
+FetchOpenInterest
+Amount is open interest in base currency (oiCcy), Value is in USD
+Amount为以币计价的持仓量(oiCcy)，Value为美元价值
+:see: https://www.okx.com/docs-v5/en/#public-data-rest-api-get-open-interest
+*/
+func (e *OKX) FetchOpenInterest(symbol string, params map[string]interface{}) (*banexg.OpenInterest, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	if !market.Contract {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "open interest only supports contracts")
+	}
+	args[FldInstType] = instTypeFromMarket(market)
+	args[FldInstId] = market.ID
+	tryNum := e.GetRetryNum("FetchOpenInterest", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodPublicGetOpenInterest, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[OpenInterest](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	if len(arr) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty open interest result")
+	}
+	it := arr[0]
+	return &banexg.OpenInterest{
+		Symbol:    market.Symbol,
+		Amount:    parseFloat(it.OiCcy),
+		Value:     parseFloat(it.OiUsd),
+		Timestamp: parseInt(it.Ts),
+		Info:      res.Result[0],
+	}, nil
+}
+
+/*
+FetchOpenInterestHistory
+:see: https://www.okx.com/docs-v5/en/#trading-statistics-rest-api-get-contract-open-interest-history
+*/
+func (e *OKX) FetchOpenInterestHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.OpenInterest, *errs.Error) {
+	args, market, err := e.loadRubikArgs(symbol, timeframe, since, limit, params)
+	if err != nil {
+		return nil, err
+	}
+	tryNum := e.GetRetryNum("FetchOpenInterestHistory", 1)
+	res := requestRetry[[][]string](e, MethodRubikGetOpenInterestHistory, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	// row: [ts, oi, oiCcy, oiUsd]
+	list := make([]*banexg.OpenInterest, 0, len(res.Result))
+	for _, row := range res.Result {
+		if len(row) < 4 {
+			continue
+		}
+		list = append(list, &banexg.OpenInterest{
+			Symbol:    market.Symbol,
+			Amount:    parseFloat(row[2]),
+			Value:     parseFloat(row[3]),
+			Timestamp: parseInt(row[0]),
+		})
+	}
+	slices.Reverse(list)
+	return list, nil
+}
+
+/*
+FetchLongShortRatioHistory
+long/short account ratio of contract, or taker buy/sell volume when ParamRatioType is RatioTaker
+合约多空账户数比，ParamRatioType为RatioTaker时返回主动买卖量
+:see: https://www.okx.com/docs-v5/en/#trading-statistics-rest-api-get-contract-long-short-ratio
+:see: https://www.okx.com/docs-v5/en/#trading-statistics-rest-api-get-contract-taker-volume
+*/
+func (e *OKX) FetchLongShortRatioHistory(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.LongShortRatio, *errs.Error) {
+	args := utils.SafeParams(params)
+	ratioType := utils.PopMapVal(args, banexg.ParamRatioType, banexg.RatioAccount)
+	args, market, err := e.loadRubikArgs(symbol, timeframe, since, limit, args)
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	switch ratioType {
+	case banexg.RatioAccount:
+		method = MethodRubikGetLongShortAcctRatio
+	case banexg.RatioTaker:
+		method = MethodRubikGetTakerVolume
+		if _, ok := args["unit"]; !ok {
+			// volume in base currency
+			args["unit"] = "0"
+		}
+	default:
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport %s: %s", banexg.ParamRatioType, ratioType)
+	}
+	tryNum := e.GetRetryNum("FetchLongShortRatioHistory", 1)
+	res := requestRetry[[][]string](e, method, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	list := parseLongShortRows(res.Result, market.Symbol, timeframe, ratioType)
+	slices.Reverse(list)
+	return list, nil
+}
+
+// parseLongShortRows row is [ts, ratio] for accounts, [ts, sellVol, buyVol] for taker volume
+func parseLongShortRows(rows [][]string, symbol, timeframe, ratioType string) []*banexg.LongShortRatio {
+	list := make([]*banexg.LongShortRatio, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		item := &banexg.LongShortRatio{
+			Symbol:    symbol,
+			Timeframe: timeframe,
+			Type:      ratioType,
+			Timestamp: parseInt(row[0]),
+		}
+		if ratioType == banexg.RatioTaker {
+			if len(row) < 3 {
+				continue
+			}
+			// ts, sellVol, buyVol
+			item.SetTakerVolume(parseFloat(row[2]), parseFloat(row[1]))
+		} else {
+			item.Ratio = parseFloat(row[1])
+		}
+		list = append(list, item)
+	}
+	return list
+}
+
+// loadRubikArgs set instId, period and time range for trading statistics apis
+func (e *OKX) loadRubikArgs(symbol, timeframe string, since int64, limit int, params map[string]interface{}) (map[string]interface{}, *banexg.Market, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !market.Contract {
+		return nil, nil, errs.NewMsg(errs.CodeUnsupportMarket, "trading statistics only supports contracts")
+	}
+	period, ok := timeFrameMap[timeframe]
+	if !ok || timeframe == "1m" || timeframe == "3m" {
+		return nil, nil, errs.NewMsg(errs.CodeParamInvalid, "unsupport timeframe: %s", timeframe)
+	}
+	args[FldInstId] = market.ID
+	args["period"] = period
+	if since > 0 {
+		args[FldBegin] = strconv.FormatInt(since, 10)
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args[FldEnd] = strconv.FormatInt(until, 10)
+	}
+	if limit > 0 {
+		args[FldLimit] = strconv.Itoa(min(limit, 100))
+	}
+	return args, market, nil
+}
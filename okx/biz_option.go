@@ -0,0 +1,160 @@
+package okx
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+	"github.com/banbox/bntp"
+)
+
+/*
+FetchGreeks
+greeks in Black-Scholes and implied volatility of options, requested by instFamily
+期权的BS希腊值和隐含波动率，按instFamily请求
+:see: https://www.okx.com/docs-v5/en/#public-data-rest-api-get-option-market-data
+*/
+func (e *OKX) FetchGreeks(symbols []string, params map[string]interface{}) ([]*banexg.Greeks, *errs.Error) {
+	families, err := e.optionFamilies(symbols)
+	if err != nil {
+		return nil, err
+	}
+	symbolSet := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		symbolSet[s] = true
+	}
+	tryNum := e.GetRetryNum("FetchGreeks", 1)
+	result := make([]*banexg.Greeks, 0, len(symbols))
+	for _, family := range families {
+		args := utils.SafeParams(params)
+		args[FldInstFamily] = family
+		res := requestRetry[[]map[string]interface{}](e, MethodPublicGetOptSummary, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		for _, item := range res.Result {
+			it := parseOptSummary(e, item)
+			if it != nil && symbolSet[it.Symbol] {
+				result = append(result, it)
+			}
+		}
+	}
+	return result, nil
+}
+
+// optionFamilies return instFamily list of option symbols, keep the order of first occurrence
+func (e *OKX) optionFamilies(symbols []string) ([]string, *errs.Error) {
+	if len(symbols) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbols required")
+	}
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return nil, err
+	}
+	families := make([]string, 0, 1)
+	exists := make(map[string]bool)
+	for _, symbol := range symbols {
+		market, err := e.GetMarket(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !market.Option {
+			return nil, errs.NewMsg(errs.CodeUnsupportMarket, "only option supported, got: %s", symbol)
+		}
+		family := instFamilyFromID(market.ID)
+		if !exists[family] {
+			exists[family] = true
+			families = append(families, family)
+		}
+	}
+	return families, nil
+}
+
+func parseOptSummary(e *OKX, item map[string]interface{}) *banexg.Greeks {
+	instId := getMapString(item, FldInstId)
+	market := getMarketByIDAny(e, instId, banexg.MarketOption)
+	if market == nil {
+		return nil
+	}
+	return &banexg.Greeks{
+		Symbol:          market.Symbol,
+		Delta:           parseFloat(getMapString(item, "deltaBS")),
+		Gamma:           parseFloat(getMapString(item, "gammaBS")),
+		Vega:            parseFloat(getMapString(item, "vegaBS")),
+		Theta:           parseFloat(getMapString(item, "thetaBS")),
+		MarkIV:          parseFloat(getMapString(item, "markVol")),
+		BidIV:           parseFloat(getMapString(item, "bidVol")),
+		AskIV:           parseFloat(getMapString(item, "askVol")),
+		UnderlyingPrice: parseFloat(getMapString(item, "fwdPx")),
+		Timestamp:       parseInt(getMapString(item, "ts")),
+		Info:            item,
+	}
+}
+
+/*
+WatchGreeks
+opt-summary channel is subscribed by instFamily, pushed items are filtered by symbols
+opt-summary按instFamily订阅，推送按symbols过滤
+
+:see: https://www.okx.com/docs-v5/en/#public-data-websocket-option-summary-channel
+*/
+func (e *OKX) WatchGreeks(symbols []string, params map[string]interface{}) (chan *banexg.Greeks, *errs.Error) {
+	client, keys, argsList, err := e.prepareWatchGreeks(symbols)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.writeWsArgs(client, 0, true, keys, argsList); err != nil {
+		return nil, err
+	}
+	chanKey := client.Prefix(WsChanOptSummary)
+	create := func(cap int) chan *banexg.Greeks { return make(chan *banexg.Greeks, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, utils.SafeParams(params))
+	e.AddWsChanRefs(chanKey, symbols...)
+	e.DumpWS("WatchGreeks", symbols)
+	return out, nil
+}
+
+// UnWatchGreeks the channels are unsubscribed when no symbol is referenced
+func (e *OKX) UnWatchGreeks(symbols []string, params map[string]interface{}) *errs.Error {
+	client, keys, argsList, err := e.prepareWatchGreeks(symbols)
+	if err != nil {
+		return err
+	}
+	chanKey := client.Prefix(WsChanOptSummary)
+	if e.DelWsChanRefs(chanKey, symbols...) > 0 {
+		return nil
+	}
+	return e.writeWsArgs(client, 0, false, keys, argsList)
+}
+
+func (e *OKX) prepareWatchGreeks(symbols []string) (*banexg.WsClient, []string, []map[string]interface{}, *errs.Error) {
+	families, err := e.optionFamilies(symbols)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	client, err := e.getWsClient(wsPublic, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keys := make([]string, 0, len(families))
+	argsList := make([]map[string]interface{}, 0, len(families))
+	for _, family := range families {
+		keys = append(keys, buildWsKey(WsChanOptSummary, family))
+		argsList = append(argsList, map[string]interface{}{FldChannel: WsChanOptSummary, FldInstFamily: family})
+	}
+	return client, keys, argsList, nil
+}
+
+func (e *OKX) handleWsOptSummary(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}) {
+	family := getMapString(arg, FldInstFamily)
+	if family != "" {
+		client.SetSubsKeyStamp(buildWsKey(WsChanOptSummary, family), bntp.UTCStamp())
+	}
+	chanKey := client.Prefix(WsChanOptSummary)
+	for _, item := range getMapSlice(msg, "data") {
+		res := parseOptSummary(e, item)
+		if res == nil || !e.HasWsChanRef(chanKey, res.Symbol) {
+			continue
+		}
+		banexg.WriteOutChan(e.Exchange, chanKey, res, true)
+	}
+}
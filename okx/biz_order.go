@@ -3,6 +3,7 @@ package okx
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
@@ -46,6 +47,40 @@ func pickOrdersHistoryMethod(args map[string]interface{}, since, until int64) st
 }
 
 func (e *OKX) CreateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	od, err := e.newOrderArgs(symbol, odType, side, amount, price, params)
+	if err != nil {
+		return nil, err
+	}
+	if od.algo {
+		return e.createAlgoOrder(od.market, odType, side, amount, price, od.args, od.stopLossPrice, od.takeProfitPrice)
+	}
+
+	tryNum := e.GetRetryNum("CreateOrder", 1)
+	res := requestTrade[[]OrderResult](e, MethodTradePostOrder, od.args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty order result")
+	}
+	return parseNewOrder(&res.Result[0], symbol, odType, side, amount, price)
+}
+
+// okxOrderArgs request args of a new order, built by newOrderArgs
+type okxOrderArgs struct {
+	market          *banexg.Market
+	args            map[string]interface{}
+	algo            bool // should be placed by createAlgoOrder
+	stopLossPrice   float64
+	takeProfitPrice float64
+}
+
+/*
+newOrderArgs
+validate the order and build the request args for CreateOrder/CreateOrders
+校验订单并构建CreateOrder/CreateOrders的请求参数
+*/
+func (e *OKX) newOrderArgs(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*okxOrderArgs, *errs.Error) {
 	args, market, err := e.LoadArgsMarket(symbol, params)
 	if err != nil {
 		return nil, err
@@ -86,7 +121,7 @@ func (e *OKX) CreateOrder(symbol, odType, side string, amount, price float64, pa
 	if market.Type == banexg.MarketSpot {
 		args[FldTdMode] = TdModeCash
 	} else {
-		mgnMode := utils.PopMapVal(args, banexg.ParamMarginMode, banexg.MarginCross)
+		mgnMode := utils.PopMapVal(args, banexg.ParamMarginMode, e.defMarginMode(market.Symbol, args))
 		args[FldTdMode] = mgnMode
 	}
 	if clOrdId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clOrdId != "" {
@@ -158,21 +193,18 @@ func (e *OKX) CreateOrder(symbol, odType, side string, amount, price float64, pa
 		args[FldPx] = strconv.FormatFloat(precPrice, 'f', -1, 64)
 	}
 
-	if algoOrder || isAlgoOrderType(odType) || stopLossPrice != 0 || takeProfitPrice != 0 {
-		return e.createAlgoOrder(market, odType, side, amount, price, args, stopLossPrice, takeProfitPrice)
-	}
+	return &okxOrderArgs{
+		market:          market,
+		args:            args,
+		algo:            algoOrder || isAlgoOrderType(odType) || stopLossPrice != 0 || takeProfitPrice != 0,
+		stopLossPrice:   stopLossPrice,
+		takeProfitPrice: takeProfitPrice,
+	}, nil
+}
 
-	tryNum := e.GetRetryNum("CreateOrder", 1)
-	res := requestRetry[[]OrderResult](e, MethodTradePostOrder, args, tryNum)
-	if res.Error != nil {
-		return nil, res.Error
-	}
-	if len(res.Result) == 0 {
-		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty order result")
-	}
-	ord := res.Result[0]
+func parseNewOrder(ord *OrderResult, symbol, odType, side string, amount, price float64) (*banexg.Order, *errs.Error) {
 	if ord.SCode != "0" {
-		return nil, errs.NewMsg(errs.CodeRunTime, "[%s] %s", ord.SCode, ord.SMsg)
+		return nil, newBizErr(ord.SCode, ord.SMsg)
 	}
 	return &banexg.Order{
 		ID:            ord.OrdId,
@@ -215,7 +247,7 @@ func (e *OKX) EditOrder(symbol, orderId, side string, amount, price float64, par
 		args[FldNewPx] = strconv.FormatFloat(precPrice, 'f', -1, 64)
 	}
 	tryNum := e.GetRetryNum("EditOrder", 1)
-	res := requestRetry[[]OrderResult](e, MethodTradePostAmendOrder, args, tryNum)
+	res := requestTrade[[]OrderResult](e, MethodTradePostAmendOrder, args, tryNum)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -224,7 +256,7 @@ func (e *OKX) EditOrder(symbol, orderId, side string, amount, price float64, par
 	}
 	item := res.Result[0]
 	if item.SCode != "0" {
-		return nil, errs.NewMsg(errs.CodeRunTime, "[%s] %s", item.SCode, item.SMsg)
+		return nil, newBizErr(item.SCode, item.SMsg)
 	}
 	return &banexg.Order{
 		ID:            item.OrdId,
@@ -251,7 +283,7 @@ func (e *OKX) CancelOrder(id string, symbol string, params map[string]interface{
 		return nil, err
 	}
 	tryNum := e.GetRetryNum("CancelOrder", 1)
-	res := requestRetry[[]OrderResult](e, MethodTradePostCancelOrder, args, tryNum)
+	res := requestTrade[[]OrderResult](e, MethodTradePostCancelOrder, args, tryNum)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -260,7 +292,7 @@ func (e *OKX) CancelOrder(id string, symbol string, params map[string]interface{
 	}
 	item := res.Result[0]
 	if item.SCode != "0" {
-		return nil, errs.NewMsg(errs.CodeRunTime, "[%s] %s", item.SCode, item.SMsg)
+		return nil, newBizErr(item.SCode, item.SMsg)
 	}
 	return &banexg.Order{
 		ID:            item.OrdId,
@@ -423,6 +455,168 @@ func (e *OKX) FetchOrders(symbol string, since int64, limit int, params map[stri
 	return parseOrders(e, res.Result, marketType, symbol)
 }
 
+/*
+FetchMyTrades get fills of account. trade/fills for recent 3 days, trade/fills-history for 3 months.
+ParamAfter/ParamBefore are billId cursors with the same meaning as OKX after/before.
+获取账户成交明细。近3天使用trade/fills，3个月内使用trade/fills-history
+*/
+func (e *OKX) FetchMyTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.MyTrade, *errs.Error) {
+	args := utils.SafeParams(params)
+	marketType := ""
+	contractType := ""
+	var err *errs.Error
+	var market *banexg.Market
+	if symbol != "" {
+		args, market, err = e.LoadArgsMarket(symbol, args)
+		if err != nil {
+			return nil, err
+		}
+		args[FldInstId] = market.ID
+		marketType = market.Type
+		if instType := instTypeFromMarket(market); instType != "" {
+			args[FldInstType] = instType
+		}
+	}
+	if marketType == "" {
+		marketType, contractType, err = e.LoadArgsMarketType(args)
+		if err != nil {
+			return nil, err
+		}
+		instType := instTypeByMarket(marketType, contractType)
+		if instType == "" {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "unsupported market: %v", marketType)
+		}
+		args[FldInstType] = instType
+	}
+	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
+	if since > 0 {
+		args[FldBegin] = strconv.FormatInt(since, 10)
+	}
+	if until > 0 {
+		args[FldEnd] = strconv.FormatInt(until, 10)
+	}
+	pageLimit := limit
+	if pageLimit <= 0 || pageLimit > 100 {
+		pageLimit = 100
+	}
+	args[FldLimit] = strconv.Itoa(pageLimit)
+	method := pickFillsMethod(args, since, until)
+	after := utils.PopMapVal(args, banexg.ParamAfter, "")
+	before := utils.PopMapVal(args, banexg.ParamBefore, "")
+	result := make([]*banexg.MyTrade, 0)
+	for page := 0; ; page++ {
+		if page >= maxTradePages {
+			return result, errs.NewMsg(errs.CodeDataTruncated, "FetchMyTrades stopped after %d pages", page)
+		}
+		if after != "" {
+			args[FldAfter] = after
+		} else {
+			delete(args, FldAfter)
+		}
+		if before != "" {
+			args[FldBefore] = before
+		} else {
+			delete(args, FldBefore)
+		}
+		tryNum := e.GetRetryNum("FetchMyTrades", 1)
+		res := requestRetry[[]map[string]interface{}](e, method, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if len(res.Result) == 0 {
+			break
+		}
+		arr, err := decodeResult[Fill](res.Result)
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range arr {
+			trade := parseFill(e, &item, res.Result[i], marketType)
+			if trade == nil || symbol != "" && trade.Symbol != symbol {
+				continue
+			}
+			result = append(result, trade)
+		}
+		if limit > 0 && len(result) >= limit {
+			return result[:limit], nil
+		}
+		// only page older records with after; before returns newer records in one page
+		if len(arr) < pageLimit || before != "" {
+			break
+		}
+		nextAfter := arr[len(arr)-1].BillId
+		if nextAfter == "" || nextAfter == after {
+			break
+		}
+		after = nextAfter
+	}
+	return result, nil
+}
+
+func pickFillsMethod(args map[string]interface{}, since, until int64) string {
+	useArchive := utils.PopMapVal(args, banexg.ParamArchive, false)
+	if useArchive {
+		return MethodTradeGetFillsHistory
+	}
+	// trade/fills only keeps fills of last 3 days
+	const threeDaysMs = int64(3 * 24 * 60 * 60 * 1000)
+	now := time.Now().UnixMilli()
+	if since > 0 && now-since > threeDaysMs || until > 0 && now-until > threeDaysMs {
+		return MethodTradeGetFillsHistory
+	}
+	return MethodTradeGetFills
+}
+
+func parseFill(e *OKX, item *Fill, info map[string]interface{}, marketType string) *banexg.MyTrade {
+	if item == nil {
+		return nil
+	}
+	if item.InstType != "" {
+		marketType = parseMarketType(item.InstType, "")
+	}
+	price := parseFloat(item.FillPx)
+	amount := parseFloat(item.FillSz)
+	symbol := item.InstId
+	market := getMarketByIDAny(e, item.InstId, marketType)
+	if market != nil {
+		symbol = market.Symbol
+		// For contract markets, convert contracts to coins
+		if market.Contract && market.ContractSize > 0 && market.ContractSize != 1 {
+			amount = amount * market.ContractSize
+		}
+	}
+	maker := item.ExecType == "M"
+	var fee *banexg.Fee
+	feeCost := parseFloat(item.Fee)
+	if feeCost != 0 || item.FeeCcy != "" {
+		fee = &banexg.Fee{IsMaker: maker, Currency: item.FeeCcy, Cost: feeCost}
+	}
+	ts := parseInt(item.FillTime)
+	if ts == 0 {
+		ts = parseInt(item.Ts)
+	}
+	return &banexg.MyTrade{
+		Trade: banexg.Trade{
+			ID:        item.TradeId,
+			Symbol:    symbol,
+			Side:      strings.ToLower(item.Side),
+			Amount:    amount,
+			Price:     price,
+			Cost:      price * amount,
+			Order:     item.OrdId,
+			Timestamp: ts,
+			Maker:     maker,
+			Fee:       fee,
+			Info:      info,
+		},
+		ClientID: item.ClOrdId,
+		Average:  price,
+		State:    banexg.OdStatusFilled,
+		PosSide:  strings.ToLower(item.PosSide),
+		Info:     info,
+	}
+}
+
 func parseOrder(e *OKX, item *Order, info map[string]interface{}, marketType string) *banexg.Order {
 	if item == nil {
 		return nil
@@ -0,0 +1,162 @@
+package okx
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+// batchOrderMax max orders of batch-orders and cancel-batch-orders per request
+const batchOrderMax = 20
+
+/*
+CreateOrders
+batch-orders is used for normal orders (20 orders per request), algo orders are created one by one.
+普通订单使用batch-orders批量下单(每次最多20个)，策略委托逐个创建
+
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-place-multiple-orders
+*/
+func (e *OKX) CreateOrders(orders []*banexg.OrderArgs, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	result := make([]*banexg.OrderRes, len(orders))
+	var batchIdx []int
+	var items []map[string]interface{}
+	for i, od := range orders {
+		result[i] = &banexg.OrderRes{}
+		args := banexg.MergeOrderParams(params, od.Params)
+		item, err := e.newOrderArgs(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+		if err != nil {
+			result[i].Err = err
+			continue
+		}
+		if item.algo {
+			result[i].Order, result[i].Err = e.CreateOrder(od.Symbol, od.OdType, od.Side, od.Amount, od.Price, args)
+			continue
+		}
+		batchIdx = append(batchIdx, i)
+		items = append(items, banexg.CleanBatchOrder(item.args))
+	}
+	tryNum := e.GetRetryNum("CreateOrders", 1)
+	for start := 0; start < len(batchIdx); start += batchOrderMax {
+		stop := min(start+batchOrderMax, len(batchIdx))
+		list, err := e.requestBatch(MethodTradePostBatchOrders, items[start:stop], params, tryNum)
+		for j, i := range batchIdx[start:stop] {
+			if err != nil {
+				result[i].Err = err
+				continue
+			}
+			od := orders[i]
+			result[i].Order, result[i].Err = parseNewOrder(&list[j], od.Symbol, od.OdType, od.Side, od.Amount, od.Price)
+		}
+	}
+	return result, nil
+}
+
+/*
+CancelOrders
+cancel-batch-orders is used for normal orders (20 orders per request), algo orders are canceled one by one.
+普通订单使用cancel-batch-orders批量撤单(每次最多20个)，策略委托逐个取消
+
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-cancel-multiple-orders
+*/
+func (e *OKX) CancelOrders(ids []string, symbol string, params map[string]interface{}) ([]*banexg.OrderRes, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	if utils.GetMapVal(args, banexg.ParamAlgoOrder, false) {
+		return e.Exchange.CancelOrders(ids, symbol, params)
+	}
+	result := make([]*banexg.OrderRes, len(ids))
+	var batchIdx []int
+	var items []map[string]interface{}
+	for i, id := range ids {
+		if strings.HasPrefix(id, "algo:") {
+			order, err := e.CancelOrder(id, symbol, params)
+			result[i] = &banexg.OrderRes{ID: id, Order: order, Err: err}
+			continue
+		}
+		batchIdx = append(batchIdx, i)
+		items = append(items, map[string]interface{}{FldInstId: market.ID, FldOrdId: id})
+	}
+	tryNum := e.GetRetryNum("CancelOrders", 1)
+	for start := 0; start < len(batchIdx); start += batchOrderMax {
+		stop := min(start+batchOrderMax, len(batchIdx))
+		list, err := e.requestBatch(MethodTradePostCancelBatchOrders, items[start:stop], params, tryNum)
+		for j, i := range batchIdx[start:stop] {
+			res := &banexg.OrderRes{ID: ids[i], Err: err}
+			result[i] = res
+			if err != nil {
+				continue
+			}
+			item := list[j]
+			if item.SCode != "0" {
+				res.Err = newBizErr(item.SCode, item.SMsg)
+				continue
+			}
+			res.Order = &banexg.Order{
+				ID:            item.OrdId,
+				ClientOrderID: item.ClOrdId,
+				Symbol:        symbol,
+				Status:        banexg.OdStatusCanceled,
+			}
+		}
+	}
+	return result, nil
+}
+
+/*
+SetCancelAllAfter
+timer of the account, symbol is ignored. timeOut is in seconds: 0 to disarm, or 10 ~ 120
+账户级别的计时器，忽略symbol。timeOut单位为秒：0表示取消，或10~120
+
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-cancel-all-after
+*/
+func (e *OKX) SetCancelAllAfter(timeoutMs int64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	secs := (timeoutMs + 999) / 1000
+	if timeoutMs < 0 || (secs > 0 && secs < 10) || secs > 120 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "%v cancel-all-after timeout should be 0 or 10~120s, got %dms", e.Name, timeoutMs)
+	}
+	args := utils.SafeParams(params)
+	args["timeOut"] = strconv.FormatInt(secs, 10)
+	tryNum := e.GetRetryNum("SetCancelAllAfter", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodTradePostCancelAllAfter, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return res.Result[0], nil
+}
+
+/*
+requestBatch
+send a batch request and return one OrderResult for each item.
+OKX returns code 1 (all failed) or 2 (partially succeed) with details of each item in data.
+发送批量请求，每项返回一个OrderResult。全部或部分失败时OKX返回code 1或2，data中包含每项详情
+*/
+func (e *OKX) requestBatch(method string, items []map[string]interface{}, params map[string]interface{}, tryNum int) ([]OrderResult, *errs.Error) {
+	args := banexg.BatchOuterArgs(params)
+	args[ArgBatchItems] = items
+	res := requestRetry[[]OrderResult](e, method, args, tryNum)
+	list := res.Result
+	if res.Error != nil {
+		if res.Content == "" {
+			return nil, res.Error
+		}
+		var rsp = struct {
+			Data []OrderResult `json:"data"`
+		}{}
+		if err := utils.UnmarshalString(res.Content, &rsp, utils.JsonNumDefault); err != nil || len(rsp.Data) == 0 {
+			return nil, res.Error
+		}
+		list = rsp.Data
+	}
+	if len(list) != len(items) {
+		return nil, errs.NewMsg(errs.CodeInvalidResponse, "batch result count mismatch: %d/%d", len(list), len(items))
+	}
+	return list, nil
+}
@@ -2,6 +2,7 @@ package okx
 
 import (
 	"testing"
+	"time"
 
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/utils"
@@ -131,6 +132,62 @@ func TestParseOrderHistory(t *testing.T) {
 	}
 }
 
+func TestParseFill(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT-SWAP", "BTC/USDT:USDT", banexg.MarketLinear)
+	exg.Markets["BTC/USDT:USDT"].Contract = true
+	exg.Markets["BTC/USDT:USDT"].ContractSize = 0.01
+	fill := &Fill{
+		InstType: "SWAP",
+		InstId:   "BTC-USDT-SWAP",
+		TradeId:  "744876980",
+		OrdId:    "681896571216195584",
+		ClOrdId:  "abc123",
+		BillId:   "681896571246403584",
+		FillPx:   "60000",
+		FillSz:   "5",
+		Side:     "buy",
+		PosSide:  "long",
+		ExecType: "M",
+		FeeCcy:   "USDT",
+		Fee:      "-0.06",
+		FillTime: "1708587373361",
+		Ts:       "1708587373362",
+	}
+	trade := parseFill(exg, fill, nil, "")
+	if trade == nil {
+		t.Fatalf("unexpected nil trade")
+	}
+	if trade.Symbol != "BTC/USDT:USDT" || trade.ID != fill.TradeId || trade.Order != fill.OrdId {
+		t.Fatalf("unexpected trade fields: %+v", trade)
+	}
+	if trade.Amount != 0.05 || trade.Price != 60000 {
+		t.Fatalf("unexpected amount/price: %v/%v", trade.Amount, trade.Price)
+	}
+	if !trade.Maker || trade.Fee == nil || trade.Fee.Cost != -0.06 || !trade.Fee.IsMaker {
+		t.Fatalf("unexpected fee: %+v", trade.Fee)
+	}
+	if trade.Timestamp != 1708587373361 || trade.PosSide != "long" || trade.ClientID != "abc123" {
+		t.Fatalf("unexpected trade: %+v", trade)
+	}
+}
+
+func TestPickFillsMethod(t *testing.T) {
+	now := time.Now().UnixMilli()
+	if m := pickFillsMethod(map[string]interface{}{}, now-3600000, 0); m != MethodTradeGetFills {
+		t.Fatalf("expect recent fills, got %s", m)
+	}
+	if m := pickFillsMethod(map[string]interface{}{}, now-86400000*5, 0); m != MethodTradeGetFillsHistory {
+		t.Fatalf("expect fills history, got %s", m)
+	}
+	if m := pickFillsMethod(map[string]interface{}{banexg.ParamArchive: true}, 0, 0); m != MethodTradeGetFillsHistory {
+		t.Fatalf("expect fills history for archive, got %s", m)
+	}
+}
+
 // ============================================================================
 // API Integration Tests - require local.json with valid credentials
 // Run manually with: go test -run TestAPI_FetchOrder -v
@@ -233,3 +290,17 @@ func TestAPI_CreateOrderWithStpMode(t *testing.T) {
 		t.Logf("failed to cancel: %v", err)
 	}
 }
+
+func TestAPI_FetchMyTrades(t *testing.T) {
+	exg := getExchange(nil)
+	symbol := "ETH/USDT"
+	trades, err := exg.FetchMyTrades(symbol, 0, 10, nil)
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("fetched %d trades for %s", len(trades), symbol)
+	for _, trade := range trades {
+		t.Logf("trade: id=%s, order=%s, side=%s, amount=%v, price=%v, fee=%+v",
+			trade.ID, trade.Order, trade.Side, trade.Amount, trade.Price, trade.Fee)
+	}
+}
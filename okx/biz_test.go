@@ -1,10 +1,12 @@
 package okx
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
 )
 
 func TestCollectInstTypes(t *testing.T) {
@@ -18,6 +20,32 @@ func TestCollectInstTypes(t *testing.T) {
 	}
 }
 
+func TestNewBizErr(t *testing.T) {
+	cases := []struct {
+		code     string
+		msg      string
+		wantCode int
+	}{
+		{"51008", "Order failed. Insufficient USDT balance in account.", errs.CodeInsufficientBalance},
+		{"51603", "Order does not exist", errs.CodeOrderNotFound},
+		{"51169", "Order failed because you don't have any positions in this direction", errs.CodeReduceOnlyRejected},
+		{"50102", "Timestamp request expired", errs.CodeInvalidNonce},
+		{"51000", "Parameter posSide error", errs.CodePositionModeMismatch},
+		{"51000", "Parameter sz error", errs.CodeRunTime},
+		{"59999", "unknown", errs.CodeRunTime},
+	}
+	for _, c := range cases {
+		err := newBizErr(c.code, c.msg)
+		if err.Code != c.wantCode || strconv.Itoa(err.BizCode) != c.code {
+			t.Errorf("%s %s: expect code %d, got %d(%d)", c.code, c.msg, c.wantCode, err.Code, err.BizCode)
+		}
+	}
+	content := `{"code":"1","msg":"All operations failed","data":[{"sCode":"51008","sMsg":"Insufficient balance"}]}`
+	if code, msg := extractDetailError(content); code != "51008" || msg != "Insufficient balance" {
+		t.Errorf("unexpected detail error: %s %s", code, msg)
+	}
+}
+
 func TestMakeSignPublicPrivate(t *testing.T) {
 	pub, err := New(nil)
 	if err != nil {
@@ -1,6 +1,7 @@
 package okx
 
 import (
+	"sort"
 	"strconv"
 	"time"
 
@@ -47,7 +48,11 @@ func (e *OKX) FetchOHLCV(symbol, timeframe string, since int64, limit int, param
 	if limit <= 0 {
 		limit = 100
 	}
-	args[FldInstId] = market.ID
+	priceType := utils.PopMapVal(args, banexg.ParamPrice, "")
+	if _, err := okxCandlePrefix(priceType); err != nil {
+		return nil, err
+	}
+	args[FldInstId] = okxCandleInstId(market, priceType)
 	args[FldBar] = e.GetTimeFrame(timeframe)
 	args[FldLimit] = strconv.Itoa(limit)
 	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
@@ -66,14 +71,19 @@ func (e *OKX) FetchOHLCV(symbol, timeframe string, since int64, limit int, param
 	if until > 0 {
 		args[FldAfter] = strconv.FormatInt(until, 10)
 	}
-	method := MethodMarketGetCandles
+	method, histMethod := MethodMarketGetCandles, MethodMarketGetHistoryCandles
+	if priceType == banexg.PriceMark {
+		method, histMethod = MethodMarketGetMarkPriceCandles, MethodMarketGetHistoryMarkPriceCandles
+	} else if priceType == banexg.PriceIndex {
+		method, histMethod = MethodMarketGetIndexCandles, MethodMarketGetHistoryIndexCandles
+	}
 	// history-candles 用于获取历史K线，当指定since且数据较老时使用
 	// 对于最近的数据（1天内），使用regular candles以获取最新数据
 	if since > 0 {
 		nowMs := time.Now().UnixMilli()
 		// 如果since在1天以前，使用history-candles
 		if nowMs-since > 86400000 {
-			method = MethodMarketGetHistoryCandles
+			method = histMethod
 		}
 	}
 	tryNum := e.GetRetryNum("FetchOHLCV", 1)
@@ -81,7 +91,14 @@ func (e *OKX) FetchOHLCV(symbol, timeframe string, since int64, limit int, param
 	if res.Error != nil {
 		return nil, res.Error
 	}
-	return parseOHLCV(res.Result), nil
+	klines := parseOHLCV(res.Result)
+	if priceType != "" {
+		// [ts,o,h,l,c,confirm] for mark/index candles, no volume
+		for _, k := range klines {
+			k.Volume, k.Quote = 0, 0
+		}
+	}
+	return klines, nil
 }
 
 func (e *OKX) FetchTickerPrice(symbol string, params map[string]interface{}) (map[string]float64, *errs.Error) {
@@ -173,6 +190,142 @@ func (e *OKX) FetchOrderBook(symbol string, limit int, params map[string]interfa
 	return parseOrderBook(market, &res.Result[0], limit), nil
 }
 
+// maxTradePages limits the requests of one FetchTrades/FetchMyTrades call
+const maxTradePages = 50
+
+/*
+FetchTrades
+Get public trades. market/trades is used for the latest trades, market/history-trades for since/ParamUntil
+or ParamAfter/ParamBefore (tradeId cursor, after returns older trades).
+paging by time stops after maxTradePages, the fetched trades are returned with CodeDataTruncated.
+获取公开成交记录，指定since/ParamUntil或ParamAfter/ParamBefore时使用history-trades。
+按时间翻页超过maxTradePages时返回已获取的成交和CodeDataTruncated
+
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-market-data-get-trades
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-market-data-get-trades-history
+*/
+func (e *OKX) FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Trade, *errs.Error) {
+	args, market, err := e.LoadArgsMarket(symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	args[FldInstId] = market.ID
+	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
+	after := utils.PopMapVal(args, banexg.ParamAfter, "")
+	before := utils.PopMapVal(args, banexg.ParamBefore, "")
+	if limit <= 0 {
+		limit = 100
+	}
+	tryNum := e.GetRetryNum("FetchTrades", 1)
+	if since <= 0 && until <= 0 && after == "" && before == "" {
+		args[FldLimit] = strconv.Itoa(min(limit, 500))
+		res := requestRetry[[]map[string]interface{}](e, MethodMarketGetTrades, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		return parseTrades(e, res.Result, 0, 0), nil
+	}
+	if after != "" || before != "" {
+		// paginate by tradeId, single page
+		args[FldType] = "1"
+		args[FldLimit] = strconv.Itoa(min(limit, 100))
+		if after != "" {
+			args[FldAfter] = after
+		}
+		if before != "" {
+			args[FldBefore] = before
+		}
+		res := requestRetry[[]map[string]interface{}](e, MethodMarketGetHistoryTrades, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		return parseTrades(e, res.Result, since, until), nil
+	}
+	// paginate by timestamp, from until back to since
+	args[FldType] = "2"
+	args[FldLimit] = "100"
+	cursor := until
+	if cursor <= 0 {
+		cursor = time.Now().UnixMilli()
+	} else {
+		cursor += 1
+	}
+	result := make([]*banexg.Trade, 0, limit)
+	seen := make(map[string]bool)
+	truncated := true
+	for page := 0; page < maxTradePages; page++ {
+		args[FldAfter] = strconv.FormatInt(cursor, 10)
+		res := requestRetry[[]map[string]interface{}](e, MethodMarketGetHistoryTrades, args, tryNum)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if len(res.Result) == 0 {
+			truncated = false
+			break
+		}
+		oldest := cursor
+		for _, item := range res.Result {
+			trade := parseWsTradeItem(e, item)
+			if trade == nil {
+				continue
+			}
+			oldest = min(oldest, trade.Timestamp)
+			if seen[trade.ID] || since > 0 && trade.Timestamp < since {
+				continue
+			}
+			seen[trade.ID] = true
+			result = append(result, trade)
+		}
+		if since > 0 && oldest < since || since <= 0 && len(result) >= limit || oldest >= cursor {
+			truncated = false
+			break
+		}
+		cursor = oldest
+	}
+	sortTrades(result)
+	if len(result) > limit {
+		if since > 0 {
+			result = result[:limit]
+		} else {
+			result = result[len(result)-limit:]
+		}
+	}
+	if truncated {
+		// trades between since and the oldest fetched one are missing
+		return result, errs.NewMsg(errs.CodeDataTruncated, "FetchTrades stopped after %d pages", maxTradePages)
+	}
+	return result, nil
+}
+
+/*
+parseTrades
+parse trades from market/trades or market/history-trades, filtered by since/until (when > 0), sorted by time asc
+*/
+func parseTrades(e *OKX, items []map[string]interface{}, since, until int64) []*banexg.Trade {
+	result := make([]*banexg.Trade, 0, len(items))
+	for _, item := range items {
+		trade := parseWsTradeItem(e, item)
+		if trade == nil {
+			continue
+		}
+		if since > 0 && trade.Timestamp < since || until > 0 && trade.Timestamp > until {
+			continue
+		}
+		result = append(result, trade)
+	}
+	sortTrades(result)
+	return result
+}
+
+func sortTrades(list []*banexg.Trade) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Timestamp != list[j].Timestamp {
+			return list[i].Timestamp < list[j].Timestamp
+		}
+		return parseInt(list[i].ID) < parseInt(list[j].ID)
+	})
+}
+
 func mapTickerInstType(marketType, contractType string) string {
 	if marketType == banexg.MarketMargin {
 		return InstTypeSpot
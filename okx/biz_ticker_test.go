@@ -2,6 +2,7 @@ package okx
 
 import (
 	"testing"
+	"time"
 
 	"github.com/banbox/banexg"
 )
@@ -86,6 +87,30 @@ func TestParseOHLCV(t *testing.T) {
 	}
 }
 
+func TestParseTrades(t *testing.T) {
+	ok, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(ok, "BTC-USDT", "BTC/USDT", banexg.MarketSpot)
+	items := []map[string]interface{}{
+		{"instId": "BTC-USDT", "tradeId": "12", "px": "100", "sz": "2", "side": "sell", "ts": "1630048897899"},
+		{"instId": "BTC-USDT", "tradeId": "11", "px": "99", "sz": "1", "side": "buy", "ts": "1630048897897"},
+		{"instId": "BTC-USDT", "tradeId": "10", "px": "98", "sz": "1", "side": "buy", "ts": "1630048897800"},
+	}
+	list := parseTrades(ok, items, 1630048897801, 0)
+	if len(list) != 2 {
+		t.Fatalf("expect 2 trades after since filter, got %d", len(list))
+	}
+	if list[0].ID != "11" || list[1].ID != "12" {
+		t.Fatalf("trades should be sorted asc: %s %s", list[0].ID, list[1].ID)
+	}
+	tr := list[1]
+	if tr.Symbol != "BTC/USDT" || tr.Side != banexg.OdSideSell || tr.Cost != 200 {
+		t.Fatalf("unexpected trade: %+v", tr)
+	}
+}
+
 func TestTickersToLastPrices(t *testing.T) {
 	tickers := []*banexg.Ticker{
 		{Symbol: "BTC/USDT", Last: 100.5, TimeStamp: 1700000000000, Info: map[string]interface{}{"src": "okx"}},
@@ -142,6 +167,35 @@ func TestMapTickerInstType(t *testing.T) {
 	}
 }
 
+func TestCandlePriceType(t *testing.T) {
+	swap := &banexg.Market{ID: "BTC-USDT-SWAP", Contract: true}
+	spot := &banexg.Market{ID: "BTC-USDT"}
+	tests := []struct {
+		priceType string
+		market    *banexg.Market
+		prefix    string
+		instId    string
+	}{
+		{"", swap, WsChanCandlePrefix, "BTC-USDT-SWAP"},
+		{banexg.PriceMark, swap, WsChanMarkCandlePrefix, "BTC-USDT-SWAP"},
+		{banexg.PriceIndex, swap, WsChanIndexCandlePrefix, "BTC-USDT"},
+		{banexg.PriceIndex, spot, WsChanIndexCandlePrefix, "BTC-USDT"},
+	}
+	for _, tt := range tests {
+		prefix, err := okxCandlePrefix(tt.priceType)
+		if err != nil {
+			t.Fatalf("okxCandlePrefix(%s): %v", tt.priceType, err)
+		}
+		instId := okxCandleInstId(tt.market, tt.priceType)
+		if prefix != tt.prefix || instId != tt.instId {
+			t.Fatalf("unexpected candle %s: %s %s", tt.priceType, prefix, instId)
+		}
+	}
+	if _, err := okxCandlePrefix(banexg.PricePremiumIndex); err == nil {
+		t.Fatalf("expected error for premium index")
+	}
+}
+
 // ============================================================================
 // API Integration Tests - require local.json with valid credentials
 // Run manually with: go test -run TestAPI_FetchTicker -v
@@ -252,6 +306,26 @@ func TestAPI_FetchOrderBook(t *testing.T) {
 	}
 }
 
+func TestAPI_FetchTrades(t *testing.T) {
+	exg := getExchange(nil)
+	symbol := "BTC/USDT"
+	trades, err := exg.FetchTrades(symbol, 0, 10, nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, tr := range trades {
+		t.Logf("trade: id=%s, side=%s, price=%v, amount=%v, ts=%d", tr.ID, tr.Side, tr.Price, tr.Amount, tr.Timestamp)
+	}
+	since := time.Now().UnixMilli() - 3600000
+	trades, err = exg.FetchTrades(symbol, since, 150, map[string]interface{}{
+		banexg.ParamUntil: since + 600000,
+	})
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("fetched %d history trades", len(trades))
+}
+
 func TestApi_FetchOHLCV_UntilOnly(t *testing.T) {
 	exg := getExchange(nil)
 	symbol := "BTC/USDT"
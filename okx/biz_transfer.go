@@ -0,0 +1,241 @@
+package okx
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+const (
+	accTrading = "18"
+	accFunding = "6"
+)
+
+// transfer bill types of funding account: [fromAccount, toAccount]. trading account is reported as MarketSpot
+var transferBillTypes = map[string][2]string{
+	"20":  {banexg.AccountFunding, banexg.AccountFunding}, // transfer to sub account
+	"21":  {banexg.AccountFunding, banexg.AccountFunding}, // transfer from sub account
+	"22":  {banexg.AccountFunding, banexg.AccountFunding}, // transfer out from sub to master account
+	"23":  {banexg.AccountFunding, banexg.AccountFunding}, // transfer in from master to sub account
+	"130": {banexg.MarketSpot, banexg.AccountFunding},     // transferred from trading account
+	"131": {banexg.AccountFunding, banexg.MarketSpot},     // transferred to trading account
+}
+
+// maxTransferPages limits the requests of bills for each transfer type in FetchTransfers
+const maxTransferPages = 10
+
+func okxTransferAcc(account string) (string, *errs.Error) {
+	if account == banexg.AccountFunding {
+		return accFunding, nil
+	}
+	if _, ok := banexg.AllMarketTypes[account]; ok {
+		return accTrading, nil
+	}
+	return "", errs.NewMsg(errs.CodeParamInvalid, "unsupport transfer account: %s", account)
+}
+
+/*
+Transfer
+transfer between funding and trading account. spot/margin/linear/inverse/option all refer to the trading account.
+ParamFromSubAcc/ParamToSubAcc transfer between master and sub accounts by subAcct name (OptSubAccId),
+sub to sub transfer must be sent with api key of the source sub account.
+ParamClientOrderId is sent as clientId to look up the transfer later, the request is never retried.
+在资金账户和交易账户间划转，spot/margin/linear/inverse/option均指交易账户。
+ParamFromSubAcc/ParamToSubAcc通过子账户名(OptSubAccId)在母子账户间划转，子账户间划转需使用转出子账户的api key。ParamClientOrderId作为clientId发送，请求不会重试
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-funds-transfer
+*/
+func (e *OKX) Transfer(code string, amount float64, fromAccount, toAccount string, params map[string]interface{}) (*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromSub := utils.PopMapVal(args, banexg.ParamFromSubAcc, "")
+	toSub := utils.PopMapVal(args, banexg.ParamToSubAcc, "")
+	from, err := okxTransferAcc(fromAccount)
+	if err != nil {
+		return nil, err
+	}
+	to, err := okxTransferAcc(toAccount)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case fromSub == "" && toSub == "":
+		if from == to {
+			return nil, errs.NewMsg(errs.CodeParamInvalid, "transfer in same account: %s -> %s", fromAccount, toAccount)
+		}
+		args[FldType] = "0"
+	case fromSub == "":
+		args[FldType] = "1"
+		args["subAcct"] = e.GetSubAccId(toSub, nil)
+	case toSub == "":
+		args[FldType] = "2"
+		args["subAcct"] = e.GetSubAccId(fromSub, nil)
+	default:
+		args[FldType] = "4"
+		args["subAcct"] = e.GetSubAccId(toSub, nil)
+	}
+	args[FldCcy] = code
+	args["amt"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["from"] = from
+	args["to"] = to
+	if clientId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clientId != "" {
+		args["clientId"] = clientId
+	}
+	// not idempotent, a retry after timeout may transfer twice
+	res := requestRetry[[]map[string]interface{}](e, MethodAssetPostTransfer, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[TransferRes](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	if len(arr) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty transfer result")
+	}
+	return &banexg.Transfer{
+		ID:          arr[0].TransId,
+		Timestamp:   e.MilliSeconds(),
+		Code:        code,
+		Amount:      amount,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		FromSubAcc:  fromSub,
+		ToSubAcc:    toSub,
+		Status:      banexg.TransferOk,
+		Info:        res.Result[0],
+	}, nil
+}
+
+/*
+FetchTransfers
+transfer history from bills of funding account, filtered by ParamFromAccount/ParamToAccount if given.
+bills of each transfer type are requested with the type filter, at most maxTransferPages pages for each type,
+CodeDataTruncated is returned with the fetched part when the limit is reached. Sub account names are not returned in bills.
+从资金账户账单中获取划转记录，可按ParamFromAccount/ParamToAccount过滤。按划转类型分别请求账单，每种类型最多
+maxTransferPages页，超出时返回已获取部分和CodeDataTruncated。账单中不返回子账户名
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-asset-bills-details
+*/
+func (e *OKX) FetchTransfers(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transfer, *errs.Error) {
+	args := utils.SafeParams(params)
+	fromAccount := utils.PopMapVal(args, banexg.ParamFromAccount, "")
+	toAccount := utils.PopMapVal(args, banexg.ParamToAccount, "")
+	billTypes, err := transferBillTypesOf(fromAccount, toAccount)
+	if err != nil {
+		return nil, err
+	}
+	if code != "" {
+		args[FldCcy] = code
+	}
+	if since > 0 {
+		args[FldBefore] = strconv.FormatInt(since-1, 10)
+	}
+	until := ""
+	if val := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); val > 0 {
+		until = strconv.FormatInt(val, 10)
+	}
+	args[FldLimit] = "100"
+	tryNum := e.GetRetryNum("FetchTransfers", 1)
+	result := make([]*banexg.Transfer, 0)
+	truncated := false
+	for _, billType := range billTypes {
+		args[FldType] = billType
+		delete(args, FldAfter)
+		after := until
+		for page := 0; ; page++ {
+			if page >= maxTransferPages {
+				truncated = true
+				break
+			}
+			if after != "" {
+				args[FldAfter] = after
+			}
+			res := requestRetry[[]map[string]interface{}](e, MethodAssetGetBills, args, tryNum)
+			if res.Error != nil {
+				return nil, res.Error
+			}
+			arr, err := decodeResult[AssetBill](res.Result)
+			if err != nil {
+				return nil, err
+			}
+			for i, item := range arr {
+				if t := parseAssetBillTransfer(e, &item, res.Result[i]); t != nil {
+					result = append(result, t)
+				}
+			}
+			if len(arr) < 100 || limit > 0 && len(billTypes) == 1 && len(result) >= limit {
+				break
+			}
+			nextAfter := arr[len(arr)-1].Ts
+			if nextAfter == "" || nextAfter == after {
+				break
+			}
+			after = nextAfter
+		}
+	}
+	// newest first, same as bills
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp > result[j].Timestamp
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	if truncated {
+		return result, errs.NewMsg(errs.CodeDataTruncated, "okx transfers stopped after %d pages", maxTransferPages)
+	}
+	return result, nil
+}
+
+/*
+transferBillTypesOf
+bill types of funding account matching the accounts of transfer, empty account matches all.
+all market types refer to the trading account, which is reported as MarketSpot.
+返回匹配划转账户的资金账户账单类型，空账户匹配全部。所有市场类型均指交易账户
+*/
+func transferBillTypesOf(fromAccount, toAccount string) ([]string, *errs.Error) {
+	var from, to string
+	var err *errs.Error
+	if fromAccount != "" {
+		if from, err = okxTransferAcc(fromAccount); err != nil {
+			return nil, err
+		}
+	}
+	if toAccount != "" {
+		if to, err = okxTransferAcc(toAccount); err != nil {
+			return nil, err
+		}
+	}
+	res := make([]string, 0, len(transferBillTypes))
+	for billType, accs := range transferBillTypes {
+		accFrom, _ := okxTransferAcc(accs[0])
+		accTo, _ := okxTransferAcc(accs[1])
+		if from != "" && from != accFrom || to != "" && to != accTo {
+			continue
+		}
+		res = append(res, billType)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func parseAssetBillTransfer(e *OKX, bill *AssetBill, info map[string]interface{}) *banexg.Transfer {
+	accs, ok := transferBillTypes[bill.Type]
+	if !ok {
+		return nil
+	}
+	amount := parseFloat(bill.BalChg)
+	if amount < 0 {
+		amount = -amount
+	}
+	return &banexg.Transfer{
+		ID:          bill.BillId,
+		Timestamp:   parseInt(bill.Ts),
+		Code:        e.SafeCurrencyCode(bill.Ccy),
+		Amount:      amount,
+		FromAccount: accs[0],
+		ToAccount:   accs[1],
+		Status:      banexg.TransferOk,
+		Info:        info,
+	}
+}
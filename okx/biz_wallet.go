@@ -0,0 +1,205 @@
+package okx
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+var (
+	depositStateMap = map[string]string{
+		"1":  banexg.TransferOk,
+		"2":  banexg.TransferOk,
+		"11": banexg.TransferFailed,
+	}
+	withdrawStateMap = map[string]string{
+		"-2": banexg.TransferCanceled,
+		"-1": banexg.TransferFailed,
+		"2":  banexg.TransferOk,
+	}
+)
+
+// okxChain chain of okx is like USDT-TRC20, network without currency prefix is also accepted
+func okxChain(code, network string) string {
+	if network == "" || strings.Contains(network, "-") {
+		return network
+	}
+	return code + "-" + network
+}
+
+/*
+FetchDepositAddress
+deposit address of currency, the selected address is returned if ParamNetwork is empty
+返回币种的充值地址，未指定ParamNetwork时返回默认选中的地址
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-get-deposit-address
+*/
+func (e *OKX) FetchDepositAddress(code string, params map[string]interface{}) (*banexg.DepositAddress, *errs.Error) {
+	args := utils.SafeParams(params)
+	chain := okxChain(code, utils.PopMapVal(args, banexg.ParamNetwork, ""))
+	args[FldCcy] = code
+	tryNum := e.GetRetryNum("FetchDepositAddress", 1)
+	res := requestRetry[[]map[string]interface{}](e, MethodAssetGetDepositAddress, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[DepositAddr](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	for i, it := range arr {
+		if chain != "" && it.Chain != chain || chain == "" && !it.Selected {
+			continue
+		}
+		tag := it.Tag
+		if tag == "" {
+			tag = it.Memo
+		}
+		if tag == "" {
+			tag = it.PmtId
+		}
+		return &banexg.DepositAddress{
+			Code:    e.SafeCurrencyCode(it.Ccy),
+			Network: it.Chain,
+			Address: it.Addr,
+			Tag:     tag,
+			Info:    res.Result[i],
+		}, nil
+	}
+	return nil, errs.NewMsg(errs.CodeDataNotFound, "no deposit address for %s %s", code, chain)
+}
+
+/*
+FetchDeposits
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-get-deposit-history
+*/
+func (e *OKX) FetchDeposits(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	return e.fetchAssetTxs(MethodAssetGetDepositHistory, banexg.TxDeposit, code, since, limit, params)
+}
+
+/*
+FetchWithdrawals
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-get-withdrawal-history
+*/
+func (e *OKX) FetchWithdrawals(code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	return e.fetchAssetTxs(MethodAssetGetWithdrawalHistory, banexg.TxWithdrawal, code, since, limit, params)
+}
+
+func (e *OKX) fetchAssetTxs(method, txType, code string, since int64, limit int, params map[string]interface{}) ([]*banexg.Transaction, *errs.Error) {
+	args := utils.SafeParams(params)
+	if code != "" {
+		args[FldCcy] = code
+	}
+	if since > 0 {
+		args[FldBefore] = strconv.FormatInt(since-1, 10)
+	}
+	if until := utils.PopMapVal(args, banexg.ParamUntil, int64(0)); until > 0 {
+		args[FldAfter] = strconv.FormatInt(until, 10)
+	}
+	if limit > 0 {
+		args[FldLimit] = strconv.Itoa(min(limit, 100))
+	}
+	apiName := banexg.ApiFetchDeposits
+	if txType == banexg.TxWithdrawal {
+		apiName = banexg.ApiFetchWithdrawals
+	}
+	tryNum := e.GetRetryNum(apiName, 1)
+	res := requestRetry[[]map[string]interface{}](e, method, args, tryNum)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	arr, err := decodeResult[AssetTx](res.Result)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*banexg.Transaction, 0, len(arr))
+	for i, it := range arr {
+		result = append(result, parseAssetTx(e, &it, txType, res.Result[i]))
+	}
+	return result, nil
+}
+
+func parseAssetTx(e *OKX, it *AssetTx, txType string, info map[string]interface{}) *banexg.Transaction {
+	code := e.SafeCurrencyCode(it.Ccy)
+	res := &banexg.Transaction{
+		TxID:      it.TxId,
+		Type:      txType,
+		Code:      code,
+		Amount:    parseFloat(it.Amt),
+		Network:   it.Chain,
+		Address:   it.To,
+		Tag:       it.Tag,
+		Timestamp: parseInt(it.Ts),
+		Info:      info,
+	}
+	if res.Tag == "" {
+		res.Tag = it.Memo
+	}
+	if res.Tag == "" {
+		res.Tag = it.PmtId
+	}
+	var ok bool
+	if txType == banexg.TxDeposit {
+		res.ID = it.DepId
+		res.Status, ok = depositStateMap[it.State]
+	} else {
+		res.ID = it.WdId
+		res.Status, ok = withdrawStateMap[it.State]
+		res.Fee = &banexg.Fee{Currency: code, Cost: parseFloat(it.Fee)}
+	}
+	if !ok {
+		res.Status = banexg.TransferPending
+	}
+	return res
+}
+
+/*
+Withdraw
+on-chain withdrawal from funding account, network can be chain like USDT-TRC20 or TRC20.
+ParamClientOrderId is sent as clientId, the request is never retried
+从资金账户链上提现，network可为USDT-TRC20或TRC20。ParamClientOrderId作为clientId发送，请求不会重试
+:see: https://www.okx.com/docs-v5/en/#funding-account-rest-api-withdrawal
+*/
+func (e *OKX) Withdraw(code string, amount float64, address, tag, network string, params map[string]interface{}) (*banexg.Transaction, *errs.Error) {
+	chain := okxChain(code, network)
+	if err := e.CheckWithdraw(code, chain, amount, params); err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	args[FldCcy] = code
+	args["amt"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	args["dest"] = "4"
+	if tag != "" {
+		args["toAddr"] = address + ":" + tag
+	} else {
+		args["toAddr"] = address
+	}
+	if chain != "" {
+		args["chain"] = chain
+	}
+	if clientId := utils.PopMapVal(args, banexg.ParamClientOrderId, ""); clientId != "" {
+		args["clientId"] = clientId
+	}
+	// not idempotent, a retry after timeout may withdraw twice
+	res := requestRetry[[]map[string]interface{}](e, MethodAssetPostWithdrawal, args, 0)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Result) == 0 {
+		return nil, errs.NewMsg(errs.CodeDataNotFound, "empty withdrawal result")
+	}
+	return &banexg.Transaction{
+		ID:        utils.GetMapVal(res.Result[0], "wdId", ""),
+		Type:      banexg.TxWithdrawal,
+		Code:      code,
+		Amount:    amount,
+		Network:   chain,
+		Address:   address,
+		Tag:       tag,
+		Status:    banexg.TransferPending,
+		Timestamp: e.MilliSeconds(),
+		Info:      res.Result[0],
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/utils"
 )
 
 func TestParseMarketType(t *testing.T) {
@@ -66,3 +67,56 @@ func TestParseInstrument(t *testing.T) {
 		t.Fatalf("expected market active")
 	}
 }
+
+func TestParseOptionInstrument(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	inst := &Instrument{
+		InstType:   "OPTION",
+		InstId:     "BTC-USD-241227-100000-C",
+		InstFamily: "BTC-USD",
+		Uly:        "BTC-USD",
+		SettleCcy:  "BTC",
+		CtVal:      "0.01",
+		TickSz:     "0.0005",
+		LotSz:      "1",
+		MinSz:      "1",
+		State:      "live",
+		ExpTime:    "1735286400000",
+		Stk:        "100000",
+		OptType:    "C",
+	}
+	mar := parseInstrument(exg, inst)
+	wantSymbol := "BTC/USD:BTC-" + utils.YMD(1735286400000, "", false) + "-100000-C"
+	if mar.Symbol != wantSymbol || mar.Base != "BTC" || mar.Quote != "USD" {
+		t.Fatalf("unexpected option symbol: %s base=%s quote=%s", mar.Symbol, mar.Base, mar.Quote)
+	}
+	if mar.Strike != 100000 || mar.OptionType != "call" || mar.Expiry != 1735286400000 {
+		t.Fatalf("unexpected option fields: strike=%v type=%s expiry=%d", mar.Strike, mar.OptionType, mar.Expiry)
+	}
+}
+
+func TestParseOptSummary(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USD-241227-100000-C", "BTC/USD:BTC-241227-100000-C", banexg.MarketOption)
+	item := map[string]interface{}{
+		"instType": "OPTION", "instId": "BTC-USD-241227-100000-C", "uly": "BTC-USD",
+		"delta": "0.01", "deltaBS": "0.45", "gammaBS": "0.00002", "vegaBS": "35.2", "thetaBS": "-60.1",
+		"markVol": "0.52", "bidVol": "0.5", "askVol": "0.54", "fwdPx": "98000", "ts": "1597026383085",
+	}
+	res := parseOptSummary(exg, item)
+	if res == nil || res.Symbol != "BTC/USD:BTC-241227-100000-C" {
+		t.Fatalf("unexpected greeks: %+v", res)
+	}
+	if res.Delta != 0.45 || res.Vega != 35.2 || res.Theta != -60.1 || res.MarkIV != 0.52 || res.UnderlyingPrice != 98000 {
+		t.Fatalf("unexpected greeks values: %+v", res)
+	}
+	if res.Timestamp != 1597026383085 {
+		t.Fatalf("unexpected greeks time: %d", res.Timestamp)
+	}
+}
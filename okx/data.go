@@ -64,17 +64,25 @@ const (
 	FldTradeQuoteCcy   = "tradeQuoteCcy"
 )
 
+// levels used to calculate the checksum of order book
+const wsBookChecksumDepth = 25
+
 // OKX WebSocket channel names
 const (
-	WsChanTrades          = "trades"
-	WsChanBooks           = "books"
-	WsChanBooks5          = "books5"
-	WsChanBalancePosition = "balance_and_position"
-	WsChanPositions       = "positions"
-	WsChanOrders          = "orders"
-	WsChanOrdersAlgo      = "orders-algo" // Algo orders channel (trigger/conditional/oco/twap/move_order_stop)
-	WsChanMarkPrice       = "mark-price"
-	WsChanCandlePrefix    = "candle"
+	WsChanTrades            = "trades"
+	WsChanBooks             = "books"
+	WsChanBooks5            = "books5"
+	WsChanBalancePosition   = "balance_and_position"
+	WsChanPositions         = "positions"
+	WsChanOrders            = "orders"
+	WsChanOrdersAlgo        = "orders-algo" // Algo orders channel (trigger/conditional/oco/twap/move_order_stop)
+	WsChanMarkPrice         = "mark-price"
+	WsChanTickers           = "tickers"
+	WsChanLiquidation       = "liquidation-orders"
+	WsChanOptSummary        = "opt-summary" // subscribed by instFamily
+	WsChanCandlePrefix      = "candle"
+	WsChanMarkCandlePrefix  = "mark-price-candle"
+	WsChanIndexCandlePrefix = "index-candle"
 )
 
 // OKX instType values
@@ -95,6 +103,8 @@ const (
 const (
 	TgtCcyQuote = "quote_ccy"
 	InstIdAny   = "ANY"
+	// ArgBatchItems []map[string]interface{} sent as the json array body of batch apis
+	ArgBatchItems = "batchItems"
 )
 
 var (
@@ -103,6 +113,12 @@ var (
 		"1h": "1H", "2h": "2H", "4h": "4H", "6h": "6H", "12h": "12H",
 		"1d": "1D", "1w": "1W", "1M": "1M",
 	}
+	// okx timeframe: banexg timeframe
+	timeFrameRevMap = map[string]string{
+		"1m": "1m", "3m": "3m", "5m": "5m", "15m": "15m", "30m": "30m",
+		"1H": "1h", "2H": "2h", "4H": "4h", "6H": "6h", "12H": "12h",
+		"1D": "1d", "1W": "1w", "1M": "1M",
+	}
 
 	orderStatusMap = map[string]string{
 		"live":             banexg.OdStatusOpen,
@@ -136,35 +152,77 @@ var (
 )
 
 const (
-	MethodPublicGetInstruments         = "publicGetInstruments"
-	MethodMarketGetTicker              = "marketGetTicker"
-	MethodMarketGetTickers             = "marketGetTickers"
-	MethodMarketGetBooks               = "marketGetBooks"
-	MethodMarketGetBooksFull           = "marketGetBooksFull"
-	MethodMarketGetCandles             = "marketGetCandles"
-	MethodMarketGetHistoryCandles      = "marketGetHistoryCandles"
-	MethodPublicGetFundingRate         = "publicGetFundingRate"
-	MethodPublicGetFundingRateHistory  = "publicGetFundingRateHistory"
-	MethodPublicGetPositionTiers       = "publicGetPositionTiers"
-	MethodAccountGetBalance            = "accountGetBalance"
-	MethodAccountGetConfig             = "accountGetConfig"
-	MethodAccountGetBills              = "accountGetBills"
-	MethodAccountGetBillsArchive       = "accountGetBillsArchive"
-	MethodAccountGetPositions          = "accountGetPositions"
-	MethodAccountGetLeverageInfo       = "accountGetLeverageInfo"
-	MethodAccountGetPositionTiers      = "accountGetPositionTiers"
-	MethodAccountSetLeverage           = "accountSetLeverage"
-	MethodTradePostOrder               = "tradePostOrder"
-	MethodTradePostCancelOrder         = "tradePostCancelOrder"
-	MethodTradePostAmendOrder          = "tradePostAmendOrder"
-	MethodTradeGetOrder                = "tradeGetOrder"
-	MethodTradeGetOrdersPending        = "tradeGetOrdersPending"
-	MethodTradeGetOrdersHistory        = "tradeGetOrdersHistory"
-	MethodTradeGetOrdersHistoryArchive = "tradeGetOrdersHistoryArchive"
-	MethodTradePostOrderAlgo           = "tradePostOrderAlgo"
-	MethodTradePostCancelAlgos         = "tradePostCancelAlgos"
-	MethodTradePostAmendAlgos          = "tradePostAmendAlgos"
-	MethodTradeGetOrderAlgo            = "tradeGetOrderAlgo"
-	MethodTradeGetOrdersAlgoPending    = "tradeGetOrdersAlgoPending"
-	MethodTradeGetOrdersAlgoHistory    = "tradeGetOrdersAlgoHistory"
+	MethodPublicGetInstruments             = "publicGetInstruments"
+	MethodPublicGetTime                    = "publicGetTime"
+	MethodMarketGetTicker                  = "marketGetTicker"
+	MethodMarketGetTickers                 = "marketGetTickers"
+	MethodMarketGetBooks                   = "marketGetBooks"
+	MethodMarketGetBooksFull               = "marketGetBooksFull"
+	MethodMarketGetCandles                 = "marketGetCandles"
+	MethodMarketGetHistoryCandles          = "marketGetHistoryCandles"
+	MethodMarketGetMarkPriceCandles        = "marketGetMarkPriceCandles"
+	MethodMarketGetHistoryMarkPriceCandles = "marketGetHistoryMarkPriceCandles"
+	MethodMarketGetIndexCandles            = "marketGetIndexCandles"
+	MethodMarketGetHistoryIndexCandles     = "marketGetHistoryIndexCandles"
+	MethodMarketGetTrades                  = "marketGetTrades"
+	MethodMarketGetHistoryTrades           = "marketGetHistoryTrades"
+	MethodPublicGetFundingRate             = "publicGetFundingRate"
+	MethodPublicGetFundingRateHistory      = "publicGetFundingRateHistory"
+	MethodPublicGetPositionTiers           = "publicGetPositionTiers"
+	MethodAccountGetBalance                = "accountGetBalance"
+	MethodAccountGetConfig                 = "accountGetConfig"
+	MethodAccountGetBills                  = "accountGetBills"
+	MethodAccountGetBillsArchive           = "accountGetBillsArchive"
+	MethodAccountGetPositions              = "accountGetPositions"
+	MethodAccountGetLeverageInfo           = "accountGetLeverageInfo"
+	MethodAccountGetPositionTiers          = "accountGetPositionTiers"
+	MethodAccountSetLeverage               = "accountSetLeverage"
+	MethodTradePostOrder                   = "tradePostOrder"
+	MethodTradePostCancelOrder             = "tradePostCancelOrder"
+	MethodTradePostBatchOrders             = "tradePostBatchOrders"
+	MethodTradePostCancelBatchOrders       = "tradePostCancelBatchOrders"
+	MethodTradePostAmendOrder              = "tradePostAmendOrder"
+	MethodTradeGetOrder                    = "tradeGetOrder"
+	MethodTradeGetOrdersPending            = "tradeGetOrdersPending"
+	MethodTradeGetOrdersHistory            = "tradeGetOrdersHistory"
+	MethodTradeGetOrdersHistoryArchive     = "tradeGetOrdersHistoryArchive"
+	MethodTradePostOrderAlgo               = "tradePostOrderAlgo"
+	MethodTradePostCancelAlgos             = "tradePostCancelAlgos"
+	MethodTradePostAmendAlgos              = "tradePostAmendAlgos"
+	MethodTradeGetOrderAlgo                = "tradeGetOrderAlgo"
+	MethodTradeGetOrdersAlgoPending        = "tradeGetOrdersAlgoPending"
+	MethodTradeGetOrdersAlgoHistory        = "tradeGetOrdersAlgoHistory"
+	MethodTradeGetFills                    = "tradeGetFills"
+	MethodTradeGetFillsHistory             = "tradeGetFillsHistory"
+	MethodAssetPostTransfer                = "assetPostTransfer"
+	MethodAssetGetBills                    = "assetGetBills"
+	MethodAssetGetDepositAddress           = "assetGetDepositAddress"
+	MethodAssetGetDepositHistory           = "assetGetDepositHistory"
+	MethodAssetGetWithdrawalHistory        = "assetGetWithdrawalHistory"
+	MethodAssetPostWithdrawal              = "assetPostWithdrawal"
+	MethodAccountPostSpotBorrowRepay       = "accountPostSpotBorrowRepay"
+	MethodAccountGetInterestRate           = "accountGetInterestRate"
+	MethodAccountGetInterestAccrued        = "accountGetInterestAccrued"
+	MethodAccountSetPositionMode           = "accountSetPositionMode"
+	MethodAccountSetIsolatedMode           = "accountSetIsolatedMode"
+	MethodAccountPostMarginBalance         = "accountPostMarginBalance"
+	MethodPublicGetOpenInterest            = "publicGetOpenInterest"
+	MethodRubikGetOpenInterestHistory      = "rubikGetOpenInterestHistory"
+	MethodRubikGetLongShortAcctRatio       = "rubikGetLongShortAcctRatio"
+	MethodRubikGetTakerVolume              = "rubikGetTakerVolume"
+	MethodPublicGetOptSummary              = "publicGetOptSummary"
+	MethodTradePostCancelAllAfter          = "tradePostCancelAllAfter"
 )
+
+/*
+weightRules
+OKX limits each endpoint separately (public by IP, private by account) and returns no usage headers,
+so local buckets are used. Cost 5 ~ 20 requests / 2s, Cost 1 ~ 60 requests / 2s for private.
+OKX按接口分别限流（公共按IP，私有按账户），且不返回用量响应头，故只使用本地令牌桶
+*/
+func weightRules() []*banexg.WeightRule {
+	return []*banexg.WeightRule{
+		{Hosts: []string{HostPublic}, Limit: 100, Interval: 2000, PerPath: true},
+		{Hosts: []string{HostPrivate}, Limit: 60, Interval: 2000, PerPath: true, Account: true},
+	}
+}
@@ -10,9 +10,10 @@ func New(options map[string]interface{}) (*OKX, *errs.Error) {
 		WsPendingRecons: make(map[string]*WsPendingRecon),
 		Exchange: &banexg.Exchange{
 			ExgInfo: &banexg.ExgInfo{
-				ID:        "okx",
-				Name:      "OKX",
-				Countries: []string{"SC"},
+				ID:         "okx",
+				Name:       "OKX",
+				Countries:  []string{"SC"},
+				OHLCVLimit: 100, // history-candles return at most 100 bars
 			},
 			RateLimit:  20,
 			Options:    options,
@@ -43,40 +44,81 @@ func New(options map[string]interface{}) (*OKX, *errs.Error) {
 				Linear: &banexg.TradeFee{FeeSide: "quote", Taker: 0.0005, Maker: 0.0002, Percentage: true},
 			},
 			Apis: map[string]*banexg.Entry{
-				MethodPublicGetInstruments:         {Path: "public/instruments", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetTicker:              {Path: "market/ticker", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetTickers:             {Path: "market/tickers", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetBooks:               {Path: "market/books", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetBooksFull:           {Path: "market/books-full", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetCandles:             {Path: "market/candles", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodMarketGetHistoryCandles:      {Path: "market/history-candles", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodPublicGetFundingRate:         {Path: "public/funding-rate", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodPublicGetFundingRateHistory:  {Path: "public/funding-rate-history", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodPublicGetPositionTiers:       {Path: "public/position-tiers", Host: HostPublic, Method: "GET", Cost: 5},
-				MethodAccountGetBalance:            {Path: "account/balance", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetConfig:             {Path: "account/config", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetBills:              {Path: "account/bills", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetBillsArchive:       {Path: "account/bills-archive", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetPositions:          {Path: "account/positions", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetLeverageInfo:       {Path: "account/leverage-info", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountGetPositionTiers:      {Path: "account/position-tiers", Host: HostPrivate, Method: "GET", Cost: 5},
-				MethodAccountSetLeverage:           {Path: "account/set-leverage", Host: HostPrivate, Method: "POST", Cost: 5},
-				MethodTradePostOrder:               {Path: "trade/order", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradePostOrderAlgo:           {Path: "trade/order-algo", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradePostCancelOrder:         {Path: "trade/cancel-order", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradePostCancelAlgos:         {Path: "trade/cancel-algos", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradePostAmendOrder:          {Path: "trade/amend-order", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradePostAmendAlgos:          {Path: "trade/amend-algos", Host: HostPrivate, Method: "POST", Cost: 1},
-				MethodTradeGetOrder:                {Path: "trade/order", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrderAlgo:            {Path: "trade/order-algo", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrdersPending:        {Path: "trade/orders-pending", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrdersAlgoPending:    {Path: "trade/orders-algo-pending", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrdersHistory:        {Path: "trade/orders-history", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrdersHistoryArchive: {Path: "trade/orders-history-archive", Host: HostPrivate, Method: "GET", Cost: 1},
-				MethodTradeGetOrdersAlgoHistory:    {Path: "trade/orders-algo-history", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodPublicGetInstruments:             {Path: "public/instruments", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodPublicGetTime:                    {Path: "public/time", Host: HostPublic, Method: "GET", Cost: 2},
+				MethodMarketGetTicker:                  {Path: "market/ticker", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetTickers:                 {Path: "market/tickers", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetBooks:                   {Path: "market/books", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetBooksFull:               {Path: "market/books-full", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetCandles:                 {Path: "market/candles", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetHistoryCandles:          {Path: "market/history-candles", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodMarketGetMarkPriceCandles:        {Path: "market/mark-price-candles", Host: HostPublic, Method: "GET", Cost: 10},
+				MethodMarketGetHistoryMarkPriceCandles: {Path: "market/history-mark-price-candles", Host: HostPublic, Method: "GET", Cost: 20},
+				MethodMarketGetIndexCandles:            {Path: "market/index-candles", Host: HostPublic, Method: "GET", Cost: 10},
+				MethodMarketGetHistoryIndexCandles:     {Path: "market/history-index-candles", Host: HostPublic, Method: "GET", Cost: 20},
+				MethodMarketGetTrades:                  {Path: "market/trades", Host: HostPublic, Method: "GET", Cost: 1},
+				MethodMarketGetHistoryTrades:           {Path: "market/history-trades", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodPublicGetFundingRate:             {Path: "public/funding-rate", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodPublicGetFundingRateHistory:      {Path: "public/funding-rate-history", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodPublicGetPositionTiers:           {Path: "public/position-tiers", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodAccountGetBalance:                {Path: "account/balance", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetConfig:                 {Path: "account/config", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetBills:                  {Path: "account/bills", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetBillsArchive:           {Path: "account/bills-archive", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetPositions:              {Path: "account/positions", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetLeverageInfo:           {Path: "account/leverage-info", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountGetPositionTiers:          {Path: "account/position-tiers", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAccountSetLeverage:               {Path: "account/set-leverage", Host: HostPrivate, Method: "POST", Cost: 5},
+				MethodTradePostOrder:                   {Path: "trade/order", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostOrderAlgo:               {Path: "trade/order-algo", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostCancelOrder:             {Path: "trade/cancel-order", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostBatchOrders:             {Path: "trade/batch-orders", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostCancelBatchOrders:       {Path: "trade/cancel-batch-orders", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostCancelAlgos:             {Path: "trade/cancel-algos", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostAmendOrder:              {Path: "trade/amend-order", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradePostAmendAlgos:              {Path: "trade/amend-algos", Host: HostPrivate, Method: "POST", Cost: 1},
+				MethodTradeGetOrder:                    {Path: "trade/order", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrderAlgo:                {Path: "trade/order-algo", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrdersPending:            {Path: "trade/orders-pending", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrdersAlgoPending:        {Path: "trade/orders-algo-pending", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrdersHistory:            {Path: "trade/orders-history", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrdersHistoryArchive:     {Path: "trade/orders-history-archive", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetOrdersAlgoHistory:        {Path: "trade/orders-algo-history", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetFills:                    {Path: "trade/fills", Host: HostPrivate, Method: "GET", Cost: 1},
+				MethodTradeGetFillsHistory:             {Path: "trade/fills-history", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAssetPostTransfer:                {Path: "asset/transfer", Host: HostPrivate, Method: "POST", Cost: 15},
+				MethodAssetGetBills:                    {Path: "asset/bills", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAssetGetDepositAddress:           {Path: "asset/deposit-address", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAssetGetDepositHistory:           {Path: "asset/deposit-history", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAssetGetWithdrawalHistory:        {Path: "asset/withdrawal-history", Host: HostPrivate, Method: "GET", Cost: 5},
+				MethodAssetPostWithdrawal:              {Path: "asset/withdrawal", Host: HostPrivate, Method: "POST", Cost: 5},
+				MethodAccountPostSpotBorrowRepay:       {Path: "account/spot-manual-borrow-repay", Host: HostPrivate, Method: "POST", Cost: 15},
+				MethodAccountGetInterestRate:           {Path: "account/interest-rate", Host: HostPrivate, Method: "GET", Cost: 12},
+				MethodAccountGetInterestAccrued:        {Path: "account/interest-accrued", Host: HostPrivate, Method: "GET", Cost: 12},
+				MethodAccountSetPositionMode:           {Path: "account/set-position-mode", Host: HostPrivate, Method: "POST", Cost: 12},
+				MethodAccountSetIsolatedMode:           {Path: "account/set-isolated-mode", Host: HostPrivate, Method: "POST", Cost: 12},
+				MethodAccountPostMarginBalance:         {Path: "account/position/margin-balance", Host: HostPrivate, Method: "POST", Cost: 3},
+				MethodPublicGetOpenInterest:            {Path: "public/open-interest", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodPublicGetOptSummary:              {Path: "public/opt-summary", Host: HostPublic, Method: "GET", Cost: 5},
+				MethodRubikGetOpenInterestHistory:      {Path: "rubik/stat/contracts/open-interest-history", Host: HostPublic, Method: "GET", Cost: 10},
+				MethodRubikGetLongShortAcctRatio:       {Path: "rubik/stat/contracts/long-short-account-ratio-contract", Host: HostPublic, Method: "GET", Cost: 20},
+				MethodRubikGetTakerVolume:              {Path: "rubik/stat/taker-volume-contract", Host: HostPublic, Method: "GET", Cost: 20},
+				MethodTradePostCancelAllAfter:          {Path: "trade/cancel-all-after", Host: HostPrivate, Method: "POST", Cost: 30},
 			},
 			Has: map[string]map[string]int{
 				"": {
+					banexg.ApiFetchTime:             banexg.HasOk,
+					banexg.ApiTransfer:              banexg.HasOk,
+					banexg.ApiFetchTransfers:        banexg.HasOk,
+					banexg.ApiFetchDepositAddress:   banexg.HasOk,
+					banexg.ApiFetchDeposits:         banexg.HasOk,
+					banexg.ApiFetchWithdrawals:      banexg.HasOk,
+					banexg.ApiWithdraw:              banexg.HasOk,
+					banexg.ApiBorrow:                banexg.HasOk,
+					banexg.ApiRepay:                 banexg.HasOk,
+					banexg.ApiFetchBorrowRate:       banexg.HasOk,
+					banexg.ApiFetchBorrowRates:      banexg.HasOk,
+					banexg.ApiFetchBorrowInterest:   banexg.HasOk,
 					banexg.ApiFetchTicker:           banexg.HasOk,
 					banexg.ApiFetchTickers:          banexg.HasOk,
 					banexg.ApiFetchTickerPrice:      banexg.HasOk,
@@ -85,16 +127,36 @@ func New(options map[string]interface{}) (*OKX, *errs.Error) {
 					banexg.ApiGetLeverage:           banexg.HasOk,
 					banexg.ApiFetchOHLCV:            banexg.HasOk,
 					banexg.ApiFetchOrderBook:        banexg.HasOk,
+					banexg.ApiFetchTrades:           banexg.HasOk,
 					banexg.ApiFetchOrder:            banexg.HasOk,
 					banexg.ApiFetchOrders:           banexg.HasOk,
 					banexg.ApiFetchBalance:          banexg.HasOk,
 					banexg.ApiFetchAccountPositions: banexg.HasOk,
 					banexg.ApiFetchPositions:        banexg.HasOk,
 					banexg.ApiFetchOpenOrders:       banexg.HasOk,
+					banexg.ApiFetchMyTrades:         banexg.HasOk,
 					banexg.ApiCreateOrder:           banexg.HasOk,
 					banexg.ApiEditOrder:             banexg.HasOk,
 					banexg.ApiCancelOrder:           banexg.HasOk,
+					banexg.ApiCreateOrders:          banexg.HasOk,
+					banexg.ApiCancelOrders:          banexg.HasOk,
+					banexg.ApiCancelAllOrders:       banexg.HasEmulated,
 					banexg.ApiSetLeverage:           banexg.HasOk,
+					banexg.ApiSetMarginMode:         banexg.HasOk,
+					banexg.ApiSetPositionMode:       banexg.HasOk,
+					banexg.ApiAddMargin:             banexg.HasOk,
+					banexg.ApiReduceMargin:          banexg.HasOk,
+					banexg.ApiFetchOpenInterest:     banexg.HasOk,
+					banexg.ApiFetchOpenInterestHist: banexg.HasOk,
+					banexg.ApiFetchLongShortRatio:   banexg.HasOk,
+					banexg.ApiWatchLiquidations:     banexg.HasOk,
+					banexg.ApiUnWatchLiquidations:   banexg.HasOk,
+					banexg.ApiFetchOptionChain:      banexg.HasOk,
+					banexg.ApiFetchGreeks:           banexg.HasOk,
+					banexg.ApiWatchGreeks:           banexg.HasOk,
+					banexg.ApiUnWatchGreeks:         banexg.HasOk,
+					banexg.ApiFetchOHLCVRange:       banexg.HasOk,
+					banexg.ApiSetCancelAllAfter:     banexg.HasOk,
 					banexg.ApiCalcMaintMargin:       banexg.HasOk,
 					banexg.ApiWatchOrderBooks:       banexg.HasOk,
 					banexg.ApiUnWatchOrderBooks:     banexg.HasOk,
@@ -104,22 +166,36 @@ func New(options map[string]interface{}) (*OKX, *errs.Error) {
 					banexg.ApiUnWatchMarkPrices:     banexg.HasOk,
 					banexg.ApiWatchTrades:           banexg.HasOk,
 					banexg.ApiUnWatchTrades:         banexg.HasOk,
+					banexg.ApiWatchTickers:          banexg.HasOk,
+					banexg.ApiUnWatchTickers:        banexg.HasOk,
 					banexg.ApiWatchMyTrades:         banexg.HasOk,
+					banexg.ApiWatchOrders:           banexg.HasOk,
 					banexg.ApiWatchBalance:          banexg.HasOk,
 					banexg.ApiWatchPositions:        banexg.HasOk,
 					banexg.ApiWatchAccountConfig:    banexg.HasOk,
 				},
 			},
-			CredKeys: map[string]bool{"ApiKey": true, "Secret": true, "Password": true},
+			CredKeys:    map[string]bool{"ApiKey": true, "Secret": true, "Password": true},
+			WeightRules: weightRules(),
 		},
 		WsAuthDone: make(map[string]chan *errs.Error),
 		WsAuthed:   make(map[string]bool),
 	}
+	exg.Self = exg
 	exg.Sign = makeSign(exg)
+	exg.MapBizErr = mapBizErr
 	exg.FetchMarkets = makeFetchMarkets(exg)
 	exg.OnWsMsg = makeHandleWsMsg(exg)
 	exg.OnWsReCon = makeHandleWsReCon(exg)
 	exg.CheckWsTimeout = makeCheckWsTimeout(exg)
+	exg.AccStateKey = func(marketType string) string {
+		// unified account, positions and balances of all markets are pushed by the private connection
+		return wsPrivate
+	}
+	exg.CancelAfterScope = func(symbol string, params map[string]interface{}) string {
+		// timer of the account
+		return ""
+	}
 	err := exg.Init()
 	return exg, err
 }
@@ -27,9 +27,6 @@ type OKX struct {
 	// WsPendingRecons stores pending reconnection info to restore subs after login.
 	WsPendingRecons map[string]*WsPendingRecon
 	WsAuthLock      deadlock.Mutex
-	// WsMyTradesChanKey stores the channel key for mytrades output.
-	// Used to ensure algo orders (business endpoint) write to the same channel as regular orders (private endpoint).
-	WsMyTradesChanKey string
 }
 
 // Instrument describes /public/instruments response item.
@@ -51,6 +48,8 @@ type Instrument struct {
 	State             string   `json:"state"`
 	ListTime          string   `json:"listTime"`
 	ExpTime           string   `json:"expTime"`
+	Stk               string   `json:"stk"`
+	OptType           string   `json:"optType"`
 	TradeQuoteCcyList []string `json:"tradeQuoteCcyList"`
 }
 
@@ -113,6 +112,85 @@ type Bill struct {
 	Notes    string `json:"notes"`
 }
 
+// TransferRes describes /asset/transfer response item.
+type TransferRes struct {
+	TransId  string `json:"transId"`
+	Ccy      string `json:"ccy"`
+	ClientId string `json:"clientId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Amt      string `json:"amt"`
+}
+
+// AssetBill describes /asset/bills response item.
+type AssetBill struct {
+	BillId   string `json:"billId"`
+	Ccy      string `json:"ccy"`
+	ClientId string `json:"clientId"`
+	BalChg   string `json:"balChg"`
+	Bal      string `json:"bal"`
+	Type     string `json:"type"`
+	Ts       string `json:"ts"`
+}
+
+// DepositAddr describes /asset/deposit-address response item.
+type DepositAddr struct {
+	Ccy      string `json:"ccy"`
+	Chain    string `json:"chain"`
+	Addr     string `json:"addr"`
+	Tag      string `json:"tag"`
+	Memo     string `json:"memo"`
+	PmtId    string `json:"pmtId"`
+	Selected bool   `json:"selected"`
+}
+
+// AssetTx describes /asset/deposit-history and /asset/withdrawal-history response item.
+type AssetTx struct {
+	Ccy   string `json:"ccy"`
+	Chain string `json:"chain"`
+	Amt   string `json:"amt"`
+	To    string `json:"to"`
+	Tag   string `json:"tag"`
+	Memo  string `json:"memo"`
+	PmtId string `json:"pmtId"`
+	TxId  string `json:"txId"`
+	Fee   string `json:"fee"`
+	Ts    string `json:"ts"`
+	State string `json:"state"`
+	DepId string `json:"depId"`
+	WdId  string `json:"wdId"`
+}
+
+// InterestAccrued describes /account/interest-accrued response item.
+type InterestAccrued struct {
+	Ccy          string `json:"ccy"`
+	InstId       string `json:"instId"`
+	MgnMode      string `json:"mgnMode"`
+	Interest     string `json:"interest"`
+	InterestRate string `json:"interestRate"`
+	Liab         string `json:"liab"`
+	Ts           string `json:"ts"`
+}
+
+// Fill describes /trade/fills and /trade/fills-history response item.
+type Fill struct {
+	InstType string `json:"instType"`
+	InstId   string `json:"instId"`
+	TradeId  string `json:"tradeId"`
+	OrdId    string `json:"ordId"`
+	ClOrdId  string `json:"clOrdId"`
+	BillId   string `json:"billId"`
+	FillPx   string `json:"fillPx"`
+	FillSz   string `json:"fillSz"`
+	Side     string `json:"side"`
+	PosSide  string `json:"posSide"`
+	ExecType string `json:"execType"`
+	FeeCcy   string `json:"feeCcy"`
+	Fee      string `json:"fee"`
+	Ts       string `json:"ts"`
+	FillTime string `json:"fillTime"`
+}
+
 // Position describes /account/positions response item.
 type Position struct {
 	InstType string `json:"instType"`
@@ -247,6 +325,16 @@ type FundingRate struct {
 	Ts              string `json:"ts"`
 }
 
+// OpenInterest describes /public/open-interest response item.
+type OpenInterest struct {
+	InstType string `json:"instType"`
+	InstId   string `json:"instId"`
+	Oi       string `json:"oi"`
+	OiCcy    string `json:"oiCcy"`
+	OiUsd    string `json:"oiUsd"`
+	Ts       string `json:"ts"`
+}
+
 // FundingRateHistory describes /public/funding-rate-history response item.
 type FundingRateHistory struct {
 	InstType     string `json:"instType"`
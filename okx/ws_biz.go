@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -97,8 +98,18 @@ func makeHandleWsMsg(e *OKX) banexg.FuncOnWsMsg {
 			e.handleWsAlgoOrders(client, msg, arg)
 		case channel == WsChanMarkPrice:
 			e.handleWsMarkPrices(client, msg, arg)
+		case channel == WsChanTickers:
+			e.handleWsTickers(client, msg, arg)
+		case channel == WsChanLiquidation:
+			e.handleWsLiquidations(client, msg, arg)
+		case channel == WsChanOptSummary:
+			e.handleWsOptSummary(client, msg, arg)
 		case strings.HasPrefix(channel, WsChanCandlePrefix):
-			e.handleWsOHLCV(client, msg, arg)
+			e.handleWsOHLCV(client, msg, arg, WsChanCandlePrefix)
+		case strings.HasPrefix(channel, WsChanMarkCandlePrefix):
+			e.handleWsOHLCV(client, msg, arg, WsChanMarkCandlePrefix)
+		case strings.HasPrefix(channel, WsChanIndexCandlePrefix):
+			e.handleWsOHLCV(client, msg, arg, WsChanIndexCandlePrefix)
 		default:
 			if channel != "" {
 				log.Debug("unhandled ws channel", zap.String("channel", channel))
@@ -144,7 +155,9 @@ func makeHandleWsReCon(e *OKX) banexg.FuncOnWsReCon {
 			if instType != "" {
 				arg[FldInstType] = instType
 			}
-			if instId != "" {
+			if ch == WsChanOptSummary {
+				arg[FldInstFamily] = instId
+			} else if instId != "" {
 				arg[FldInstId] = instId
 			}
 			args = append(args, arg)
@@ -286,46 +299,89 @@ func (e *OKX) UnWatchTrades(symbols []string, params map[string]interface{}) *er
 	return nil
 }
 
-func (e *OKX) WatchOHLCVs(jobs [][2]string, params map[string]interface{}) (chan *banexg.PairTFKline, *errs.Error) {
-	if len(jobs) == 0 {
-		return nil, errs.NewMsg(errs.CodeParamRequired, "jobs required for WatchOHLCVs")
+/*
+WatchTickers
+watches tickers of symbols with the tickers channel, pushed every 100ms at most when changed
+
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-market-data-ws-tickers-channel
+*/
+func (e *OKX) WatchTickers(symbols []string, params map[string]interface{}) (chan *banexg.Ticker, *errs.Error) {
+	if len(symbols) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "symbols required for WatchTickers")
 	}
 	_, err := e.LoadMarkets(false, nil)
 	if err != nil {
 		return nil, err
 	}
-	client, err := e.getWsClient(wsBusiness, "")
+	client, err := e.getWsClient(wsPublic, "")
 	if err != nil {
 		return nil, err
 	}
-	argsList := make([]map[string]interface{}, 0, len(jobs))
-	keys := make([]string, 0, len(jobs))
-	refKeys := make([]string, 0, len(jobs))
-	for _, job := range jobs {
-		symbol := job[0]
-		timeframe := job[1]
-		if symbol == "" || timeframe == "" {
-			return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid job for WatchOHLCVs")
-		}
-		id, err := e.GetMarketID(symbol)
+	keys, argsList, err := e.wsSymbolArgs(WsChanTickers, symbols)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.writeWsArgs(client, 0, true, keys, argsList); err != nil {
+		return nil, err
+	}
+	chanKey := client.Prefix(WsChanTickers)
+	create := func(cap int) chan *banexg.Ticker { return make(chan *banexg.Ticker, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, params)
+	e.AddWsChanRefs(chanKey, symbols...)
+	e.DumpWS("WatchTickers", symbols)
+	return out, nil
+}
+
+func (e *OKX) UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error {
+	if len(symbols) == 0 {
+		return errs.NewMsg(errs.CodeParamRequired, "symbols required for UnWatchTickers")
+	}
+	client, err := e.getWsClient(wsPublic, "")
+	if err != nil {
+		return err
+	}
+	keys, argsList, err := e.wsSymbolArgs(WsChanTickers, symbols)
+	if err != nil {
+		return err
+	}
+	if err := e.writeWsArgs(client, 0, false, keys, argsList); err != nil {
+		return err
+	}
+	chanKey := client.Prefix(WsChanTickers)
+	e.DelWsChanRefs(chanKey, symbols...)
+	return nil
+}
+
+// wsSymbolArgs build subscription keys and args of channel for symbols
+func (e *OKX) wsSymbolArgs(channel string, symbols []string) ([]string, []map[string]interface{}, *errs.Error) {
+	argsList := make([]map[string]interface{}, 0, len(symbols))
+	keys := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		id, err := e.GetMarketID(sym)
 		if err != nil {
-			return nil, err
-		}
-		tf := e.GetTimeFrame(timeframe)
-		if tf == "" {
-			return nil, errs.NewMsg(errs.CodeInvalidTimeFrame, "invalid timeframe: %s", timeframe)
+			return nil, nil, err
 		}
-		channel := okxCandleChannel(tf)
 		argsList = append(argsList, map[string]interface{}{FldChannel: channel, FldInstId: id})
 		keys = append(keys, buildWsKey(channel, id))
-		refKeys = append(refKeys, symbol+"@"+timeframe)
+	}
+	return keys, argsList, nil
+}
+
+func (e *OKX) WatchOHLCVs(jobs [][2]string, params map[string]interface{}) (chan *banexg.PairTFKline, *errs.Error) {
+	if len(jobs) == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "jobs required for WatchOHLCVs")
+	}
+	args := utils.SafeParams(params)
+	client, chanPrefix, keys, argsList, refKeys, err := e.prepareWsOHLCVs(jobs, args, "WatchOHLCVs")
+	if err != nil {
+		return nil, err
 	}
 	if err := e.writeWsArgs(client, 0, true, keys, argsList); err != nil {
 		return nil, err
 	}
-	chanKey := client.Prefix("candle")
+	chanKey := client.Prefix(chanPrefix)
 	create := func(cap int) chan *banexg.PairTFKline { return make(chan *banexg.PairTFKline, cap) }
-	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, params)
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
 	e.AddWsChanRefs(chanKey, refKeys...)
 	e.DumpWS("WatchOHLCVs", jobs)
 	return out, nil
@@ -335,13 +391,37 @@ func (e *OKX) UnWatchOHLCVs(jobs [][2]string, params map[string]interface{}) *er
 	if len(jobs) == 0 {
 		return errs.NewMsg(errs.CodeParamRequired, "jobs required for UnWatchOHLCVs")
 	}
-	_, err := e.LoadMarkets(false, nil)
+	args := utils.SafeParams(params)
+	client, chanPrefix, keys, argsList, refKeys, err := e.prepareWsOHLCVs(jobs, args, "UnWatchOHLCVs")
 	if err != nil {
 		return err
 	}
+	if err := e.writeWsArgs(client, 0, false, keys, argsList); err != nil {
+		return err
+	}
+	chanKey := client.Prefix(chanPrefix)
+	e.DelWsChanRefs(chanKey, refKeys...)
+	return nil
+}
+
+/*
+prepareWsOHLCVs
+build candle subscriptions of jobs, mark/index price candles are used when banexg.ParamPrice is set
+构建K线订阅参数，设置banexg.ParamPrice时使用标记/指数价格K线
+*/
+func (e *OKX) prepareWsOHLCVs(jobs [][2]string, args map[string]interface{}, opName string) (*banexg.WsClient, string, []string, []map[string]interface{}, []string, *errs.Error) {
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return nil, "", nil, nil, nil, err
+	}
+	priceType := utils.PopMapVal(args, banexg.ParamPrice, "")
+	chanPrefix, err := okxCandlePrefix(priceType)
+	if err != nil {
+		return nil, "", nil, nil, nil, err
+	}
 	client, err := e.getWsClient(wsBusiness, "")
 	if err != nil {
-		return err
+		return nil, "", nil, nil, nil, err
 	}
 	argsList := make([]map[string]interface{}, 0, len(jobs))
 	keys := make([]string, 0, len(jobs))
@@ -350,27 +430,23 @@ func (e *OKX) UnWatchOHLCVs(jobs [][2]string, params map[string]interface{}) *er
 		symbol := job[0]
 		timeframe := job[1]
 		if symbol == "" || timeframe == "" {
-			return errs.NewMsg(errs.CodeParamInvalid, "invalid job for UnWatchOHLCVs")
+			return nil, "", nil, nil, nil, errs.NewMsg(errs.CodeParamInvalid, "invalid job for %s", opName)
 		}
-		id, err := e.GetMarketID(symbol)
+		market, err := e.GetMarket(symbol)
 		if err != nil {
-			return err
+			return nil, "", nil, nil, nil, err
 		}
 		tf := e.GetTimeFrame(timeframe)
 		if tf == "" {
-			return errs.NewMsg(errs.CodeInvalidTimeFrame, "invalid timeframe: %s", timeframe)
+			return nil, "", nil, nil, nil, errs.NewMsg(errs.CodeInvalidTimeFrame, "invalid timeframe: %s", timeframe)
 		}
-		channel := okxCandleChannel(tf)
+		id := okxCandleInstId(market, priceType)
+		channel := chanPrefix + tf
 		argsList = append(argsList, map[string]interface{}{FldChannel: channel, FldInstId: id})
 		keys = append(keys, buildWsKey(channel, id))
 		refKeys = append(refKeys, symbol+"@"+timeframe)
 	}
-	if err := e.writeWsArgs(client, 0, false, keys, argsList); err != nil {
-		return err
-	}
-	chanKey := client.Prefix("candle")
-	e.DelWsChanRefs(chanKey, refKeys...)
-	return nil
+	return client, chanPrefix, keys, argsList, refKeys, nil
 }
 
 func (e *OKX) WatchMarkPrices(symbols []string, params map[string]interface{}) (chan map[string]float64, *errs.Error) {
@@ -438,6 +514,76 @@ func (e *OKX) UnWatchMarkPrices(symbols []string, params map[string]interface{})
 	return nil
 }
 
+/*
+WatchLiquidations
+liquidation-orders channel is subscribed by instType, pushed items are filtered by symbols,
+all symbols of the instType are pushed if symbols is empty
+liquidation-orders按instType订阅，推送按symbols过滤，symbols为空时推送该instType所有品种
+
+:see: https://www.okx.com/docs-v5/en/#public-data-websocket-liquidation-orders-channel
+*/
+func (e *OKX) WatchLiquidations(symbols []string, params map[string]interface{}) (chan *banexg.Liquidation, *errs.Error) {
+	client, instType, args, err := e.prepareWatchLiquidations(symbols, params)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{buildWsKeyWithType(WsChanLiquidation, instType, "")}
+	argsList := []map[string]interface{}{{FldChannel: WsChanLiquidation, FldInstType: instType}}
+	if err := e.writeWsArgs(client, 0, true, keys, argsList); err != nil {
+		return nil, err
+	}
+	refKeys := symbols
+	if len(refKeys) == 0 {
+		refKeys = []string{instType}
+	}
+	chanKey := client.Prefix(WsChanLiquidation)
+	create := func(cap int) chan *banexg.Liquidation { return make(chan *banexg.Liquidation, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKeys...)
+	e.DumpWS("WatchLiquidations", symbols)
+	return out, nil
+}
+
+// UnWatchLiquidations the channel of instType is unsubscribed when no symbol is referenced
+func (e *OKX) UnWatchLiquidations(symbols []string, params map[string]interface{}) *errs.Error {
+	client, instType, _, err := e.prepareWatchLiquidations(symbols, params)
+	if err != nil {
+		return err
+	}
+	refKeys := symbols
+	if len(refKeys) == 0 {
+		refKeys = []string{instType}
+	}
+	chanKey := client.Prefix(WsChanLiquidation)
+	if e.DelWsChanRefs(chanKey, refKeys...) > 0 {
+		return nil
+	}
+	keys := []string{buildWsKeyWithType(WsChanLiquidation, instType, "")}
+	argsList := []map[string]interface{}{{FldChannel: WsChanLiquidation, FldInstType: instType}}
+	return e.writeWsArgs(client, 0, false, keys, argsList)
+}
+
+func (e *OKX) prepareWatchLiquidations(symbols []string, params map[string]interface{}) (*banexg.WsClient, string, map[string]interface{}, *errs.Error) {
+	_, err := e.LoadMarkets(false, nil)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	args := utils.SafeParams(params)
+	marketType, contractType, err := e.LoadArgsMarketType(args, symbols...)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	instType := instTypeByMarket(marketType, contractType)
+	if instType != InstTypeSwap && instType != InstTypeFutures && instType != InstTypeOption {
+		return nil, "", nil, errs.NewMsg(errs.CodeUnsupportMarket, "WatchLiquidations support derivatives, current: %s", marketType)
+	}
+	client, err := e.getWsClient(wsPublic, "")
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return client, instType, args, nil
+}
+
 func (e *OKX) WatchBalance(params map[string]interface{}) (chan *banexg.Balances, *errs.Error) {
 	client, err := e.subscribePrivateChannel(params, WsChanBalancePosition, "", "")
 	if err != nil {
@@ -500,6 +646,41 @@ func (e *OKX) WatchAccountConfig(params map[string]interface{}) (chan *banexg.Ac
 
 func (e *OKX) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyTrade, *errs.Error) {
 	args := utils.SafeParams(params)
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
+	client, err := e.subscribeOrderChannels(args)
+	if err != nil {
+		return nil, err
+	}
+	chanKey := client.Prefix("mytrades")
+	create := func(cap int) chan *banexg.MyTrade { return make(chan *banexg.MyTrade, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKey)
+	e.DumpWS("WatchMyTrades", nil)
+	return out, nil
+}
+
+/*
+WatchOrders
+watches full order snapshots from the orders and orders-algo channels, including non-fill events
+监听orders和orders-algo频道的订单快照，包括未成交的事件
+*/
+func (e *OKX) WatchOrders(params map[string]interface{}) (chan *banexg.Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
+	client, err := e.subscribeOrderChannels(args)
+	if err != nil {
+		return nil, err
+	}
+	chanKey := client.Prefix("orders")
+	create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
+	e.AddWsChanRefs(chanKey, refKey)
+	e.DumpWS("WatchOrders", nil)
+	return out, nil
+}
+
+// subscribeOrderChannels subscribes orders channel on private endpoint and orders-algo on business endpoint.
+func (e *OKX) subscribeOrderChannels(args map[string]interface{}) (*banexg.WsClient, *errs.Error) {
 	symbol := utils.PopMapVal(args, banexg.ParamSymbol, "")
 	instType := ""
 	instId := ""
@@ -522,18 +703,11 @@ func (e *OKX) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyTrade
 	if err != nil {
 		return nil, err
 	}
-	chanKey := client.Prefix("mytrades")
-	// Store the channel key so algo orders (business endpoint) can write to the same channel
-	e.WsMyTradesChanKey = chanKey
 	// Subscribe to algo orders channel (on business endpoint) for trigger/conditional/oco orders
 	if err := e.subscribeAlgoOrdersChannel(args, instType, instId); err != nil {
 		log.Warn("subscribe algo orders channel fail", zap.Error(err))
 	}
-	create := func(cap int) chan *banexg.MyTrade { return make(chan *banexg.MyTrade, cap) }
-	out := banexg.GetWsOutChan(e.Exchange, chanKey, create, args)
-	e.AddWsChanRefs(chanKey, "account")
-	e.DumpWS("WatchMyTrades", nil)
-	return out, nil
+	return client, nil
 }
 
 // subscribeAlgoOrdersChannel subscribes to the orders-algo channel on business endpoint.
@@ -568,6 +742,15 @@ func (e *OKX) subscribeAlgoOrdersChannel(params map[string]interface{}, instType
 	return e.writeWsArgs(client, 0, true, []string{key}, []map[string]interface{}{arg})
 }
 
+// privateChanKey key of the out chan on the private connection of the account of client, same as client.Prefix of it
+func (e *OKX) privateChanKey(client *banexg.WsClient, key string) string {
+	wsUrl := e.GetHost(HostWsPrivate)
+	if wsUrl == "" {
+		return client.Prefix(key)
+	}
+	return client.AccName + "@" + wsUrl + "#" + key
+}
+
 func (e *OKX) getWsClient(kind, accName string) (*banexg.WsClient, *errs.Error) {
 	var hostKey string
 	switch kind {
@@ -634,7 +817,7 @@ func (e *OKX) wsLoginAsync(client *banexg.WsClient, acc *banexg.Account, connID
 	if err != nil {
 		return err
 	}
-	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	timestamp := strconv.FormatInt(e.Nonce()/1000, 10)
 	payload := timestamp + "GET" + "/users/self/verify"
 	sign, err2 := utils.Signature(payload, creds.Secret, "hmac", "sha256", "base64")
 	if err2 != nil {
@@ -699,7 +882,7 @@ func (e *OKX) wsLogin(client *banexg.WsClient, acc *banexg.Account, connID int)
 		e.WsAuthLock.Unlock()
 		return err
 	}
-	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	timestamp := strconv.FormatInt(e.Nonce()/1000, 10)
 	payload := timestamp + "GET" + "/users/self/verify"
 	sign, err2 := utils.Signature(payload, creds.Secret, "hmac", "sha256", "base64")
 	if err2 != nil {
@@ -821,6 +1004,30 @@ func (e *OKX) handleWsTrades(client *banexg.WsClient, msg map[string]interface{}
 	}
 }
 
+func (e *OKX) handleWsTickers(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}) {
+	items := getMapSlice(msg, "data")
+	if instId := getMapString(arg, "instId"); instId != "" {
+		client.SetSubsKeyStamp(buildWsKey(WsChanTickers, instId), bntp.UTCStamp())
+	}
+	arr, err := decodeResult[Ticker](items)
+	if err != nil {
+		log.Error("ws tickers decode fail", zap.Error(err))
+		return
+	}
+	chanKey := client.Prefix(WsChanTickers)
+	for i := range arr {
+		marketType := ""
+		if market := getMarketByIDAny(e, arr[i].InstId, parseMarketType(arr[i].InstType, "")); market != nil {
+			marketType = market.Type
+		}
+		ticker := parseTicker(e, &arr[i], items[i], marketType)
+		if ticker == nil {
+			continue
+		}
+		banexg.WriteOutChan(e.Exchange, chanKey, e.UpdateWsTicker(ticker), true)
+	}
+}
+
 func (e *OKX) handleWsOrderBooks(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}, channel string) {
 	items := getMapSlice(msg, "data")
 	instId := getMapString(arg, "instId")
@@ -836,7 +1043,12 @@ func (e *OKX) handleWsOrderBooks(client *banexg.WsClient, msg map[string]interfa
 	action := getMapString(msg, "action")
 	chanKey := client.Prefix(channel)
 	for _, item := range items {
-		book := e.applyWsOrderBookUpdate(client, item, channel, action)
+		book, reset := e.applyWsOrderBookUpdate(item, action)
+		if reset != nil {
+			e.ResetOdBook(client, reset)
+			go e.resubWsOrderBook(client, channel, getMapString(item, "instId"))
+			continue
+		}
 		if book == nil {
 			continue
 		}
@@ -844,10 +1056,18 @@ func (e *OKX) handleWsOrderBooks(client *banexg.WsClient, msg map[string]interfa
 	}
 }
 
-func (e *OKX) applyWsOrderBookUpdate(client *banexg.WsClient, item map[string]interface{}, channel, action string) *banexg.OrderBook {
+/*
+applyWsOrderBookUpdate
+apply snapshot or update to the local book. For channels with seqId, update whose prevSeqId is not the
+last seqId, or checksum mismatch, or crossed book will return a reset event.
+Updates are dropped after reset until the snapshot is received from resubscribing.
+应用快照或增量更新。对带seqId的频道，prevSeqId不连续、校验和不一致或买卖交叉时返回重置事件。
+重置后丢弃增量更新，直到重新订阅收到快照
+*/
+func (e *OKX) applyWsOrderBookUpdate(item map[string]interface{}, action string) (*banexg.OrderBook, *banexg.OdBookReset) {
 	instId := getMapString(item, "instId")
 	if instId == "" {
-		return nil
+		return nil, nil
 	}
 	market := getMarketByIDAny(e, instId, "")
 	symbol := instId
@@ -859,9 +1079,11 @@ func (e *OKX) applyWsOrderBookUpdate(client *banexg.WsClient, item map[string]in
 	asks := parseWsBookSide(asksRaw)
 	bids := parseWsBookSide(bidsRaw)
 	ts := parseInt(getMapString(item, "ts"))
+	seqId := parseInt(getMapString(item, "seqId"))
+	_, hasSeq := item["prevSeqId"]
 	e.OdBookLock.Lock()
 	book, ok := e.OrderBooks[symbol]
-	if !ok || action == "snapshot" {
+	if action == "snapshot" || !ok && !hasSeq {
 		limit := len(asks)
 		if len(bids) > limit {
 			limit = len(bids)
@@ -872,27 +1094,78 @@ func (e *OKX) applyWsOrderBookUpdate(client *banexg.WsClient, item map[string]in
 		book = &banexg.OrderBook{
 			Symbol:    symbol,
 			TimeStamp: ts,
-			Asks:      banexg.NewOdBookSide(false, limit, asks),
-			Bids:      banexg.NewOdBookSide(true, limit, bids),
+			Asks:      banexg.NewOdBookSide(false, limit, nil),
+			Bids:      banexg.NewOdBookSide(true, limit, nil),
+			Nonce:     seqId,
 			Limit:     limit,
 			Cache:     make([]map[string]string, 0),
 		}
+		book.Asks.UpdateTexts(asks)
+		book.Bids.UpdateTexts(bids)
 		e.OrderBooks[symbol] = book
 		e.OdBookLock.Unlock()
-		return book
+		return book, checkWsOrderBook(book, item)
 	}
 	e.OdBookLock.Unlock()
+	if hasSeq {
+		if !ok || book.Nonce == 0 {
+			// waiting for snapshot
+			return nil, nil
+		}
+		prevSeqId := parseInt(getMapString(item, "prevSeqId"))
+		if prevSeqId != book.Nonce {
+			return nil, &banexg.OdBookReset{Symbol: symbol, Reason: banexg.OdBookResetGap,
+				Expect: book.Nonce, Actual: prevSeqId}
+		}
+		book.Nonce = seqId
+	}
 	if len(asks) > 0 {
-		book.Asks.Update(asks)
+		book.Asks.UpdateTexts(asks)
 	}
 	if len(bids) > 0 {
-		book.Bids.Update(bids)
+		book.Bids.UpdateTexts(bids)
 	}
 	book.TimeStamp = ts
-	return book
+	return book, checkWsOrderBook(book, item)
 }
 
-func (e *OKX) handleWsOHLCV(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}) {
+/*
+checkWsOrderBook
+verify the crc32 checksum of the top 25 levels and whether the book is crossed
+校验前25档的crc32校验和，以及买卖是否交叉
+*/
+func checkWsOrderBook(book *banexg.OrderBook, item map[string]interface{}) *banexg.OdBookReset {
+	if text := getMapString(item, "checksum"); text != "" {
+		expect := parseInt(text)
+		actual := int64(book.Checksum(wsBookChecksumDepth))
+		if expect != actual {
+			return &banexg.OdBookReset{Symbol: book.Symbol, Reason: banexg.OdBookResetChecksum,
+				Expect: expect, Actual: actual}
+		}
+	}
+	if book.IsCrossed() {
+		return &banexg.OdBookReset{Symbol: book.Symbol, Reason: banexg.OdBookResetCrossed}
+	}
+	return nil
+}
+
+// resubWsOrderBook unsubscribe and subscribe the book channel again to receive a new snapshot
+func (e *OKX) resubWsOrderBook(client *banexg.WsClient, channel, instId string) {
+	if instId == "" {
+		return
+	}
+	keys := []string{buildWsKey(channel, instId)}
+	argsList := []map[string]interface{}{{FldChannel: channel, FldInstId: instId}}
+	err := e.writeWsArgs(client, 0, false, keys, argsList)
+	if err == nil {
+		err = e.writeWsArgs(client, 0, true, keys, argsList)
+	}
+	if err != nil {
+		log.Error("okx resubscribe order book fail", zap.String("instId", instId), zap.Error(err))
+	}
+}
+
+func (e *OKX) handleWsOHLCV(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}, prefix string) {
 	items := getMapSlice(msg, "data")
 	if len(items) == 0 {
 		return
@@ -905,30 +1178,63 @@ func (e *OKX) handleWsOHLCV(client *banexg.WsClient, msg map[string]interface{},
 	if channel == "" || instId == "" {
 		return
 	}
-	symbol := instId
-	if market := getMarketByIDAny(e, instId, ""); market != nil {
-		symbol = market.Symbol
-	}
-	tf := strings.TrimPrefix(channel, "candle")
+	tf := strings.TrimPrefix(channel, prefix)
 	if tf == channel {
 		tf = ""
 	}
 	client.SetSubsKeyStamp(buildWsKey(channel, instId), bntp.UTCStamp())
-	chanKey := client.Prefix("candle")
+	chanKey := client.Prefix(prefix)
+	symbols := []string{instId}
+	if prefix == WsChanIndexCandlePrefix {
+		symbols = e.indexCandleSymbols(chanKey, instId, tf)
+	} else if market := getMarketByIDAny(e, instId, ""); market != nil {
+		symbols = []string{market.Symbol}
+	}
 	for _, item := range items {
 		kline := parseWsCandleItem(item)
 		if kline == nil {
 			continue
 		}
-		out := &banexg.PairTFKline{
-			Symbol:    symbol,
-			TimeFrame: tf,
-			Kline:     *kline,
+		if prefix != WsChanCandlePrefix {
+			// [ts,o,h,l,c,confirm] for mark/index candles, no volume
+			kline.Volume, kline.Quote = 0, 0
+		}
+		for _, symbol := range symbols {
+			out := &banexg.PairTFKline{
+				Symbol:    symbol,
+				TimeFrame: tf,
+				Kline:     *kline,
+			}
+			banexg.WriteOutChan(e.Exchange, chanKey, out, true)
 		}
-		banexg.WriteOutChan(e.Exchange, chanKey, out, true)
 	}
 }
 
+// indexCandleSymbols index id is shared by spot, swap and futures, return all subscribed symbols of it
+func (e *OKX) indexCandleSymbols(chanKey, instId, tf string) []string {
+	timeframe, ok := timeFrameRevMap[tf]
+	if !ok {
+		timeframe = tf
+	}
+	suffix := "@" + timeframe
+	var res []string
+	for _, key := range e.GetWsChanRefs(chanKey) {
+		symbol, ok := strings.CutSuffix(key, suffix)
+		if !ok {
+			continue
+		}
+		market, err := e.GetMarket(symbol)
+		if err == nil && okxCandleInstId(market, banexg.PriceIndex) == instId {
+			res = append(res, symbol)
+		}
+	}
+	if len(res) == 0 {
+		return []string{instId}
+	}
+	sort.Strings(res)
+	return res
+}
+
 func (e *OKX) handleWsMarkPrices(client *banexg.WsClient, msg map[string]interface{}, _ map[string]interface{}) {
 	items := getMapSlice(msg, "data")
 	if len(items) == 0 {
@@ -961,6 +1267,23 @@ func (e *OKX) handleWsMarkPrices(client *banexg.WsClient, msg map[string]interfa
 	}
 }
 
+func (e *OKX) handleWsLiquidations(client *banexg.WsClient, msg map[string]interface{}, arg map[string]interface{}) {
+	instType := getMapString(arg, FldInstType)
+	if instType != "" {
+		client.SetSubsKeyStamp(buildWsKeyWithType(WsChanLiquidation, instType, ""), bntp.UTCStamp())
+	}
+	chanKey := client.Prefix(WsChanLiquidation)
+	allSymbols := e.HasWsChanRef(chanKey, instType)
+	for _, item := range getMapSlice(msg, "data") {
+		for _, res := range parseWsLiquidations(e, item) {
+			if !allSymbols && !e.HasWsChanRef(chanKey, res.Symbol) {
+				continue
+			}
+			banexg.WriteOutChan(e.Exchange, chanKey, res, true)
+		}
+	}
+}
+
 func (e *OKX) handleWsBalanceAndPosition(client *banexg.WsClient, msg map[string]interface{}) {
 	items := getMapSlice(msg, "data")
 	if len(items) == 0 {
@@ -1086,18 +1409,19 @@ func parseWsTradeItem(e *OKX, item map[string]interface{}) *banexg.Trade {
 	return trade
 }
 
-func parseWsBookSide(levels []map[string]interface{}) [][2]float64 {
+// parseWsBookSide return the raw price and size strings of levels, they are needed by checksum
+func parseWsBookSide(levels []map[string]interface{}) [][2]string {
 	if len(levels) == 0 {
 		return nil
 	}
-	res := make([][2]float64, 0, len(levels))
+	res := make([][2]string, 0, len(levels))
 	for _, lvl := range levels {
-		price := parseFloat(getMapString(lvl, "0"))
-		size := parseFloat(getMapString(lvl, "1"))
-		if price == 0 && size == 0 {
+		price := getMapString(lvl, "0")
+		size := getMapString(lvl, "1")
+		if price == "" && size == "" {
 			continue
 		}
-		res = append(res, [2]float64{price, size})
+		res = append(res, [2]string{price, size})
 	}
 	return res
 }
@@ -1233,7 +1557,11 @@ func (e *OKX) handleWsOrders(client *banexg.WsClient, msg map[string]interface{}
 	}
 	instType := getMapString(arg, "instType")
 	chanKey := client.Prefix("mytrades")
+	odChanKey := client.Prefix("orders")
 	for _, item := range items {
+		if order := parseWsOrder(e, item, instType); order != nil {
+			banexg.WriteOutChan(e.Exchange, odChanKey, order, true)
+		}
 		trade := parseWsMyTrade(e, item, instType)
 		if trade == nil {
 			continue
@@ -1259,14 +1587,13 @@ func (e *OKX) handleWsAlgoOrders(client *banexg.WsClient, msg map[string]interfa
 		return
 	}
 	instType := getMapString(arg, "instType")
-	// Use the stored channel key from WatchMyTrades to ensure algo orders
-	// (business endpoint) write to the same channel as regular orders (private endpoint)
-	chanKey := e.WsMyTradesChanKey
-	if chanKey == "" {
-		// Fallback to client prefix if WatchMyTrades hasn't been called
-		chanKey = client.Prefix("mytrades")
-	}
+	// algo orders are pushed on business endpoint, write to the chans of regular orders of the same account
+	chanKey := e.privateChanKey(client, "mytrades")
+	odChanKey := e.privateChanKey(client, "orders")
 	for _, item := range items {
+		if order := parseAlgoOrder(e, item, parseMarketType(getMapString(item, "instType"), "")); order != nil {
+			banexg.WriteOutChan(e.Exchange, odChanKey, order, true)
+		}
 		trade := parseWsAlgoOrder(e, item, instType)
 		if trade == nil {
 			continue
@@ -1383,6 +1710,32 @@ func parseWsMyTrade(e *OKX, item map[string]interface{}, instType string) *banex
 	return trade
 }
 
+// parseWsOrder parses an "orders" channel item as a full order snapshot.
+func parseWsOrder(e *OKX, item map[string]interface{}, instType string) *banexg.Order {
+	if item == nil {
+		return nil
+	}
+	var ord WsOrder
+	if err := utils.DecodeStructMap(item, &ord, "json"); err != nil {
+		log.Error("ws order decode fail", zap.Error(err))
+		return nil
+	}
+	if ord.InstType == "" {
+		ord.InstType = instType
+	}
+	order := parseOrder(e, &ord.Order, item, parseMarketType(ord.InstType, ""))
+	if ord.AlgoClOrdId != "" && order.ClientOrderID == "" {
+		order.ClientOrderID = ord.AlgoClOrdId
+	}
+	if fillTime := parseInt(ord.FillTime); fillTime > 0 {
+		order.LastTradeTimestamp = fillTime
+	}
+	if order.Average > 0 {
+		order.Cost = order.Average * order.Filled
+	}
+	return order
+}
+
 func parseWsAlgoOrder(e *OKX, item map[string]interface{}, instType string) *banexg.MyTrade {
 	if item == nil {
 		return nil
@@ -1474,8 +1827,26 @@ func parseWsAlgoOrder(e *OKX, item map[string]interface{}, instType string) *ban
 	return trade
 }
 
-func okxCandleChannel(timeframe string) string {
-	return "candle" + timeframe
+// okxCandlePrefix channel prefix of candles for banexg.ParamPrice, premium index is not supported
+func okxCandlePrefix(priceType string) (string, *errs.Error) {
+	switch priceType {
+	case "":
+		return WsChanCandlePrefix, nil
+	case banexg.PriceMark:
+		return WsChanMarkCandlePrefix, nil
+	case banexg.PriceIndex:
+		return WsChanIndexCandlePrefix, nil
+	default:
+		return "", errs.NewMsg(errs.CodeNotSupport, "unsupport %s: %s", banexg.ParamPrice, priceType)
+	}
+}
+
+// okxCandleInstId index candles use index id like BTC-USDT instead of contract id
+func okxCandleInstId(market *banexg.Market, priceType string) string {
+	if priceType == banexg.PriceIndex && market.Contract {
+		return instFamilyFromID(market.ID)
+	}
+	return market.ID
 }
 
 func parseWsCandleItem(item map[string]interface{}) *banexg.Kline {
@@ -1529,6 +1900,29 @@ func parseWsMarkPriceItem(e *OKX, item map[string]interface{}) (string, float64,
 	return symbol, price, marketType, instId
 }
 
+// parseWsLiquidations parse item of liquidation-orders, sz of details is contracts for derivatives, converted to base amount
+func parseWsLiquidations(e *OKX, item map[string]interface{}) []*banexg.Liquidation {
+	instId := getMapString(item, FldInstId)
+	market := getMarketByIDAny(e, instId, parseMarketType(getMapString(item, FldInstType), ""))
+	if market == nil {
+		return nil
+	}
+	details := getMapSlice(item, "details")
+	res := make([]*banexg.Liquidation, 0, len(details))
+	for _, d := range details {
+		price := parseFloat(getMapString(d, "bkPx"))
+		res = append(res, &banexg.Liquidation{
+			Symbol:    market.Symbol,
+			Side:      getMapString(d, "side"),
+			Price:     price,
+			Amount:    market.BaseAmount(parseFloat(getMapString(d, "sz")), price),
+			Timestamp: parseInt(getMapString(d, "ts")),
+			Info:      d,
+		})
+	}
+	return res
+}
+
 func updateAccLeverages(acc *banexg.Account, positions []*banexg.Position) []*banexg.AccountConfig {
 	if acc == nil || len(positions) == 0 {
 		return nil
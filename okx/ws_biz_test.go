@@ -1,10 +1,13 @@
 package okx
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
 	"github.com/sasha-s/go-deadlock"
 )
 
@@ -183,7 +186,7 @@ func TestParseWsBookSide(t *testing.T) {
 	if len(out) != 2 {
 		t.Fatalf("unexpected len: %d", len(out))
 	}
-	if out[0][0] != 100 || out[0][1] != 2 {
+	if out[0][0] != "100" || out[0][1] != "2" {
 		t.Fatalf("unexpected level: %+v", out[0])
 	}
 }
@@ -232,6 +235,42 @@ func TestParseWsMarkPriceItem(t *testing.T) {
 	}
 }
 
+func TestParseWsLiquidations(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT-SWAP", "BTC/USDT:USDT", banexg.MarketLinear)
+	market := exg.Markets["BTC/USDT:USDT"]
+	market.Contract = true
+	market.ContractSize = 0.01
+	item := map[string]interface{}{
+		"instType": "SWAP",
+		"instId":   "BTC-USDT-SWAP",
+		"details": []interface{}{
+			map[string]interface{}{"side": "sell", "posSide": "long", "bkPx": "30000", "sz": "20", "ts": "1700000000000"},
+		},
+	}
+	res := parseWsLiquidations(exg, item)
+	if len(res) != 1 {
+		t.Fatalf("unexpected liquidations: %d", len(res))
+	}
+	liq := res[0]
+	if liq.Symbol != "BTC/USDT:USDT" || liq.Side != banexg.OdSideSell || liq.Price != 30000 || liq.Amount != 0.2 {
+		t.Fatalf("unexpected liquidation: %+v", liq)
+	}
+	seedMarket(exg, "BTC-USD-SWAP", "BTC/USD:BTC", banexg.MarketInverse)
+	inverse := exg.Markets["BTC/USD:BTC"]
+	inverse.Contract = true
+	inverse.Inverse = true
+	inverse.ContractSize = 100
+	item["instId"] = "BTC-USD-SWAP"
+	res = parseWsLiquidations(exg, item)
+	if len(res) != 1 || res[0].Symbol != "BTC/USD:BTC" || math.Abs(res[0].Amount-2000.0/30000) > 1e-12 {
+		t.Fatalf("inverse liquidation should be in base amount: %+v", res)
+	}
+}
+
 func TestUpdateAccLeverages(t *testing.T) {
 	acc := &banexg.Account{
 		Leverages:    map[string]int{},
@@ -363,3 +402,215 @@ func TestAPI_WatchAccount(t *testing.T) {
 		}
 	}
 }
+
+func TestParseWsOrder(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT", "BTC/USDT", banexg.MarketSpot)
+	item := map[string]interface{}{
+		"instType":  "SPOT",
+		"instId":    "BTC-USDT",
+		"ordId":     "1",
+		"clOrdId":   "c1",
+		"px":        "30000",
+		"sz":        "0.5",
+		"side":      "buy",
+		"ordType":   "post_only",
+		"avgPx":     "",
+		"accFillSz": "0",
+		"fillSz":    "0",
+		"state":     "canceled",
+		"cTime":     "1700000000000",
+		"uTime":     "1700000001000",
+	}
+	od := parseWsOrder(exg, item, "")
+	if od == nil {
+		t.Fatalf("unexpected nil order")
+	}
+	if od.Symbol != "BTC/USDT" || od.Status != banexg.OdStatusCanceled || od.Amount != 0.5 || !od.PostOnly {
+		t.Fatalf("unexpected order: %+v", od)
+	}
+	if od.LastUpdateTimestamp != 1700000001000 {
+		t.Fatalf("unexpected update time: %d", od.LastUpdateTimestamp)
+	}
+}
+
+func TestApplyWsOrderBookSeq(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT", "BTC/USDT", banexg.MarketSpot)
+	snap := map[string]interface{}{
+		"instId":    "BTC-USDT",
+		"asks":      []interface{}{[]interface{}{"3366.8", "9", "0", "3"}, []interface{}{"3368", "8", "0", "4"}},
+		"bids":      []interface{}{[]interface{}{"3366.1", "7", "0", "3"}, []interface{}{"3366", "6", "0", "4"}},
+		"ts":        "1700000000000",
+		"checksum":  int64(-1881014294),
+		"prevSeqId": int64(-1),
+		"seqId":     int64(100),
+	}
+	book, reset := exg.applyWsOrderBookUpdate(snap, "snapshot")
+	if book == nil || reset != nil || book.Nonce != 100 {
+		t.Fatalf("unexpected snapshot: %+v, %+v", book, reset)
+	}
+	update := map[string]interface{}{
+		"instId":    "BTC-USDT",
+		"asks":      []interface{}{},
+		"bids":      []interface{}{[]interface{}{"3366", "0", "0", "0"}},
+		"ts":        "1700000000100",
+		"checksum":  int64(0),
+		"prevSeqId": int64(100),
+		"seqId":     int64(101),
+	}
+	_, reset = exg.applyWsOrderBookUpdate(update, "update")
+	if reset == nil || reset.Reason != banexg.OdBookResetChecksum {
+		t.Fatalf("expect checksum reset, got %+v", reset)
+	}
+	book.Nonce = 101
+	update["prevSeqId"] = int64(105)
+	update["seqId"] = int64(106)
+	_, reset = exg.applyWsOrderBookUpdate(update, "update")
+	if reset == nil || reset.Reason != banexg.OdBookResetGap || reset.Expect != 101 || reset.Actual != 105 {
+		t.Fatalf("expect gap reset, got %+v", reset)
+	}
+	exg.ResetOdBook(nil, reset)
+	book, reset = exg.applyWsOrderBookUpdate(update, "update")
+	if book != nil || reset != nil {
+		t.Fatalf("update should be dropped before snapshot: %+v, %+v", book, reset)
+	}
+}
+
+func TestIndexCandleSymbols(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USD-SWAP", "BTC/USD:BTC", banexg.MarketInverse)
+	seedMarket(exg, "BTC-USD-250328", "BTC/USD:BTC-250328", banexg.MarketInverse)
+	seedMarket(exg, "ETH-USD-250328", "ETH/USD:ETH-250328", banexg.MarketInverse)
+	for _, m := range exg.Markets {
+		m.Contract = true
+	}
+	chanKey := "test#" + WsChanIndexCandlePrefix
+	exg.AddWsChanRefs(chanKey, "BTC/USD:BTC-250328@1h", "ETH/USD:ETH-250328@1h", "BTC/USD:BTC@4h")
+	res := exg.indexCandleSymbols(chanKey, "BTC-USD", "1H")
+	if len(res) != 1 || res[0] != "BTC/USD:BTC-250328" {
+		t.Fatalf("futures symbol of index expected, got %v", res)
+	}
+	exg.AddWsChanRefs(chanKey, "BTC/USD:BTC@1h")
+	res = exg.indexCandleSymbols(chanKey, "BTC-USD", "1H")
+	if len(res) != 2 || res[0] != "BTC/USD:BTC" || res[1] != "BTC/USD:BTC-250328" {
+		t.Fatalf("all subscribed symbols of index expected, got %v", res)
+	}
+	if res = exg.indexCandleSymbols(chanKey, "SOL-USD", "1H"); len(res) != 1 || res[0] != "SOL-USD" {
+		t.Fatalf("instId expected without subscription, got %v", res)
+	}
+}
+
+type keeperOKX struct {
+	*OKX
+	positions []*banexg.Position
+}
+
+func (e *keeperOKX) FetchPositions(symbols []string, params map[string]interface{}) ([]*banexg.Position, *errs.Error) {
+	return e.positions, nil
+}
+
+func (e *keeperOKX) FetchBalance(params map[string]interface{}) (*banexg.Balances, *errs.Error) {
+	return &banexg.Balances{}, nil
+}
+
+func TestAccountKeeperPrivateState(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT-SWAP", "BTC/USDT:USDT", banexg.MarketLinear)
+	seedMarket(exg, "BTC-USD-SWAP", "BTC/USD:BTC", banexg.MarketInverse)
+	acc := &banexg.Account{
+		Name:         "acc1",
+		MarPositions: map[string][]*banexg.Position{},
+		MarBalances:  map[string]*banexg.Balances{},
+		Leverages:    map[string]int{},
+		Data:         map[string]interface{}{},
+		LockPos:      &deadlock.Mutex{},
+		LockBalance:  &deadlock.Mutex{},
+		LockLeverage: &deadlock.Mutex{},
+		LockData:     &deadlock.Mutex{},
+	}
+	exg.Accounts = map[string]*banexg.Account{"acc1": acc}
+	exg.DefAccName = "acc1"
+	client := &banexg.WsClient{AccName: "acc1", MarketType: wsPrivate}
+	msg := map[string]interface{}{"data": []interface{}{
+		map[string]interface{}{"instId": "BTC-USDT-SWAP", "instType": "SWAP", "posSide": "long", "pos": "1",
+			"avgPx": "100", "uTime": "10"},
+		map[string]interface{}{"instId": "BTC-USD-SWAP", "instType": "SWAP", "posSide": "long", "pos": "2",
+			"avgPx": "100", "uTime": "10"},
+	}}
+	exg.handleWsPositions(client, msg, map[string]interface{}{"instType": "ANY"})
+	stub := &keeperOKX{OKX: exg, positions: []*banexg.Position{
+		{Symbol: "BTC/USDT:USDT", Side: "long", Contracts: 1.5, EntryPrice: 100},
+	}}
+	keeper, err := banexg.NewAccountKeeper(stub, map[string]interface{}{banexg.ParamMarket: banexg.MarketLinear})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keeper.StateKey != wsPrivate {
+		t.Fatalf("expect state key %s, got %s", wsPrivate, keeper.StateKey)
+	}
+	evts, err := keeper.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evts) != 1 || evts[0].Key != "BTC/USDT:USDT#long" {
+		t.Fatalf("expect drift of linear position only, got %v", evts)
+	}
+	healed := acc.MarPositions[wsPrivate]
+	if len(healed) != 2 {
+		t.Fatalf("inverse position should be kept after heal, got %d", len(healed))
+	}
+	for _, p := range healed {
+		if p.Symbol == "BTC/USDT:USDT" && p.Contracts != 1.5 || p.Symbol == "BTC/USD:BTC" && p.Contracts != 2 {
+			t.Errorf("unexpected healed position: %+v", p)
+		}
+	}
+}
+
+func TestAlgoOrdersByAccount(t *testing.T) {
+	exg, err := New(nil)
+	if err != nil {
+		t.Fatalf("new okx: %v", err)
+	}
+	seedMarket(exg, "BTC-USDT-SWAP", "BTC/USDT:USDT", banexg.MarketLinear)
+	bizUrl := exg.GetHost(HostWsBusiness)
+	outs := make(map[string]chan *banexg.Order)
+	for _, name := range []string{"a", "b"} {
+		priv := &banexg.WsClient{AccName: name, URL: exg.GetHost(HostWsPrivate)}
+		create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+		outs[name] = banexg.GetWsOutChan(exg.Exchange, priv.Prefix("orders"), create, nil)
+	}
+	for _, name := range []string{"a", "b"} {
+		client := &banexg.WsClient{AccName: name, URL: bizUrl, MarketType: wsBusiness}
+		msg := map[string]interface{}{"data": []interface{}{
+			map[string]interface{}{"instId": "BTC-USDT-SWAP", "instType": "SWAP", "algoId": "algo_" + name,
+				"state": "live", "ordType": "trigger", "side": "buy", "sz": "1", "triggerPx": "100"},
+		}}
+		exg.handleWsAlgoOrders(client, msg, map[string]interface{}{"instType": "ANY"})
+	}
+	for _, name := range []string{"a", "b"} {
+		select {
+		case od := <-outs[name]:
+			if !strings.HasSuffix(od.ID, "algo_"+name) {
+				t.Errorf("account %s got order of other account: %s", name, od.ID)
+			}
+		default:
+			t.Errorf("account %s got no algo order", name)
+		}
+		if len(outs[name]) != 0 {
+			t.Errorf("account %s got extra orders", name)
+		}
+	}
+}
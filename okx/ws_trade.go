@@ -0,0 +1,69 @@
+package okx
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+// rest api: op of websocket trading api on the private channel
+var wsTradeOps = map[string]string{
+	MethodTradePostOrder:       "order",
+	MethodTradePostCancelOrder: "cancel-order",
+	MethodTradePostAmendOrder:  "amend-order",
+}
+
+/*
+requestTrade
+send the order request by websocket when ParamWsTrade/OptWsTrade is set, fallback to rest when the socket is down.
+the response of websocket is the same as rest: {code, msg, data}
+设置ParamWsTrade/OptWsTrade时通过websocket发送订单请求，连接不可用时回退到rest
+:see: https://www.okx.com/docs-v5/en/#order-book-trading-trade-ws-place-order
+*/
+func requestTrade[T any](e *OKX, api string, params map[string]interface{}, tryNum int) *banexg.ApiRes[T] {
+	if e.IsWsTrade(params) {
+		if op, ok := wsTradeOps[api]; ok {
+			res := wsRequestTrade[T](e, api, op, params)
+			if res.Error == nil || res.Error.Code != errs.CodeConnectFail {
+				return res
+			}
+			log.Warn("okx ws trade unavailable, use rest", zap.String("api", api), zap.String("err", res.Error.Short()))
+		}
+	}
+	return requestRetry[T](e, api, params, tryNum)
+}
+
+func wsRequestTrade[T any](e *OKX, api, op string, params map[string]interface{}) *banexg.ApiRes[T] {
+	args := utils.SafeParams(params)
+	ctx := banexg.ParamsCtx(args)
+	delete(args, banexg.ParamCtx)
+	delete(args, banexg.ParamNoCache)
+	accName := e.PopAccName(args)
+	res := &banexg.ApiRes[T]{HttpRes: &banexg.HttpRes{AccName: accName}}
+	if res.Error = e.CheckRiskyAllowed(e.Apis[api], accName); res.Error != nil {
+		return res
+	}
+	client, err := e.getAuthClient(map[string]interface{}{banexg.ParamAccount: accName})
+	if err != nil {
+		// login not finished, the order is not sent yet
+		res.Error = errs.NewFull(errs.CodeConnectFail, err, "okx ws trade login fail")
+		return res
+	}
+	id := banexg.NewWsReqID()
+	msg := map[string]interface{}{
+		"id":   id,
+		"op":   op,
+		"args": []map[string]interface{}{args},
+	}
+	rsp, err := client.Request(ctx, id, msg)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	res.Url = client.URL
+	res.Content = rsp.Text
+	decodeApiRes(res)
+	return res
+}
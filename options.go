@@ -0,0 +1,83 @@
+package banexg
+
+import (
+	"sort"
+
+	"github.com/banbox/banexg/errs"
+)
+
+const msecsPerDay = int64(86400000)
+
+/*
+FetchOptionChain
+list active options of underlying from loaded markets and fill greeks by FetchGreeks.
+options are filtered by UTC day of expiry when expiry > 0, the result is sorted by expiry, strike and type.
+从已加载的市场中列出标的的期权，并通过FetchGreeks填充希腊值。
+expiry>0时按到期日(UTC)过滤，结果按到期时间、行权价、类型排序
+*/
+func (e *Exchange) FetchOptionChain(underlying string, expiry int64, params map[string]interface{}) ([]*OptionChainItem, *errs.Error) {
+	exg := e.self()
+	_, err := exg.LoadMarkets(false, params)
+	if err != nil {
+		return nil, err
+	}
+	items := e.getOptionMarkets(underlying, expiry)
+	if len(items) == 0 {
+		return items, nil
+	}
+	symbols := make([]string, 0, len(items))
+	for _, it := range items {
+		symbols = append(symbols, it.Symbol)
+	}
+	greeks, err := exg.FetchGreeks(symbols, params)
+	if err != nil {
+		if err.Code == errs.CodeNotImplement {
+			return items, nil
+		}
+		return nil, err
+	}
+	greekMap := make(map[string]*Greeks, len(greeks))
+	for _, g := range greeks {
+		greekMap[g.Symbol] = g
+	}
+	for _, it := range items {
+		it.Greeks = greekMap[it.Symbol]
+	}
+	return items, nil
+}
+
+func (e *Exchange) getOptionMarkets(underlying string, expiry int64) []*OptionChainItem {
+	expDay := expiry / msecsPerDay
+	items := make([]*OptionChainItem, 0)
+	e.MarketsLock.Lock()
+	for _, mar := range e.Markets {
+		if !mar.Option || !mar.Active || mar.Base != underlying {
+			continue
+		}
+		if expiry > 0 && mar.Expiry/msecsPerDay != expDay {
+			continue
+		}
+		items = append(items, &OptionChainItem{
+			Symbol:     mar.Symbol,
+			Underlying: mar.Base,
+			Strike:     mar.Strike,
+			OptionType: mar.OptionType,
+			Expiry:     mar.Expiry,
+		})
+	}
+	e.MarketsLock.Unlock()
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Expiry != b.Expiry {
+			return a.Expiry < b.Expiry
+		}
+		if a.Strike != b.Strike {
+			return a.Strike < b.Strike
+		}
+		if a.OptionType != b.OptionType {
+			return a.OptionType < b.OptionType
+		}
+		return a.Symbol < b.Symbol
+	})
+	return items
+}
@@ -0,0 +1,559 @@
+package banexg
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"github.com/sasha-s/go-deadlock"
+	"go.uber.org/zap"
+)
+
+// FuncOnOrderChange called when an order of OrderTracker is added or updated, prev is nil for new orders
+type FuncOnOrderChange = func(od, prev *Order)
+
+/*
+OrderTracker
+local cache of orders of one account, keyed by ID and ClientOrderID. WatchOrders (or WatchMyTrades merged by
+trades when WatchOrders is not implemented) keeps it updated, and FetchOpenOrders+FetchOrder reconcile it on
+start, after the private ws connection of the account is reconnected, and every ReconcileIntv.
+Stale updates (older timestamp, less filled, or reopen of a done order) are ignored, so ws and rest results can
+be applied in any order. The tracker reads a private copy of the order channel (AddWsChanTap), so other
+consumers of WatchOrders still receive every update.
+单个账户的本地订单缓存，按ID和ClientOrderID索引。通过WatchOrders(未实现时使用WatchMyTrades按成交合并)更新，
+并在启动时、该账户私有ws重连后、每隔ReconcileIntv使用FetchOpenOrders+FetchOrder与rest对账。
+过期的更新(时间戳更早、成交量更少、已完成订单重新打开)会被忽略，故ws和rest结果可按任意顺序应用。
+tracker读取订单通道的私有副本(AddWsChanTap)，WatchOrders的其他使用者仍能收到全部更新
+*/
+type OrderTracker struct {
+	Exg            BanExchange
+	AccName        string
+	Params         map[string]interface{} // extra params for watch and fetch, e.g. ParamMarket
+	Symbols        []string               // symbols to fetch open orders on reconcile, empty for all symbols
+	OnChange       FuncOnOrderChange      // called in sequence, never concurrently
+	ReconcileDelay time.Duration          // wait after reconnect before reconcile, subscriptions are being restored
+	ReconcileIntv  time.Duration          // reconcile periodically when > 0
+	DoneKeep       time.Duration          // done orders older than this are removed on reconcile, 0 to keep all
+
+	orders    map[string]*Order // key: order id
+	cliIDs    map[string]string // key: client order id, value: order id
+	lock      deadlock.Mutex    // for orders, cliIDs
+	cbLock    deadlock.Mutex    // calling OnChange
+	reconLock deadlock.Mutex    // one reconcile at a time
+	stop      chan struct{}
+	chanKey   string      // key of the out chan of WatchOrders or WatchMyTrades tapped by the tracker
+	pending   atomic.Bool // a delayed reconcile is scheduled
+	hookKey   string
+}
+
+// tracker retries to watch after the order channel closed, wait is doubled until max
+const (
+	trackerRetryMin = time.Second
+	trackerRetryMax = 30 * time.Second
+	trackerChanCap  = 1000
+)
+
+/*
+NewOrderTracker
+create an order tracker for the account of ParamAccount in params (default account if empty),
+other params are passed to WatchOrders/FetchOpenOrders/FetchOrder. Call Start to begin tracking.
+为params中ParamAccount指定的账户(为空时默认账户)创建订单跟踪器，其他参数会传给WatchOrders/FetchOpenOrders/FetchOrder。
+调用Start开始跟踪
+*/
+func NewOrderTracker(exg BanExchange, params map[string]interface{}) (*OrderTracker, *errs.Error) {
+	args := utils.SafeParams(params)
+	acc, err := exg.GetAccount(utils.PopMapVal(args, ParamAccount, ""))
+	if err != nil {
+		return nil, err
+	}
+	return &OrderTracker{
+		Exg:            exg,
+		AccName:        acc.Name,
+		Params:         args,
+		ReconcileDelay: 2 * time.Second,
+		DoneKeep:       time.Hour,
+		orders:         make(map[string]*Order),
+		cliIDs:         make(map[string]string),
+	}, nil
+}
+
+/*
+Start
+subscribe order updates, reconcile with rest once, then keep tracking in background until Stop.
+订阅订单更新，与rest对账一次，然后在后台持续跟踪直到Stop
+*/
+func (t *OrderTracker) Start() *errs.Error {
+	t.lock.Lock()
+	if t.stop != nil {
+		t.lock.Unlock()
+		return nil
+	}
+	stop := make(chan struct{})
+	t.stop = stop
+	t.lock.Unlock()
+	t.hookKey = fmt.Sprintf("odTracker_%p", t)
+	odChan, tradeChan, err := t.subscribe()
+	if err != nil {
+		t.Stop()
+		return err
+	}
+	t.Exg.GetExg().SetWsReConHook(t.hookKey, func(client *WsClient, connID int) *errs.Error {
+		if client != nil && client.AccName == t.AccName {
+			t.scheduleReconcile(stop)
+		}
+		return nil
+	})
+	// subscribe first, so no update is missed between the snapshot and the stream
+	if err = t.Reconcile(); err != nil {
+		t.Stop()
+		return err
+	}
+	go t.loop(stop, odChan, tradeChan)
+	return nil
+}
+
+// Stop stop tracking, the cached orders can still be queried
+func (t *OrderTracker) Stop() {
+	t.lock.Lock()
+	stop := t.stop
+	t.stop = nil
+	t.lock.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	if t.hookKey != "" {
+		t.Exg.GetExg().SetWsReConHook(t.hookKey, nil)
+	}
+	t.lock.Lock()
+	chanKey := t.chanKey
+	t.chanKey = ""
+	t.lock.Unlock()
+	if chanKey != "" {
+		t.Exg.GetExg().DelWsChanTap(chanKey, t.hookKey)
+	}
+}
+
+func (t *OrderTracker) args() map[string]interface{} {
+	args := utils.SafeParams(t.Params)
+	args[ParamAccount] = t.AccName
+	return args
+}
+
+// subscribe watch with the ref key of the tracker, and read a private tap of the out chan
+func (t *OrderTracker) subscribe() (chan *Order, chan *MyTrade, *errs.Error) {
+	args := t.args()
+	args[ParamRefKey] = t.hookKey
+	odChan, err := t.Exg.WatchOrders(args)
+	if err == nil {
+		tap, err := tapOutChan(t, odChan)
+		return tap, nil, err
+	}
+	if err.Code != errs.CodeNotImplement {
+		return nil, nil, err
+	}
+	tradeChan, err := t.Exg.WatchMyTrades(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	tap, err := tapOutChan(t, tradeChan)
+	return nil, tap, err
+}
+
+/*
+tapOutChan
+register a tap of the out chan returned by watch, then release the ref of the tracker on the out chan at once:
+the out chan is kept only for other consumers, and closed if there is none.
+为watch返回的输出通道注册私有副本，然后立即释放tracker对输出通道的引用：输出通道仅为其他使用者保留，无使用者时关闭
+*/
+func tapOutChan[T any](t *OrderTracker, out chan T) (chan T, *errs.Error) {
+	exg := t.Exg.GetExg()
+	chanKey := exg.GetWsChanKey(out)
+	if chanKey == "" {
+		return nil, errs.NewMsg(errs.CodeRunTime, "out chan of order tracker not found: %s", t.AccName)
+	}
+	tap := AddWsChanTap[T](exg, chanKey, t.hookKey, trackerChanCap)
+	exg.DelWsChanRefs(chanKey, t.hookKey)
+	t.lock.Lock()
+	stopped := t.stop == nil
+	if !stopped {
+		t.chanKey = chanKey
+	}
+	t.lock.Unlock()
+	if stopped {
+		// stopped while watching
+		exg.DelWsChanTap(chanKey, t.hookKey)
+	}
+	return tap, nil
+}
+
+func (t *OrderTracker) loop(stop chan struct{}, odChan chan *Order, tradeChan chan *MyTrade) {
+	var tick <-chan time.Time
+	if t.ReconcileIntv > 0 {
+		ticker := time.NewTicker(t.ReconcileIntv)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		var closed bool
+		select {
+		case <-stop:
+			return
+		case od, ok := <-odChan:
+			if ok {
+				t.Apply(od)
+			} else {
+				closed = true
+			}
+		case trade, ok := <-tradeChan:
+			if ok {
+				t.ApplyTrade(trade)
+			} else {
+				closed = true
+			}
+		case <-tick:
+			if err := t.Reconcile(); err != nil {
+				log.Warn("reconcile orders fail", zap.String("acc", t.AccName), zap.Error(err))
+			}
+		}
+		if closed {
+			odChan, tradeChan = t.resubscribe(stop)
+			if odChan == nil && tradeChan == nil {
+				return
+			}
+		}
+	}
+}
+
+// resubscribe watch again after the channel closed (ws client closed), and reconcile for the missed updates
+func (t *OrderTracker) resubscribe(stop chan struct{}) (chan *Order, chan *MyTrade) {
+	wait := trackerRetryMin
+	for {
+		select {
+		case <-stop:
+			return nil, nil
+		case <-time.After(wait):
+		}
+		odChan, tradeChan, err := t.subscribe()
+		if err == nil {
+			if err = t.Reconcile(); err != nil {
+				log.Warn("reconcile orders fail", zap.String("acc", t.AccName), zap.Error(err))
+			}
+			return odChan, tradeChan
+		}
+		log.Warn("order tracker watch fail", zap.String("acc", t.AccName), zap.Error(err))
+		wait = min(wait*2, trackerRetryMax)
+	}
+}
+
+func (t *OrderTracker) scheduleReconcile(stop chan struct{}) {
+	if !t.pending.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		select {
+		case <-stop:
+			t.pending.Store(false)
+			return
+		case <-time.After(t.ReconcileDelay):
+		}
+		t.pending.Store(false)
+		if err := t.Reconcile(); err != nil {
+			log.Warn("reconcile orders after reconnect fail", zap.String("acc", t.AccName), zap.Error(err))
+		}
+	}()
+}
+
+/*
+Reconcile
+fetch open orders by rest and apply them; cached open orders missing from the result are fetched by FetchOrder
+to get the final state. Done orders older than DoneKeep are removed.
+通过rest获取挂单并应用；缓存中未出现在结果里的挂单通过FetchOrder获取最终状态。移除早于DoneKeep的已完成订单
+*/
+func (t *OrderTracker) Reconcile() *errs.Error {
+	t.reconLock.Lock()
+	defer t.reconLock.Unlock()
+	symbols := t.Symbols
+	if len(symbols) == 0 {
+		symbols = []string{""}
+	}
+	openIds := make(map[string]bool)
+	for _, symbol := range symbols {
+		list, err := t.Exg.FetchOpenOrders(symbol, 0, 0, t.args())
+		if err != nil {
+			return err
+		}
+		for _, od := range list {
+			openIds[od.ID] = true
+			t.Apply(od)
+		}
+	}
+	scope := BuildSymbolSet(t.Symbols)
+	for _, od := range t.OpenOrders("") {
+		if openIds[od.ID] {
+			continue
+		}
+		if _, ok := scope[od.Symbol]; len(scope) > 0 && !ok {
+			continue
+		}
+		res, err := t.Exg.FetchOrder(od.Symbol, od.ID, t.args())
+		if err != nil {
+			log.Warn("fetch missing open order fail", zap.String("acc", t.AccName), zap.String("id", od.ID),
+				zap.String("symbol", od.Symbol), zap.Error(err))
+			continue
+		}
+		t.Apply(res)
+	}
+	if t.DoneKeep > 0 {
+		t.Prune(t.Exg.MilliSeconds() - t.DoneKeep.Milliseconds())
+	}
+	return nil
+}
+
+/*
+Apply
+apply an order snapshot from ws, rest, or the result of CreateOrder/EditOrder/CancelOrder.
+return false if it's stale or nothing changed. zero fields are filled from the cached order.
+应用来自ws、rest或CreateOrder/EditOrder/CancelOrder结果的订单快照。过期或无变化时返回false，零值字段使用缓存订单填充
+*/
+func (t *OrderTracker) Apply(od *Order) bool {
+	if od == nil || od.ID == "" {
+		return false
+	}
+	t.lock.Lock()
+	prev := t.orders[od.ID]
+	if prev != nil && !isOrderNewer(prev, od) {
+		t.lock.Unlock()
+		return false
+	}
+	cur := mergeOrder(prev, od)
+	t.setOrder(cur)
+	t.lock.Unlock()
+	t.fireChange(cur, prev)
+	return true
+}
+
+/*
+ApplyTrade
+merge a trade of WatchMyTrades into the cached order, duplicated trades and trades already covered by the
+filled of the order (e.g. applied by a snapshot from rest) are ignored. Filled never exceeds Amount.
+将WatchMyTrades的成交合并到缓存订单，重复的成交和已被订单成交量覆盖的成交(如已应用rest快照)会被忽略。成交量不超过订单数量
+*/
+func (t *OrderTracker) ApplyTrade(trade *MyTrade) bool {
+	if trade == nil || trade.Order == "" {
+		return false
+	}
+	t.lock.Lock()
+	prev := t.orders[trade.Order]
+	var cur *Order
+	if prev == nil {
+		od, err := MergeMyTrades([]*MyTrade{trade})
+		if err != nil || od == nil {
+			t.lock.Unlock()
+			return false
+		}
+		od.Status = trade.State
+		cur = od
+	} else {
+		for _, tr := range prev.Trades {
+			if tr.ID != "" && tr.ID == trade.ID {
+				t.lock.Unlock()
+				return false
+			}
+		}
+		// the cumulative filled of the trade is known: skip if the order already covers it
+		if trade.Filled > 0 && trade.Filled <= prev.Filled {
+			t.lock.Unlock()
+			return false
+		}
+		// without cumulative filled, a fully filled order can't take more trades
+		if trade.Filled <= 0 && prev.Amount > 0 && prev.Filled >= prev.Amount {
+			t.lock.Unlock()
+			return false
+		}
+		od := *prev
+		od.Trades = append(prev.Trades[:len(prev.Trades):len(prev.Trades)], &trade.Trade)
+		if trade.Filled > 0 {
+			od.Filled = trade.Filled
+		} else {
+			od.Filled = prev.Filled + trade.Amount
+		}
+		if od.Amount > 0 && od.Filled > od.Amount {
+			od.Filled = od.Amount
+		}
+		if trade.Average > 0 {
+			od.Average = trade.Average
+		}
+		od.Cost += trade.Cost
+		if trade.State != "" && !IsOrderDone(prev.Status) {
+			od.Status = trade.State
+		}
+		od.LastTradeTimestamp = trade.Timestamp
+		od.LastUpdateTimestamp = max(prev.LastUpdateTimestamp, trade.Timestamp)
+		if trade.Fee != nil {
+			fee := Fee{}
+			if prev.Fee != nil {
+				fee = *prev.Fee
+			}
+			fee.Cost += trade.Fee.Cost
+			fee.QuoteCost += trade.Fee.QuoteCost
+			fee.Currency = trade.Fee.Currency
+			fee.IsMaker = trade.Fee.IsMaker
+			od.Fee = &fee
+		}
+		cur = &od
+	}
+	t.setOrder(cur)
+	t.lock.Unlock()
+	t.fireChange(cur, prev)
+	return true
+}
+
+// setOrder should be called with lock held
+func (t *OrderTracker) setOrder(od *Order) {
+	t.orders[od.ID] = od
+	if od.ClientOrderID != "" {
+		t.cliIDs[od.ClientOrderID] = od.ID
+	}
+}
+
+func (t *OrderTracker) fireChange(od, prev *Order) {
+	if t.OnChange == nil {
+		return
+	}
+	t.cbLock.Lock()
+	defer t.cbLock.Unlock()
+	t.OnChange(copyOrder(od), copyOrder(prev))
+}
+
+// Get return a copy of the cached order, nil if not found
+func (t *OrderTracker) Get(id string) *Order {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return copyOrder(t.orders[id])
+}
+
+// GetByClientID return a copy of the latest order with clientOrderId, nil if not found
+func (t *OrderTracker) GetByClientID(clientID string) *Order {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	id, ok := t.cliIDs[clientID]
+	if !ok {
+		return nil
+	}
+	return copyOrder(t.orders[id])
+}
+
+// OpenOrders return copies of cached orders not done of symbol (all symbols if empty), sorted by time
+func (t *OrderTracker) OpenOrders(symbol string) []*Order {
+	t.lock.Lock()
+	res := make([]*Order, 0, len(t.orders))
+	for _, od := range t.orders {
+		if IsOrderDone(od.Status) || symbol != "" && od.Symbol != symbol {
+			continue
+		}
+		res = append(res, copyOrder(od))
+	}
+	t.lock.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Timestamp < res[j].Timestamp
+	})
+	return res
+}
+
+// Prune remove done orders updated before the timestamp
+func (t *OrderTracker) Prune(before int64) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	num := 0
+	for id, od := range t.orders {
+		if !IsOrderDone(od.Status) || max(od.LastUpdateTimestamp, od.Timestamp) >= before {
+			continue
+		}
+		delete(t.orders, id)
+		if od.ClientOrderID != "" && t.cliIDs[od.ClientOrderID] == id {
+			delete(t.cliIDs, od.ClientOrderID)
+		}
+		num += 1
+	}
+	return num
+}
+
+// isOrderNewer whether od should replace old: done orders are not reopened, filled and update time never go back
+func isOrderNewer(old, od *Order) bool {
+	if IsOrderDone(old.Status) && !IsOrderDone(od.Status) {
+		return false
+	}
+	if od.Filled < old.Filled {
+		return false
+	}
+	if od.LastUpdateTimestamp > 0 && od.LastUpdateTimestamp < old.LastUpdateTimestamp {
+		return false
+	}
+	return od.Status != old.Status || od.Filled != old.Filled || od.Amount != old.Amount || od.Price != old.Price ||
+		od.LastUpdateTimestamp != old.LastUpdateTimestamp
+}
+
+// mergeOrder copy od, fill zero fields from old, since ws updates may omit them
+func mergeOrder(old, od *Order) *Order {
+	res := *od
+	if old == nil {
+		return &res
+	}
+	if res.ClientOrderID == "" {
+		res.ClientOrderID = old.ClientOrderID
+	}
+	if res.Symbol == "" {
+		res.Symbol = old.Symbol
+	}
+	if res.Type == "" {
+		res.Type = old.Type
+	}
+	if res.Side == "" {
+		res.Side = old.Side
+	}
+	if res.PositionSide == "" {
+		res.PositionSide = old.PositionSide
+	}
+	if res.TimeInForce == "" {
+		res.TimeInForce = old.TimeInForce
+	}
+	if res.Timestamp == 0 {
+		res.Timestamp = old.Timestamp
+		res.Datetime = old.Datetime
+	}
+	if res.Price == 0 {
+		res.Price = old.Price
+	}
+	if res.Amount == 0 {
+		res.Amount = old.Amount
+	}
+	if res.Average == 0 {
+		res.Average = old.Average
+	}
+	if res.TriggerPrice == 0 {
+		res.TriggerPrice = old.TriggerPrice
+	}
+	if res.LastUpdateTimestamp == 0 {
+		res.LastUpdateTimestamp = old.LastUpdateTimestamp
+	}
+	if len(res.Trades) == 0 {
+		res.Trades = old.Trades
+	}
+	if res.Fee == nil {
+		res.Fee = old.Fee
+	}
+	return &res
+}
+
+func copyOrder(od *Order) *Order {
+	if od == nil {
+		return nil
+	}
+	res := *od
+	return &res
+}
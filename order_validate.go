@@ -0,0 +1,243 @@
+package banexg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+const (
+	VioInactive   = "inactive"
+	VioSession    = "session"
+	VioPosSide    = "positionSide"
+	VioPricePrec  = "pricePrecision"
+	VioPriceMin   = "priceMin"
+	VioPriceMax   = "priceMax"
+	VioAmountPrec = "amountPrecision"
+	VioAmountMin  = "amountMin"
+	VioAmountMax  = "amountMax"
+	VioMarketMax  = "marketAmountMax"
+	VioCostMin    = "costMin"
+	VioCostMax    = "costMax"
+)
+
+// OrderViolation a rule of the market broken by an order
+type OrderViolation struct {
+	Rule  string  // Vio*
+	Field string  // symbol/time/positionSide/price/amount/cost
+	Value float64 // value of the order
+	Limit float64 // the limit broken, or the value after rounding for precision rules
+	Fixed bool    // adjusted by ParamAdjust, the order satisfies this rule now
+}
+
+func (v *OrderViolation) String() string {
+	text := fmt.Sprintf("%s: %s %v, limit %v", v.Rule, v.Field, v.Value, v.Limit)
+	if v.Fixed {
+		text += " (fixed)"
+	}
+	return text
+}
+
+// OrderCheck result of ValidateOrder
+type OrderCheck struct {
+	Symbol     string
+	Amount     float64 // amount after adjusting, same as input when ParamAdjust is false
+	Price      float64 // price after adjusting
+	Violations []*OrderViolation
+}
+
+// Valid whether all violations are fixed
+func (c *OrderCheck) Valid() bool {
+	for _, v := range c.Violations {
+		if !v.Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// Error return CodeParamInvalid with all unfixed violations, nil if valid
+func (c *OrderCheck) Error() *errs.Error {
+	var texts []string
+	for _, v := range c.Violations {
+		if !v.Fixed {
+			texts = append(texts, v.String())
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	return errs.NewMsg(errs.CodeParamInvalid, "%s order invalid, %s", c.Symbol, strings.Join(texts, "; "))
+}
+
+/*
+ValidateOrder
+check an order against the rules of market before sending it: Market.Active, trading sessions (DayTimes/NightTimes,
+sessions starting on the weekend of ExgInfo.TimeLoc are closed unless NoHoliday), position side vs position mode,
+Precision, Limits of price, amount, market order amount and cost. Holidays are not checked.
+price is only used to compute the cost for market orders, pass 0 to skip the cost check.
+params:
+  - ParamPositionSide: position side of order, checked for contracts when ParamHedged is present
+  - ParamHedged: bool, whether the account is in hedge mode (long/short), one-way mode requires empty or both
+  - ParamAdjust: bool, round amount/price to precision, clip them into max limits and band of price.
+    amount is never increased to reach the minimum limits.
+
+下单前根据市场规则检查订单：是否可交易、交易时段(NoHoliday为false时TimeLoc时区周末开始的时段休市，不检查节假日)、
+持仓方向与持仓模式、精度、价格/数量/市价单数量/金额限制。价格仅用于计算市价单的金额，传0跳过金额检查。ParamAdjust为true时按精度取整，并裁剪到最大值和价格区间内，
+不会为满足最小值而增加数量。
+*/
+func (e *Exchange) ValidateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*OrderCheck, *errs.Error) {
+	if side != OdSideBuy && side != OdSideSell {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid order side: %s", side)
+	}
+	if amount <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "amount must > 0")
+	}
+	market, err := e.GetMarket(symbol)
+	if err != nil {
+		return nil, err
+	}
+	args := utils.SafeParams(params)
+	adjust := utils.PopMapVal(args, ParamAdjust, false)
+	res := &OrderCheck{Symbol: market.Symbol, Amount: amount, Price: price}
+	add := func(rule, field string, value, limit float64, fixed bool) {
+		res.Violations = append(res.Violations, &OrderViolation{
+			Rule: rule, Field: field, Value: value, Limit: limit, Fixed: fixed,
+		})
+	}
+	if !market.Active {
+		add(VioInactive, "symbol", 0, 0, false)
+	}
+	if times := market.GetTradeTimes(); len(times) > 0 {
+		var loc *time.Location
+		if !e.NoHoliday {
+			loc = e.TimeLoc
+		}
+		now := e.MilliSeconds()
+		if !inTradeTimes(times, now, loc) {
+			add(VioSession, "time", float64(now), 0, false)
+		}
+	}
+	if hedged, ok := args[ParamHedged].(bool); ok && market.Contract {
+		posSide := strings.ToLower(utils.GetMapVal(args, ParamPositionSide, ""))
+		dual := posSide == PosSideLong || posSide == PosSideShort
+		if hedged != dual {
+			add(VioPosSide, "positionSide", 0, 0, false)
+		}
+	}
+	isMarket := odType == OdTypeMarket
+	lim := market.Limits
+	if lim == nil {
+		lim = &MarketLimits{}
+	}
+	if price > 0 && !isMarket {
+		if market.Precision != nil {
+			val, err := e.PrecPrice(market, res.Price)
+			if err != nil {
+				return nil, err
+			}
+			if val != res.Price {
+				add(VioPricePrec, "price", res.Price, val, adjust)
+				if adjust {
+					res.Price = val
+				}
+			}
+		}
+		if r := lim.Price; r != nil {
+			if r.Min > 0 && res.Price < r.Min {
+				add(VioPriceMin, "price", res.Price, r.Min, adjust)
+				if adjust {
+					res.Price = r.Min
+				}
+			} else if r.Max > 0 && res.Price > r.Max {
+				add(VioPriceMax, "price", res.Price, r.Max, adjust)
+				if adjust {
+					res.Price = r.Max
+				}
+			}
+		}
+	}
+	if r := lim.Amount; r != nil && r.Max > 0 && res.Amount > r.Max {
+		add(VioAmountMax, "amount", res.Amount, r.Max, adjust)
+		if adjust {
+			res.Amount = r.Max
+		}
+	}
+	if r := lim.Market; isMarket && r != nil && r.Max > 0 && res.Amount > r.Max {
+		add(VioMarketMax, "amount", res.Amount, r.Max, adjust)
+		if adjust {
+			res.Amount = r.Max
+		}
+	}
+	// cost is in quote currency, not comparable for inverse contracts
+	costRate := 0.0
+	if res.Price > 0 && !market.Inverse {
+		costRate = res.Price
+		if market.Contract && market.ContractSize > 0 {
+			costRate *= market.ContractSize
+		}
+	}
+	if r := lim.Cost; r != nil && costRate > 0 && r.Max > 0 && res.Amount*costRate > r.Max {
+		add(VioCostMax, "cost", res.Amount*costRate, r.Max, adjust)
+		if adjust {
+			res.Amount = r.Max / costRate
+		}
+	}
+	if market.Precision != nil {
+		val, err := e.PrecAmount(market, res.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if val != res.Amount {
+			// rounding down after clipping to max is not a violation of the order itself
+			if res.Amount == amount {
+				add(VioAmountPrec, "amount", res.Amount, val, adjust)
+			}
+			if adjust {
+				res.Amount = val
+			}
+		}
+	}
+	if r := lim.Amount; r != nil && r.Min > 0 && res.Amount < r.Min {
+		add(VioAmountMin, "amount", res.Amount, r.Min, false)
+	}
+	if r := lim.Cost; r != nil && costRate > 0 && r.Min > 0 && res.Amount*costRate < r.Min {
+		add(VioCostMin, "cost", res.Amount*costRate, r.Min, false)
+	}
+	return res, nil
+}
+
+/*
+inTradeTimes
+whether stamp is in any range of times (UTC time of day, ranges may end in the next day).
+sessions starting on saturday or sunday of loc are closed, e.g. friday night session of china spills into saturday
+local time and is open, while sunday night is closed. pass nil loc to skip the weekday check.
+stamp是否在任一交易时段内(UTC日内时间，可跨日)。loc不为空时，在loc时区周六或周日开始的时段视为休市
+*/
+func inTradeTimes(times [][2]int64, stamp int64, loc *time.Location) bool {
+	dayMSecs := int64(utils.SecsDay) * 1000
+	msecs := stamp % dayMSecs
+	dayStart := stamp - msecs
+	for _, rg := range times {
+		var start int64
+		if msecs >= rg[0] && msecs < rg[1] {
+			start = dayStart + rg[0]
+		} else if msecs+dayMSecs >= rg[0] && msecs+dayMSecs < rg[1] {
+			// the session started yesterday
+			start = dayStart - dayMSecs + rg[0]
+		} else {
+			continue
+		}
+		if loc != nil {
+			weekday := time.UnixMilli(start).In(loc).Weekday()
+			if weekday == time.Saturday || weekday == time.Sunday {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
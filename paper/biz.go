@@ -0,0 +1,181 @@
+package paper
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+)
+
+/*
+Market data apis are forwarded to Src, trading apis are simulated in biz_order.go
+行情接口转发到Src，交易接口在biz_order.go中模拟
+*/
+
+func (e *Paper) LoadMarkets(reload bool, params map[string]interface{}) (banexg.MarketMap, *errs.Error) {
+	markets, err := e.Src.LoadMarkets(reload, params)
+	if err != nil {
+		return nil, err
+	}
+	e.syncMarkets()
+	return markets, nil
+}
+
+// syncMarkets share the markets and currencies of Src
+func (e *Paper) syncMarkets() {
+	src := e.Src.GetExg()
+	src.MarketsLock.Lock()
+	markets := src.Markets
+	src.MarketsLock.Unlock()
+	src.MarketsByIdLock.Lock()
+	marketsById := src.MarketsById
+	src.MarketsByIdLock.Unlock()
+	e.MarketsLock.Lock()
+	e.Markets = markets
+	e.MarketsLock.Unlock()
+	e.MarketsByIdLock.Lock()
+	e.MarketsById = marketsById
+	e.MarketsByIdLock.Unlock()
+	src.CurrByCodeLock.Lock()
+	e.CurrByCodeLock.Lock()
+	e.CurrenciesByCode = src.CurrenciesByCode
+	e.CurrByCodeLock.Unlock()
+	src.CurrByCodeLock.Unlock()
+	src.CurrByIdLock.Lock()
+	e.CurrByIdLock.Lock()
+	e.CurrenciesById = src.CurrenciesById
+	e.CurrByIdLock.Unlock()
+	src.CurrByIdLock.Unlock()
+}
+
+func (e *Paper) MapMarket(rawID string, year int) (*banexg.Market, *errs.Error) {
+	return e.Src.MapMarket(rawID, year)
+}
+
+func (e *Paper) FetchTime(params map[string]interface{}) (int64, *errs.Error) {
+	return e.Src.FetchTime(params)
+}
+
+func (e *Paper) FetchTicker(symbol string, params map[string]interface{}) (*banexg.Ticker, *errs.Error) {
+	return e.Src.FetchTicker(symbol, params)
+}
+
+func (e *Paper) FetchTickers(symbols []string, params map[string]interface{}) ([]*banexg.Ticker, *errs.Error) {
+	return e.Src.FetchTickers(symbols, params)
+}
+
+func (e *Paper) FetchTickerPrice(symbol string, params map[string]interface{}) (map[string]float64, *errs.Error) {
+	return e.Src.FetchTickerPrice(symbol, params)
+}
+
+func (e *Paper) LoadLeverageBrackets(reload bool, params map[string]interface{}) *errs.Error {
+	return e.Src.LoadLeverageBrackets(reload, params)
+}
+
+func (e *Paper) InitLeverageBrackets() *errs.Error {
+	return e.Src.InitLeverageBrackets()
+}
+
+func (e *Paper) CalcMaintMargin(symbol string, cost float64) (float64, *errs.Error) {
+	return e.Src.CalcMaintMargin(symbol, cost)
+}
+
+func (e *Paper) FetchOHLCV(symbol, timeframe string, since int64, limit int, params map[string]interface{}) ([]*banexg.Kline, *errs.Error) {
+	return e.Src.FetchOHLCV(symbol, timeframe, since, limit, params)
+}
+
+func (e *Paper) FetchOHLCVRange(symbol, timeframe string, since, until int64, params map[string]interface{}) ([]*banexg.Kline, *errs.Error) {
+	return e.Src.FetchOHLCVRange(symbol, timeframe, since, until, params)
+}
+
+func (e *Paper) FetchOrderBook(symbol string, limit int, params map[string]interface{}) (*banexg.OrderBook, *errs.Error) {
+	return e.Src.FetchOrderBook(symbol, limit, params)
+}
+
+func (e *Paper) FetchTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Trade, *errs.Error) {
+	return e.Src.FetchTrades(symbol, since, limit, params)
+}
+
+func (e *Paper) FetchLastPrices(symbols []string, params map[string]interface{}) ([]*banexg.LastPrice, *errs.Error) {
+	return e.Src.FetchLastPrices(symbols, params)
+}
+
+func (e *Paper) FetchFundingRate(symbol string, params map[string]interface{}) (*banexg.FundingRateCur, *errs.Error) {
+	return e.Src.FetchFundingRate(symbol, params)
+}
+
+func (e *Paper) FetchFundingRates(symbols []string, params map[string]interface{}) ([]*banexg.FundingRateCur, *errs.Error) {
+	return e.Src.FetchFundingRates(symbols, params)
+}
+
+func (e *Paper) FetchFundingRateHistory(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.FundingRate, *errs.Error) {
+	return e.Src.FetchFundingRateHistory(symbol, since, limit, params)
+}
+
+func (e *Paper) SetFees(fees map[string]map[string]float64) {
+	e.Src.SetFees(fees)
+}
+
+func (e *Paper) CalculateFee(symbol, odType, side string, amount float64, price float64, isMaker bool,
+	params map[string]interface{}) (*banexg.Fee, *errs.Error) {
+	return e.Src.CalculateFee(symbol, odType, side, amount, price, isMaker, params)
+}
+
+func (e *Paper) SetMarketType(marketType, contractType string) *errs.Error {
+	err := e.Src.SetMarketType(marketType, contractType)
+	if err != nil {
+		return err
+	}
+	return e.Exchange.SetMarketType(marketType, contractType)
+}
+
+/*
+SetLeverage
+set leverage of symbol, which is used for margin of orders created later
+设置品种杠杆，用于之后创建订单的保证金计算
+*/
+func (e *Paper) SetLeverage(leverage float64, symbol string, params map[string]interface{}) (map[string]interface{}, *errs.Error) {
+	market, err := e.GetMarket(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if !market.Contract {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "SetLeverage only support contract markets")
+	}
+	lever := int(leverage)
+	if lever < 1 {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "leverage must >= 1")
+	}
+	if lim := market.Limits; lim != nil && lim.Leverage != nil && lim.Leverage.Max > 0 && leverage > lim.Leverage.Max {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "leverage must <= %v", lim.Leverage.Max)
+	}
+	e.lock.Lock()
+	e.leverages[market.Symbol] = lever
+	if pos, ok := e.positions[market.Symbol]; ok {
+		pos.Leverage = lever
+	}
+	e.lock.Unlock()
+	return map[string]interface{}{"symbol": market.Symbol, "leverage": lever}, nil
+}
+
+func (e *Paper) GetLeverage(symbol string, notional float64, account string) (float64, float64) {
+	_, maxVal := e.Src.GetLeverage(symbol, notional, account)
+	e.lock.Lock()
+	lever := e.getLeverage(symbol)
+	e.lock.Unlock()
+	return float64(lever), maxVal
+}
+
+func (e *Paper) getLeverage(symbol string) int {
+	if lever, ok := e.leverages[symbol]; ok {
+		return lever
+	}
+	return e.defLever
+}
+
+func (e *Paper) Close() *errs.Error {
+	err := e.Src.Close()
+	err2 := e.Exchange.Close()
+	if err != nil {
+		return err
+	}
+	return err2
+}
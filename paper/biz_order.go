@@ -0,0 +1,417 @@
+package paper
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+/*
+CreateOrder
+create a simulated order. The order is matched immediately against the order book of Src (ws book if watched,
+otherwise FetchOrderBook), the remaining part of limit orders rests until a trade or order book of Src crosses it.
+Trigger orders (ParamTriggerPrice/ParamStopLossPrice/ParamTakeProfitPrice) wait until the price crosses trigger price.
+创建模拟订单。订单立即与Src的订单簿撮合(已订阅时使用ws订单簿，否则FetchOrderBook)，
+限价单剩余部分挂单，直到Src的成交或订单簿穿过其价格。条件单等待价格穿过触发价
+*/
+func (e *Paper) CreateOrder(symbol, odType, side string, amount, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	od, err := e.newSimOrder(symbol, odType, side, amount, price, args)
+	if err != nil {
+		return nil, err
+	}
+	var levels []level
+	var refPrice float64
+	if od.trigger {
+		refPrice, err = e.lastPrice(od.market)
+		if err != nil {
+			return nil, err
+		}
+		od.trigUp = od.TriggerPrice > refPrice
+	} else {
+		levels, err = e.loadLevels(od.market, side)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ev := &simEvents{}
+	e.lock.Lock()
+	od.ID = e.newID()
+	if od.trigger {
+		e.opens[od.Symbol] = append(e.opens[od.Symbol], od)
+		ev.orders = append(ev.orders, copyOrder(od))
+	} else {
+		err = e.execOrder(od, levels, od.Timestamp, ev)
+	}
+	if err == nil {
+		e.orders[od.ID] = od
+	}
+	res := copyOrder(od)
+	resting := od.Status == banexg.OdStatusOpen || od.Status == banexg.OdStatusPartFilled
+	e.unlockEmit(ev)
+	if err != nil {
+		return nil, err
+	}
+	if resting {
+		e.watchOrderFeed(od.Symbol)
+	}
+	return res, nil
+}
+
+// newSimOrder parse and validate args of a new order, amount and price are rounded by market precision
+func (e *Paper) newSimOrder(symbol, odType, side string, amount, price float64, args map[string]interface{}) (*simOrder, *errs.Error) {
+	market, err := e.GetMarket(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if market.Option {
+		return nil, errs.NewMsg(errs.CodeUnsupportMarket, "paper exchange not support option")
+	}
+	if side != banexg.OdSideBuy && side != banexg.OdSideSell {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "invalid order side: %s", side)
+	}
+	odType = strings.ToLower(odType)
+	triggerPrice := utils.PopMapVal(args, banexg.ParamTriggerPrice, 0.0)
+	if triggerPrice == 0 {
+		triggerPrice = utils.PopMapVal(args, banexg.ParamStopLossPrice, 0.0)
+	}
+	if triggerPrice == 0 {
+		triggerPrice = utils.PopMapVal(args, banexg.ParamTakeProfitPrice, 0.0)
+	}
+	isMarket := odType == banexg.OdTypeMarket || strings.HasSuffix(odType, "_market")
+	switch odType {
+	case banexg.OdTypeStop, banexg.OdTypeStopLoss, banexg.OdTypeTakeProfit:
+		isMarket = price == 0
+	}
+	if odType != banexg.OdTypeMarket && odType != banexg.OdTypeLimit && odType != banexg.OdTypeLimitMaker && triggerPrice == 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "%s is required for %s order", banexg.ParamTriggerPrice, odType)
+	}
+	if !isMarket && price <= 0 {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "price is required for %s order", odType)
+	}
+	if market.Precision != nil {
+		amount, err = e.PrecAmount(market, amount)
+		if err != nil {
+			return nil, err
+		}
+		if price > 0 {
+			price, err = e.PrecPrice(market, price)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if triggerPrice > 0 {
+			triggerPrice, err = e.PrecPrice(market, triggerPrice)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = checkLimits(market, amount, price); err != nil {
+		return nil, err
+	}
+	timeInForce := utils.PopMapVal(args, banexg.ParamTimeInForce, "")
+	if timeInForce == "" && !isMarket {
+		timeInForce = banexg.DefTimeInForce
+	}
+	postOnly := utils.PopMapVal(args, banexg.ParamPostOnly, false) || odType == banexg.OdTypeLimitMaker ||
+		timeInForce == banexg.TimeInForcePO || timeInForce == banexg.TimeInForceGTX
+	if postOnly && isMarket {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "post only is invalid for market order")
+	}
+	stamp := e.MilliSeconds()
+	od := &banexg.Order{
+		ClientOrderID:       utils.PopMapVal(args, banexg.ParamClientOrderId, ""),
+		Datetime:            utils.ISO8601(stamp),
+		Timestamp:           stamp,
+		LastUpdateTimestamp: stamp,
+		Status:              banexg.OdStatusOpen,
+		Symbol:              market.Symbol,
+		Type:                odType,
+		TimeInForce:         timeInForce,
+		PositionSide:        utils.PopMapVal(args, banexg.ParamPositionSide, ""),
+		Side:                side,
+		Price:               price,
+		Amount:              amount,
+		Remaining:           amount,
+		TriggerPrice:        triggerPrice,
+		PostOnly:            postOnly,
+		ReduceOnly:          utils.PopMapVal(args, banexg.ParamReduceOnly, false),
+	}
+	return &simOrder{Order: od, market: market, trigger: triggerPrice > 0, isMarket: isMarket}, nil
+}
+
+// checkLimits check amount, price and cost of order against Market.Limits
+func checkLimits(market *banexg.Market, amount, price float64) *errs.Error {
+	if amount <= 0 {
+		return errs.NewMsg(errs.CodeParamInvalid, "amount must > 0")
+	}
+	lim := market.Limits
+	if lim == nil {
+		return nil
+	}
+	outRange := func(r *banexg.LimitRange, val float64) bool {
+		return r != nil && (r.Min > 0 && val < r.Min || r.Max > 0 && val > r.Max)
+	}
+	if outRange(lim.Amount, amount) {
+		return errs.NewMsg(errs.CodeParamInvalid, "amount %v out of range [%v, %v]", amount, lim.Amount.Min, lim.Amount.Max)
+	}
+	if price <= 0 {
+		return nil
+	}
+	if outRange(lim.Price, price) {
+		return errs.NewMsg(errs.CodeParamInvalid, "price %v out of range [%v, %v]", price, lim.Price.Min, lim.Price.Max)
+	}
+	cost := amount * price
+	if market.Contract && !market.Inverse {
+		cost *= contractSize(market)
+	}
+	if !market.Inverse && outRange(lim.Cost, cost) {
+		return errs.NewMsg(errs.CodeParamInvalid, "cost %v out of range [%v, %v]", cost, lim.Cost.Min, lim.Cost.Max)
+	}
+	return nil
+}
+
+/*
+loadLevels
+return levels of order book which an order of side can take. No lock required.
+Use the ws book of Src if watched, otherwise FetchOrderBook, fallback to the last price.
+返回side方向订单可吃的订单簿档位。优先使用Src的ws订单簿，其次FetchOrderBook，最后使用最新价
+*/
+func (e *Paper) loadLevels(market *banexg.Market, side string) ([]level, *errs.Error) {
+	book := e.srcBook(market.Symbol)
+	if book == nil {
+		var err *errs.Error
+		book, err = e.Src.FetchOrderBook(market.Symbol, e.bookDepth, nil)
+		if err != nil {
+			log.Warn("paper fetch order book fail, use last price", zap.String("symbol", market.Symbol), zap.Error(err))
+		}
+	}
+	if book != nil {
+		if levels := bookLevels(book, side); len(levels) > 0 {
+			return levels, nil
+		}
+	}
+	price, err := e.lastPrice(market)
+	if err != nil {
+		return nil, err
+	}
+	return []level{{price, math.MaxFloat64}}, nil
+}
+
+// lastPrice return the last price of market, no lock required
+func (e *Paper) lastPrice(market *banexg.Market) (float64, *errs.Error) {
+	e.lock.Lock()
+	price := e.prices[market.Symbol]
+	e.lock.Unlock()
+	if price > 0 {
+		return price, nil
+	}
+	prices, err := e.Src.FetchTickerPrice(market.Symbol, nil)
+	if err != nil {
+		return 0, err
+	}
+	price = prices[market.Symbol]
+	if price <= 0 {
+		return 0, errs.NewMsg(errs.CodeDataNotFound, "no price for %s", market.Symbol)
+	}
+	return price, nil
+}
+
+/*
+EditOrder
+edit amount and price of an open order. The order loses its queue position and is matched again.
+修改挂单的数量和价格，订单失去排队位置并重新撮合
+*/
+func (e *Paper) EditOrder(symbol, orderId, side string, amount, price float64, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	market, err := e.GetMarket(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if market.Precision != nil {
+		if amount, err = e.PrecAmount(market, amount); err != nil {
+			return nil, err
+		}
+		if price, err = e.PrecPrice(market, price); err != nil {
+			return nil, err
+		}
+	}
+	if err = checkLimits(market, amount, price); err != nil {
+		return nil, err
+	}
+	levels, err := e.loadLevels(market, side)
+	if err != nil {
+		return nil, err
+	}
+	ev := &simEvents{}
+	e.lock.Lock()
+	od, err := e.getOpenOrder(orderId, market.Symbol)
+	if err == nil && od.Side != side {
+		err = errs.NewMsg(errs.CodeParamInvalid, "side of order can not be changed")
+	}
+	if err == nil && amount <= od.Filled {
+		err = errs.NewMsg(errs.CodeParamInvalid, "amount must > filled %v", od.Filled)
+	}
+	if err != nil {
+		e.lock.Unlock()
+		return nil, err
+	}
+	stamp := e.MilliSeconds()
+	e.closeOrder(od, od.Status, stamp, nil)
+	ev.balance = true
+	oldAmount, oldPrice := od.Amount, od.Price
+	od.Amount, od.Price, od.Remaining = amount, price, amount-od.Filled
+	if od.trigger {
+		e.opens[od.Symbol] = append(e.opens[od.Symbol], od)
+		ev.orders = append(ev.orders, copyOrder(od))
+	} else if err = e.execOrder(od, levels, stamp, ev); err != nil {
+		// restore the order when new args are rejected
+		od.Amount, od.Price, od.Remaining = oldAmount, oldPrice, oldAmount-od.Filled
+		if err2 := e.execOrder(od, levels, stamp, ev); err2 != nil {
+			e.closeOrder(od, banexg.OdStatusCanceled, stamp, ev)
+		}
+	}
+	res := copyOrder(od)
+	e.unlockEmit(ev)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (e *Paper) getOpenOrder(id, symbol string) (*simOrder, *errs.Error) {
+	od, ok := e.orders[id]
+	if !ok || symbol != "" && od.Symbol != symbol {
+		return nil, errs.NewMsg(errs.CodeOrderNotFound, "order not found: %s", id)
+	}
+	if od.Status != banexg.OdStatusOpen && od.Status != banexg.OdStatusPartFilled {
+		return nil, errs.NewMsg(errs.CodeParamInvalid, "order %s is %s", id, od.Status)
+	}
+	return od, nil
+}
+
+func (e *Paper) CancelOrder(id string, symbol string, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	ev := &simEvents{}
+	e.lock.Lock()
+	od, err := e.getOpenOrder(id, symbol)
+	if err != nil {
+		e.lock.Unlock()
+		return nil, err
+	}
+	e.closeOrder(od, banexg.OdStatusCanceled, e.MilliSeconds(), ev)
+	res := copyOrder(od)
+	e.unlockEmit(ev)
+	return res, nil
+}
+
+func (e *Paper) FetchOrder(symbol, id string, params map[string]interface{}) (*banexg.Order, *errs.Error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	od, ok := e.orders[id]
+	if !ok || symbol != "" && od.Symbol != symbol {
+		return nil, errs.NewMsg(errs.CodeOrderNotFound, "order not found: %s", id)
+	}
+	return copyOrder(od), nil
+}
+
+func (e *Paper) FetchOrders(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Order, *errs.Error) {
+	e.lock.Lock()
+	list := make([]*simOrder, 0, len(e.orders))
+	for _, od := range e.orders {
+		if (symbol == "" || od.Symbol == symbol) && od.Timestamp >= since {
+			list = append(list, od)
+		}
+	}
+	res := copyOrders(list, limit)
+	e.lock.Unlock()
+	return res, nil
+}
+
+func (e *Paper) FetchOpenOrders(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.Order, *errs.Error) {
+	e.lock.Lock()
+	var list []*simOrder
+	for sym, items := range e.opens {
+		if symbol != "" && sym != symbol {
+			continue
+		}
+		for _, od := range items {
+			if od.Timestamp >= since {
+				list = append(list, od)
+			}
+		}
+	}
+	res := copyOrders(list, limit)
+	e.lock.Unlock()
+	return res, nil
+}
+
+// copyOrders sort orders by id and keep the latest limit ones
+func copyOrders(list []*simOrder, limit int) []*banexg.Order {
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i].ID) != len(list[j].ID) {
+			return len(list[i].ID) < len(list[j].ID)
+		}
+		return list[i].ID < list[j].ID
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+	res := make([]*banexg.Order, 0, len(list))
+	for _, od := range list {
+		res = append(res, copyOrder(od))
+	}
+	return res
+}
+
+func (e *Paper) FetchMyTrades(symbol string, since int64, limit int, params map[string]interface{}) ([]*banexg.MyTrade, *errs.Error) {
+	e.lock.Lock()
+	var res []*banexg.MyTrade
+	for _, trade := range e.myTrades {
+		if (symbol == "" || trade.Symbol == symbol) && trade.Timestamp >= since {
+			res = append(res, trade)
+		}
+	}
+	e.lock.Unlock()
+	if limit > 0 && len(res) > limit {
+		res = res[len(res)-limit:]
+	}
+	return res, nil
+}
+
+func (e *Paper) FetchBalance(params map[string]interface{}) (*banexg.Balances, *errs.Error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.balances(), nil
+}
+
+func (e *Paper) FetchPositions(symbols []string, params map[string]interface{}) ([]*banexg.Position, *errs.Error) {
+	var symSet map[string]bool
+	if len(symbols) > 0 {
+		symSet = make(map[string]bool, len(symbols))
+		for _, sym := range symbols {
+			symSet[sym] = true
+		}
+	}
+	e.lock.Lock()
+	res := make([]*banexg.Position, 0, len(e.positions))
+	for sym, pos := range e.positions {
+		if pos.Contracts > 0 && (symSet == nil || symSet[sym]) {
+			res = append(res, copyPosition(pos))
+		}
+	}
+	e.lock.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Symbol < res[j].Symbol
+	})
+	return res, nil
+}
+
+func (e *Paper) FetchAccountPositions(symbols []string, params map[string]interface{}) ([]*banexg.Position, *errs.Error) {
+	return e.FetchPositions(symbols, params)
+}
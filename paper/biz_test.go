@@ -0,0 +1,230 @@
+package paper
+
+import (
+	"math"
+	"testing"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+)
+
+// fakeSrc provides fixed markets, order book and price without network
+type fakeSrc struct {
+	*banexg.Exchange
+	bids   [][2]float64
+	asks   [][2]float64
+	price  float64
+	trades chan *banexg.Trade
+}
+
+func (f *fakeSrc) FetchOrderBook(symbol string, limit int, params map[string]interface{}) (*banexg.OrderBook, *errs.Error) {
+	return &banexg.OrderBook{
+		Symbol: symbol,
+		Bids:   banexg.NewOdBookSide(true, limit, f.bids),
+		Asks:   banexg.NewOdBookSide(false, limit, f.asks),
+	}, nil
+}
+
+func (f *fakeSrc) FetchTickerPrice(symbol string, params map[string]interface{}) (map[string]float64, *errs.Error) {
+	return map[string]float64{symbol: f.price}, nil
+}
+
+func (f *fakeSrc) WatchTrades(symbols []string, params map[string]interface{}) (chan *banexg.Trade, *errs.Error) {
+	return f.trades, nil
+}
+
+func newFakeSrc(t *testing.T) *fakeSrc {
+	src := &fakeSrc{
+		Exchange: &banexg.Exchange{
+			ExgInfo: &banexg.ExgInfo{ID: "fake", Name: "Fake"},
+			Options: map[string]interface{}{banexg.OptProxy: "no"},
+			Hosts:   &banexg.ExgHosts{},
+			Apis:    map[string]*banexg.Entry{},
+			Has:     map[string]map[string]int{},
+		},
+		bids:   [][2]float64{{99, 1}, {98, 2}},
+		asks:   [][2]float64{{100, 1}, {101, 2}},
+		price:  100,
+		trades: make(chan *banexg.Trade),
+	}
+	src.Self = src
+	if err := src.Init(); err != nil {
+		t.Fatalf("init fake src fail: %v", err)
+	}
+	src.Markets = banexg.MarketMap{
+		"BTC/USDT": {ID: "BTCUSDT", Symbol: "BTC/USDT", Base: "BTC", Quote: "USDT", Type: banexg.MarketSpot,
+			Spot: true, Taker: 0.001, Maker: 0.0005,
+			Limits: &banexg.MarketLimits{Amount: &banexg.LimitRange{Min: 0.1}}},
+		"BTC/USDT:USDT": {ID: "BTCUSDT", Symbol: "BTC/USDT:USDT", Base: "BTC", Quote: "USDT", Settle: "USDT",
+			Type: banexg.MarketLinear, Swap: true, Contract: true, Linear: true, ContractSize: 1},
+	}
+	src.MarketsById = banexg.MarketArrMap{}
+	return src
+}
+
+func newTestPaper(t *testing.T, src *fakeSrc) *Paper {
+	exg, err := New(map[string]interface{}{
+		OptSrcExg:       banexg.BanExchange(src),
+		OptBalances:     map[string]float64{"USDT": 1000},
+		OptLeverage:     10,
+		banexg.OptProxy: "no",
+	})
+	if err != nil {
+		t.Fatalf("new paper fail: %v", err)
+	}
+	return exg
+}
+
+func nearly(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSpotLimitOrderFill(t *testing.T) {
+	src := newFakeSrc(t)
+	exg := newTestPaper(t, src)
+	od, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeLimit, banexg.OdSideBuy, 1.5, 100.5, nil)
+	if err != nil {
+		t.Fatalf("create order fail: %v", err)
+	}
+	if od.Status != banexg.OdStatusPartFilled || !nearly(od.Filled, 1) || !nearly(od.Remaining, 0.5) {
+		t.Fatalf("unexpected order after taker fill: %+v", od)
+	}
+	bal, _ := exg.FetchBalance(nil)
+	// 1000 - 100 - 0.1 (taker fee), 0.5*100.5 locked for the resting part
+	if !nearly(bal.Used["USDT"], 50.25) || !nearly(bal.Free["USDT"], 849.65) {
+		t.Fatalf("unexpected balance after taker fill: free %v used %v", bal.Free["USDT"], bal.Used["USDT"])
+	}
+	exg.onTrade(&banexg.Trade{Symbol: "BTC/USDT", Side: banexg.OdSideSell, Price: 100.6, Amount: 5})
+	od, _ = exg.FetchOrder("BTC/USDT", od.ID, nil)
+	if od.Status != banexg.OdStatusPartFilled {
+		t.Fatalf("order should not fill on trade above limit: %+v", od)
+	}
+	exg.onTrade(&banexg.Trade{Symbol: "BTC/USDT", Side: banexg.OdSideSell, Price: 100.4, Amount: 0.1})
+	od, _ = exg.FetchOrder("BTC/USDT", od.ID, nil)
+	if od.Status != banexg.OdStatusFilled || !nearly(od.Filled, 1.5) {
+		t.Fatalf("order should be filled: %+v", od)
+	}
+	bal, _ = exg.FetchBalance(nil)
+	// maker fill at limit price: 0.5*100.5=50.25, fee 0.025125
+	if !nearly(bal.Used["USDT"], 0) || !nearly(bal.Free["USDT"], 849.624875) || !nearly(bal.Free["BTC"], 1.5) {
+		t.Fatalf("unexpected balance after maker fill: %+v %+v", bal.Free, bal.Used)
+	}
+	trades, _ := exg.FetchMyTrades("BTC/USDT", 0, 0, nil)
+	if len(trades) != 2 || trades[0].Maker || !trades[1].Maker || trades[1].Price != 100.5 {
+		t.Fatalf("unexpected my trades: %+v", trades)
+	}
+}
+
+func TestContractPosition(t *testing.T) {
+	src := newFakeSrc(t)
+	src.asks = [][2]float64{{100, 5}}
+	src.bids = [][2]float64{{110, 5}}
+	exg := newTestPaper(t, src)
+	_, err := exg.CreateOrder("BTC/USDT:USDT", banexg.OdTypeMarket, banexg.OdSideBuy, 2, 0, nil)
+	if err != nil {
+		t.Fatalf("open long fail: %v", err)
+	}
+	pos, _ := exg.FetchPositions(nil, nil)
+	if len(pos) != 1 || pos[0].Side != banexg.PosSideLong || !nearly(pos[0].Contracts, 2) || !nearly(pos[0].InitialMargin, 20) {
+		t.Fatalf("unexpected long position: %+v", pos)
+	}
+	// close 2 long with pnl 20, open 1 short at 110 with margin 11
+	_, err = exg.CreateOrder("BTC/USDT:USDT", banexg.OdTypeMarket, banexg.OdSideSell, 3, 0, nil)
+	if err != nil {
+		t.Fatalf("reverse position fail: %v", err)
+	}
+	pos, _ = exg.FetchPositions(nil, nil)
+	if len(pos) != 1 || pos[0].Side != banexg.PosSideShort || !nearly(pos[0].Contracts, 1) || pos[0].EntryPrice != 110 {
+		t.Fatalf("unexpected short position: %+v", pos)
+	}
+	bal, _ := exg.FetchBalance(nil)
+	if !nearly(bal.Used["USDT"], 11) || !nearly(bal.Total["USDT"], 1020) {
+		t.Fatalf("unexpected balance: %+v %+v", bal.Used, bal.Total)
+	}
+	_, err = exg.CreateOrder("BTC/USDT:USDT", banexg.OdTypeMarket, banexg.OdSideSell, 1, 0,
+		map[string]interface{}{banexg.ParamReduceOnly: true})
+	if err == nil {
+		t.Fatalf("reduce only order should not increase position")
+	}
+}
+
+func TestOrderCheck(t *testing.T) {
+	src := newFakeSrc(t)
+	exg := newTestPaper(t, src)
+	cases := []struct {
+		name   string
+		odType string
+		side   string
+		amount float64
+		price  float64
+		params map[string]interface{}
+	}{
+		{"amount below limit", banexg.OdTypeLimit, banexg.OdSideBuy, 0.01, 90, nil},
+		{"post only cross", banexg.OdTypeLimit, banexg.OdSideBuy, 1, 100, map[string]interface{}{banexg.ParamPostOnly: true}},
+		{"insufficient balance", banexg.OdTypeLimit, banexg.OdSideBuy, 20, 90, nil},
+		{"sell without base", banexg.OdTypeLimit, banexg.OdSideSell, 1, 120, nil},
+	}
+	for _, c := range cases {
+		if _, err := exg.CreateOrder("BTC/USDT", c.odType, c.side, c.amount, c.price, c.params); err == nil {
+			t.Errorf("%s: expect error", c.name)
+		}
+	}
+	od, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeLimit, banexg.OdSideBuy, 2, 100,
+		map[string]interface{}{banexg.ParamTimeInForce: banexg.TimeInForceFOK})
+	if err != nil || od.Status != banexg.OdStatusExpired || od.Filled != 0 {
+		t.Fatalf("FOK order should expire: %+v %v", od, err)
+	}
+	bal, _ := exg.FetchBalance(nil)
+	if !nearly(bal.Free["USDT"], 1000) || !nearly(bal.Used["USDT"], 0) {
+		t.Fatalf("balance should be unchanged: %+v %+v", bal.Free, bal.Used)
+	}
+}
+
+func TestSpendAllBalance(t *testing.T) {
+	src := newFakeSrc(t)
+	src.asks = [][2]float64{{100, 20}}
+	exg := newTestPaper(t, src)
+	// 10*100 costs the whole 1000, the taker fee 1 can't be paid
+	if _, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeMarket, banexg.OdSideBuy, 10, 0, nil); err == nil {
+		t.Fatalf("order spending whole balance without fee should fail")
+	}
+	// 9.99*100 + 0.999 fee
+	od, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeMarket, banexg.OdSideBuy, 9.99, 0, nil)
+	if err != nil || od.Status != banexg.OdStatusFilled {
+		t.Fatalf("order with fee in balance should fill: %+v %v", od, err)
+	}
+	bal, _ := exg.FetchBalance(nil)
+	if bal.Free["USDT"] < 0 || !nearly(bal.Free["USDT"], 0.001) {
+		t.Fatalf("unexpected balance after spending all: %+v", bal.Free)
+	}
+}
+
+func TestTriggerOrder(t *testing.T) {
+	src := newFakeSrc(t)
+	exg := newTestPaper(t, src)
+	_, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeMarket, banexg.OdSideBuy, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("buy fail: %v", err)
+	}
+	od, err := exg.CreateOrder("BTC/USDT", banexg.OdTypeStopLoss, banexg.OdSideSell, 0.999, 0,
+		map[string]interface{}{banexg.ParamTriggerPrice: 95.0})
+	if err != nil {
+		t.Fatalf("create stop loss fail: %v", err)
+	}
+	opens, _ := exg.FetchOpenOrders("BTC/USDT", 0, 0, nil)
+	if len(opens) != 1 || opens[0].ID != od.ID {
+		t.Fatalf("stop loss should be open: %+v", opens)
+	}
+	exg.onTrade(&banexg.Trade{Symbol: "BTC/USDT", Price: 96, Amount: 1})
+	if od, _ = exg.FetchOrder("", od.ID, nil); od.Status != banexg.OdStatusOpen {
+		t.Fatalf("stop loss should not trigger: %+v", od)
+	}
+	exg.onTrade(&banexg.Trade{Symbol: "BTC/USDT", Price: 94, Amount: 1})
+	if od, _ = exg.FetchOrder("", od.ID, nil); od.Status != banexg.OdStatusFilled || od.Average != 94 {
+		t.Fatalf("stop loss should fill at trigger price: %+v", od)
+	}
+	opens, _ = exg.FetchOpenOrders("", 0, 0, nil)
+	if len(opens) != 0 {
+		t.Fatalf("no open orders expected: %+v", opens)
+	}
+}
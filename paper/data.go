@@ -0,0 +1,24 @@
+package paper
+
+const (
+	OptSrcExg    = "SrcExg"    // banexg.BanExchange which provides market data 提供行情数据的交易所
+	OptSrcName   = "SrcName"   // name of source exchange, used by bex.New when OptSrcExg is missing 源交易所名称
+	OptBalances  = "Balances"  // initial balances, code: amount 初始资产
+	OptLeverage  = "Leverage"  // default leverage of contracts 合约默认杠杆
+	OptBookDepth = "BookDepth" // depth of order book to match market orders when no ws book 无ws订单簿时撮合市价单的深度
+)
+
+const (
+	chanTrades    = "trades"
+	chanOrderBook = "orderbook"
+	chanMyTrades  = "mytrades"
+	chanOrders    = "orders"
+	chanBalance   = "balance"
+	chanPositions = "positions"
+)
+
+const (
+	defLeverage  = 1
+	defBookDepth = 20
+	minQty       = 1e-12 // amounts below this are treated as zero
+)
@@ -0,0 +1,89 @@
+package paper
+
+import (
+	"maps"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/utils"
+)
+
+// apis simulated locally, other apis are the same as Src
+var simApis = []string{
+	banexg.ApiFetchOrder, banexg.ApiFetchOrders, banexg.ApiFetchBalance, banexg.ApiFetchAccountPositions,
+	banexg.ApiFetchPositions, banexg.ApiFetchOpenOrders, banexg.ApiFetchMyTrades, banexg.ApiCreateOrder,
+	banexg.ApiEditOrder, banexg.ApiCancelOrder, banexg.ApiCreateOrders, banexg.ApiCancelOrders,
+	banexg.ApiCancelAllOrders, banexg.ApiSetLeverage, banexg.ApiWatchMyTrades, banexg.ApiWatchOrders,
+	banexg.ApiWatchBalance, banexg.ApiWatchPositions,
+}
+
+func New(Options map[string]interface{}) (*Paper, *errs.Error) {
+	var srcNil banexg.BanExchange
+	src := utils.PopMapVal(Options, OptSrcExg, srcNil)
+	if src == nil {
+		return nil, errs.NewMsg(errs.CodeParamRequired, "%s is required for paper exchange", OptSrcExg)
+	}
+	srcExg := src.GetExg()
+	has := make(map[string]map[string]int, len(srcExg.Has))
+	for market, items := range srcExg.Has {
+		has[market] = maps.Clone(items)
+	}
+	if has[""] == nil {
+		has[""] = map[string]int{}
+	}
+	for _, key := range simApis {
+		has[""][key] = banexg.HasOk
+	}
+	exg := &Paper{
+		Exchange: &banexg.Exchange{
+			ExgInfo: &banexg.ExgInfo{
+				ID:        "paper",
+				Name:      "Paper",
+				Countries: srcExg.Countries,
+				NoHoliday: srcExg.NoHoliday,
+				FullDay:   srcExg.FullDay,
+				FixedLvg:  srcExg.FixedLvg,
+				TimeLoc:   srcExg.TimeLoc,
+			},
+			RateLimit: srcExg.RateLimit,
+			Options:   Options,
+			Hosts:     &banexg.ExgHosts{},
+			Fees:      srcExg.Fees,
+			Apis:      map[string]*banexg.Entry{},
+			Has:       has,
+		},
+		Src:       src,
+		orders:    map[string]*simOrder{},
+		opens:     map[string][]*simOrder{},
+		assets:    map[string]*banexg.Asset{},
+		positions: map[string]*banexg.Position{},
+		leverages: map[string]int{},
+		prices:    map[string]float64{},
+		srcFeeds:  map[interface{}]bool{},
+		tradeSyms: map[string]bool{},
+	}
+	exg.Self = exg
+	err := exg.Init()
+	if err != nil {
+		return nil, err
+	}
+	exg.FetchMarkets = func(marketTypes []string, params map[string]interface{}) (banexg.MarketMap, *errs.Error) {
+		return src.LoadMarkets(false, params)
+	}
+	exg.MarketType = srcExg.MarketType
+	exg.ContractType = srcExg.ContractType
+	exg.bookDepth = utils.GetMapVal(Options, OptBookDepth, defBookDepth)
+	exg.defLever = utils.GetMapVal(Options, OptLeverage, defLeverage)
+	balances := utils.GetMapVal(Options, OptBalances, map[string]float64{})
+	for code, amount := range balances {
+		exg.assets[code] = &banexg.Asset{Code: code, Free: amount, Total: amount}
+	}
+	if srcExg.Markets != nil {
+		exg.syncMarkets()
+	}
+	return exg, nil
+}
+
+func NewExchange(Options map[string]interface{}) (banexg.BanExchange, *errs.Error) {
+	return New(Options)
+}
@@ -0,0 +1,560 @@
+package paper
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"go.uber.org/zap"
+)
+
+/*
+Matching engine of Paper. Functions in this file require e.lock held unless noted.
+Taker fills walk the levels of Src order book, resting orders fill as maker at their limit price
+when a trade or the order book of Src crosses them.
+Paper的撮合引擎，除特别说明外本文件函数需持有e.lock。
+吃单按Src订单簿逐档成交，挂单在Src的成交或订单簿穿过其限价时按限价以maker成交
+*/
+
+func (e *Paper) newID() string {
+	e.nextID += 1
+	return strconv.FormatInt(e.nextID, 10)
+}
+
+func (e *Paper) getAsset(code string) *banexg.Asset {
+	a, ok := e.assets[code]
+	if !ok {
+		a = &banexg.Asset{Code: code}
+		e.assets[code] = a
+	}
+	return a
+}
+
+func moveFree(a *banexg.Asset, amount float64) {
+	a.Free += amount
+	a.Total = a.Free + a.Used
+}
+
+func moveUsed(a *banexg.Asset, amount float64) {
+	a.Free -= amount
+	a.Used += amount
+	a.Total = a.Free + a.Used
+}
+
+func contractSize(m *banexg.Market) float64 {
+	if m.ContractSize > 0 {
+		return m.ContractSize
+	}
+	return 1
+}
+
+// contractValue value of qty contracts at price, in settle currency
+func contractValue(m *banexg.Market, qty, price float64) float64 {
+	if m.Inverse {
+		return qty * contractSize(m) / price
+	}
+	return qty * price * contractSize(m)
+}
+
+func calcPnl(m *banexg.Market, entry, price, qty float64, long bool) float64 {
+	var pnl float64
+	if m.Inverse {
+		pnl = (1/entry - 1/price) * qty * contractSize(m)
+	} else {
+		pnl = (price - entry) * qty * contractSize(m)
+	}
+	if !long {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+func isBuy(side string) bool {
+	return side == banexg.OdSideBuy
+}
+
+/*
+fundsNeed
+return the currency and amount to lock for qty of order at price.
+Only the part which opens a contract position needs margin.
+返回订单qty数量按price需要锁定的币种和金额，合约仅开仓部分需要保证金
+*/
+func (e *Paper) fundsNeed(od *simOrder, qty, price float64) (string, float64) {
+	m := od.market
+	if !m.Contract {
+		if isBuy(od.Side) {
+			return m.Quote, qty * price
+		}
+		return m.Base, qty
+	}
+	openQty := qty
+	if pos := e.positions[m.Symbol]; pos != nil && pos.Contracts > 0 && (pos.Side == banexg.PosSideLong) != isBuy(od.Side) {
+		openQty = max(0, qty-pos.Contracts)
+	}
+	if openQty <= 0 || price <= 0 {
+		return m.Settle, 0
+	}
+	return m.Settle, contractValue(m, openQty, price) / float64(e.getLeverage(m.Symbol))
+}
+
+// expectFee taker fee of qty at price if it's charged in code, which is paid from free when filled
+func (e *Paper) expectFee(od *simOrder, code string, qty, price float64) float64 {
+	fee, err := e.Src.CalculateFee(od.market.Symbol, od.Type, od.Side, qty, price, false, nil)
+	if err != nil || fee == nil || fee.Currency != code {
+		return 0
+	}
+	return fee.Cost
+}
+
+// takeLevels return levels the order can take from the opposite side of book
+func takeLevels(od *simOrder, levels []level) []level {
+	var fills []level
+	rest := od.Remaining
+	for _, lv := range levels {
+		if rest <= 0 {
+			break
+		}
+		if !od.isMarket && (isBuy(od.Side) && lv[0] > od.Price || !isBuy(od.Side) && lv[0] < od.Price) {
+			break
+		}
+		qty := min(lv[1], rest)
+		if qty <= 0 {
+			continue
+		}
+		fills = append(fills, level{lv[0], qty})
+		rest -= qty
+	}
+	return fills
+}
+
+/*
+execOrder
+match a new (or just triggered) order as taker against levels, then rest the remaining part with funds locked if needed.
+新订单(或刚触发的订单)作为taker与levels撮合，剩余部分按需挂单并锁定资金
+*/
+func (e *Paper) execOrder(od *simOrder, levels []level, stamp int64, ev *simEvents) *errs.Error {
+	m := od.market
+	if od.ReduceOnly && m.Contract {
+		pos := e.positions[m.Symbol]
+		if pos == nil || pos.Contracts <= 0 || (pos.Side == banexg.PosSideLong) == isBuy(od.Side) {
+			return errs.NewMsg(errs.CodeReduceOnlyRejected, "reduce only order would not reduce position")
+		}
+		if od.Remaining > pos.Contracts+minQty {
+			return errs.NewMsg(errs.CodeReduceOnlyRejected, "reduce only order amount %v > position %v", od.Remaining, pos.Contracts)
+		}
+	}
+	fills := takeLevels(od, levels)
+	var fillQty, fillCost float64
+	for _, f := range fills {
+		fillQty += f[1]
+		fillCost += f[0] * f[1]
+	}
+	if od.PostOnly && fillQty > 0 {
+		return errs.NewMsg(errs.CodeOrderWouldMatch, "post only order would take liquidity")
+	}
+	if od.TimeInForce == banexg.TimeInForceFOK && fillQty < od.Remaining-minQty {
+		e.closeOrder(od, banexg.OdStatusExpired, stamp, ev)
+		return nil
+	}
+	restQty := 0.0
+	if !od.isMarket && od.TimeInForce != banexg.TimeInForceIOC && od.TimeInForce != banexg.TimeInForceFOK {
+		restQty = od.Remaining - fillQty
+	}
+	lockQty := fillQty + restQty
+	if lockQty <= 0 {
+		e.closeOrder(od, banexg.OdStatusExpired, stamp, ev)
+		return nil
+	}
+	avgPrice := (fillCost + restQty*od.Price) / lockQty
+	code, need := e.fundsNeed(od, lockQty, avgPrice)
+	if need > 0 {
+		need += e.expectFee(od, code, lockQty, avgPrice)
+	}
+	asset := e.getAsset(code)
+	if need > asset.Free+minQty {
+		return errs.NewMsg(errs.CodeInsufficientBalance, "insufficient %s balance: need %v, free %v", code, need, asset.Free)
+	}
+	for _, f := range fills {
+		e.fill(od, f[0], f[1], false, stamp, ev)
+	}
+	if od.Remaining <= 0 {
+		return nil
+	}
+	if restQty > 0 {
+		// taker fills are settled from free directly, only the resting part is locked
+		code, need = e.fundsNeed(od, od.Remaining, od.Price)
+		moveUsed(e.getAsset(code), need)
+		od.lockCode, od.locked, od.lockQty = code, need, od.Remaining
+		e.opens[m.Symbol] = append(e.opens[m.Symbol], od)
+		ev.orders = append(ev.orders, copyOrder(od))
+	} else {
+		e.closeOrder(od, banexg.OdStatusCanceled, stamp, ev)
+	}
+	return nil
+}
+
+/*
+fill
+fill qty of order at price, update order, balances, position and fee
+按price成交订单qty数量，更新订单、资产、持仓和手续费
+*/
+func (e *Paper) fill(od *simOrder, price, qty float64, maker bool, stamp int64, ev *simEvents) {
+	m := od.market
+	if od.locked > 0 && od.lockQty > 0 {
+		part := od.locked * min(1, qty/od.lockQty)
+		od.locked -= part
+		od.lockQty -= qty
+		moveUsed(e.getAsset(od.lockCode), -part)
+	}
+	if m.Contract {
+		e.applyPosition(m, od.Side, price, qty, stamp, ev)
+	} else if isBuy(od.Side) {
+		moveFree(e.getAsset(m.Quote), -price*qty)
+		moveFree(e.getAsset(m.Base), qty)
+	} else {
+		moveFree(e.getAsset(m.Base), -qty)
+		moveFree(e.getAsset(m.Quote), price*qty)
+	}
+	fee, err := e.Src.CalculateFee(m.Symbol, od.Type, od.Side, qty, price, maker, nil)
+	if err != nil {
+		log.Warn("paper calc fee fail", zap.String("symbol", m.Symbol), zap.Error(err))
+	} else if fee != nil {
+		moveFree(e.getAsset(fee.Currency), -fee.Cost)
+		if od.Fee == nil {
+			od.Fee = &banexg.Fee{Currency: fee.Currency}
+		}
+		od.Fee.Cost += fee.Cost
+		od.Fee.QuoteCost += fee.QuoteCost
+	}
+	od.Filled += qty
+	od.Remaining -= qty
+	if od.Remaining <= minQty {
+		od.Remaining = 0
+	}
+	od.Cost += price * qty
+	od.Average = od.Cost / od.Filled
+	od.LastTradeTimestamp = stamp
+	od.LastUpdateTimestamp = stamp
+	if od.Remaining == 0 {
+		od.Status = banexg.OdStatusFilled
+		e.closeOrder(od, banexg.OdStatusFilled, stamp, nil)
+	} else {
+		od.Status = banexg.OdStatusPartFilled
+	}
+	trade := &banexg.MyTrade{
+		Trade: banexg.Trade{
+			ID:        e.newID(),
+			Symbol:    m.Symbol,
+			Side:      od.Side,
+			Type:      od.Type,
+			Amount:    qty,
+			Price:     price,
+			Cost:      price * qty,
+			Order:     od.ID,
+			Timestamp: stamp,
+			Maker:     maker,
+			Fee:       fee,
+		},
+		Filled:     od.Filled,
+		ClientID:   od.ClientOrderID,
+		Average:    od.Average,
+		State:      od.Status,
+		PosSide:    od.PositionSide,
+		ReduceOnly: od.ReduceOnly,
+	}
+	e.myTrades = append(e.myTrades, trade)
+	ev.trades = append(ev.trades, trade)
+	ev.orders = append(ev.orders, copyOrder(od))
+	ev.balance = true
+}
+
+/*
+applyPosition
+update the one-way position of market with a fill, the closed part releases margin and realizes pnl
+用一次成交更新单向持仓，平仓部分释放保证金并实现盈亏
+*/
+func (e *Paper) applyPosition(m *banexg.Market, side string, price, qty float64, stamp int64, ev *simEvents) {
+	asset := e.getAsset(m.Settle)
+	long := isBuy(side)
+	pos, ok := e.positions[m.Symbol]
+	if !ok {
+		pos = &banexg.Position{
+			ID:           m.Symbol,
+			Symbol:       m.Symbol,
+			ContractSize: contractSize(m),
+			MarginMode:   banexg.MarginCross,
+		}
+		e.positions[m.Symbol] = pos
+	}
+	pos.Leverage = e.getLeverage(m.Symbol)
+	if pos.Contracts > 0 && (pos.Side == banexg.PosSideLong) != long {
+		closeQty := min(qty, pos.Contracts)
+		pnl := calcPnl(m, pos.EntryPrice, price, closeQty, pos.Side == banexg.PosSideLong)
+		margin := pos.InitialMargin * closeQty / pos.Contracts
+		pos.Contracts -= closeQty
+		pos.InitialMargin -= margin
+		moveUsed(asset, -margin)
+		moveFree(asset, pnl)
+		qty -= closeQty
+		if pos.Contracts <= minQty {
+			moveUsed(asset, -pos.InitialMargin)
+			pos.Contracts, pos.InitialMargin, pos.EntryPrice = 0, 0, 0
+		}
+	}
+	if qty > minQty {
+		margin := contractValue(m, qty, price) / float64(pos.Leverage)
+		if pos.Contracts == 0 {
+			pos.Side = banexg.PosSideShort
+			if long {
+				pos.Side = banexg.PosSideLong
+			}
+			pos.EntryPrice = price
+		} else if m.Inverse {
+			pos.EntryPrice = (pos.Contracts + qty) / (pos.Contracts/pos.EntryPrice + qty/price)
+		} else {
+			pos.EntryPrice = (pos.EntryPrice*pos.Contracts + price*qty) / (pos.Contracts + qty)
+		}
+		pos.Contracts += qty
+		pos.InitialMargin += margin
+		moveUsed(asset, margin)
+	}
+	pos.TimeStamp = stamp
+	markPosition(m, pos, price)
+	ev.positions = append(ev.positions, copyPosition(pos))
+}
+
+func markPosition(m *banexg.Market, pos *banexg.Position, price float64) {
+	pos.MarkPrice = price
+	if pos.Contracts <= 0 {
+		pos.Notional, pos.UnrealizedPnl, pos.Collateral, pos.Percentage = 0, 0, 0, 0
+		return
+	}
+	pos.Notional = contractValue(m, pos.Contracts, price)
+	pos.UnrealizedPnl = calcPnl(m, pos.EntryPrice, price, pos.Contracts, pos.Side == banexg.PosSideLong)
+	pos.Collateral = pos.InitialMargin + pos.UnrealizedPnl
+	if pos.InitialMargin > 0 {
+		pos.Percentage = pos.UnrealizedPnl / pos.InitialMargin * 100
+	}
+}
+
+// closeOrder release locked funds and remove order from open list. ev can be nil when order is emitted by caller
+func (e *Paper) closeOrder(od *simOrder, status string, stamp int64, ev *simEvents) {
+	if od.locked != 0 {
+		moveUsed(e.getAsset(od.lockCode), -od.locked)
+		od.locked, od.lockQty = 0, 0
+		if ev != nil {
+			ev.balance = true
+		}
+	}
+	od.Status = status
+	od.LastUpdateTimestamp = stamp
+	list := e.opens[od.Symbol]
+	for i, it := range list {
+		if it == od {
+			e.opens[od.Symbol] = append(list[:i:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(e.opens[od.Symbol]) == 0 {
+		delete(e.opens, od.Symbol)
+	}
+	if ev != nil {
+		ev.orders = append(ev.orders, copyOrder(od))
+	}
+}
+
+/*
+matchTrade
+fill resting orders crossed by a public trade of Src. Orders crossed strictly are fully filled,
+orders at the trade price share the trade amount in time order.
+用Src的公开成交撮合挂单，被完全穿过的订单全部成交，价格相同的订单按时间顺序分摊成交量
+*/
+func (e *Paper) matchTrade(trade *banexg.Trade, ev *simEvents) {
+	stamp := trade.Timestamp
+	left := trade.Amount
+	for _, od := range append([]*simOrder(nil), e.opens[trade.Symbol]...) {
+		if od.trigger {
+			continue
+		}
+		var qty float64
+		if isBuy(od.Side) && trade.Price < od.Price || !isBuy(od.Side) && trade.Price > od.Price {
+			qty = od.Remaining
+		} else if trade.Price == od.Price && left > 0 {
+			qty = min(od.Remaining, left)
+			left -= qty
+		}
+		if qty > 0 {
+			e.fill(od, od.Price, qty, true, stamp, ev)
+		}
+	}
+	e.onPrice(trade.Symbol, trade.Price, stamp, ev)
+}
+
+/*
+matchBook
+fill resting orders crossed by the order book of Src, each level is consumed only once.
+用Src的订单簿撮合被穿过的挂单，每档数量只使用一次
+*/
+func (e *Paper) matchBook(symbol string, bids, asks []level, stamp int64, ev *simEvents) {
+	for _, od := range append([]*simOrder(nil), e.opens[symbol]...) {
+		if od.trigger {
+			continue
+		}
+		levels := asks
+		if !isBuy(od.Side) {
+			levels = bids
+		}
+		var qty float64
+		for i := range levels {
+			lv := &levels[i]
+			if isBuy(od.Side) && lv[0] > od.Price || !isBuy(od.Side) && lv[0] < od.Price {
+				break
+			}
+			take := min(lv[1], od.Remaining-qty)
+			lv[1] -= take
+			qty += take
+			if qty >= od.Remaining {
+				break
+			}
+		}
+		if qty > 0 {
+			e.fill(od, od.Price, qty, true, stamp, ev)
+		}
+	}
+	if len(bids) > 0 && len(asks) > 0 {
+		e.onPrice(symbol, (bids[0][0]+asks[0][0])/2, stamp, ev)
+	}
+}
+
+/*
+onPrice
+update last price, mark positions and fire trigger orders of symbol.
+Triggered orders take the ws order book of Src if exists, otherwise fill at price.
+更新最新价，标记持仓盈亏并触发条件单。触发的订单使用Src的ws订单簿撮合，无订单簿时按price成交
+*/
+func (e *Paper) onPrice(symbol string, price float64, stamp int64, ev *simEvents) {
+	if price <= 0 {
+		return
+	}
+	e.prices[symbol] = price
+	if pos, ok := e.positions[symbol]; ok && pos.Contracts > 0 {
+		if market, err := e.GetMarket(symbol); err == nil {
+			markPosition(market, pos, price)
+		}
+	}
+	for _, od := range append([]*simOrder(nil), e.opens[symbol]...) {
+		if !od.trigger || od.trigUp && price < od.TriggerPrice || !od.trigUp && price > od.TriggerPrice {
+			continue
+		}
+		od.trigger = false
+		e.closeOrder(od, banexg.OdStatusOpen, stamp, nil)
+		levels := []level{{price, math.MaxFloat64}}
+		if book := e.srcBook(symbol); book != nil {
+			levels = bookLevels(book, od.Side)
+		}
+		if err := e.execOrder(od, levels, stamp, ev); err != nil {
+			log.Warn("paper triggered order rejected", zap.String("id", od.ID), zap.Error(err))
+			e.closeOrder(od, banexg.OdStatusRejected, stamp, ev)
+		}
+	}
+}
+
+// srcBook return the ws order book of Src if it has levels, no lock required
+func (e *Paper) srcBook(symbol string) *banexg.OrderBook {
+	src := e.Src.GetExg()
+	src.OdBookLock.Lock()
+	book, ok := src.OrderBooks[symbol]
+	src.OdBookLock.Unlock()
+	if !ok || book.Asks == nil || book.Bids == nil {
+		return nil
+	}
+	if p, _ := book.Asks.Level(0); p == 0 {
+		return nil
+	}
+	return book
+}
+
+// bookLevels copy the levels which an order of side can take, no lock required
+func bookLevels(book *banexg.OrderBook, side string) []level {
+	obs := book.Asks
+	if !isBuy(side) {
+		obs = book.Bids
+	}
+	if obs == nil {
+		return nil
+	}
+	obs.Lock.Lock()
+	defer obs.Lock.Unlock()
+	res := make([]level, 0, len(obs.Price))
+	for i, p := range obs.Price {
+		if i < len(obs.Size) {
+			res = append(res, level{p, obs.Size[i]})
+		}
+	}
+	return res
+}
+
+func copyOrder(od *simOrder) *banexg.Order {
+	res := *od.Order
+	if od.Fee != nil {
+		fee := *od.Fee
+		res.Fee = &fee
+	}
+	return &res
+}
+
+func copyPosition(pos *banexg.Position) *banexg.Position {
+	res := *pos
+	return &res
+}
+
+// balances snapshot of assets, unrealized pnl of positions is set to UPol
+func (e *Paper) balances() *banexg.Balances {
+	upol := make(map[string]float64)
+	for symbol, pos := range e.positions {
+		if pos.Contracts <= 0 {
+			continue
+		}
+		if market, err := e.GetMarket(symbol); err == nil {
+			upol[market.Settle] += pos.UnrealizedPnl
+		}
+	}
+	res := &banexg.Balances{
+		TimeStamp: e.MilliSeconds(),
+		Assets:    make(map[string]*banexg.Asset, len(e.assets)),
+	}
+	for code, a := range e.assets {
+		item := *a
+		item.UPol = upol[code]
+		res.Assets[code] = &item
+	}
+	return res.Init()
+}
+
+// emit write events to ws chans, no lock required
+func (e *Paper) emit(ev *simEvents, bal *banexg.Balances) {
+	for _, trade := range ev.trades {
+		banexg.WriteOutChan(e.Exchange, chanMyTrades, trade, true)
+	}
+	for _, od := range ev.orders {
+		banexg.WriteOutChan(e.Exchange, chanOrders, od, true)
+	}
+	if bal != nil {
+		banexg.WriteOutChan(e.Exchange, chanBalance, bal, true)
+	}
+	if len(ev.positions) > 0 {
+		banexg.WriteOutChan(e.Exchange, chanPositions, ev.positions, true)
+	}
+}
+
+// unlockEmit release e.lock and emit events
+func (e *Paper) unlockEmit(ev *simEvents) {
+	var bal *banexg.Balances
+	if ev.balance {
+		bal = e.balances()
+	}
+	e.lock.Unlock()
+	e.emit(ev, bal)
+}
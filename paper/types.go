@@ -0,0 +1,54 @@
+package paper
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/sasha-s/go-deadlock"
+)
+
+/*
+Paper
+Simulated exchange on top of the market data of Src. Orders are matched locally against the order books and
+trades of Src, balances and positions of one account are kept in memory.
+基于Src行情数据的模拟交易所。订单在本地根据Src的订单簿和成交撮合，单账户的资产和持仓保存在内存中
+*/
+type Paper struct {
+	*banexg.Exchange
+	Src banexg.BanExchange
+
+	lock      deadlock.Mutex
+	nextID    int64
+	orders    map[string]*simOrder        // id: order, including closed orders
+	opens     map[string][]*simOrder      // symbol: open orders in time order
+	myTrades  []*banexg.MyTrade           // fills in time order
+	assets    map[string]*banexg.Asset    // code: asset
+	positions map[string]*banexg.Position // symbol: position of one-way mode
+	leverages map[string]int              // symbol: leverage
+	prices    map[string]float64          // symbol: last trade price
+	srcFeeds  map[interface{}]bool        // chans of Src which are being consumed
+	tradeSyms map[string]bool             // symbols whose trades of Src are watched
+	feedLock  deadlock.Mutex              // for srcFeeds, tradeSyms
+	bookDepth int
+	defLever  int
+}
+
+type simOrder struct {
+	*banexg.Order
+	market   *banexg.Market
+	lockCode string  // currency of locked
+	locked   float64 // locked amount of quote(spot buy)/base(spot sell)/settle(contract), not released yet
+	lockQty  float64 // amount of order covered by locked
+	trigger  bool    // trigger order which is not triggered yet
+	trigUp   bool    // triggered when price rises to TriggerPrice, else falls to it
+	isMarket bool    // fill at any price of the book
+}
+
+// level of order book: price, size
+type level = [2]float64
+
+// events produced under lock, emitted to ws chans after unlock
+type simEvents struct {
+	trades    []*banexg.MyTrade
+	orders    []*banexg.Order
+	positions []*banexg.Position
+	balance   bool
+}
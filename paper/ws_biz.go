@@ -0,0 +1,231 @@
+package paper
+
+import (
+	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+/*
+WatchOrderBooks
+watch order books of Src, each update is also used to match resting orders.
+订阅Src的订单簿，每次更新同时用于撮合挂单
+*/
+func (e *Paper) WatchOrderBooks(symbols []string, limit int, params map[string]interface{}) (chan *banexg.OrderBook, *errs.Error) {
+	args := utils.SafeParams(params)
+	src, err := e.Src.WatchOrderBooks(symbols, limit, args)
+	if err != nil {
+		return nil, err
+	}
+	create := func(cap int) chan *banexg.OrderBook { return make(chan *banexg.OrderBook, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanOrderBook, create, args)
+	e.AddWsChanRefs(chanOrderBook, symbols...)
+	if e.addFeed(src) {
+		go func() {
+			defer e.delFeed(src)
+			for book := range src {
+				e.onBook(book)
+			}
+		}()
+	}
+	return out, nil
+}
+
+func (e *Paper) UnWatchOrderBooks(symbols []string, params map[string]interface{}) *errs.Error {
+	e.DelWsChanRefs(chanOrderBook, symbols...)
+	return e.Src.UnWatchOrderBooks(symbols, params)
+}
+
+/*
+WatchTrades
+watch public trades of Src, each trade is also used to match resting orders.
+订阅Src的公开成交，每笔成交同时用于撮合挂单
+*/
+func (e *Paper) WatchTrades(symbols []string, params map[string]interface{}) (chan *banexg.Trade, *errs.Error) {
+	args := utils.SafeParams(params)
+	err := e.watchSrcTrades(symbols, args)
+	if err != nil {
+		return nil, err
+	}
+	create := func(cap int) chan *banexg.Trade { return make(chan *banexg.Trade, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanTrades, create, args)
+	e.AddWsChanRefs(chanTrades, symbols...)
+	return out, nil
+}
+
+/*
+UnWatchTrades
+trades of Src are kept watching for symbols which have open orders
+有挂单的品种保持订阅Src的成交
+*/
+func (e *Paper) UnWatchTrades(symbols []string, params map[string]interface{}) *errs.Error {
+	e.DelWsChanRefs(chanTrades, symbols...)
+	e.lock.Lock()
+	var unSyms []string
+	for _, sym := range symbols {
+		if len(e.opens[sym]) == 0 {
+			unSyms = append(unSyms, sym)
+		}
+	}
+	e.lock.Unlock()
+	if len(unSyms) == 0 {
+		return nil
+	}
+	e.feedLock.Lock()
+	for _, sym := range unSyms {
+		delete(e.tradeSyms, sym)
+	}
+	e.feedLock.Unlock()
+	return e.Src.UnWatchTrades(unSyms, params)
+}
+
+// watchSrcTrades watch trades of Src for symbols which are not watched yet, and consume the chan
+func (e *Paper) watchSrcTrades(symbols []string, params map[string]interface{}) *errs.Error {
+	var newSyms []string
+	e.feedLock.Lock()
+	for _, sym := range symbols {
+		if !e.tradeSyms[sym] {
+			newSyms = append(newSyms, sym)
+		}
+	}
+	e.feedLock.Unlock()
+	if len(newSyms) == 0 {
+		return nil
+	}
+	src, err := e.Src.WatchTrades(newSyms, params)
+	if err != nil {
+		return err
+	}
+	e.feedLock.Lock()
+	for _, sym := range newSyms {
+		e.tradeSyms[sym] = true
+	}
+	e.feedLock.Unlock()
+	if e.addFeed(src) {
+		go func() {
+			defer e.delFeed(src)
+			for trade := range src {
+				e.onTrade(trade)
+			}
+		}()
+	}
+	return nil
+}
+
+// watchOrderFeed make sure resting orders of symbol can be matched by trades of Src
+func (e *Paper) watchOrderFeed(symbol string) {
+	err := e.watchSrcTrades([]string{symbol}, nil)
+	if err != nil {
+		log.Warn("paper watch trades fail, resting orders will not be filled",
+			zap.String("symbol", symbol), zap.Error(err))
+	}
+}
+
+// addFeed return true if src chan is not consumed yet
+func (e *Paper) addFeed(src interface{}) bool {
+	e.feedLock.Lock()
+	defer e.feedLock.Unlock()
+	if e.srcFeeds[src] {
+		return false
+	}
+	e.srcFeeds[src] = true
+	return true
+}
+
+func (e *Paper) delFeed(src interface{}) {
+	e.feedLock.Lock()
+	delete(e.srcFeeds, src)
+	e.feedLock.Unlock()
+}
+
+func (e *Paper) onTrade(trade *banexg.Trade) {
+	if trade.Timestamp == 0 {
+		trade.Timestamp = e.MilliSeconds()
+	}
+	ev := &simEvents{}
+	e.lock.Lock()
+	e.matchTrade(trade, ev)
+	e.unlockEmit(ev)
+	if e.HasWsChanRef(chanTrades, trade.Symbol) {
+		banexg.WriteOutChan(e.Exchange, chanTrades, trade, true)
+	}
+}
+
+func (e *Paper) onBook(book *banexg.OrderBook) {
+	stamp := book.TimeStamp
+	if stamp == 0 {
+		stamp = e.MilliSeconds()
+	}
+	bids := bookLevels(book, banexg.OdSideSell)
+	asks := bookLevels(book, banexg.OdSideBuy)
+	ev := &simEvents{}
+	e.lock.Lock()
+	e.matchBook(book.Symbol, bids, asks, stamp, ev)
+	e.unlockEmit(ev)
+	if e.HasWsChanRef(chanOrderBook, book.Symbol) {
+		banexg.WriteOutChan(e.Exchange, chanOrderBook, book, true)
+	}
+}
+
+func (e *Paper) WatchOHLCVs(jobs [][2]string, params map[string]interface{}) (chan *banexg.PairTFKline, *errs.Error) {
+	return e.Src.WatchOHLCVs(jobs, params)
+}
+
+func (e *Paper) UnWatchOHLCVs(jobs [][2]string, params map[string]interface{}) *errs.Error {
+	return e.Src.UnWatchOHLCVs(jobs, params)
+}
+
+func (e *Paper) WatchMarkPrices(symbols []string, params map[string]interface{}) (chan map[string]float64, *errs.Error) {
+	return e.Src.WatchMarkPrices(symbols, params)
+}
+
+func (e *Paper) UnWatchMarkPrices(symbols []string, params map[string]interface{}) *errs.Error {
+	return e.Src.UnWatchMarkPrices(symbols, params)
+}
+
+func (e *Paper) WatchTickers(symbols []string, params map[string]interface{}) (chan *banexg.Ticker, *errs.Error) {
+	return e.Src.WatchTickers(symbols, params)
+}
+
+func (e *Paper) UnWatchTickers(symbols []string, params map[string]interface{}) *errs.Error {
+	return e.Src.UnWatchTickers(symbols, params)
+}
+
+/*
+WatchMyTrades
+watch simulated fills of this account
+订阅本账户的模拟成交
+*/
+func (e *Paper) WatchMyTrades(params map[string]interface{}) (chan *banexg.MyTrade, *errs.Error) {
+	args := utils.SafeParams(params)
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
+	create := func(cap int) chan *banexg.MyTrade { return make(chan *banexg.MyTrade, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanMyTrades, create, args)
+	e.AddWsChanRefs(chanMyTrades, refKey)
+	return out, nil
+}
+
+func (e *Paper) WatchOrders(params map[string]interface{}) (chan *banexg.Order, *errs.Error) {
+	args := utils.SafeParams(params)
+	refKey := utils.PopMapVal(args, banexg.ParamRefKey, "account")
+	create := func(cap int) chan *banexg.Order { return make(chan *banexg.Order, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanOrders, create, args)
+	e.AddWsChanRefs(chanOrders, refKey)
+	return out, nil
+}
+
+func (e *Paper) WatchBalance(params map[string]interface{}) (chan *banexg.Balances, *errs.Error) {
+	create := func(cap int) chan *banexg.Balances { return make(chan *banexg.Balances, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanBalance, create, utils.SafeParams(params))
+	e.AddWsChanRefs(chanBalance, "account")
+	return out, nil
+}
+
+func (e *Paper) WatchPositions(params map[string]interface{}) (chan []*banexg.Position, *errs.Error) {
+	create := func(cap int) chan []*banexg.Position { return make(chan []*banexg.Position, cap) }
+	out := banexg.GetWsOutChan(e.Exchange, chanPositions, create, utils.SafeParams(params))
+	e.AddWsChanRefs(chanPositions, "account")
+	return out, nil
+}
@@ -0,0 +1,232 @@
+package banexg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"github.com/banbox/bntp"
+)
+
+/*
+GetRateBucket
+Get the token bucket for key, shared by all exchange instances in this process.
+获取指定key的令牌桶，同一进程内所有交易所实例共享
+*/
+func GetRateBucket(key string, capacity float64, interval int64) *RateBucket {
+	rateBucketLock.Lock()
+	defer rateBucketLock.Unlock()
+	b, ok := rateBuckets[key]
+	if !ok {
+		b = &RateBucket{Capacity: capacity, Interval: interval, tokens: capacity, lastMS: bntp.UTCStamp()}
+		rateBuckets[key] = b
+	}
+	return b
+}
+
+func (b *RateBucket) refill(nowMS int64) {
+	if nowMS > b.lastMS && b.Interval > 0 {
+		b.tokens += float64(nowMS-b.lastMS) * b.Capacity / float64(b.Interval)
+		b.tokens = math.Min(b.tokens, b.Capacity)
+	}
+	b.lastMS = nowMS
+}
+
+/*
+Reserve
+Take cost tokens from the bucket and return the milliseconds to wait before sending.
+Tokens may go negative, so concurrent callers queue up in order.
+从桶中扣除cost个令牌，返回发送前需等待的毫秒数。令牌可为负数，并发请求依次排队
+*/
+func (b *RateBucket) Reserve(cost float64, nowMS int64) int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill(nowMS)
+	b.tokens -= cost
+	var waitMS int64
+	if b.blockUntil > nowMS {
+		waitMS = b.blockUntil - nowMS
+	}
+	if b.tokens < 0 && b.Capacity > 0 {
+		refillMS := int64(math.Ceil(-b.tokens * float64(b.Interval) / b.Capacity))
+		waitMS = max(waitMS, refillMS)
+	}
+	return waitMS
+}
+
+// Refund give back tokens of a reservation which was not sent
+func (b *RateBucket) Refund(cost float64) {
+	b.lock.Lock()
+	b.tokens = math.Min(b.tokens+cost, b.Capacity)
+	b.lock.Unlock()
+}
+
+/*
+Sync
+Sync the bucket with the remaining weight reported by exchange. capacity/resetMS are ignored when <= 0.
+根据交易所返回的剩余权重同步令牌桶，capacity/resetMS小于等于0时忽略
+*/
+func (b *RateBucket) Sync(remain, capacity float64, resetMS, nowMS int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill(nowMS)
+	if capacity > 0 {
+		b.Capacity = capacity
+	}
+	b.tokens = math.Min(b.tokens, remain)
+	if remain <= 0 && resetMS > nowMS {
+		b.blockUntil = resetMS
+	}
+}
+
+func (r *WeightRule) match(api *Entry) bool {
+	if r.Limit <= 0 || r.Interval <= 0 {
+		return false
+	}
+	if len(r.Hosts) > 0 && !slices.Contains(r.Hosts, api.Host) {
+		return false
+	}
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, api.Method) {
+		return false
+	}
+	return true
+}
+
+func (r *WeightRule) cost(apiCost float64) float64 {
+	if r.Fixed > 0 {
+		return r.Fixed
+	}
+	if r.Ratio > 0 {
+		return apiCost * r.Ratio
+	}
+	return apiCost
+}
+
+func (e *Exchange) weightBucketKey(idx int, rule *WeightRule, api *Entry, accKey string) string {
+	key := fmt.Sprintf("%s|%d%s|%s", e.ID, idx, rule.Header, api.RawHost)
+	if rule.Account && accKey != "" {
+		key += "|" + accKey
+	}
+	if rule.PerPath {
+		key += "|" + api.Method + api.Path
+	}
+	return key
+}
+
+/*
+rateAccKey
+return a key to identify the account for per-account weight limits, empty for public requests
+返回用于按账户限流的标识，公共请求返回空
+*/
+func (e *Exchange) rateAccKey(params map[string]interface{}) string {
+	if len(e.Accounts) == 0 {
+		return ""
+	}
+	name := e.GetAccName(params)
+	acc, ok := e.Accounts[name]
+	if !ok && name == "" && len(e.Accounts) == 1 {
+		for _, a := range e.Accounts {
+			acc, ok = a, true
+		}
+	}
+	if !ok {
+		return ""
+	}
+	if acc.Creds != nil && acc.Creds.ApiKey != "" {
+		return utils.MD5([]byte(acc.Creds.ApiKey))[:12]
+	}
+	return acc.Name
+}
+
+/*
+waitWeightLimits
+Block until all matched weight buckets have enough tokens for this request
+阻塞直到所有匹配的权重令牌桶都有足够令牌
+*/
+func (e *Exchange) waitWeightLimits(ctx context.Context, api *Entry, cost float64, accKey string) *errs.Error {
+	if len(e.WeightRules) == 0 {
+		return nil
+	}
+	var waitMS int64
+	var buckets []*RateBucket
+	var costs []float64
+	nowMS := bntp.UTCStamp()
+	for i, rule := range e.WeightRules {
+		if !rule.match(api) {
+			continue
+		}
+		ruleCost := rule.cost(cost)
+		b := GetRateBucket(e.weightBucketKey(i, rule, api, accKey), rule.Limit, rule.Interval)
+		waitMS = max(waitMS, b.Reserve(ruleCost, nowMS))
+		buckets = append(buckets, b)
+		costs = append(costs, ruleCost)
+	}
+	if waitMS <= 0 {
+		return nil
+	}
+	if e.DebugAPI {
+		log.Debug(fmt.Sprintf("weight limit, wait %d ms for %s", waitMS, api.Url))
+	}
+	if err := SleepCtx(ctx, time.Duration(waitMS)*time.Millisecond); err != nil {
+		for i, b := range buckets {
+			b.Refund(costs[i])
+		}
+		return err
+	}
+	return nil
+}
+
+/*
+syncWeightLimits
+Update weight buckets from the usage headers returned by exchange
+根据交易所返回的用量响应头更新权重令牌桶
+*/
+func (e *Exchange) syncWeightLimits(api *Entry, accKey string, head http.Header) {
+	if len(e.WeightRules) == 0 || head == nil {
+		return
+	}
+	nowMS := bntp.UTCStamp()
+	for i, rule := range e.WeightRules {
+		if rule.Header == "" || !rule.match(api) {
+			continue
+		}
+		val, ok := parseHeaderFloat(head, rule.Header)
+		if !ok {
+			continue
+		}
+		capacity, _ := parseHeaderFloat(head, rule.LimitHeader)
+		resetMS, _ := parseHeaderFloat(head, rule.ResetHeader)
+		remain := val
+		if !rule.Remain {
+			limit := rule.Limit
+			if capacity > 0 {
+				limit = capacity
+			}
+			remain = limit - val
+		}
+		b := GetRateBucket(e.weightBucketKey(i, rule, api, accKey), rule.Limit, rule.Interval)
+		b.Sync(remain, capacity, int64(resetMS), nowMS)
+	}
+}
+
+func parseHeaderFloat(head http.Header, key string) (float64, bool) {
+	if key == "" {
+		return 0, false
+	}
+	text := head.Get(key)
+	if text == "" {
+		return 0, false
+	}
+	val, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, false
+	}
+	return val, true
+}
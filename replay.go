@@ -0,0 +1,258 @@
+package banexg
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
+	"go.uber.org/zap"
+)
+
+/*
+RequestApiRetryAdv
+request api with retry. In dump mode the response is recorded as WsLogHttpRes together with websocket messages,
+in replay mode the recorded response is returned without network.
+带重试请求api。dump模式下响应与websocket消息一起记录为WsLogHttpRes，replay模式下返回记录的响应，不访问网络
+*/
+func (e *Exchange) RequestApiRetryAdv(ctx context.Context, endpoint string, params map[string]interface{}, retryNum int, readCache, writeCache bool) *HttpRes {
+	if e.WsDecoder == nil && e.WsEncoder == nil {
+		return e.requestApiRetryAdv(ctx, endpoint, params, retryNum, readCache, writeCache)
+	}
+	key := apiLogKey(endpoint, params)
+	if e.WsDecoder != nil {
+		return e.replayApi(key)
+	}
+	rsp := e.requestApiRetryAdv(ctx, endpoint, params, retryNum, readCache, writeCache)
+	res := *rsp
+	res.Error = nil
+	item := &ApiLog{Key: key, Res: &res}
+	if rsp.Error != nil {
+		item.ErrCode = rsp.Error.Code
+		item.ErrBizCode = rsp.Error.BizCode
+		item.ErrMsg = rsp.Error.Message()
+	}
+	e.DumpWS(WsLogHttpRes, item)
+	return rsp
+}
+
+/*
+apiLogKey
+key of a rest request for replay, params are sorted and local-only params are ignored
+用于重放的rest请求键，参数排序，忽略仅本地使用的参数
+*/
+func apiLogKey(endpoint string, params map[string]interface{}) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == ParamCtx || k == ParamDebug || k == ParamNoCache || k == ParamRetry {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(endpoint)
+	for i, k := range keys {
+		if i == 0 {
+			b.WriteString("?")
+		} else {
+			b.WriteString("&")
+		}
+		b.WriteString(fmt.Sprintf("%s=%v", k, params[k]))
+	}
+	return b.String()
+}
+
+/*
+replayApi
+return the recorded response of key. Responses of the same key are returned in recorded order, the last one is
+kept for later requests.
+返回key对应的已记录响应，同一key的多个响应按记录顺序返回，最后一个保留用于后续请求
+*/
+func (e *Exchange) replayApi(key string) *HttpRes {
+	e.apiReplayLock.Lock()
+	items := e.ApiReplays[key]
+	if len(items) > 1 {
+		e.ApiReplays[key] = items[1:]
+	}
+	e.apiReplayLock.Unlock()
+	if len(items) == 0 {
+		err := errs.NewMsg(errs.CodeDataNotFound, "no recorded response for %s: %s", e.Name, key)
+		return &HttpRes{Error: err}
+	}
+	item := items[0]
+	res := &HttpRes{}
+	if item.Res != nil {
+		*res = *item.Res
+	}
+	if item.ErrCode != 0 {
+		res.Error = errs.NewMsg(item.ErrCode, "%s", item.ErrMsg)
+		res.Error.BizCode = item.ErrBizCode
+	}
+	return res
+}
+
+/*
+loadApiReplays
+scan the dump file, index recorded rest responses by key, and return the time of the first log
+扫描dump文件，按key索引记录的rest响应，并返回第一条记录的时间
+*/
+func loadApiReplays(path string) (map[string][]*ApiLog, int64, *errs.Error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, errs.New(errs.CodeIOReadFail, err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, 0, errs.New(errs.CodeIOReadFail, err)
+	}
+	defer reader.Close()
+	decoder := gob.NewDecoder(reader)
+	res := make(map[string][]*ApiLog)
+	var firstMS int64
+	for {
+		var rows []*WsLog
+		if err = decoder.Decode(&rows); err != nil {
+			break
+		}
+		for _, row := range rows {
+			if firstMS == 0 {
+				firstMS = row.TimeMS
+			}
+			if row.Name != WsLogHttpRes {
+				continue
+			}
+			var item ApiLog
+			if err_ := utils.UnmarshalString(row.Content, &item, utils.JsonNumDefault); err_ != nil {
+				log.Warn("unmarshal recorded api fail", zap.Error(err_))
+				continue
+			}
+			res[item.Key] = append(res[item.Key], &item)
+		}
+	}
+	if err != io.EOF {
+		log.Warn("read dump file end with error", zap.String("path", path), zap.Error(err))
+	}
+	return res, firstMS, nil
+}
+
+/*
+dumpFilePath
+the dump file of exchange in path. When path is a directory, each exchange uses its own file "<id>.dump" in it
+path中交易所的dump文件。path为目录时，每个交易所使用其中的"<id>.dump"文件
+*/
+func (e *Exchange) dumpFilePath(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, e.ID+".dump")
+	}
+	return path
+}
+
+/*
+replayWsMsg
+default handler of wsMsg in replay mode, feed the raw message to the ws client
+replay模式下wsMsg的默认处理函数，将原始消息交给ws客户端处理
+*/
+func (e *Exchange) replayWsMsg(item *WsLog) *errs.Error {
+	var arr = make([]string, 0)
+	err_ := utils.UnmarshalString(item.Content, &arr, utils.JsonNumDefault)
+	if err_ != nil {
+		return errs.New(errs.CodeUnmarshalFail, err_)
+	}
+	if len(arr) < 4 {
+		return errs.NewMsg(errs.CodeParamInvalid, "wsMsg content invalid")
+	}
+	client, err := e.GetClient(arr[0], arr[1], arr[2])
+	if err != nil {
+		return err
+	}
+	client.HandleRawMsg([]byte(arr[3]))
+	return nil
+}
+
+// getReplayFn return the replay handler of name, nil for logs which need no handling
+func (e *Exchange) getReplayFn(name string) (func(item *WsLog) *errs.Error, bool) {
+	if handle, ok := e.WsReplayFn[name]; ok {
+		return handle, true
+	}
+	if name == WsLogHttpRes {
+		return nil, true
+	} else if name == WsLogWsMsg {
+		return e.replayWsMsg, true
+	}
+	return nil, false
+}
+
+// replayConn is used by ws clients in replay mode, it never connects and messages come from the dump file
+type replayConn struct {
+	id   int
+	done chan struct{}
+	once sync.Once
+}
+
+func newReplayConn() *AsyncConn {
+	return &AsyncConn{
+		WsConn:  &replayConn{done: make(chan struct{})},
+		send:    make(chan []byte, 10),
+		control: make(chan int, 2),
+	}
+}
+
+func (c *replayConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	return nil
+}
+
+func (c *replayConn) WriteClose() error {
+	return nil
+}
+
+func (c *replayConn) ReConnect() error {
+	return nil
+}
+
+func (c *replayConn) NextWriter() (io.WriteCloser, error) {
+	return nopWriteCloser{Writer: io.Discard}, nil
+}
+
+func (c *replayConn) ReadMsg() ([]byte, error) {
+	<-c.done
+	return nil, io.EOF
+}
+
+func (c *replayConn) IsOK() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *replayConn) GetID() int {
+	return c.id
+}
+
+func (c *replayConn) SetID(v int) {
+	c.id = v
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (n nopWriteCloser) Close() error {
+	return nil
+}
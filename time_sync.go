@@ -0,0 +1,107 @@
+package banexg
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/banbox/banexg/errs"
+	"github.com/banbox/banexg/log"
+	"go.uber.org/zap"
+)
+
+/*
+SyncTime
+fetch server time of exchange and update TimeDelay, network latency is compensated by half of the round trip.
+Signed requests use Nonce() which subtracts TimeDelay, so they stay inside recvWindow when local clock drifts.
+获取交易所服务器时间并更新TimeDelay，使用往返耗时的一半补偿网络延迟。
+签名请求使用扣除TimeDelay的Nonce()，本地时钟漂移时仍在recvWindow内
+*/
+func (e *Exchange) SyncTime(params map[string]interface{}) *errs.Error {
+	if e.NetDisable {
+		return errs.NewMsg(errs.CodeNetDisable, "net disabled for %v, skip sync time", e.Name)
+	}
+	start := e.MilliSeconds()
+	serverMS, err := e.self().FetchTime(params)
+	if err != nil {
+		return err
+	}
+	end := e.MilliSeconds()
+	delay := (start+end)/2 - serverMS
+	old := atomic.SwapInt64(&e.TimeDelay, delay)
+	if old != delay {
+		log.Debug("sync server time", zap.String("exg", e.Name), zap.Int64("delay", delay),
+			zap.Int64("rtt", end-start))
+	}
+	return nil
+}
+
+/*
+GetTimeDelay
+milliseconds of local clock ahead of exchange server
+本地时钟领先交易所服务器的毫秒数
+*/
+func (e *Exchange) GetTimeDelay() int64 {
+	return atomic.LoadInt64(&e.TimeDelay)
+}
+
+/*
+ResyncOnNonce
+sync server time when err is caused by timestamp out of recvWindow, return true if the request should be
+signed and sent again.
+当错误是时间戳超出recvWindow时同步服务器时间，返回true表示应重新签名并重试
+*/
+func (e *Exchange) ResyncOnNonce(ctx context.Context, err *errs.Error) bool {
+	if err == nil || err.Code != errs.CodeInvalidNonce {
+		return false
+	}
+	err2 := e.SyncTime(WithCtx(ctx, nil))
+	if err2 != nil {
+		log.Warn("sync time for invalid nonce fail", zap.String("exg", e.Name), zap.Error(err2))
+		return false
+	}
+	log.Info("timestamp rejected, resign with synced time", zap.String("exg", e.Name),
+		zap.Int64("delay", e.GetTimeDelay()))
+	return true
+}
+
+/*
+startSyncTime
+sync server time every intvSecs until Close, called from Init when OptSyncTimeSecs > 0
+每intvSecs秒同步一次服务器时间，直到Close
+*/
+func (e *Exchange) startSyncTime(intvSecs int) {
+	if intvSecs <= 0 || e.WsDecoder != nil {
+		return
+	}
+	stop := make(chan struct{})
+	e.syncTimeLock.Lock()
+	if e.syncTimeStop != nil {
+		close(e.syncTimeStop)
+	}
+	e.syncTimeStop = stop
+	e.syncTimeLock.Unlock()
+	go func() {
+		ticker := time.NewTicker(time.Duration(intvSecs) * time.Second)
+		defer ticker.Stop()
+		for {
+			if err := e.SyncTime(nil); err != nil {
+				log.Warn("sync server time fail", zap.String("exg", e.Name), zap.Error(err))
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (e *Exchange) stopSyncTime() {
+	e.syncTimeLock.Lock()
+	defer e.syncTimeLock.Unlock()
+	if e.syncTimeStop != nil {
+		close(e.syncTimeStop)
+		e.syncTimeStop = nil
+	}
+}
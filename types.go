@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/banbox/banexg/errs"
 	"github.com/sasha-s/go-deadlock"
@@ -18,6 +19,9 @@ type FuncFetchMarkets = func(marketTypes []string, params map[string]interface{}
 type FuncAuthWS = func(acc *Account, params map[string]interface{}) *errs.Error
 type FuncCalcFee = func(market *Market, curr string, maker bool, amount, price decimal.Decimal, params map[string]interface{}) (*Fee, *errs.Error)
 
+// FuncMapBizErr map business code & message of exchange to errs code, return 0 if not a known trading error
+type FuncMapBizErr = func(bizCode int, msg string) int
+
 type FuncOnWsMsg = func(client *WsClient, msg *WsMsg)
 type FuncOnWsMethod = func(client *WsClient, msg map[string]string, info *WsJobInfo)
 type FuncOnWsErr = func(client *WsClient, err *errs.Error)
@@ -31,6 +35,9 @@ type FuncCalcRateLimiterCost = func(api *Entry, params map[string]interface{}) f
 // key: acc@url#marketType@method
 type FuncOnWsChan = func(key string, out interface{})
 
+// called after a ws order book failed the integrity check and was reset, before the snapshot is re-fetched
+type FuncOnOdBookReset = func(client *WsClient, evt *OdBookReset)
+
 type Exchange struct {
 	*ExgInfo
 	Hosts   *ExgHosts
@@ -50,7 +57,9 @@ type Exchange struct {
 	lastRequestMS       int64          // 上次请求的13位时间戳
 	rateM               deadlock.Mutex // 同步锁
 	CalcRateLimiterCost FuncCalcRateLimiterCost
-	WsTimeout           int64 // websocket msg timeout in milliseconds
+	WeightRules         []*WeightRule // 按交易所返回的权重限流的规则，由子交易所设置
+	WsTimeout           int64         // websocket msg timeout in milliseconds
+	WsTrade             bool          // send orders by websocket trading api if supported, see OptWsTrade
 	WsChecking          bool
 
 	MarketsWait chan interface{} // whether is loading markets
@@ -63,39 +72,56 @@ type Exchange struct {
 
 	Retries map[string]int // retry nums for methods
 
-	TimeDelay  int64 // 系统时钟延迟的毫秒数
+	TimeDelay  int64 // 系统时钟延迟的毫秒数，本地时间减服务器时间，使用GetTimeDelay读取
 	HttpClient *http.Client
 	NetDisable bool
 
-	WSClients  map[string]*WsClient           // accName@url: websocket clients
-	WsIntvs    map[string]int                 // milli secs interval for ws endpoints
-	WsOutChans map[string]interface{}         // accName@url+msgHash: chan Type
-	WsChanRefs map[string]map[string]struct{} // accName@url+msgHash: symbols use this chan
-
-	WsCache     []*WsLog // websocket cache logs waiting for replay/dump
-	WsNextMS    int64    // timestamp of next replay log
-	WsReplayTo  int64    // timestamp of latest replay log
-	WsFile      *os.File // file to replay/dump
-	WsWriter    *gzip.Writer
-	WsEncoder   *gob.Encoder
-	WsReader    *gzip.Reader
-	WsDecoder   *gob.Decoder
-	WsBatchSize int
-	WsReplayFn  map[string]func(item *WsLog) *errs.Error
-	wsCacheLock deadlock.Mutex
-	lockWsRef   deadlock.Mutex
-	lockOutChan deadlock.Mutex
+	WSClients  map[string]*WsClient              // accName@url: websocket clients
+	WsIntvs    map[string]int                    // milli secs interval for ws endpoints
+	WsOutChans map[string]interface{}            // accName@url+msgHash: chan Type
+	WsChanRefs map[string]map[string]struct{}    // accName@url+msgHash: symbols use this chan
+	WsChanTaps map[string]map[string]interface{} // accName@url+msgHash: key: chan Type, private copies of out chans
+
+	WsCache       []*WsLog // websocket cache logs waiting for replay/dump
+	WsNextMS      int64    // timestamp of next replay log
+	WsReplayTo    int64    // timestamp of latest replay log
+	WsFile        *os.File // file to replay/dump
+	WsWriter      *gzip.Writer
+	WsEncoder     *gob.Encoder
+	WsReader      *gzip.Reader
+	WsDecoder     *gob.Decoder
+	WsBatchSize   int
+	WsReplayFn    map[string]func(item *WsLog) *errs.Error
+	ApiReplays    map[string][]*ApiLog // recorded rest responses for replay, key: endpoint with sorted params
+	wsCacheLock   deadlock.Mutex
+	dumpLock      deadlock.Mutex
+	apiReplayLock deadlock.Mutex
+	replayNetOff  bool // NetDisable before SetReplay, restored when replay stops
+	syncTimeStop  chan struct{}
+	syncTimeLock  deadlock.Mutex
+	cancelAfters  map[string]*cancelAfterJob // key: account@scope, keepers of KeepCancelAllAfter
+	cancelLock    deadlock.Mutex
+	reConHooks    map[string]FuncOnWsReCon // called after OnWsReCon, key: name of the listener
+	reConLock     deadlock.Mutex
+	lockWsRef     deadlock.Mutex
+	lockOutChan   deadlock.Mutex
 
 	KeyTimeStamps map[string]int64 // key: int64 更新的时间戳
 
 	// for calling sub struct func in parent struct
+	Self            BanExchange // the outermost exchange, used by *Ctx methods
 	Sign            FuncSign
 	FetchCurrencies FuncFetchCurr
 	FetchMarkets    FuncFetchMarkets
 	AuthWS          FuncAuthWS
 	CalcFee         FuncCalcFee
+	MapBizErr       FuncMapBizErr           // 将交易所业务错误码映射为errs中的标准错误码
 	GetRetryWait    func(e *errs.Error) int // 根据错误信息计算重试间隔秒数，<0表示无需重试
 	CheckWsTimeout  func()
+	// scope of the exchange timer of SetCancelAllAfter, keepers in the same scope replace each other, symbol by default
+	CancelAfterScope func(symbol string, params map[string]interface{}) string
+	// key of Account.MarPositions/MarBalances maintained by ws for marketType, marketType by default
+	AccStateKey func(marketType string) string
 
 	OnWsMsg   FuncOnWsMsg
 	OnWsErr   FuncOnWsErr
@@ -103,17 +129,21 @@ type Exchange struct {
 	OnWsReCon FuncOnWsReCon
 	OnWsChan  FuncOnWsChan
 
+	OnOdBookReset FuncOnOdBookReset
+
 	Flags map[string]string
 }
 
 type ExgInfo struct {
-	ID        string   // 交易所ID
-	Name      string   // 显示名称
-	Countries []string // 可用国家
-	NoHoliday bool     // true表示365天全年开放
-	FullDay   bool     // true表示一天24小时可交易
-	Min1mHole int      // 1分钟K线空洞的最小间隔，少于此认为正常无交易而非空洞
-	FixedLvg  bool     // 杠杆倍率是否固定不可修改
+	ID         string         // 交易所ID
+	Name       string         // 显示名称
+	Countries  []string       // 可用国家
+	NoHoliday  bool           // true表示365天全年开放
+	FullDay    bool           // true表示一天24小时可交易
+	Min1mHole  int            // 1分钟K线空洞的最小间隔，少于此认为正常无交易而非空洞
+	OHLCVLimit int            // 单次FetchOHLCV请求的最大K线数，FetchOHLCVRange分页使用
+	FixedLvg   bool           // 杠杆倍率是否固定不可修改
+	TimeLoc    *time.Location // 交易时段的本地时区，NoHoliday为false时，此时区周末开始的交易时段视为休市
 
 	DebugWS  bool // 是否输出WS调试信息
 	DebugAPI bool // 是否输出API请求测试信息
@@ -127,8 +157,10 @@ type ExgInfo struct {
 	MarketsById      MarketArrMap                  // markets index by id
 	OrderBooks       map[string]*OrderBook         // symbol: OrderBook update by wss
 	MarkPrices       map[string]map[string]float64 // marketType: symbol: mark price
+	Tickers          map[string]*Ticker            // symbol: Ticker merged from wss updates
 	OdBookLock       deadlock.Mutex
 	MarkPriceLock    deadlock.Mutex
+	TickerLock       deadlock.Mutex
 	MarketsByIdLock  deadlock.Mutex
 	MarketsLock      deadlock.Mutex
 	CurrByCodeLock   deadlock.Mutex
@@ -203,6 +235,36 @@ type Entry struct {
 	Risky     bool // 危险操作：下单、撤单、修改订单、修改杠杆等
 }
 
+/*
+WeightRule
+Token bucket rule for exchange weight limits. The bucket is synced from the used/remaining
+value the exchange returns in response headers, and requests block before the limit is hit.
+交易所权重限流规则，根据响应头返回的已用/剩余值同步令牌桶，在触发限制前阻塞请求
+*/
+type WeightRule struct {
+	Header      string   // header of used (or remaining when Remain) weight, empty for local only
+	LimitHeader string   // optional header of the current limit, overrides Limit
+	ResetHeader string   // optional header of the 13-digit reset timestamp
+	Hosts       []string // Entry.Host keys this rule applies to, empty for all
+	Methods     []string // http methods this rule applies to, empty for all
+	Limit       float64  // max weight in one Interval
+	Interval    int64    // window milliseconds
+	Ratio       float64  // weight of one Entry.Cost unit, 0 means 1
+	Fixed       float64  // fixed weight per request, instead of Entry.Cost
+	Remain      bool     // header value is the remaining count instead of used
+	Account     bool     // counted per account (api key) instead of per IP
+	PerPath     bool     // counted per Entry.Path
+}
+
+type RateBucket struct {
+	Capacity   float64
+	Interval   int64
+	tokens     float64
+	lastMS     int64
+	blockUntil int64
+	lock       deadlock.Mutex
+}
+
 type Credential struct {
 	ApiKey   string
 	Secret   string
@@ -500,6 +562,23 @@ type Order struct {
 	Fee                 *Fee                   `json:"fee"`
 }
 
+// OrderArgs arguments of one order for CreateOrders
+type OrderArgs struct {
+	Symbol string                 `json:"symbol"`
+	OdType string                 `json:"odType"`
+	Side   string                 `json:"side"`
+	Amount float64                `json:"amount"`
+	Price  float64                `json:"price"`
+	Params map[string]interface{} `json:"params"` // override the common params of CreateOrders
+}
+
+// OrderRes result of one order in batch operations, Err is set when this order failed
+type OrderRes struct {
+	ID    string      `json:"id"` // order id passed to CancelOrders, empty for CreateOrders
+	Order *Order      `json:"order"`
+	Err   *errs.Error `json:"-"`
+}
+
 type Trade struct {
 	ID        string                 `json:"id"`        // 交易ID
 	Symbol    string                 `json:"symbol"`    // 币种ID
@@ -545,16 +624,32 @@ type OrderBook struct {
 	Cache     []map[string]string
 }
 
+/*
+OdBookReset
+event of a ws order book which failed the sequence/checksum/crossed check and was reset
+ws订单簿未通过序号/校验和/交叉检查而被重置的事件
+*/
+type OdBookReset struct {
+	Symbol     string `json:"symbol"`
+	MarketType string `json:"marketType"`
+	Reason     string `json:"reason"` // OdBookResetGap/OdBookResetChecksum/OdBookResetCrossed
+	Nonce      int64  `json:"nonce"`  // nonce of local book when the check failed
+	Expect     int64  `json:"expect"` // expected sequence or checksum
+	Actual     int64  `json:"actual"` // received sequence or checksum
+	TimeStamp  int64  `json:"timestamp"`
+}
+
 /*
 OdBookSide
 On one side of the order book. No need to add a lock, as only one goroutine can be modified
 订单簿一侧。不需要加锁，因为只有一个goroutine可以修改
 */
 type OdBookSide struct {
-	IsBuy bool      `json:"is_buy"`
-	Price []float64 `json:"price"` // bid: desc   ask: asc
-	Size  []float64 `json:"size"`
-	Depth int       `json:"depth"`
+	IsBuy bool                  `json:"is_buy"`
+	Price []float64             `json:"price"` // bid: desc   ask: asc
+	Size  []float64             `json:"size"`
+	Depth int                   `json:"depth"`
+	Texts map[float64][2]string `json:"-"` // raw price and size strings of levels set by UpdateTexts, for Checksum
 	Lock  deadlock.Mutex
 }
 
@@ -569,6 +664,88 @@ type Income struct {
 	TradeID    string  `json:"tradeId"`
 }
 
+/*
+Transfer
+fund transfer between account types or sub accounts. FromAccount/ToAccount is AccountFunding or market type
+账户类型或子账户之间的资金划转。FromAccount/ToAccount为AccountFunding或市场类型
+*/
+type Transfer struct {
+	ID          string                 `json:"id"`
+	Timestamp   int64                  `json:"timestamp"`
+	Code        string                 `json:"code"`
+	Amount      float64                `json:"amount"`
+	FromAccount string                 `json:"fromAccount"`
+	ToAccount   string                 `json:"toAccount"`
+	FromSubAcc  string                 `json:"fromSubAcc,omitempty"`
+	ToSubAcc    string                 `json:"toSubAcc,omitempty"`
+	Status      string                 `json:"status"` // TransferOk/TransferPending/TransferFailed
+	Info        map[string]interface{} `json:"info"`
+}
+
+/*
+Transaction
+on-chain deposit or withdrawal. Status is TransferOk/TransferPending/TransferFailed/TransferCanceled
+链上充值或提现。Status为TransferOk/TransferPending/TransferFailed/TransferCanceled
+*/
+type Transaction struct {
+	ID        string                 `json:"id"`
+	TxID      string                 `json:"txid"`
+	Type      string                 `json:"type"` // TxDeposit/TxWithdrawal
+	Code      string                 `json:"code"`
+	Amount    float64                `json:"amount"`
+	Network   string                 `json:"network"`
+	Address   string                 `json:"address"`
+	Tag       string                 `json:"tag"`
+	Status    string                 `json:"status"`
+	Fee       *Fee                   `json:"fee"`
+	Timestamp int64                  `json:"timestamp"`
+	Updated   int64                  `json:"updated"`
+	Info      map[string]interface{} `json:"info"`
+}
+
+type DepositAddress struct {
+	Code    string                 `json:"code"`
+	Network string                 `json:"network"`
+	Address string                 `json:"address"`
+	Tag     string                 `json:"tag"`
+	Info    map[string]interface{} `json:"info"`
+}
+
+/*
+MarginLoan
+result of Borrow or Repay on margin account. Symbol is empty for cross margin
+杠杆账户借币或还币结果，全仓时Symbol为空
+*/
+type MarginLoan struct {
+	ID         string                 `json:"id"`
+	Code       string                 `json:"code"`
+	Amount     float64                `json:"amount"`
+	Symbol     string                 `json:"symbol"`
+	MarginMode string                 `json:"marginMode"` // MarginCross/MarginIsolated
+	Timestamp  int64                  `json:"timestamp"`
+	Info       map[string]interface{} `json:"info"`
+}
+
+// BorrowRate Rate is the interest rate for Period milliseconds, hourly for all exchanges now
+type BorrowRate struct {
+	Code      string                 `json:"code"`
+	Rate      float64                `json:"rate"`
+	Period    int64                  `json:"period"`
+	Timestamp int64                  `json:"timestamp"`
+	Info      map[string]interface{} `json:"info"`
+}
+
+type BorrowInterest struct {
+	Code       string                 `json:"code"`
+	Symbol     string                 `json:"symbol"`
+	MarginMode string                 `json:"marginMode"`
+	Interest   float64                `json:"interest"`
+	Rate       float64                `json:"rate"`
+	Amount     float64                `json:"amount"` // borrowed principal when accrued 计息时的借币本金
+	Timestamp  int64                  `json:"timestamp"`
+	Info       map[string]interface{} `json:"info"`
+}
+
 type FundingRate struct {
 	Symbol      string                 `json:"symbol"`
 	FundingRate float64                `json:"fundingRate"`
@@ -593,6 +770,60 @@ type FundingRateCur struct {
 	Info                 map[string]interface{} `json:"info"`
 }
 
+type OpenInterest struct {
+	Symbol    string                 `json:"symbol"`
+	Amount    float64                `json:"amount"` // open interest in base amount or contracts 持仓量
+	Value     float64                `json:"value"`  // notional value in quote currency 持仓价值
+	Timestamp int64                  `json:"timestamp"`
+	Info      map[string]interface{} `json:"info"`
+}
+
+type LongShortRatio struct {
+	Symbol     string                 `json:"symbol"`
+	Timeframe  string                 `json:"timeframe"`
+	Type       string                 `json:"type"` // RatioAccount/RatioTaker
+	Ratio      float64                `json:"ratio"`
+	LongRatio  float64                `json:"longRatio,omitempty"`  // share of long accounts or taker buy volume
+	ShortRatio float64                `json:"shortRatio,omitempty"` // share of short accounts or taker sell volume
+	BuyVolume  float64                `json:"buyVolume,omitempty"`  // taker buy volume, RatioTaker only
+	SellVolume float64                `json:"sellVolume,omitempty"` // taker sell volume, RatioTaker only
+	Timestamp  int64                  `json:"timestamp"`
+	Info       map[string]interface{} `json:"info"`
+}
+
+type Liquidation struct {
+	Symbol    string                 `json:"symbol"`
+	Side      string                 `json:"side"` // side of liquidation order, sell means a long position was liquidated
+	Price     float64                `json:"price"`
+	Amount    float64                `json:"amount"` // in base currency for all markets 基础币数量
+	Timestamp int64                  `json:"timestamp"`
+	Info      map[string]interface{} `json:"info"`
+}
+
+type Greeks struct {
+	Symbol          string                 `json:"symbol"`
+	Delta           float64                `json:"delta"`
+	Gamma           float64                `json:"gamma"`
+	Vega            float64                `json:"vega"`
+	Theta           float64                `json:"theta"`
+	MarkIV          float64                `json:"markIV"` // implied volatility of mark price 标记价格隐含波动率
+	BidIV           float64                `json:"bidIV"`
+	AskIV           float64                `json:"askIV"`
+	MarkPrice       float64                `json:"markPrice"`
+	UnderlyingPrice float64                `json:"underlyingPrice"`
+	Timestamp       int64                  `json:"timestamp"`
+	Info            map[string]interface{} `json:"info"`
+}
+
+type OptionChainItem struct {
+	Symbol     string  `json:"symbol"`
+	Underlying string  `json:"underlying"` // base currency code, e.g. BTC
+	Strike     float64 `json:"strike"`
+	OptionType string  `json:"optionType"` // call/put
+	Expiry     int64   `json:"expiry"`
+	Greeks     *Greeks `json:"greeks"` // nil if greeks of this option is missing
+}
+
 type LastPrice struct {
 	Symbol    string                 `json:"symbol"`
 	Timestamp int64                  `json:"timestamp"`
@@ -616,6 +847,7 @@ type WsJobInfo struct {
 	Symbols []string
 	Method  func(client *WsClient, msg map[string]string, info *WsJobInfo)
 	Params  map[string]interface{}
+	Result  chan *WsMsg // receive the response instead of Method, used by WsClient.Request
 }
 
 /*
@@ -642,6 +874,15 @@ type WsLog struct {
 	Content string `json:"content,omitempty"`
 }
 
+// ApiLog is a rest response recorded in dump file, Error of Res is kept in ErrCode/ErrBizCode/ErrMsg
+type ApiLog struct {
+	Key        string   `json:"key"`
+	Res        *HttpRes `json:"res"`
+	ErrCode    int      `json:"errCode,omitempty"`
+	ErrBizCode int      `json:"errBizCode,omitempty"`
+	ErrMsg     string   `json:"errMsg,omitempty"`
+}
+
 type OdBookShotLog struct {
 	MarketType string     `json:"marketType,omitempty"`
 	Symbol     string     `json:"symbol,omitempty"`
@@ -1,12 +1,14 @@
 package vietnam
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
 	"github.com/banbox/banexg/log"
+	"github.com/banbox/banexg/utils"
 	"go.uber.org/zap"
 )
 
@@ -18,14 +20,15 @@ func (e *Vietnam) LoadMarkets(reload bool, params map[string]interface{}) (banex
 	marketsByID := make(banexg.MarketArrMap)
 	lookupByRaw := make(map[string]*banexg.Market)
 	lookupByTicker := make(map[string][]*banexg.Market)
+	ctx := banexg.ParamsCtx(params)
 
 	for _, board := range marketBoards {
 		boardStart := e.MilliSeconds()
-		rows, err := e.fetchSecuritiesRows(board)
+		rows, err := e.fetchSecuritiesRows(ctx, board)
 		if err != nil {
 			return nil, err
 		}
-		detailRows, _ := e.fetchSecuritiesDetailRows(board)
+		detailRows, _ := e.fetchSecuritiesDetailRows(ctx, board)
 		log.Info("vietnam load markets board",
 			zap.String("board", board),
 			zap.Int("securities", len(rows)),
@@ -82,7 +85,7 @@ func (e *Vietnam) LoadMarkets(reload bool, params map[string]interface{}) (banex
 	return markets, nil
 }
 
-func (e *Vietnam) fetchSecuritiesRows(board string) ([]map[string]interface{}, *errs.Error) {
+func (e *Vietnam) fetchSecuritiesRows(ctx context.Context, board string) ([]map[string]interface{}, *errs.Error) {
 	rows := make([]map[string]interface{}, 0)
 	for page := 1; page <= 10; page++ {
 		payload := map[string]interface{}{
@@ -95,7 +98,7 @@ func (e *Vietnam) fetchSecuritiesRows(board string) ([]map[string]interface{}, *
 			zap.Int("page", page),
 			zap.Any("payload", payload),
 		)
-		data, err := requestSSI[[]map[string]interface{}](e, MethodPublicPostMarketSecurities, payload, e.GetRetryNum("LoadMarkets", 1))
+		data, err := requestSSI[[]map[string]interface{}](ctx, e, MethodPublicPostMarketSecurities, payload, e.GetRetryNum("LoadMarkets", 1))
 		if err != nil {
 			return nil, err
 		}
@@ -115,7 +118,7 @@ func (e *Vietnam) fetchSecuritiesRows(board string) ([]map[string]interface{}, *
 	return rows, nil
 }
 
-func (e *Vietnam) fetchSecuritiesDetailRows(board string) ([]map[string]interface{}, *errs.Error) {
+func (e *Vietnam) fetchSecuritiesDetailRows(ctx context.Context, board string) ([]map[string]interface{}, *errs.Error) {
 	rows := make([]map[string]interface{}, 0)
 	for page := 1; page <= 10; page++ {
 		payload := map[string]interface{}{
@@ -129,7 +132,7 @@ func (e *Vietnam) fetchSecuritiesDetailRows(board string) ([]map[string]interfac
 			zap.Any("payload", payload),
 		)
 		// SecuritiesDetails returns dataList[].repeatedinfoList[] nested structure
-		data, err := requestSSI[[]map[string]interface{}](e, MethodPublicPostMarketSecuritiesInfo, payload, e.GetRetryNum("LoadMarkets", 1))
+		data, err := requestSSI[[]map[string]interface{}](ctx, e, MethodPublicPostMarketSecuritiesInfo, payload, e.GetRetryNum("LoadMarkets", 1))
 		if err != nil {
 			return rows, err
 		}
@@ -165,6 +168,12 @@ func newStockMarket(board, ticker string, info map[string]interface{}) *banexg.M
 	if priceTick > 0 {
 		modePrice = banexg.PrecModeTickSize
 	}
+	amountPrec, modeAmount := 0.0, banexg.PrecModeDecimalPlace
+	limits := &banexg.MarketLimits{}
+	if lot, ok := boardLots[board]; ok {
+		amountPrec, modeAmount = lot, banexg.PrecModeTickSize
+		limits.Amount = &banexg.LimitRange{Min: lot}
+	}
 	market := &banexg.Market{
 		ID:          rawID,
 		LowercaseID: strings.ToLower(rawID),
@@ -176,12 +185,16 @@ func newStockMarket(board, ticker string, info map[string]interface{}) *banexg.M
 		Active:      true,
 		FeeSide:     "quote",
 		Precision: &banexg.Precision{
-			Amount:     0,
+			Amount:     amountPrec,
 			Price:      priceTick,
-			ModeAmount: banexg.PrecModeDecimalPlace,
+			ModeAmount: modeAmount,
 			ModePrice:  modePrice,
 		},
-		Info: info,
+		Limits: limits,
+		Info:   info,
+	}
+	if sessions, ok := boardSessions[board]; ok {
+		market.DayTimes, _ = utils.ParseTimeRanges(sessions, banexg.LocUTC)
 	}
 	market.Info["board"] = board
 	market.Info["ticker"] = ticker
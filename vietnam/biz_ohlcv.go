@@ -1,6 +1,7 @@
 package vietnam
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ func (e *Vietnam) FetchOHLCV(symbol, timeframe string, since int64, limit int, p
 	if limit <= 0 {
 		limit = 200
 	}
+	ctx := banexg.ParamsCtx(args)
 	until := utils.PopMapVal(args, banexg.ParamUntil, int64(0))
 	if until <= 0 {
 		until = e.MilliSeconds()
@@ -34,13 +36,13 @@ func (e *Vietnam) FetchOHLCV(symbol, timeframe string, since int64, limit int, p
 	}
 
 	if strings.EqualFold(timeframe, "1d") {
-		return e.fetchDailyOHLCV(ticker, from, until, limit)
+		return e.fetchDailyOHLCV(ctx, ticker, from, until, limit)
 	}
 	res := e.GetTimeFrame(timeframe)
 	if res == "" || strings.EqualFold(res, "1D") {
 		return nil, errs.NewMsg(errs.CodeInvalidTimeFrame, "invalid timeframe: %s", timeframe)
 	}
-	return e.fetchIntradayOHLCV(ticker, res, from, until, limit)
+	return e.fetchIntradayOHLCV(ctx, ticker, res, from, until, limit)
 }
 
 func inferSinceByLimit(timeframe string, until int64, limit int) int64 {
@@ -54,7 +56,7 @@ func inferSinceByLimit(timeframe string, until int64, limit int) int64 {
 	return until - int64(limit*secs*1000)
 }
 
-func (e *Vietnam) fetchDailyOHLCV(ticker string, since, until int64, limit int) ([]*banexg.Kline, *errs.Error) {
+func (e *Vietnam) fetchDailyOHLCV(ctx context.Context, ticker string, since, until int64, limit int) ([]*banexg.Kline, *errs.Error) {
 	payload := map[string]interface{}{
 		"Symbol":    ticker,
 		"FromDate":  msToSSIDate(since),
@@ -63,7 +65,7 @@ func (e *Vietnam) fetchDailyOHLCV(ticker string, since, until int64, limit int)
 		"PageSize":  max(limit, 100),
 		"ascending": true,
 	}
-	rows, err := requestSSI[[]map[string]interface{}](e, MethodPublicGetMarketDailyOhlc, payload, e.GetRetryNum("FetchOHLCV", 1))
+	rows, err := requestSSI[[]map[string]interface{}](ctx, e, MethodPublicGetMarketDailyOhlc, payload, e.GetRetryNum("FetchOHLCV", 1))
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +73,7 @@ func (e *Vietnam) fetchDailyOHLCV(ticker string, since, until int64, limit int)
 	return normalizeKlineRange(out, since, until, limit), nil
 }
 
-func (e *Vietnam) fetchIntradayOHLCV(ticker, resolution string, since, until int64, limit int) ([]*banexg.Kline, *errs.Error) {
+func (e *Vietnam) fetchIntradayOHLCV(ctx context.Context, ticker, resolution string, since, until int64, limit int) ([]*banexg.Kline, *errs.Error) {
 	payload := map[string]interface{}{
 		"Symbol":     ticker,
 		"FromDate":   msToSSIDate(since),
@@ -81,7 +83,7 @@ func (e *Vietnam) fetchIntradayOHLCV(ticker, resolution string, since, until int
 		"resolution": resolution,
 		"ascending":  true,
 	}
-	rows, err := requestSSI[[]map[string]interface{}](e, MethodPublicPostMarketIntradayOHLC, payload, e.GetRetryNum("FetchOHLCV", 1))
+	rows, err := requestSSI[[]map[string]interface{}](ctx, e, MethodPublicPostMarketIntradayOHLC, payload, e.GetRetryNum("FetchOHLCV", 1))
 	if err != nil {
 		return nil, err
 	}
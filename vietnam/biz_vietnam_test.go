@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/banbox/banexg"
+	"github.com/banbox/banexg/errs"
 	"github.com/banbox/banexg/utils"
 )
 
@@ -103,3 +104,48 @@ func toMapRows(t *testing.T, value interface{}) []map[string]interface{} {
 	}
 	return rows
 }
+
+func TestStockMarketLots(t *testing.T) {
+	hose := newStockMarket("HOSE", "SSI", map[string]interface{}{"symbol": "SSI"})
+	if hose.Limits.Amount == nil || hose.Limits.Amount.Min != 100 || hose.Precision.Amount != 100 {
+		t.Fatalf("unexpected lot of HOSE: %+v %+v", hose.Limits.Amount, hose.Precision)
+	}
+	if len(hose.DayTimes) != 2 || hose.DayTimes[0][0] != 2*3600000 {
+		t.Fatalf("unexpected sessions of HOSE: %v", hose.DayTimes)
+	}
+	exg := &banexg.Exchange{ExgInfo: &banexg.ExgInfo{Markets: banexg.MarketMap{hose.Symbol: hose}}}
+	res, err := exg.ValidateOrder(hose.Symbol, banexg.OdTypeLimit, banexg.OdSideBuy, 150, 0, map[string]interface{}{
+		banexg.ParamAdjust: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Amount != 100 {
+		t.Fatalf("amount should be rounded to lot, got %v", res.Amount)
+	}
+}
+
+func TestEnsureSSISuccessBizErr(t *testing.T) {
+	cases := []struct {
+		status interface{}
+		msg    string
+		code   int
+	}{
+		{float64(400), "Insufficient purchasing power", errs.CodeInsufficientBalance},
+		{float64(400), "Không tìm thấy lệnh", errs.CodeOrderNotFound},
+		{"FAILED", "Order price exceeds ceiling price", errs.CodeParamInvalid},
+		{float64(400), "Market closed", errs.CodeMarketClosed},
+		{float64(400), "bad request", errs.CodeParamInvalid},
+		{float64(502), "gateway", errs.CodeServerError},
+		{"FAILED", "unknown", errs.CodeRunTime},
+	}
+	for _, c := range cases {
+		err := ensureSSISuccess(map[string]interface{}{"status": c.status, "message": c.msg})
+		if err == nil || err.Code != c.code {
+			t.Fatalf("%v %s: expect code %d, got %v", c.status, c.msg, c.code, err)
+		}
+	}
+	if err := ensureSSISuccess(map[string]interface{}{"status": "SUCCESS"}); err != nil {
+		t.Fatalf("success status should pass: %v", err)
+	}
+}
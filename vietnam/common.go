@@ -132,7 +132,7 @@ func (e *Vietnam) getAccessToken() (string, *errs.Error) {
 	return token, nil
 }
 
-func requestSSI[T any](e *Vietnam, endpoint string, payload map[string]interface{}, retryNum int) (T, *errs.Error) {
+func requestSSI[T any](ctx context.Context, e *Vietnam, endpoint string, payload map[string]interface{}, retryNum int) (T, *errs.Error) {
 	var zero T
 	api := e.Apis[endpoint]
 	method, url := "", ""
@@ -146,7 +146,7 @@ func requestSSI[T any](e *Vietnam, endpoint string, payload map[string]interface
 		zap.String("url", url),
 		zap.Any("payload", sanitizePayload(payload)),
 	)
-	res := e.RequestApiRetryAdv(context.Background(), endpoint, payload, retryNum, false, false)
+	res := e.RequestApiRetryAdv(ctx, endpoint, payload, retryNum, false, false)
 	if res.Error != nil {
 		log.Warn("vietnam ssi request failed",
 			zap.String("endpoint", endpoint),
@@ -263,7 +263,63 @@ func ensureSSISuccess(obj map[string]interface{}) *errs.Error {
 	if msg == "" {
 		msg = "ssi api failed"
 	}
-	return errs.NewMsg(errs.CodeRunTime, msg)
+	bizCode := int(anyInt64(status))
+	errCode := mapBizErr(bizCode, msg)
+	if errCode == 0 {
+		errCode = errs.CodeRunTime
+	}
+	err := errs.NewMsg(errCode, msg)
+	err.BizCode = bizCode
+	return err
+}
+
+/*
+ssiBizErrs
+SSI reports business errors with a generic status (400 or a failed status text), the reason is only in message,
+which is english or vietnamese. keywords are matched in order against the lower case message.
+SSI的业务错误只返回通用状态码，原因在message中(英文或越南文)，按顺序匹配小写message中的关键字
+*/
+var ssiBizErrs = []struct {
+	keys []string
+	code int
+}{
+	{[]string{"insufficient", "not enough", "không đủ", "vượt quá sức mua"}, errs.CodeInsufficientBalance},
+	{[]string{"order not found", "order does not exist", "không tìm thấy lệnh", "lệnh không tồn tại"}, errs.CodeOrderNotFound},
+	{[]string{"market closed", "outside trading", "not in trading session", "ngoài giờ giao dịch", "hết giờ giao dịch"}, errs.CodeMarketClosed},
+	{[]string{"ceiling", "floor", "giá trần", "giá sàn"}, errs.CodeParamInvalid},
+	{[]string{"token expired", "invalid token", "unauthorized", "hết hạn"}, errs.CodeUnauthorized},
+	{[]string{"signature"}, errs.CodeSignFail},
+	{[]string{"too many request", "rate limit"}, errs.CodeSystemBusy},
+	{[]string{"symbol not found", "invalid symbol", "mã chứng khoán không"}, errs.CodeNoMarketForPair},
+	{[]string{"data not found", "no data", "không có dữ liệu"}, errs.CodeDataNotFound},
+}
+
+// mapBizErr map SSI response status and message to normalized errs code
+func mapBizErr(bizCode int, msg string) int {
+	low := strings.ToLower(msg)
+	for _, item := range ssiBizErrs {
+		for _, key := range item.keys {
+			if strings.Contains(low, key) {
+				return item.code
+			}
+		}
+	}
+	switch bizCode {
+	case 400:
+		return errs.CodeParamInvalid
+	case 401:
+		return errs.CodeUnauthorized
+	case 403:
+		return errs.CodeForbidden
+	case 404:
+		return errs.CodeDataNotFound
+	case 429:
+		return errs.CodeSystemBusy
+	}
+	if bizCode >= 500 {
+		return errs.CodeServerError
+	}
+	return 0
 }
 
 func parseJWTExpMS(token string) int64 {
@@ -31,6 +31,22 @@ var (
 
 	marketBoards = []string{"HOSE", "HNX", "UPCOM", "DER"}
 
+	// round lot of each board, odd lots are matched in a separate session
+	boardLots = map[string]float64{
+		"HOSE":  100,
+		"HNX":   100,
+		"UPCOM": 100,
+		"DER":   1,
+	}
+
+	// continuous trading sessions in UTC, 09:00-11:30 and 13:00-14:45 ICT (UTC+7) for stocks
+	boardSessions = map[string][]string{
+		"HOSE":  {"02:00-04:30", "06:00-07:45"},
+		"HNX":   {"02:00-04:30", "06:00-07:45"},
+		"UPCOM": {"02:00-04:30", "06:00-07:45"},
+		"DER":   {"01:45-04:30", "06:00-07:45"},
+	}
+
 	statusSuccess = map[string]bool{
 		"SUCCESS": true,
 		"OK":      true,
@@ -38,6 +54,18 @@ var (
 
 	defaultHas = map[string]map[string]int{
 		"": {
+			banexg.ApiFetchTime:             banexg.HasFail,
+			banexg.ApiTransfer:              banexg.HasFail,
+			banexg.ApiFetchTransfers:        banexg.HasFail,
+			banexg.ApiFetchDepositAddress:   banexg.HasFail,
+			banexg.ApiFetchDeposits:         banexg.HasFail,
+			banexg.ApiFetchWithdrawals:      banexg.HasFail,
+			banexg.ApiWithdraw:              banexg.HasFail,
+			banexg.ApiBorrow:                banexg.HasFail,
+			banexg.ApiRepay:                 banexg.HasFail,
+			banexg.ApiFetchBorrowRate:       banexg.HasFail,
+			banexg.ApiFetchBorrowRates:      banexg.HasFail,
+			banexg.ApiFetchBorrowInterest:   banexg.HasFail,
 			banexg.ApiFetchTicker:           banexg.HasFail,
 			banexg.ApiFetchTickers:          banexg.HasFail,
 			banexg.ApiFetchTickerPrice:      banexg.HasFail,
@@ -46,16 +74,36 @@ var (
 			banexg.ApiGetLeverage:           banexg.HasFail,
 			banexg.ApiFetchOHLCV:            banexg.HasOk,
 			banexg.ApiFetchOrderBook:        banexg.HasFail,
+			banexg.ApiFetchTrades:           banexg.HasFail,
 			banexg.ApiFetchOrder:            banexg.HasFail,
 			banexg.ApiFetchOrders:           banexg.HasFail,
 			banexg.ApiFetchBalance:          banexg.HasFail,
 			banexg.ApiFetchAccountPositions: banexg.HasFail,
 			banexg.ApiFetchPositions:        banexg.HasFail,
 			banexg.ApiFetchOpenOrders:       banexg.HasFail,
+			banexg.ApiFetchMyTrades:         banexg.HasFail,
 			banexg.ApiCreateOrder:           banexg.HasFail,
 			banexg.ApiEditOrder:             banexg.HasFail,
 			banexg.ApiCancelOrder:           banexg.HasFail,
+			banexg.ApiCreateOrders:          banexg.HasFail,
+			banexg.ApiCancelOrders:          banexg.HasFail,
+			banexg.ApiCancelAllOrders:       banexg.HasFail,
 			banexg.ApiSetLeverage:           banexg.HasFail,
+			banexg.ApiSetMarginMode:         banexg.HasFail,
+			banexg.ApiSetPositionMode:       banexg.HasFail,
+			banexg.ApiAddMargin:             banexg.HasFail,
+			banexg.ApiReduceMargin:          banexg.HasFail,
+			banexg.ApiFetchOpenInterest:     banexg.HasFail,
+			banexg.ApiFetchOpenInterestHist: banexg.HasFail,
+			banexg.ApiFetchLongShortRatio:   banexg.HasFail,
+			banexg.ApiWatchLiquidations:     banexg.HasFail,
+			banexg.ApiUnWatchLiquidations:   banexg.HasFail,
+			banexg.ApiFetchOptionChain:      banexg.HasFail,
+			banexg.ApiFetchGreeks:           banexg.HasFail,
+			banexg.ApiWatchGreeks:           banexg.HasFail,
+			banexg.ApiUnWatchGreeks:         banexg.HasFail,
+			banexg.ApiFetchOHLCVRange:       banexg.HasOk,
+			banexg.ApiSetCancelAllAfter:     banexg.HasFail,
 			banexg.ApiCalcMaintMargin:       banexg.HasFail,
 			banexg.ApiWatchOrderBooks:       banexg.HasFail,
 			banexg.ApiUnWatchOrderBooks:     banexg.HasFail,
@@ -65,7 +113,10 @@ var (
 			banexg.ApiUnWatchMarkPrices:     banexg.HasFail,
 			banexg.ApiWatchTrades:           banexg.HasFail,
 			banexg.ApiUnWatchTrades:         banexg.HasFail,
+			banexg.ApiWatchTickers:          banexg.HasFail,
+			banexg.ApiUnWatchTickers:        banexg.HasFail,
 			banexg.ApiWatchMyTrades:         banexg.HasFail,
+			banexg.ApiWatchOrders:           banexg.HasFail,
 			banexg.ApiWatchBalance:          banexg.HasFail,
 			banexg.ApiWatchPositions:        banexg.HasFail,
 			banexg.ApiWatchAccountConfig:    banexg.HasFail,
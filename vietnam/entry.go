@@ -1,6 +1,8 @@
 package vietnam
 
 import (
+	"time"
+
 	"github.com/banbox/banexg"
 	"github.com/banbox/banexg/errs"
 )
@@ -20,9 +22,11 @@ func New(options map[string]interface{}) (*Vietnam, *errs.Error) {
 	exg := &Vietnam{
 		Exchange: &banexg.Exchange{
 			ExgInfo: &banexg.ExgInfo{
-				ID:        "vietnam",
-				Name:      "Vietnam",
-				Countries: []string{"VN"},
+				ID:         "vietnam",
+				Name:       "Vietnam",
+				Countries:  []string{"VN"},
+				OHLCVLimit: 1000,
+				TimeLoc:    time.FixedZone("ICT", 7*60*60),
 			},
 			RateLimit:  50,
 			Options:    options,
@@ -57,6 +61,7 @@ func New(options map[string]interface{}) (*Vietnam, *errs.Error) {
 		marketsByRawID:  map[string]*banexg.Market{},
 		marketsByTicker: map[string][]*banexg.Market{},
 	}
+	exg.Self = exg
 	exg.Sign = makeSign(exg)
 	err := exg.Init()
 	if err != nil {
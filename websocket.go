@@ -1,6 +1,7 @@
 package banexg
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/banbox/banexg/errs"
@@ -25,6 +27,9 @@ import (
 var (
 	maxClientConn = 20
 	connMinSubs   = 50
+	// WsReqTimeout max time to wait for the response of WsClient.Request
+	WsReqTimeout = 10 * time.Second
+	wsReqSeq     atomic.Int64
 )
 
 type WsClient struct {
@@ -51,6 +56,7 @@ type WsClient struct {
 	connLock      deadlock.Mutex
 	limitsLock    deadlock.Mutex // for odBookLimits
 	subsLock      deadlock.Mutex // for SubsKeyStamps
+	jobsLock      deadlock.Mutex // for JobInfos
 }
 
 type AsyncConn struct {
@@ -321,6 +327,9 @@ func (e *Exchange) GetClient(wsUrl string, marketType, accName string) (*WsClien
 	}
 	if conn, ok := e.Options[OptWsConn]; ok {
 		params[OptWsConn] = conn
+	} else if e.WsDecoder != nil {
+		// messages come from dump file in replay mode
+		params[OptWsConn] = newReplayConn()
 	}
 	if e.OnWsMsg == nil {
 		return nil, errs.NewMsg(errs.CodeParamInvalid, "OnWsMsg is required for ws client")
@@ -375,35 +384,133 @@ func WriteOutChan[T any](e *Exchange, chanKey string, msg T, popIfNeed bool) boo
 	// Keep lock held during send so DelWsChanRefs can't close the channel concurrently.
 	// Otherwise, we can panic with "send on closed channel" under unsubscribe races.
 	e.lockOutChan.Lock()
+	defer e.lockOutChan.Unlock()
+	for key, tapRaw := range e.WsChanTaps[chanKey] {
+		if tap, ok := tapRaw.(chan T); ok {
+			sendOutChan(tap, chanKey+"@"+key, msg, popIfNeed)
+		} else {
+			log.Error("tap chan type error", zap.String("k", chanKey), zap.String("tap", key))
+		}
+	}
 	outRaw, outOk := e.WsOutChans[chanKey]
 	if !outOk {
-		e.lockOutChan.Unlock()
 		return false
 	}
 	out, ok := outRaw.(chan T)
 	if !ok {
-		e.lockOutChan.Unlock()
 		log.Error("out chan type error", zap.String("k", chanKey))
 		return false
 	}
+	return sendOutChan(out, chanKey, msg, popIfNeed)
+}
+
+// sendOutChan should be called with lockOutChan held
+func sendOutChan[T any](out chan T, chanKey string, msg T, popIfNeed bool) bool {
 	select {
 	case out <- msg:
-		e.lockOutChan.Unlock()
 		return true
 	default:
 		if !popIfNeed {
-			e.lockOutChan.Unlock()
 			log.Error("out chan full", zap.String("k", chanKey))
 			return false
 		}
 		// chan通道满了，弹出最早的消息，重新发送
 		<-out
 		out <- msg
-		e.lockOutChan.Unlock()
 		return true
 	}
 }
 
+/*
+AddWsChanTap
+register a private chan of key for chanKey: every message written to chanKey is also sent to it, even after the
+out chan is removed. Internal consumers (e.g. OrderTracker) use it, so they never compete with users of the out chan.
+The tap is closed by DelWsChanTap or Close.
+为chanKey注册key的私有通道：写入chanKey的每条消息也会发送到该通道，即使输出通道已被移除。
+内部消费者(如OrderTracker)使用它，避免与输出通道的使用者争抢消息。通过DelWsChanTap或Close关闭
+*/
+func AddWsChanTap[T any](e *Exchange, chanKey, key string, chanCap int) chan T {
+	tap := make(chan T, chanCap)
+	e.lockOutChan.Lock()
+	defer e.lockOutChan.Unlock()
+	if e.WsChanTaps == nil {
+		e.WsChanTaps = make(map[string]map[string]interface{})
+	}
+	data, ok := e.WsChanTaps[chanKey]
+	if !ok {
+		data = make(map[string]interface{})
+		e.WsChanTaps[chanKey] = data
+	}
+	if old, ok := data[key]; ok {
+		reflect.ValueOf(old).Close()
+	}
+	data[key] = tap
+	return tap
+}
+
+// DelWsChanTap remove and close the private chan of key for chanKey
+func (e *Exchange) DelWsChanTap(chanKey, key string) {
+	e.lockOutChan.Lock()
+	defer e.lockOutChan.Unlock()
+	data := e.WsChanTaps[chanKey]
+	if tap, ok := data[key]; ok {
+		reflect.ValueOf(tap).Close()
+		delete(data, key)
+	}
+	if len(data) == 0 {
+		delete(e.WsChanTaps, chanKey)
+	}
+}
+
+/*
+UpdateWsTicker
+merge the ticker update into the Tickers cache, return a copy of the merged ticker for output
+将ticker更新合并到Tickers缓存，返回合并后的副本用于输出
+*/
+func (e *Exchange) UpdateWsTicker(u *Ticker) *Ticker {
+	e.TickerLock.Lock()
+	defer e.TickerLock.Unlock()
+	if e.Tickers == nil {
+		e.Tickers = make(map[string]*Ticker)
+	}
+	cur, ok := e.Tickers[u.Symbol]
+	if !ok {
+		cur = &Ticker{Symbol: u.Symbol}
+		e.Tickers[u.Symbol] = cur
+	}
+	cur.Merge(u)
+	res := *cur
+	return &res
+}
+
+/*
+ResetOdBook
+reset the cached ws order book which failed the integrity check, then fire OnOdBookReset.
+The caller should re-fetch a snapshot, updates before the snapshot should be dropped as Nonce is 0.
+重置未通过完整性检查的ws订单簿并触发OnOdBookReset，调用方需重新获取快照，Nonce为0时应丢弃快照前的更新
+*/
+func (e *Exchange) ResetOdBook(client *WsClient, evt *OdBookReset) {
+	e.OdBookLock.Lock()
+	book, ok := e.OrderBooks[evt.Symbol]
+	e.OdBookLock.Unlock()
+	if ok {
+		evt.Nonce = book.Nonce
+		book.Reset()
+	}
+	if evt.MarketType == "" && client != nil {
+		evt.MarketType = client.MarketType
+	}
+	if evt.TimeStamp == 0 {
+		evt.TimeStamp = e.MilliSeconds()
+	}
+	log.Warn("ws order book reset", zap.String("exg", e.Name), zap.String("symbol", evt.Symbol),
+		zap.String("reason", evt.Reason), zap.Int64("nonce", evt.Nonce), zap.Int64("expect", evt.Expect),
+		zap.Int64("actual", evt.Actual))
+	if e.OnOdBookReset != nil {
+		e.OnOdBookReset(client, evt)
+	}
+}
+
 func (e *Exchange) AddWsChanRefs(chanKey string, keys ...string) {
 	e.lockWsRef.Lock()
 	data, ok := e.WsChanRefs[chanKey]
@@ -443,6 +550,37 @@ func (e *Exchange) DelWsChanRefs(chanKey string, keys ...string) int {
 	return hasNum
 }
 
+// GetWsChanKey key of the out chan in WsOutChans, empty if not found
+func (e *Exchange) GetWsChanKey(out interface{}) string {
+	e.lockOutChan.Lock()
+	defer e.lockOutChan.Unlock()
+	for key, val := range e.WsOutChans {
+		if val == out {
+			return key
+		}
+	}
+	return ""
+}
+
+// GetWsChanRefs keys referenced by the out chan
+func (e *Exchange) GetWsChanRefs(chanKey string) []string {
+	e.lockWsRef.Lock()
+	defer e.lockWsRef.Unlock()
+	return utils.KeysOfMap(e.WsChanRefs[chanKey])
+}
+
+// HasWsChanRef whether the key is still referenced by the out chan, used when a ws topic is shared by chans
+func (e *Exchange) HasWsChanRef(chanKey, key string) bool {
+	e.lockWsRef.Lock()
+	defer e.lockWsRef.Unlock()
+	data, ok := e.WsChanRefs[chanKey]
+	if !ok {
+		return false
+	}
+	_, ok = data[key]
+	return ok
+}
+
 func (e *Exchange) handleWsClientClosed(client *WsClient) int {
 	prefix := client.Prefix("")
 	removeNum := 0
@@ -582,9 +720,11 @@ func (c *WsClient) Write(conn *AsyncConn, msg interface{}, info *WsJobInfo) *err
 		if info.ID == "" {
 			return errs.NewMsg(errs.CodeParamRequired, "WsJobInfo.ID is required")
 		}
+		c.jobsLock.Lock()
 		if _, ok := c.JobInfos[info.ID]; !ok {
 			c.JobInfos[info.ID] = info
 		}
+		c.jobsLock.Unlock()
 	}
 	if c.Debug {
 		log.Debug("write ws msg", zap.String("url", c.URL), zap.Int("id", conn.GetID()),
@@ -594,6 +734,55 @@ func (c *WsClient) Write(conn *AsyncConn, msg interface{}, info *WsJobInfo) *err
 	return nil
 }
 
+/*
+Request
+send msg and wait for the response with the same id, used by websocket trading api.
+CodeConnectFail is returned when the msg is not sent, so the caller can fallback to rest api safely.
+发送消息并等待相同id的响应，用于websocket交易接口。
+消息未发出时返回CodeConnectFail，调用方可安全地回退到rest接口
+*/
+func (c *WsClient) Request(ctx context.Context, id string, msg interface{}) (*WsMsg, *errs.Error) {
+	if c.Exg != nil && c.Exg.WsDecoder != nil {
+		return nil, errs.NewMsg(errs.CodeConnectFail, "ws request not available in replay mode")
+	}
+	_, conn := c.UpdateSubs(0, true, nil)
+	if conn == nil || !conn.IsOK() {
+		return nil, errs.NewMsg(errs.CodeConnectFail, "ws conn not ready: %s", c.URL)
+	}
+	info := &WsJobInfo{ID: id, Result: make(chan *WsMsg, 1)}
+	if err := c.Write(conn, msg, info); err != nil {
+		c.DelJobInfo(id)
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(WsReqTimeout)
+	defer timer.Stop()
+	select {
+	case rsp := <-info.Result:
+		return rsp, nil
+	case <-timer.C:
+		c.DelJobInfo(id)
+		return nil, errs.NewMsg(errs.CodeTimeout, "ws request timeout: %s %s", c.URL, id)
+	case <-ctx.Done():
+		c.DelJobInfo(id)
+		return nil, CtxError(ctx)
+	}
+}
+
+// DelJobInfo remove the job of id, the response of it will be passed to OnMessage
+func (c *WsClient) DelJobInfo(id string) {
+	c.jobsLock.Lock()
+	delete(c.JobInfos, id)
+	c.jobsLock.Unlock()
+}
+
+// NewWsReqID return an unique alphanumeric id for websocket requests
+func NewWsReqID() string {
+	return strconv.FormatInt(time.Now().UnixMilli(), 36) + strconv.FormatInt(wsReqSeq.Add(1), 36)
+}
+
 // WriteRaw sends raw bytes without JSON marshaling (e.g., for OKX ping/pong)
 func (c *WsClient) WriteRaw(conn *AsyncConn, data []byte) *errs.Error {
 	if conn == nil || c.Exg.WsDecoder != nil {
@@ -707,7 +896,7 @@ func (c *WsClient) read(conn *AsyncConn) {
 		if c.Exg.WsDecoder == nil {
 			// We cannot start a goroutine for each message here, otherwise it will result in incorrect message processing order
 			// 这里不能对每个消息启动一个goroutine，否则会导致消息处理顺序错误
-			c.Exg.DumpWS("wsMsg", []string{c.URL, c.MarketType, c.AccName, string(msgRaw)})
+			c.Exg.DumpWS(WsLogWsMsg, []string{c.URL, c.MarketType, c.AccName, string(msgRaw)})
 			c.HandleRawMsg(msgRaw)
 		}
 	}
@@ -731,10 +920,19 @@ func (c *WsClient) HandleRawMsg(msgRaw []byte) {
 		return
 	}
 	if !msg.IsArray && msg.ID != "" {
-		if sub, ok := c.JobInfos[msg.ID]; ok && sub.Method != nil {
+		c.jobsLock.Lock()
+		sub, ok := c.JobInfos[msg.ID]
+		if ok && (sub.Result != nil || sub.Method != nil) {
+			delete(c.JobInfos, msg.ID)
+		}
+		c.jobsLock.Unlock()
+		if ok && sub.Result != nil {
+			// 同步请求等待响应，Result有缓冲，不会阻塞
+			sub.Result <- msg
+			return
+		} else if ok && sub.Method != nil {
 			// 订阅信息中提供了处理函数，则调用处理函数
 			sub.Method(c, msg.Object, sub)
-			delete(c.JobInfos, msg.ID)
 			return
 		}
 	}
@@ -857,11 +1055,25 @@ func (c *WsClient) HasSubKeyPrefix(prefix string) bool {
 
 func (c *WsClient) newConn(add bool) (*AsyncConn, *errs.Error) {
 	connID := c.NextConnId
-	conn, err := newWebSocket(connID, c.URL, c.connArgs, func() *errs.Error {
-		return c.OnReConn(c, connID)
-	})
-	if err != nil {
-		return nil, err
+	var conn *AsyncConn
+	var err *errs.Error
+	if c.Exg != nil && c.Exg.WsDecoder != nil {
+		conn = newReplayConn()
+		conn.SetID(connID)
+	} else {
+		conn, err = newWebSocket(connID, c.URL, c.connArgs, func() *errs.Error {
+			var err2 *errs.Error
+			if c.OnReConn != nil {
+				err2 = c.OnReConn(c, connID)
+			}
+			if c.Exg != nil {
+				c.Exg.runWsReConHooks(c, connID)
+			}
+			return err2
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 	log.Debug("new websocket conn", zap.String("url", c.URL), zap.Int("id", conn.GetID()))
 	c.NextConnId += 1
@@ -904,6 +1116,10 @@ func NewWsMsg(msgText string) (*WsMsg, *errs.Error) {
 			var obj = utils.MapValStr(msg)
 			event, _ := utils.SafeMapVal(obj, "e", "")
 			id, _ := utils.SafeMapVal(obj, "id", "")
+			if id == "" {
+				// bybit trade api use reqId
+				id, _ = utils.SafeMapVal(obj, "reqId", "")
+			}
 			return &WsMsg{Event: event, ID: id, Object: obj, Text: msgText}, nil
 		}
 	} else if strings.HasPrefix(msgText, "[") {